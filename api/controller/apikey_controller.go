@@ -0,0 +1,205 @@
+package controller
+
+import (
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	keycmd "github.com/Efamamo/GoCrudChallange/application/apikeys/command"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/apikey"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// APIKeyController defines handlers for administering API keys.
+type APIKeyController struct {
+	BaseController
+
+	CreateHandler icmd.IHandler[*keycmd.CreateAPIKeyCommand, *keycmd.CreateAPIKeyResult]
+	UpdateHandler icmd.IHandler[*keycmd.UpdateAPIKeyCommand, *apikey.APIKey]
+	RevokeHandler icmd.IHandler[uuid.UUID, *apikey.APIKey]
+	DeleteHandler icmd.IHandler[uuid.UUID, bool]
+	GetHandler    iquery.IHandler[uuid.UUID, *apikey.APIKey]
+	GetAllHandler iquery.IHandler[struct{}, []*apikey.APIKey]
+}
+
+// Create handles generating a new API key.
+// The key belongs to the tenant of the body, or else of the request, and its requests are confined to it.
+// Responds with a 201 status code and the key along with its secret, which is only ever shown here,
+// or 403 if the caller belongs to another tenant.
+func (kc *APIKeyController) Create(c *gin.Context) {
+	if !kc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	var dto APIKeyDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		e := errapi.NewBadRequest("Invalid input data format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	t, ok := kc.tenantOf(c, dto.Tenant)
+	if !ok {
+		return
+	}
+
+	command := &keycmd.CreateAPIKeyCommand{
+		Name:      dto.Name,
+		Scopes:    dto.Scopes,
+		ExpiresAt: dto.ExpiresAt,
+		Tenant:    t,
+	}
+
+	result, err := kc.CreateHandler.Handle(command)
+	if err != nil {
+		e := errapi.Map(err)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(201, CreatedAPIKeyResponseDTO{
+		APIKeyResponseDTO: newAPIKeyResponseDTO(result.Key),
+		Secret:            result.Secret,
+	})
+}
+
+// GetAll retrieves every API key, without their secrets.
+// Responds with a 200 status code and the keys.
+func (kc *APIKeyController) GetAll(c *gin.Context) {
+	if !kc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	keys, err := kc.GetAllHandler.Handle(struct{}{})
+	if err != nil {
+		kc.respondQueryError(c, err)
+		return
+	}
+
+	var responses = make([]APIKeyResponseDTO, 0, len(keys))
+	for _, k := range keys {
+		responses = append(responses, newAPIKeyResponseDTO(k))
+	}
+
+	c.IndentedJSON(200, responses)
+}
+
+// Get retrieves an API key by its ID.
+// Responds with a 200 status code and the key, or 404 if it was not found.
+func (kc *APIKeyController) Get(c *gin.Context) {
+	if !kc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	key, qerr := kc.GetHandler.Handle(id)
+	if qerr != nil {
+		kc.respondQueryError(c, qerr)
+		return
+	}
+
+	c.IndentedJSON(200, newAPIKeyResponseDTO(key))
+}
+
+// Update handles renaming an API key and changing its scopes and expiry.
+// Responds with a 200 status code and the key, or 404 if it was not found.
+func (kc *APIKeyController) Update(c *gin.Context) {
+	if !kc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	var dto APIKeyDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		e := errapi.NewBadRequest("Invalid input data format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	command := &keycmd.UpdateAPIKeyCommand{
+		ID:        id,
+		Name:      dto.Name,
+		Scopes:    dto.Scopes,
+		ExpiresAt: dto.ExpiresAt,
+	}
+
+	key, cerr := kc.UpdateHandler.Handle(command)
+	if cerr != nil {
+		e := errapi.Map(cerr)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(200, newAPIKeyResponseDTO(key))
+}
+
+// Revoke handles permanently disabling an API key, keeping it listed for auditing.
+// Responds with a 200 status code and the revoked key, or 404 if it was not found.
+func (kc *APIKeyController) Revoke(c *gin.Context) {
+	if !kc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	key, cerr := kc.RevokeHandler.Handle(id)
+	if cerr != nil {
+		e := errapi.Map(cerr)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(200, newAPIKeyResponseDTO(key))
+}
+
+// Delete handles the removal of an API key by its ID.
+// Responds with a 204 status code if successful, or 404 if the key was not found.
+func (kc *APIKeyController) Delete(c *gin.Context) {
+	if !kc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	if _, err := kc.DeleteHandler.Handle(id); err != nil {
+		e := errapi.Map(err)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(204, nil)
+}
+
+// respondQueryError writes the error returned by a query handler.
+func (kc *APIKeyController) respondQueryError(c *gin.Context, err error) {
+	if customErr, ok := err.(ierr.IErr); ok {
+		e := errapi.Map(customErr)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+	c.IndentedJSON(500, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/domain/model/apikey"
+	"github.com/google/uuid"
+)
+
+// APIKeyDTO represents the data structure for creating or updating an API key.
+type APIKeyDTO struct {
+	Name      string     `json:"name" binding:"required"` // Name describing the holder of the key; required
+	Scopes    []string   `json:"scopes"`                  // Permissions granted to the key; optional
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`    // Time after which the key is rejected; optional
+	Tenant    string     `json:"tenant,omitempty"`        // Tenant the key belongs to, on creation; the tenant of the request when empty
+}
+
+// APIKeyResponseDTO defines the data structure for returning API keys in responses.
+// The secret is never part of it, only its prefix.
+type APIKeyResponseDTO struct {
+	ID         uuid.UUID  `json:"id"`                     // Unique identifier of the key
+	Name       string     `json:"name"`                   // Name describing the holder of the key
+	Prefix     string     `json:"prefix"`                 // First characters of the secret, to recognize the key
+	Scopes     []string   `json:"scopes"`                 // Permissions granted to the key
+	Tenant     string     `json:"tenant,omitempty"`       // Tenant the key belongs to, and its requests are confined to
+	CreatedAt  time.Time  `json:"created_at"`             // Time the key was generated
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`   // Time after which the key is rejected
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`   // Time the key was revoked
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"` // Time the key last authenticated a request
+}
+
+// CreatedAPIKeyResponseDTO is returned once when a key is generated, along with its secret.
+type CreatedAPIKeyResponseDTO struct {
+	APIKeyResponseDTO
+	Secret string `json:"secret"` // Secret to send in the X-API-Key header; never shown again
+}
+
+// newAPIKeyResponseDTO maps an APIKey to its APIKeyResponseDTO.
+func newAPIKeyResponseDTO(k *apikey.APIKey) APIKeyResponseDTO {
+	return APIKeyResponseDTO{
+		ID:         k.Id(),
+		Name:       k.Name(),
+		Prefix:     k.Prefix(),
+		Scopes:     k.Scopes(),
+		Tenant:     k.Tenant(),
+		CreatedAt:  k.CreatedAt(),
+		ExpiresAt:  k.ExpiresAt(),
+		RevokedAt:  k.RevokedAt(),
+		LastUsedAt: k.LastUsedAt(),
+	}
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	attrcmd "github.com/Efamamo/GoCrudChallange/application/attributes/command"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/attribute"
+	"github.com/gin-gonic/gin"
+)
+
+// AttributeController defines handlers for administering the schema of custom attributes on people.
+type AttributeController struct {
+	BaseController
+
+	SaveHandler   icmd.IHandler[*attrcmd.SaveAttributeCommand, *attribute.Definition]
+	DeleteHandler icmd.IHandler[string, bool]
+	GetAllHandler iquery.IHandler[struct{}, []*attribute.Definition]
+}
+
+// Save handles defining or redefining the custom attribute named in the URL.
+// Responds with a 200 status code and the definition if successful, or 400 if the definition is invalid.
+func (ac *AttributeController) Save(c *gin.Context) {
+	if !ac.Authorize(c, auth.Admin) {
+		return
+	}
+
+	var dto AttributeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		e := errapi.NewBadRequest("Invalid input data format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	command := &attrcmd.SaveAttributeCommand{
+		Name:     c.Param("name"),
+		Type:     dto.Type,
+		Required: dto.Required,
+		Enum:     dto.Enum,
+		Min:      dto.Min,
+		Max:      dto.Max,
+	}
+
+	definition, err := ac.SaveHandler.Handle(command)
+	if err != nil {
+		e := errapi.Map(err)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(200, newAttributeResponseDTO(definition))
+}
+
+// GetAll retrieves every custom attribute definition.
+// Responds with a 200 status code and the definitions sorted by name.
+func (ac *AttributeController) GetAll(c *gin.Context) {
+	if !ac.Authorize(c, auth.Admin) {
+		return
+	}
+
+	definitions, err := ac.GetAllHandler.Handle(struct{}{})
+	if err != nil {
+		if customErr, ok := err.(ierr.IErr); ok {
+			e := errapi.Map(customErr)
+			c.IndentedJSON(e.StatusCode(), errorBody(e))
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	var responses = make([]AttributeResponseDTO, 0, len(definitions))
+	for _, d := range definitions {
+		responses = append(responses, newAttributeResponseDTO(d))
+	}
+
+	c.IndentedJSON(200, responses)
+}
+
+// Delete handles the removal of a custom attribute definition by its name.
+// Responds with a 204 status code if successful, or 404 if the attribute was not found.
+func (ac *AttributeController) Delete(c *gin.Context) {
+	if !ac.Authorize(c, auth.Admin) {
+		return
+	}
+
+	if _, err := ac.DeleteHandler.Handle(c.Param("name")); err != nil {
+		e := errapi.Map(err)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(204, nil)
+}
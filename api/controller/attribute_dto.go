@@ -0,0 +1,32 @@
+package controller
+
+import "github.com/Efamamo/GoCrudChallange/domain/model/attribute"
+
+// AttributeDTO represents the data structure for defining a custom attribute.
+type AttributeDTO struct {
+	Type     string   `json:"type" binding:"required"` // Type of the value: string, number, integer or boolean; required
+	Required bool     `json:"required"`                // Whether every person should carry the attribute; optional
+	Enum     []string `json:"enum,omitempty"`          // Allowed values of a string attribute; optional
+	Min      *float64 `json:"min,omitempty"`           // Inclusive lower bound of a numeric attribute; optional
+	Max      *float64 `json:"max,omitempty"`           // Inclusive upper bound of a numeric attribute; optional
+}
+
+// AttributeResponseDTO defines the data structure for returning attribute definitions in responses.
+type AttributeResponseDTO struct {
+	Name string `json:"name"` // Name of the attribute
+	AttributeDTO
+}
+
+// newAttributeResponseDTO maps an attribute Definition to its AttributeResponseDTO.
+func newAttributeResponseDTO(d *attribute.Definition) AttributeResponseDTO {
+	return AttributeResponseDTO{
+		Name: d.Name(),
+		AttributeDTO: AttributeDTO{
+			Type:     d.Type(),
+			Required: d.Required(),
+			Enum:     d.Enum(),
+			Min:      d.Min(),
+			Max:      d.Max(),
+		},
+	}
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"time"
+
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	apperror "github.com/Efamamo/GoCrudChallange/application/error"
+	usercmd "github.com/Efamamo/GoCrudChallange/application/users/command"
+	"github.com/gin-gonic/gin"
+)
+
+// AuthController defines handlers for obtaining and revoking tokens of local users.
+type AuthController struct {
+	BaseController
+
+	LoginHandler   icmd.IHandler[*usercmd.LoginCommand, *usercmd.TokenPair]
+	RefreshHandler icmd.IHandler[string, *usercmd.TokenPair]
+	LogoutHandler  icmd.IHandler[*usercmd.LogoutCommand, bool]
+}
+
+// Login handles exchanging a username and password for a pair of tokens.
+// Responds with a 200 status code and the tokens, or 401 if the credentials are wrong.
+func (ac *AuthController) Login(c *gin.Context) {
+	var dto LoginDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		e := errapi.NewBadRequest("Invalid input data format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	pair, err := ac.LoginHandler.Handle(&usercmd.LoginCommand{Username: dto.Username, Password: dto.Password})
+	if err != nil {
+		e := errapi.Map(err)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(200, newTokenResponseDTO(pair))
+}
+
+// Refresh handles exchanging a refresh token for a new pair of tokens.
+// Responds with a 200 status code and the tokens, or 401 if the refresh token is invalid, expired or used.
+func (ac *AuthController) Refresh(c *gin.Context) {
+	var dto RefreshDTO
+	if err := c.ShouldBindJSON(&dto); err != nil || dto.RefreshToken == "" {
+		e := errapi.NewBadRequest("Invalid input data format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	pair, err := ac.RefreshHandler.Handle(dto.RefreshToken)
+	if err != nil {
+		e := errapi.Map(err)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(200, newTokenResponseDTO(pair))
+}
+
+// Logout handles ending the session of the caller: its access token and, when sent, its refresh token are revoked.
+// Responds with a 204 status code if successful, or 401 if the caller is not authenticated.
+func (ac *AuthController) Logout(c *gin.Context) {
+	principal, ok := auth.PrincipalFrom(c.Request.Context())
+	if !ok || principal.IsAnonymous() {
+		e := errapi.Map(apperror.InvalidCredential("missing bearer token"))
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	// The refresh token is optional, so an empty body is accepted.
+	var dto RefreshDTO
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&dto); err != nil {
+			e := errapi.NewBadRequest("Invalid input data format")
+			c.IndentedJSON(e.StatusCode(), errorBody(e))
+			return
+		}
+	}
+
+	command := &usercmd.LogoutCommand{
+		UserID:       principal.Subject,
+		RefreshToken: dto.RefreshToken,
+	}
+	if id, ok := principal.Claims["jti"].(string); ok {
+		command.AccessTokenID = id
+	}
+	if exp, ok := principal.Claims["exp"].(float64); ok {
+		command.AccessExpiresAt = time.Unix(int64(exp), 0)
+	}
+
+	if _, err := ac.LogoutHandler.Handle(command); err != nil {
+		e := errapi.Map(err)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(204, nil)
+}
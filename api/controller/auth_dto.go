@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"time"
+
+	usercmd "github.com/Efamamo/GoCrudChallange/application/users/command"
+	"github.com/Efamamo/GoCrudChallange/domain/model/user"
+	"github.com/google/uuid"
+)
+
+// LoginDTO represents the credentials sent to log in.
+type LoginDTO struct {
+	Username string `json:"username" binding:"required"` // Username of the account; required
+	Password string `json:"password" binding:"required"` // Password of the account; required
+}
+
+// RefreshDTO represents the refresh token sent to refresh or end a session.
+type RefreshDTO struct {
+	RefreshToken string `json:"refresh_token"` // Refresh token issued on login or on the previous refresh
+}
+
+// TokenResponseDTO defines the data structure for returning issued tokens.
+type TokenResponseDTO struct {
+	AccessToken      string    `json:"access_token"`       // Bearer token to send in the Authorization header
+	TokenType        string    `json:"token_type"`         // Always "Bearer"
+	ExpiresIn        int64     `json:"expires_in"`         // Seconds until the access token expires
+	RefreshToken     string    `json:"refresh_token"`      // Single-use token exchanged for new tokens
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"` // Time after which the refresh token is rejected
+}
+
+// newTokenResponseDTO maps a TokenPair to its TokenResponseDTO.
+func newTokenResponseDTO(pair *usercmd.TokenPair) TokenResponseDTO {
+	return TokenResponseDTO{
+		AccessToken:      pair.AccessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(time.Until(pair.AccessExpiresAt).Seconds()),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt,
+	}
+}
+
+// UserDTO represents the data structure for creating a local user.
+type UserDTO struct {
+	Username string   `json:"username" binding:"required"` // Username of the account; required
+	Password string   `json:"password" binding:"required"` // Password of the account, 8 to 72 characters; required
+	Roles    []string `json:"roles"`                       // Roles granted to the user; optional
+	Tenant   string   `json:"tenant"`                      // Tenant the user belongs to; the tenant of the request when empty
+}
+
+// UserResponseDTO defines the data structure for returning users in responses, without their password.
+type UserResponseDTO struct {
+	ID        uuid.UUID `json:"id"`               // Unique identifier of the user, the subject of its tokens
+	Username  string    `json:"username"`         // Username of the account
+	Roles     []string  `json:"roles"`            // Roles granted to the user
+	Tenant    string    `json:"tenant,omitempty"` // Tenant the user belongs to, and its tokens are confined to
+	CreatedAt time.Time `json:"created_at"`       // Time the user was created
+}
+
+// newUserResponseDTO maps a User to its UserResponseDTO.
+func newUserResponseDTO(u *user.User) UserResponseDTO {
+	return UserResponseDTO{
+		ID:        u.Id(),
+		Username:  u.Username(),
+		Roles:     u.Roles(),
+		Tenant:    u.Tenant(),
+		CreatedAt: u.CreatedAt(),
+	}
+}
@@ -1,12 +1,52 @@
 package controller
 
 import (
+	"strings"
+
 	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	apperror "github.com/Efamamo/GoCrudChallange/application/error"
 	"github.com/gin-gonic/gin"
 )
 
 // BaseController is a base struct for all HTTP request handlers that provides basic HTTP functionalities.
-type BaseController struct{}
+type BaseController struct {
+	Authorizer *auth.Authorizer // Checks the permissions of callers; every caller is allowed when nil
+}
+
+// Authorize checks that the caller holds the permission before a command or query handler is invoked.
+// It responds with a 401 or 403 and returns false when the caller is not allowed.
+func (h *BaseController) Authorize(c *gin.Context, permission auth.Permission) bool {
+	if h.Authorizer == nil {
+		return true
+	}
+
+	if err := h.Authorizer.Authorize(c.Request.Context(), permission); err != nil {
+		e := errapi.Map(err)
+		render(c, e.StatusCode(), errorBody(e))
+		return false
+	}
+	return true
+}
+
+// tenantOf returns the tenant of a user or API key created by the caller: the requested one, which only callers
+// belonging to no tenant may name, or else the tenant of the request. It responds with a 403 and returns false
+// when a caller belonging to a tenant names another one.
+func (h *BaseController) tenantOf(c *gin.Context, requested string) (string, bool) {
+	current := tenant.From(c.Request.Context())
+	requested = strings.ToLower(strings.TrimSpace(requested))
+	if requested == "" || requested == current {
+		return current, true
+	}
+
+	if principal, ok := auth.PrincipalFrom(c.Request.Context()); ok && principal.Tenant != "" {
+		e := errapi.Map(apperror.AccessDenied("caller doesn't belong to the tenant"))
+		render(c, e.StatusCode(), errorBody(e))
+		return "", false
+	}
+	return requested, true
+}
 
 // RespondError handles errors by writing an appropriate response to the Gin context.
 // The primary usage is to hide specific messages from the client.
@@ -14,7 +54,17 @@ func (h *BaseController) RespondError(c *gin.Context, err errapi.Error) {
 	if err.StatusCode() == errapi.ServerError {
 		err = errapi.NewServerError("something went wrong")
 	}
-	c.JSON(err.StatusCode(), gin.H{"error": err.Error()})
+	c.JSON(err.StatusCode(), errorBody(err))
+}
+
+// errorBody builds the JSON body of an error response.
+// Conflicts caused by an existing record also carry the ID of that record.
+func errorBody(err errapi.Error) gin.H {
+	body := gin.H{"error": err.Error()}
+	if id := err.ExistingID(); id != "" {
+		body["existing_id"] = id
+	}
+	return body
 }
 
 // Respond writes a JSON response to the Gin context.
@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// defaultHeartbeat is the time between the heartbeats of idle feeds when none is configured.
+const defaultHeartbeat = 15 * time.Second
+
+// writeTimeout bounds the time spent sending a message to a WebSocket client.
+const writeTimeout = 10 * time.Second
+
+// upgrader upgrades requests to WebSocket connections. Every origin is allowed, like in the CORS settings,
+// since callers are authenticated by their token rather than by cookies.
+var upgrader = websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+
+// EventController streams the changes of people over Server-Sent Events and WebSocket.
+type EventController struct {
+	BaseController
+
+	Events    ievents.IBroker // Source of the changes; the feeds are unavailable when nil
+	Heartbeat time.Duration   // Time between the heartbeats of idle feeds; 15 seconds when zero
+}
+
+// eventFilter selects the changes sent to a subscriber.
+type eventFilter struct {
+	types map[ievents.EventType]bool // Kinds of changes to send; every kind when empty
+	ids   map[uuid.UUID]bool         // People whose changes to send; everyone when empty
+	after uint64                     // ID of the last change the subscriber got, to resume after it
+}
+
+// Stream streams the changes of the people of the tenant that the caller may access as Server-Sent Events,
+// until the client disconnects. The types and ids query parameters take comma-separated lists of the kinds
+// of changes and of the people to stream. Clients resume after the change in the Last-Event-ID header, or
+// the last_event_id query parameter, as long as it is still held for replay.
+// Responds with a 200 status code and the stream, 400 if a filter is invalid, or 404 if changes aren't published.
+// The stream ends when the client falls too far behind; clients should then reconnect with Last-Event-ID.
+func (ec *EventController) Stream(c *gin.Context) {
+	filter, ok := ec.subscription(c)
+	if !ok {
+		return
+	}
+
+	events := ec.Events.Subscribe(c.Request.Context(), filter.after)
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(200)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(ec.heartbeat())
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if !ec.match(c.Request.Context(), filter, event) {
+				continue
+			}
+			data, err := json.Marshal(newEventDTO(c, event))
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+// WebSocket streams the same changes as Stream over a WebSocket connection, one JSON message per change,
+// with the same filters; clients resume after the change in the last_event_id query parameter.
+// Heartbeats are ping frames. The connection is closed with the 1013 (try again later) code when the
+// client falls too far behind.
+func (ec *EventController) WebSocket(c *gin.Context) {
+	filter, ok := ec.subscription(c)
+	if !ok {
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already responded with the error
+		return
+	}
+	defer conn.Close()
+
+	// Read the frames of the client, so that its close and pong frames are handled, until it disconnects.
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	conn.SetReadLimit(512)
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	events := ec.Events.Subscribe(ctx, filter.after)
+	heartbeat := time.NewTicker(ec.heartbeat())
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				if ctx.Err() == nil {
+					message := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "subscriber fell behind, reconnect with last_event_id")
+					conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(writeTimeout))
+				}
+				return
+			}
+			if !ec.match(ctx, filter, event) {
+				continue
+			}
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteJSON(newEventDTO(c, event)); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// subscription checks that the caller may read people and parses the filters of the request.
+// It responds with a 401, 403, 400 or 404 and returns false when the feed can't be streamed.
+func (ec *EventController) subscription(c *gin.Context) (eventFilter, bool) {
+	filter := eventFilter{types: make(map[ievents.EventType]bool), ids: make(map[uuid.UUID]bool)}
+	if !ec.Authorize(c, auth.PersonRead) {
+		return filter, false
+	}
+	if ec.Events == nil {
+		e := errapi.NewNotFound("changes of people are not published")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return filter, false
+	}
+
+	for _, t := range commaSeparated(c.Query("types")) {
+		eventType := ievents.EventType(t)
+		if eventType != ievents.PersonCreated && eventType != ievents.PersonUpdated && eventType != ievents.PersonDeleted {
+			e := errapi.NewBadRequest(fmt.Sprintf("unknown event type %q", t))
+			c.IndentedJSON(e.StatusCode(), errorBody(e))
+			return filter, false
+		}
+		filter.types[eventType] = true
+	}
+	for _, raw := range commaSeparated(c.Query("ids")) {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			e := errapi.NewBadRequest(fmt.Sprintf("invalid person id %q", raw))
+			c.IndentedJSON(e.StatusCode(), errorBody(e))
+			return filter, false
+		}
+		filter.ids[id] = true
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	if lastEventID != "" {
+		after, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			e := errapi.NewBadRequest("the last event id should be a non-negative integer")
+			c.IndentedJSON(e.StatusCode(), errorBody(e))
+			return filter, false
+		}
+		filter.after = after
+	}
+	return filter, true
+}
+
+// match reports whether the change passes the filter, and concerns a person of the tenant that the caller may access.
+func (ec *EventController) match(ctx context.Context, filter eventFilter, event ievents.PersonEvent) bool {
+	if event.Tenant != tenant.From(ctx) {
+		return false
+	}
+	if len(filter.types) > 0 && !filter.types[event.Type] {
+		return false
+	}
+	if len(filter.ids) > 0 && !filter.ids[event.Person.Id()] {
+		return false
+	}
+	return ec.Authorizer.CanAccess(ctx, event.Person.Owner())
+}
+
+// heartbeat returns the time between the heartbeats of idle feeds.
+func (ec *EventController) heartbeat() time.Duration {
+	if ec.Heartbeat <= 0 {
+		return defaultHeartbeat
+	}
+	return ec.Heartbeat
+}
+
+// commaSeparated splits a comma-separated query parameter, skipping blank items.
+func commaSeparated(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
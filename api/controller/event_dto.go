@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"time"
+
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
+	"github.com/gin-gonic/gin"
+)
+
+// EventDTO defines the data structure of a change of a person sent over the change feeds.
+type EventDTO struct {
+	ID         uint64    `json:"id"`          // Sequence number of the change, to resume the feed after it
+	Type       string    `json:"type"`        // Kind of change: person.created, person.updated or person.deleted
+	Person     any       `json:"person"`      // The person after the change, or before their deletion, in the version of the request
+	OccurredAt time.Time `json:"occurred_at"` // Time the change was saved
+}
+
+// newEventDTO maps a PersonEvent to the EventDTO of the version of the request.
+func newEventDTO(c *gin.Context, event ievents.PersonEvent) EventDTO {
+	return EventDTO{
+		ID:         event.ID,
+		Type:       string(event.Type),
+		Person:     personDTO(c, event.Person),
+		OccurredAt: event.OccurredAt,
+	}
+}
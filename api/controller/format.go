@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Format is a representation of request and response bodies, negotiated with the Accept and Content-Type headers.
+type Format string
+
+const (
+	JSON    Format = "application/json"    // The default format
+	XML     Format = "application/xml"     // For legacy XML systems
+	YAML    Format = "application/yaml"    // For humans editing bodies by hand
+	MsgPack Format = "application/msgpack" // A compact binary equivalent of JSON
+	CSV     Format = "text/csv"            // A header row followed by a row per person, for spreadsheets
+)
+
+// NDJSON is the format of uploads and downloads streamed a person per line, which responses aren't negotiated for.
+const NDJSON Format = "application/x-ndjson"
+
+// Formats lists every supported Format, in the order they are preferred when a wildcard is accepted.
+var Formats = []Format{JSON, XML, YAML, MsgPack, CSV}
+
+// ErrNotAcceptable is the error responded with when a request accepts none of the Formats.
+var ErrNotAcceptable = errapi.NewNotAcceptable("Accept should include one of " + supportedFormats())
+
+// errUnsupported is the error responded with when a request body is in none of the Formats.
+var errUnsupported = errapi.NewUnsupported("Content-Type should be one of " + supportedFormats())
+
+// FormatKey is the key under which the Format of the responses is stored in the Gin context.
+const FormatKey = "api_format"
+
+// mediaTypes maps the media types of every Format, including their unregistered aliases.
+var mediaTypes = map[string]Format{
+	"application/json":        JSON,
+	"application/xml":         XML,
+	"text/xml":                XML,
+	"application/yaml":        YAML,
+	"application/x-yaml":      YAML,
+	"text/yaml":               YAML,
+	"application/msgpack":     MsgPack,
+	"application/x-msgpack":   MsgPack,
+	"application/vnd.msgpack": MsgPack,
+	"text/csv":                CSV,
+}
+
+// suffixes maps the structured syntax suffixes of vendor media types, e.g. application/vnd.gocrud.v2+xml.
+var suffixes = map[string]Format{"json": JSON, "xml": XML, "yaml": YAML, "msgpack": MsgPack}
+
+// NegotiateFormat returns the Format of the responses to a request with the Accept header, preferring
+// the media types with the highest quality. It returns false when none of the accepted media types is supported.
+// A missing header accepts JSON.
+func NegotiateFormat(accept string) (Format, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return JSON, true
+	}
+
+	return preferred(accept, func(mediaType string) (Format, bool) {
+		switch mediaType {
+		case "*/*", "application/*":
+			return JSON, true
+		case "text/*":
+			return CSV, true
+		}
+		return formatOfMediaType(mediaType)
+	})
+}
+
+// preferred returns the Format of the media type with the highest quality in the Accept header, among the ones
+// formatOf knows, keeping the order of the header between equal qualities. It returns false when it knows none.
+func preferred(accept string, formatOf func(mediaType string) (Format, bool)) (Format, bool) {
+	type candidate struct {
+		format  Format
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if raw, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(raw, 64); err != nil {
+				continue
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		if format, ok := formatOf(mediaType); ok {
+			candidates = append(candidates, candidate{format, quality})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+	return candidates[0].format, true
+}
+
+// ContentFormat returns the Format of a request body with the Content-Type header, JSON when it is missing.
+// It returns false when the media type is not supported.
+func ContentFormat(contentType string) (Format, bool) {
+	if strings.TrimSpace(contentType) == "" {
+		return JSON, true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+	return formatOfMediaType(mediaType)
+}
+
+// formatOfMediaType returns the Format of a media type, including vendor media types with a known suffix.
+func formatOfMediaType(mediaType string) (Format, bool) {
+	if format, ok := mediaTypes[mediaType]; ok {
+		return format, true
+	}
+	if _, suffix, ok := strings.Cut(mediaType, "+"); ok && strings.HasPrefix(mediaType, "application/vnd.") {
+		format, ok := suffixes[suffix]
+		return format, ok
+	}
+	return "", false
+}
+
+// supportedFormats lists the media types of every Format, for error messages.
+func supportedFormats() string {
+	supported := make([]string, 0, len(Formats))
+	for _, format := range Formats {
+		supported = append(supported, string(format))
+	}
+	return strings.Join(supported, ", ")
+}
+
+// formatOf returns the Format of the responses to the request, JSON unless another one was negotiated.
+func formatOf(c *gin.Context) Format {
+	if f, ok := c.Get(FormatKey); ok {
+		if format, ok := f.(Format); ok {
+			return format
+		}
+	}
+	return JSON
+}
+
+// render writes a response in the Format negotiated for the request.
+func render(c *gin.Context, status int, data any) {
+	format := formatOf(c)
+	if format == JSON {
+		c.IndentedJSON(status, data)
+		return
+	}
+
+	body, err := encode(format, data)
+	if err != nil {
+		c.IndentedJSON(500, gin.H{"error": "failed to encode the response as " + string(format)})
+		return
+	}
+
+	contentType := string(format)
+	if format != MsgPack {
+		contentType += "; charset=utf-8"
+	}
+	c.Data(status, contentType, body)
+}
+
+// bind decodes the request body into obj, in the Format of its Content-Type, and validates it.
+// It returns an errapi.Error with a 415 status code when the Content-Type isn't supported.
+func bind(c *gin.Context, obj any) error {
+	format, ok := ContentFormat(c.ContentType())
+	if !ok {
+		return errUnsupported
+	}
+	if format == JSON {
+		return c.ShouldBindJSON(obj)
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+	raw, err := decode(format, body, obj)
+	if err != nil {
+		return err
+	}
+	return binding.JSON.BindBody(raw, obj)
+}
+
+// bindError maps an error of bind to the errapi.Error to respond with, a 400 with the message unless it is one already.
+func bindError(err error, message string) errapi.Error {
+	if e, ok := err.(errapi.Error); ok {
+		return e
+	}
+	return errapi.NewBadRequest(message)
+}
@@ -0,0 +1,624 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+	"gopkg.in/yaml.v3"
+)
+
+// Bodies in other formats than JSON are converted from and to JSON, so that every format has the fields,
+// names and omissions of the JSON DTOs. Responses are decoded from their JSON into a tree of objects,
+// arrays and scalars keeping the order of the fields, and the tree is written in the negotiated format.
+// Request bodies are decoded into a tree that is converted to the JSON of the DTO they are bound to.
+
+// member is a field of an object of a tree.
+type member struct {
+	key   string
+	value any
+}
+
+// object is an object of a tree, whose fields keep the order of the JSON they were decoded from.
+type object []member
+
+// listSeparator joins the items of lists of scalars in CSV cells, e.g. "chess;hiking".
+const listSeparator = ";"
+
+// singulars names the XML elements of the items of lists, after the element of the list; others are named item.
+var singulars = map[string]string{
+	"people":  "person",
+	"data":    "person",
+	"hobbies": "hobby",
+	"groups":  "group",
+	"rows":    "row",
+}
+
+// msgpackHandle configures the MessagePack codec to decode maps with string keys, as JSON would.
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{}
+	h.RawToString = true
+	h.MapType = reflect.TypeOf(map[string]any(nil))
+	return h
+}()
+
+// encode writes the response data in the format.
+func encode(format Format, data any) ([]byte, error) {
+	if format == CSV {
+		return encodeCSV(data)
+	}
+
+	tree, err := toTree(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case XML:
+		return encodeXML(rootElement(data), tree)
+	case YAML:
+		return yaml.Marshal(yamlNode(tree))
+	case MsgPack:
+		var body []byte
+		err := codec.NewEncoderBytes(&body, msgpackHandle).Encode(plain(tree))
+		return body, err
+	default:
+		return json.Marshal(data)
+	}
+}
+
+// decode reads a request body in the format, and returns it as the JSON of obj.
+func decode(format Format, body []byte, obj any) ([]byte, error) {
+	var tree any
+	var err error
+	switch format {
+	case XML:
+		tree, err = decodeXML(body)
+	case YAML:
+		err = yaml.Unmarshal(body, &tree)
+	case MsgPack:
+		err = codec.NewDecoderBytes(body, msgpackHandle).Decode(&tree)
+	case CSV:
+		tree, err = decodeCSV(body)
+	default:
+		return body, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(coerce(tree, reflect.TypeOf(obj)))
+}
+
+// toTree converts data to a tree through its JSON.
+func toTree(data any) (any, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	return readTree(decoder)
+}
+
+// readTree reads the next value of the JSON decoder as a tree.
+func readTree(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		obj := object{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := readTree(decoder)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, member{key: key.(string), value: value})
+		}
+		_, err := decoder.Token()
+		return obj, err
+	case json.Delim('['):
+		list := []any{}
+		for decoder.More() {
+			value, err := readTree(decoder)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err := decoder.Token()
+		return list, err
+	}
+	return token, nil
+}
+
+// plain converts a tree to maps, slices and scalars, for the encoders that don't keep the order of fields.
+func plain(tree any) any {
+	switch v := tree.(type) {
+	case object:
+		m := make(map[string]any, len(v))
+		for _, field := range v {
+			m[field.key] = plain(field.value)
+		}
+		return m
+	case []any:
+		list := make([]any, 0, len(v))
+		for _, item := range v {
+			list = append(list, plain(item))
+		}
+		return list
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return tree
+}
+
+// rootElement names the root XML element of the response data.
+func rootElement(data any) string {
+	switch data.(type) {
+	case ResponseDTO, PersonV2DTO:
+		return "person"
+	case []any:
+		return "people"
+	case []DuplicateGroupDTO:
+		return "groups"
+	case MergeResponseDTO:
+		return "merge"
+	case ImportResponseDTO:
+		return "import"
+	case gin.H:
+		return "error"
+	}
+	return "response"
+}
+
+// encodeXML writes a tree as an XML document with the root element.
+func encodeXML(root string, tree any) ([]byte, error) {
+	var body bytes.Buffer
+	body.WriteString(xml.Header)
+
+	encoder := xml.NewEncoder(&body)
+	encoder.Indent("", "  ")
+	if err := writeXML(encoder, root, tree); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	body.WriteString("\n")
+	return body.Bytes(), nil
+}
+
+// writeXML writes a value of a tree as an element. Fields whose names aren't valid element names,
+// such as the IDs keying redirects, are written as entry elements with a key attribute.
+func writeXML(encoder *xml.Encoder, name string, value any) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if !validElement(name) {
+		start = xml.StartElement{
+			Name: xml.Name{Local: "entry"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: name}},
+		}
+	}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case object:
+		for _, field := range v {
+			if err := writeXML(encoder, field.key, field.value); err != nil {
+				return err
+			}
+		}
+	case []any:
+		item, ok := singulars[name]
+		if !ok {
+			item = "item"
+		}
+		for _, value := range v {
+			if err := writeXML(encoder, item, value); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		if err := encoder.EncodeToken(xml.CharData(fmt.Sprint(v))); err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
+
+// validElement reports whether a name can be used as the name of an XML element.
+func validElement(name string) bool {
+	for i, r := range name {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || (!unicode.IsDigit(r) && r != '-' && r != '.')) {
+			return false
+		}
+	}
+	return name != "" && !strings.HasPrefix(strings.ToLower(name), "xml")
+}
+
+// decodeXML reads an XML document as a tree, ignoring the name of its root element. Elements with children
+// become objects, repeated children become lists, and the others become their text.
+func decodeXML(body []byte) (any, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := token.(xml.StartElement); ok {
+			return readXML(decoder)
+		}
+	}
+}
+
+// readXML reads the content of the element that was just started as a tree.
+func readXML(decoder *xml.Decoder) (any, error) {
+	var text strings.Builder
+	children := map[string]any{}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			child, err := readXML(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			name := t.Name.Local
+			if key := xmlAttribute(t, "key"); name == "entry" && key != "" {
+				name = key
+			}
+			switch existing := children[name].(type) {
+			case nil:
+				children[name] = child
+			case []any:
+				children[name] = append(existing, child)
+			default:
+				children[name] = []any{existing, child}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if len(children) > 0 {
+				return children, nil
+			}
+			return strings.TrimSpace(text.String()), nil
+		}
+	}
+}
+
+// xmlAttribute returns the value of the attribute of an element, or an empty string.
+func xmlAttribute(start xml.StartElement, name string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// yamlNode converts a tree to a YAML node, keeping the order of the fields.
+func yamlNode(tree any) *yaml.Node {
+	switch v := tree.(type) {
+	case object:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, field := range v {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: field.key}, yamlNode(field.value))
+		}
+		return node
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range v {
+			node.Content = append(node.Content, yamlNode(item))
+		}
+		return node
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: v.String()}
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: v.String()}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fmt.Sprint(tree)}
+}
+
+// encodeCSV writes the response data as a header row followed by a row per person, or a single row for
+// other data. Nested objects are flattened into columns named after their path, e.g. address.city,
+// lists of scalars are joined with semicolons, and v2 envelopes are reduced to their data.
+func encodeCSV(data any) ([]byte, error) {
+	if envelope, ok := data.(EnvelopeDTO); ok {
+		data = envelope.Data
+	}
+
+	tree, err := toTree(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []object
+	switch v := tree.(type) {
+	case []any:
+		for i, item := range v {
+			// Duplicate groups become a row per person, numbered after their group
+			if group, ok := item.(object); ok && len(group) == 1 && group[0].key == "people" {
+				people, _ := group[0].value.([]any)
+				for _, person := range people {
+					row := object{{key: "group", value: i + 1}}
+					rows = append(rows, append(row, flatten("", person)...))
+				}
+				continue
+			}
+			rows = append(rows, flatten("", item))
+		}
+	default:
+		rows = append(rows, flatten("", tree))
+	}
+
+	// Rows may lack some columns, such as unset addresses or attributes
+	var columns []string
+	seen := map[string]bool{}
+	for _, row := range rows {
+		for _, field := range row {
+			if !seen[field.key] {
+				seen[field.key] = true
+				columns = append(columns, field.key)
+			}
+		}
+	}
+
+	var body bytes.Buffer
+	writer := csv.NewWriter(&body)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		cells := make(map[string]string, len(row))
+		for _, field := range row {
+			cells[field.key] = fmt.Sprint(field.value)
+		}
+		record := make([]string, 0, len(columns))
+		for _, column := range columns {
+			record = append(record, cells[column])
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return body.Bytes(), writer.Error()
+}
+
+// flatten converts a value of a tree to the cells of a CSV row, named after the path of the value.
+// Lists of objects, which don't fit in columns, are written as JSON.
+func flatten(path string, value any) object {
+	if v, ok := value.(object); ok {
+		var row object
+		for _, field := range v {
+			key := field.key
+			if path != "" {
+				key = path + "." + key
+			}
+			row = append(row, flatten(key, field.value)...)
+		}
+		return row
+	}
+
+	if path == "" {
+		path = "value"
+	}
+	switch v := value.(type) {
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case object, []any:
+				raw, _ := json.Marshal(plain(v))
+				return object{{key: path, value: string(raw)}}
+			}
+			items = append(items, fmt.Sprint(item))
+		}
+		return object{{key: path, value: strings.Join(items, listSeparator)}}
+	case nil:
+		return object{{key: path, value: ""}}
+	}
+	return object{{key: path, value: value}}
+}
+
+// decodeCSV reads a CSV body holding a header row and a single row as a tree.
+func decodeCSV(body []byte) (any, error) {
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) != 2 {
+		return nil, errors.New("CSV body should hold a header row and a single row")
+	}
+
+	return csvRecord(records[0], records[1]), nil
+}
+
+// csvRecord converts a CSV row to a tree, in which the columns of the header named after a path,
+// e.g. address.city, become fields of nested objects.
+func csvRecord(header []string, record []string) map[string]any {
+	tree := map[string]any{}
+	for i, column := range header {
+		if i >= len(record) {
+			break
+		}
+
+		parent := tree
+		keys := strings.Split(column, ".")
+		for _, key := range keys[:len(keys)-1] {
+			child, ok := parent[key].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				parent[key] = child
+			}
+			parent = child
+		}
+		parent[keys[len(keys)-1]] = record[i]
+	}
+	return tree
+}
+
+// coerce converts a decoded tree to the JSON types of the fields of t. XML and CSV carry every value
+// as text, so numbers are parsed, lists are split or unwrapped from their element, and empty objects are dropped.
+func coerce(value any, t reflect.Type) any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		if empty(value) {
+			return nil
+		}
+		return coerce(value, t.Elem())
+	case reflect.Struct:
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		coerced := make(map[string]any, len(fields))
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if field, ok := fields[name]; ok {
+				coerced[name] = coerce(field, t.Field(i).Type)
+			}
+		}
+		return coerced
+	case reflect.Slice:
+		items := list(value)
+		if items == nil {
+			return nil
+		}
+		coerced := make([]any, 0, len(items))
+		for _, item := range items {
+			coerced = append(coerced, coerce(item, t.Elem()))
+		}
+		return coerced
+	case reflect.Map:
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		coerced := make(map[string]any, len(fields))
+		for key, field := range fields {
+			// Empty cells, such as the attributes a person of a CSV export lacks, leave the attribute unset
+			if field == "" {
+				continue
+			}
+			coerced[key] = literal(field)
+		}
+		return coerced
+	case reflect.String:
+		if value == nil {
+			return nil
+		}
+		return fmt.Sprint(value)
+	case reflect.Array:
+		// IDs are empty rather than missing in CSV rows
+		if value == "" {
+			return nil
+		}
+		return value
+	}
+
+	if s, ok := value.(string); ok {
+		if s = strings.TrimSpace(s); s == "" {
+			return nil
+		}
+		if json.Valid([]byte(s)) {
+			return json.RawMessage(s)
+		}
+	}
+	return value
+}
+
+// list returns the items of a decoded list, which XML wraps in an element per item and CSV joins with semicolons.
+func list(value any) []any {
+	switch v := value.(type) {
+	case []any:
+		return v
+	case map[string]any:
+		if len(v) == 1 {
+			for _, items := range v {
+				return list(items)
+			}
+		}
+		return []any{v}
+	case string:
+		items := []any{}
+		for _, item := range strings.Split(v, listSeparator) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	case nil:
+		return nil
+	}
+	return []any{value}
+}
+
+// literal converts a decoded custom attribute to a number or a boolean when its text is one.
+func literal(value any) any {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+	var parsed any
+	if err := json.Unmarshal([]byte(s), &parsed); err == nil {
+		switch parsed.(type) {
+		case float64, bool:
+			return parsed
+		}
+	}
+	return s
+}
+
+// empty reports whether a decoded value holds nothing but empty text, e.g. the unset address of a CSV row.
+func empty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case map[string]any:
+		for _, field := range v {
+			if !empty(field) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// GraphQLController serves the GraphQL schema of people, whose resolvers delegate to the same
+// command and query handlers as the PersonController.
+type GraphQLController struct {
+	Schema *graphql.Schema
+}
+
+// GraphQLRequestDTO represents the body of a GraphQL request.
+type GraphQLRequestDTO struct {
+	Query         string         `json:"query" binding:"required"` // Document holding the operation to execute; required
+	OperationName string         `json:"operationName"`            // Operation of the document to execute; optional when it holds one
+	Variables     map[string]any `json:"variables"`                // Values of the variables of the operation; optional
+}
+
+// Serve executes the operation of a GraphQL request in the context of the caller.
+// Responds with a 200 status code and the result, whose errors carry their code and HTTP status in
+// their extensions, or with a 400 if the body isn't a GraphQL request.
+func (gc *GraphQLController) Serve(c *gin.Context) {
+	var dto GraphQLRequestDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		e := errapi.NewBadRequest("Invalid GraphQL request")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	response := gc.Schema.Exec(c.Request.Context(), dto.Query, dto.OperationName, dto.Variables)
+	c.IndentedJSON(200, response)
+}
@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	jobcmd "github.com/Efamamo/GoCrudChallange/application/jobs/command"
+	jobquery "github.com/Efamamo/GoCrudChallange/application/jobs/query"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// JobController defines handlers for submitting background jobs, following their progress and cancelling them.
+type JobController struct {
+	BaseController
+
+	SubmitHandler icmd.IHandler[*jobcmd.SubmitJobCommand, *job.Job]
+	CancelHandler icmd.IHandler[*jobcmd.CancelJobCommand, *job.Job]
+	GetHandler    iquery.IHandler[*jobquery.GetJobQuery, *job.Job]
+	GetAllHandler iquery.IHandler[*jobquery.GetJobsQuery, []*job.Job]
+
+	MaxBodySize int64 // Size in bytes of the largest body submitting a job; 64 KiB when zero
+}
+
+// defaultJobMaxBodySize is the size of the largest body submitting a job, whose parameters never carry uploads.
+const defaultJobMaxBodySize = 64 << 10
+
+// Submit handles queuing a job, run on behalf of the caller.
+// Responds with a 202 status code, the queued job and its Location, 400 if the kind or the parameters are invalid,
+// or 413 if the body is too large.
+func (jc *JobController) Submit(c *gin.Context) {
+	if !jc.Authorize(c, auth.PersonWrite) {
+		return
+	}
+
+	maxSize := jc.MaxBodySize
+	if maxSize <= 0 {
+		maxSize = defaultJobMaxBodySize
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize)
+
+	var dto JobDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		var tooLarge *http.MaxBytesError
+		e := errapi.NewBadRequest("Invalid input data format")
+		if errors.As(err, &tooLarge) {
+			e = errapi.NewTooLarge(fmt.Sprintf("body should be at most %d bytes", maxSize))
+		}
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	params := []byte(dto.Params)
+	if bytes.Equal(bytes.TrimSpace(params), []byte("null")) {
+		params = nil
+	}
+
+	j, err := jc.SubmitHandler.Handle(&jobcmd.SubmitJobCommand{
+		Context: c.Request.Context(),
+		Kind:    dto.Kind,
+		Params:  params,
+	})
+	if err != nil {
+		e := errapi.Map(err)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/jobs/%s", j.Id()))
+	c.IndentedJSON(202, newJobResponseDTO(j))
+}
+
+// GetAll retrieves the jobs of the caller, oldest first, optionally only the jobs in the state of the status
+// query parameter: queued, running, succeeded, failed or cancelled.
+// Responds with a 200 status code and the jobs, or 400 if the status is unknown.
+func (jc *JobController) GetAll(c *gin.Context) {
+	if !jc.Authorize(c, auth.PersonRead) {
+		return
+	}
+
+	status := job.Status(c.Query("status"))
+	if status != "" && !slices.Contains(job.Statuses, status) {
+		e := errapi.NewBadRequest(fmt.Sprintf("unknown job status %q", status))
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	jobs, err := jc.GetAllHandler.Handle(&jobquery.GetJobsQuery{Context: c.Request.Context(), Status: status})
+	if err != nil {
+		jc.respondQueryError(c, err)
+		return
+	}
+
+	var responses = make([]JobResponseDTO, 0, len(jobs))
+	for _, j := range jobs {
+		responses = append(responses, newJobResponseDTO(j))
+	}
+
+	c.IndentedJSON(200, responses)
+}
+
+// Get retrieves a job by its ID, to follow its progress.
+// Responds with a 200 status code and the job, or 404 if it was not found.
+func (jc *JobController) Get(c *gin.Context) {
+	if !jc.Authorize(c, auth.PersonRead) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	j, qerr := jc.GetHandler.Handle(&jobquery.GetJobQuery{Context: c.Request.Context(), ID: id})
+	if qerr != nil {
+		jc.respondQueryError(c, qerr)
+		return
+	}
+
+	c.IndentedJSON(200, newJobResponseDTO(j))
+}
+
+// Cancel handles cancelling a job by its ID. A queued job is cancelled right away, while a running job
+// is returned with its cancellation requested, and is cancelled once its task stops.
+// Responds with a 200 status code and the job, 404 if it was not found, or 409 if it already finished.
+func (jc *JobController) Cancel(c *gin.Context) {
+	if !jc.Authorize(c, auth.PersonWrite) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	j, cerr := jc.CancelHandler.Handle(&jobcmd.CancelJobCommand{Context: c.Request.Context(), ID: id})
+	if cerr != nil {
+		e := errapi.Map(cerr)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(200, newJobResponseDTO(j))
+}
+
+// respondQueryError writes the error returned by a query handler.
+func (jc *JobController) respondQueryError(c *gin.Context, err error) {
+	if customErr, ok := err.(ierr.IErr); ok {
+		e := errapi.Map(customErr)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+	c.IndentedJSON(500, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
+	"github.com/google/uuid"
+)
+
+// JobDTO represents the data structure for submitting a job.
+type JobDTO struct {
+	Kind   string          `json:"kind" binding:"required"` // Kind of the job, e.g. people.purge; required
+	Params json.RawMessage `json:"params"`                  // Parameters of the job, checked by the task of its kind
+}
+
+// JobResponseDTO defines the data structure for returning jobs in responses.
+type JobResponseDTO struct {
+	ID              uuid.UUID       `json:"id"`                         // Unique identifier of the job
+	Kind            string          `json:"kind"`                       // Kind of the job
+	Status          string          `json:"status"`                     // queued, running, succeeded, failed or cancelled
+	Progress        JobProgressDTO  `json:"progress"`                   // How far the job got
+	Params          json.RawMessage `json:"params,omitempty"`           // Parameters the job was submitted with
+	Result          json.RawMessage `json:"result,omitempty"`           // Result of a succeeded job
+	Reason          string          `json:"reason,omitempty"`           // Why a failed job stopped
+	CancelRequested bool            `json:"cancel_requested,omitempty"` // Whether a running job was asked to stop
+	Owner           string          `json:"owner,omitempty"`            // Subject of the principal who submitted the job
+	CreatedAt       time.Time       `json:"created_at"`                 // Time at which the job was submitted
+	StartedAt       *time.Time      `json:"started_at,omitempty"`       // Time at which a worker picked the job
+	FinishedAt      *time.Time      `json:"finished_at,omitempty"`      // Time at which the job finished
+}
+
+// JobProgressDTO defines the data structure for the progress of a job.
+type JobProgressDTO struct {
+	Done    int    `json:"done"`              // Number of items processed so far
+	Total   int    `json:"total"`             // Number of items to process; zero when unknown
+	Message string `json:"message,omitempty"` // What the job is doing
+}
+
+// newJobResponseDTO maps a Job to its JobResponseDTO.
+func newJobResponseDTO(j *job.Job) JobResponseDTO {
+	progress := j.Progress()
+	return JobResponseDTO{
+		ID:              j.Id(),
+		Kind:            j.Kind(),
+		Status:          string(j.Status()),
+		Progress:        JobProgressDTO{Done: progress.Done, Total: progress.Total, Message: progress.Message},
+		Params:          j.Params(),
+		Result:          j.Result(),
+		Reason:          j.Reason(),
+		CancelRequested: j.CancelRequested(),
+		Owner:           j.Owner(),
+		CreatedAt:       j.CreatedAt(),
+		StartedAt:       j.StartedAt(),
+		FinishedAt:      j.FinishedAt(),
+	}
+}
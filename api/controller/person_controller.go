@@ -1,11 +1,16 @@
 package controller
 
 import (
+	"strconv"
+
 	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
 	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
 	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
 	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/Efamamo/GoCrudChallange/application/people/query"
 	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
 	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -13,124 +18,190 @@ import (
 
 // PersonController defines handlers for managing CRUD operations on Person entities.
 type PersonController struct {
+	BaseController
+
 	CreateHandler icmd.IHandler[*command.CreatePersonCommand, *model.Person]
 	UpdateHandler icmd.IHandler[*command.UpdatePersonCommand, *model.Person]
-	DeleteHandler icmd.IHandler[uuid.UUID, bool]
-	GetHandler    iquery.IHandler[uuid.UUID, *model.Person]
-	GetAllHandler iquery.IHandler[struct{}, []*model.Person]
+	DeleteHandler icmd.IHandler[*command.DeletePersonCommand, bool]
+	GetHandler    iquery.IHandler[*query.GetPersonQuery, *model.Person]
+	GetAllHandler iquery.IHandler[*query.GetPeopleQuery, []*model.Person]
+
+	DuplicatesHandler iquery.IHandler[*query.GetDuplicatesQuery, [][]*model.Person]
+	MergeHandler      icmd.IHandler[*command.MergePeopleCommand, *command.MergePeopleResult]
+	RedirectHandler   iquery.IHandler[*query.GetRedirectQuery, uuid.UUID]
+	MergesHandler     iquery.IHandler[*query.GetMergesQuery, []*model.Merge]
+	TransferHandler   icmd.IHandler[*command.TransferOwnershipCommand, *model.Person]
+
+	ImportHandler      icmd.IHandler[*command.ImportPeopleCommand, *model.Import]
+	StartImportHandler icmd.IHandler[*command.StartImportCommand, *job.Job] // Starts the jobs of the imports run in the background
+	ImportSyncLimit    int64                                                // Size in bytes of the largest upload imported before responding; 1 MiB when zero
+	ImportMaxSize      int64                                                // Size in bytes of the largest upload accepted; 32 MiB when zero
+
+	ExportHandler iquery.IHandler[*query.ExportPeopleQuery, int]
+
+	Upsert bool // Whether updating a person that doesn't exist creates it with the ID of the URL
 }
 
 // Create handles the creation of a new Person.
-// It parses JSON data from the request body, validates it, and calls the CreateHandler to add a new Person.
-// Responds with a 201 status code if successful, or 400 if the input is invalid.
+// It parses the request body in the format of its Content-Type, validates it, and calls the CreateHandler to add a new Person.
+// The person gets the optional id of the body, or a new one.
+// Responds with a 201 status code if successful, 400 if the input is invalid, or 409 if the id is taken.
 func (pc *PersonController) Create(c *gin.Context) {
+	if !pc.Authorize(c, auth.PersonWrite) {
+		return
+	}
+
 	var dto CreateDTO
-	err := c.ShouldBindJSON(&dto)
+	err := bind(c, &dto)
 	if err != nil {
-		e := errapi.NewBadRequest("Invalid input data format")
-		c.IndentedJSON(e.StatusCode(), gin.H{"error": e.Error()})
+		e := bindError(err, "Invalid input data format")
+		render(c, e.StatusCode(), errorBody(e))
 		return
 	}
 
 	command := &command.CreatePersonCommand{
+		Context: c.Request.Context(),
+
+		ID:      dto.ID,
 		Name:    dto.Name,
 		Age:     dto.Age,
 		Hobbies: dto.Hobbies,
+		Email:   dto.Email,
+		Phone:   dto.Phone,
+		Address: dto.Address.toAddressConfig(),
+
+		Attributes: dto.Attributes,
 	}
 
 	// Calls CreateHandler to process the creation command
-	p, err := pc.CreateHandler.Handle(command)
+	p, cerr := pc.CreateHandler.Handle(command)
 
-	if err != nil {
-		c.IndentedJSON(400, gin.H{"error": err.Error()})
+	if cerr != nil {
+		// Map domain errors (e.g. validation or conflicts) to their HTTP counterparts
+		e := errapi.Map(cerr)
+		render(c, e.StatusCode(), errorBody(e))
 		return
 	}
 
-	// Prepares the response DTO
-	response := ResponseDTO{
-		ID:      p.Id(),
-		Name:    p.Name(),
-		Age:     p.Age(),
-		Hobbies: p.Hobbies(),
-	}
-
-	c.IndentedJSON(201, response)
+	respond(c, 201, personDTO(c, p))
 }
 
 // Update handles updating an existing Person's details.
-// It retrieves the ID from the URL, binds JSON data from the request, and calls UpdateHandler to apply changes.
-// Returns a 201 status code if successful or relevant errors for invalid input or update issues.
+// It retrieves the ID from the URL, binds the request body in the format of its Content-Type, and calls UpdateHandler to apply changes.
+// When Upsert is enabled, a person that doesn't exist is created with the ID of the URL instead.
+// Returns a 200 status code if updated, 201 if created, or relevant errors for invalid input or update issues.
 func (pc *PersonController) Update(c *gin.Context) {
+	if !pc.Authorize(c, auth.PersonWrite) {
+		return
+	}
+
 	var dto CreateDTO
 
 	// Parse and validate UUID from the URL
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		e := errapi.NewBadRequest("Invalid id format")
-		c.IndentedJSON(e.StatusCode(), gin.H{"error": e.Error()})
+		render(c, e.StatusCode(), errorBody(e))
 		return
 	}
 
-	// Bind JSON data to the DTO
-	if err := c.ShouldBindJSON(&dto); err != nil {
-		e := errapi.NewBadRequest(err.Error())
-		c.IndentedJSON(e.StatusCode(), gin.H{"error": e.Error()})
+	// Bind the body to the DTO, in the format of its Content-Type
+	if err := bind(c, &dto); err != nil {
+		e := bindError(err, err.Error())
+		render(c, e.StatusCode(), errorBody(e))
+		return
+	}
+	if dto.ID != uuid.Nil && dto.ID != id {
+		e := errapi.NewBadRequest("id of the body doesn't match the id of the URL")
+		render(c, e.StatusCode(), errorBody(e))
 		return
 	}
 
 	command := &command.UpdatePersonCommand{
+		Context: c.Request.Context(),
+
 		ID:      id,
 		Name:    dto.Name,
 		Age:     dto.Age,
 		Hobbies: dto.Hobbies,
+		Email:   dto.Email,
+		Phone:   dto.Phone,
+		Address: dto.Address.toAddressConfig(),
+
+		Attributes: dto.Attributes,
 	}
 
 	// Call UpdateHandler to process the update command
 	person, err := pc.UpdateHandler.Handle(command)
+	if customErr, ok := err.(ierr.IErr); ok && pc.Upsert && customErr.Type() == ierr.NotFound {
+		pc.create(c, dto, id)
+		return
+	}
 	if err != nil {
 		// Handle custom errors defined by ierr.IErr interface
 		if customErr, ok := err.(ierr.IErr); ok {
 			e := errapi.Map(customErr)
-			c.IndentedJSON(e.StatusCode(), gin.H{"error": e.Error()})
+			render(c, e.StatusCode(), errorBody(e))
 			return
 		} else {
-			c.IndentedJSON(400, gin.H{"error": err.Error()})
+			render(c, 400, gin.H{"error": err.Error()})
 			return
 		}
 	}
 
-	// Prepare response DTO
-	response := ResponseDTO{
-		ID:      person.Id(),
-		Name:    person.Name(),
-		Age:     person.Age(),
-		Hobbies: person.Hobbies(),
+	respond(c, 200, personDTO(c, person))
+}
+
+// create creates the person of an upsert with the ID of the URL, and responds with a 201 status code.
+// A person the caller can't access already holding the ID gets a 409.
+func (pc *PersonController) create(c *gin.Context, dto CreateDTO, id uuid.UUID) {
+	person, err := pc.CreateHandler.Handle(&command.CreatePersonCommand{
+		Context: c.Request.Context(),
+
+		ID:      id,
+		Name:    dto.Name,
+		Age:     dto.Age,
+		Hobbies: dto.Hobbies,
+		Email:   dto.Email,
+		Phone:   dto.Phone,
+		Address: dto.Address.toAddressConfig(),
+
+		Attributes: dto.Attributes,
+	})
+	if err != nil {
+		e := errapi.Map(err)
+		render(c, e.StatusCode(), errorBody(e))
+		return
 	}
 
-	c.IndentedJSON(201, response)
+	respond(c, 201, personDTO(c, person))
 }
 
 // Delete handles the deletion of a Person by ID.
 // It validates the ID, then calls DeleteHandler to remove the specified Person.
 // Responds with a 204 status code if successful, or 404 if the Person was not found.
 func (pc *PersonController) Delete(c *gin.Context) {
+	if !pc.Authorize(c, auth.PersonDelete) {
+		return
+	}
+
 	id, err := uuid.Parse(c.Param("id"))
 
 	if err != nil {
 		e := errapi.NewBadRequest("Invalid id format")
-		c.IndentedJSON(e.StatusCode(), gin.H{"error": e.Error()})
+		render(c, e.StatusCode(), errorBody(e))
 		return
 	}
 
-	_, err = pc.DeleteHandler.Handle(id)
+	_, err = pc.DeleteHandler.Handle(&command.DeletePersonCommand{Context: c.Request.Context(), ID: id})
 	if err != nil {
 		// Handle custom error types using ierr.IErr interface mapping
 		if customErr, ok := err.(ierr.IErr); ok {
 			e := errapi.Map(customErr)
-			c.IndentedJSON(e.StatusCode(), gin.H{"error": e.Error()})
+			render(c, e.StatusCode(), errorBody(e))
 			return
 		} else {
-			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			render(c, 404, gin.H{"error": err.Error()})
 			return
 		}
 	}
@@ -141,54 +212,214 @@ func (pc *PersonController) Delete(c *gin.Context) {
 // Get retrieves a Person by their ID.
 // It parses the ID from the URL, calls GetHandler to fetch the Person, and returns a 200 status code with Person data if found.
 func (pc *PersonController) Get(c *gin.Context) {
+	if !pc.Authorize(c, auth.PersonRead) {
+		return
+	}
+
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		e := errapi.NewBadRequest("Invalid id format")
-		c.IndentedJSON(e.StatusCode(), gin.H{"error": e.Error()})
+		render(c, e.StatusCode(), errorBody(e))
 		return
 	}
 
-	person, err := pc.GetHandler.Handle(id)
+	person, err := pc.GetHandler.Handle(&query.GetPersonQuery{Context: c.Request.Context(), ID: id})
 	if err != nil {
+		// People retired by a merge redirect to the person they were merged into
+		if pc.RedirectHandler != nil {
+			if to, rerr := pc.RedirectHandler.Handle(&query.GetRedirectQuery{Context: c.Request.Context(), ID: id}); rerr == nil {
+				c.Redirect(301, personPath(c, to))
+				return
+			}
+		}
+
 		// Map custom errors to HTTP response codes using ierr.IErr interface
 		if customErr, ok := err.(ierr.IErr); ok {
 			e := errapi.Map(customErr)
-			c.IndentedJSON(e.StatusCode(), gin.H{"error": e.Error()})
+			render(c, e.StatusCode(), errorBody(e))
 			return
 		} else {
-			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			render(c, 404, gin.H{"error": err.Error()})
 			return
 		}
 	}
 
-	// Prepare response DTO
-	response := ResponseDTO{
-		ID:      person.Id(),
-		Name:    person.Name(),
-		Age:     person.Age(),
-		Hobbies: person.Hobbies(),
-	}
-
-	c.IndentedJSON(200, response)
+	respond(c, 200, personDTO(c, person))
 }
 
-// GetAll retrieves all Person entities.
+// GetAll retrieves all Person entities owned by the caller, or every one of them for admins.
+// Custom attributes can be filtered on with query parameters such as attributes[department]=sales.
 // It calls GetAllHandler to fetch all Persons and returns them as a JSON array with a 200 status code.
 func (pc *PersonController) GetAll(c *gin.Context) {
-	persons, _ := pc.GetAllHandler.Handle(struct{}{})
-
-	// Prepare response list by mapping each Person to ResponseDTO
-	var responses = make([]ResponseDTO, 0)
-	for _, person := range persons {
-		responses = append(responses, ResponseDTO{
-			ID:      person.Id(),
-			Name:    person.Name(),
-			Age:     person.Age(),
-			Hobbies: person.Hobbies(),
+	if !pc.Authorize(c, auth.PersonRead) {
+		return
+	}
+
+	persons, _ := pc.GetAllHandler.Handle(&query.GetPeopleQuery{
+		Context:    c.Request.Context(),
+		Attributes: c.QueryMap("attributes"),
+	})
+
+	// Respond with every Person, or with a page of them for v2
+	respondPage(c, persons)
+}
+
+// Duplicates retrieves groups of people that are likely duplicates of each other.
+// People are grouped when they share the same age and their names are at least as similar as
+// the optional threshold query parameter, and the groups are returned with a 200 status code.
+func (pc *PersonController) Duplicates(c *gin.Context) {
+	if !pc.Authorize(c, auth.PersonRead) {
+		return
+	}
+
+	q := &query.GetDuplicatesQuery{Context: c.Request.Context()}
+
+	if raw := c.Query("threshold"); raw != "" {
+		threshold, err := strconv.ParseFloat(raw, 64)
+		if err != nil || threshold <= 0 || threshold > 1 {
+			e := errapi.NewBadRequest("threshold should be a number greater than 0 and at most 1")
+			render(c, e.StatusCode(), errorBody(e))
+			return
+		}
+		q.Threshold = threshold
+	}
+
+	groups, err := pc.DuplicatesHandler.Handle(q)
+	if err != nil {
+		if customErr, ok := err.(ierr.IErr); ok {
+			e := errapi.Map(customErr)
+			render(c, e.StatusCode(), errorBody(e))
+			return
+		}
+		render(c, 500, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Prepare response list by mapping each group of Persons to a DuplicateGroupDTO
+	var responses = make([]DuplicateGroupDTO, 0, len(groups))
+	for _, group := range groups {
+		responses = append(responses, DuplicateGroupDTO{People: peopleDTOs(c, group)})
+	}
+
+	respond(c, 200, responses)
+}
+
+// Merge merges a set of people into a single survivor.
+// The survivor keeps the union of all hobbies and the chosen name and age, while the other people are
+// retired. Responds with a 200 status code and the redirects for the retired IDs if successful.
+func (pc *PersonController) Merge(c *gin.Context) {
+	if !pc.Authorize(c, auth.PersonWrite) {
+		return
+	}
+
+	var dto MergeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		e := errapi.NewBadRequest("Invalid input data format")
+		render(c, e.StatusCode(), errorBody(e))
+		return
+	}
+
+	command := &command.MergePeopleCommand{
+		Context: c.Request.Context(),
+
+		IDs:        dto.IDs,
+		SurvivorID: dto.SurvivorID,
+		Name:       dto.Name,
+		Age:        dto.Age,
+	}
+
+	// Call MergeHandler to process the merge command
+	result, err := pc.MergeHandler.Handle(command)
+	if err != nil {
+		e := errapi.Map(err)
+		render(c, e.StatusCode(), errorBody(e))
+		return
+	}
+
+	redirects := make(map[string]string, len(result.Merge.RetiredIDs()))
+	for _, id := range result.Merge.RetiredIDs() {
+		redirects[id.String()] = personPath(c, result.Survivor.Id())
+	}
+
+	response := MergeResponseDTO{
+		ID:        result.Merge.Id(),
+		Survivor:  personDTO(c, result.Survivor),
+		Redirects: redirects,
+		MergedAt:  result.Merge.MergedAt(),
+	}
+
+	respond(c, 200, response)
+}
+
+// Merges lists the merges that folded people into a Person, directly or through someone later merged into them.
+// It parses the ID from the URL and returns the merges, oldest first, with a 200 status code.
+func (pc *PersonController) Merges(c *gin.Context) {
+	if !pc.Authorize(c, auth.PersonRead) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		render(c, e.StatusCode(), errorBody(e))
+		return
+	}
+
+	merges, err := pc.MergesHandler.Handle(&query.GetMergesQuery{Context: c.Request.Context(), ID: id})
+	if err != nil {
+		if customErr, ok := err.(ierr.IErr); ok {
+			e := errapi.Map(customErr)
+			render(c, e.StatusCode(), errorBody(e))
+			return
+		}
+		render(c, 500, gin.H{"error": err.Error()})
+		return
+	}
+
+	responses := make([]MergeRecordDTO, 0, len(merges))
+	for _, merge := range merges {
+		responses = append(responses, MergeRecordDTO{
+			ID:         merge.Id(),
+			SurvivorID: merge.SurvivorID(),
+			RetiredIDs: merge.RetiredIDs(),
+			MergedAt:   merge.MergedAt(),
 		})
 	}
 
-	
+	respond(c, 200, responses)
+}
+
+// Transfer handles handing a Person over to another owner.
+// Responds with a 200 status code and the Person if successful, or 404 if the caller doesn't own the Person.
+func (pc *PersonController) Transfer(c *gin.Context) {
+	if !pc.Authorize(c, auth.PersonWrite) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		render(c, e.StatusCode(), errorBody(e))
+		return
+	}
+
+	var dto OwnerDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		e := errapi.NewBadRequest("Invalid input data format")
+		render(c, e.StatusCode(), errorBody(e))
+		return
+	}
+
+	person, cerr := pc.TransferHandler.Handle(&command.TransferOwnershipCommand{
+		Context: c.Request.Context(),
+		ID:      id,
+		Owner:   dto.Owner,
+	})
+	if cerr != nil {
+		e := errapi.Map(cerr)
+		render(c, e.StatusCode(), errorBody(e))
+		return
+	}
 
-	c.IndentedJSON(200, responses)
+	respond(c, 200, personDTO(c, person))
 }
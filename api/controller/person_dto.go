@@ -1,18 +1,169 @@
 package controller
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
+)
 
 // CreateDTO represents the data structure for creating or updating a Person.
 type CreateDTO struct {
-	Name    string   `json:"name" binding:"required"` // Name of the person; required for creating or updating
-	Age     int16    `json:"age" binding:"required"`  // Age of the person; required for creating or updating
-	Hobbies []string `json:"hobbies"`                 // List of hobbies for the person; optional
+	ID      uuid.UUID   `json:"id"`                      // ID of the person; optional, generated when creating without one
+	Name    string      `json:"name" binding:"required"` // Name of the person; required for creating or updating
+	Age     int16       `json:"age" binding:"required"`  // Age of the person; required for creating or updating
+	Hobbies []string    `json:"hobbies"`                 // List of hobbies for the person; optional
+	Email   string      `json:"email"`                   // Email of the person; optional
+	Phone   string      `json:"phone"`                   // Phone number of the person in the E.164 format; optional
+	Address *AddressDTO `json:"address"`                 // Postal address of the person; optional
+
+	Attributes map[string]any `json:"attributes"` // Custom attributes defined by the attribute schema; optional
+}
+
+// AddressDTO represents the postal address of a Person in requests and responses.
+type AddressDTO struct {
+	Street     string `json:"street"`                // Street line of the address
+	City       string `json:"city"`                  // City of the address
+	Region     string `json:"region,omitempty"`      // Region, state or province of the address; optional
+	PostalCode string `json:"postal_code,omitempty"` // Postal code of the address; optional
+	Country    string `json:"country"`               // ISO 3166-1 alpha-2 country code of the address
 }
 
 // ResponseDTO defines the data structure for returning Person data in responses.
 type ResponseDTO struct {
-	ID      uuid.UUID `json:"id"`      // Unique identifier of the person
-	Name    string    `json:"name"`    // Name of the person
-	Age     int16     `json:"age"`     // Age of the person
-	Hobbies []string  `json:"hobbies"` // List of hobbies for the person
+	ID      uuid.UUID   `json:"id"`                // Unique identifier of the person
+	Name    string      `json:"name"`              // Name of the person
+	Age     int16       `json:"age"`               // Age of the person
+	Hobbies []string    `json:"hobbies"`           // List of hobbies for the person
+	Email   string      `json:"email,omitempty"`   // Email of the person, when set
+	Phone   string      `json:"phone,omitempty"`   // Phone number of the person, when set
+	Address *AddressDTO `json:"address,omitempty"` // Postal address of the person, when set
+
+	Attributes map[string]any `json:"attributes,omitempty"` // Custom attributes of the person, when set
+	Owner      string         `json:"owner,omitempty"`      // Subject of the principal owning the person, when owned
+}
+
+// OwnerDTO represents the data structure for transferring a person to another owner.
+type OwnerDTO struct {
+	Owner string `json:"owner" binding:"required"` // Subject of the new owner; required
+}
+
+// MergeDTO represents the data structure for merging a set of people into one survivor.
+type MergeDTO struct {
+	IDs        []uuid.UUID `json:"ids" binding:"required,min=2"` // IDs of the people to merge, survivor included
+	SurvivorID uuid.UUID   `json:"survivor_id"`                  // ID of the person that survives; defaults to the first ID
+	Name       string      `json:"name"`                         // Name kept by the survivor; optional
+	Age        *int16      `json:"age"`                          // Age kept by the survivor; optional
+}
+
+// MergeResponseDTO defines the data structure returned after a merge.
+type MergeResponseDTO struct {
+	ID        uuid.UUID         `json:"id"`        // Unique identifier of the merge
+	Survivor  any               `json:"survivor"`  // The person that survived the merge, as a ResponseDTO or a PersonV2DTO
+	Redirects map[string]string `json:"redirects"` // Retired IDs mapped to the location of the survivor
+	MergedAt  time.Time         `json:"merged_at"` // Time at which the merge happened
+}
+
+// MergeRecordDTO defines the data structure for a merge in the history of a person.
+type MergeRecordDTO struct {
+	ID         uuid.UUID   `json:"id"`          // Unique identifier of the merge
+	SurvivorID uuid.UUID   `json:"survivor_id"` // ID of the person that survived the merge
+	RetiredIDs []uuid.UUID `json:"retired_ids"` // IDs of the people retired by the merge
+	MergedAt   time.Time   `json:"merged_at"`   // Time at which the merge happened
+}
+
+// DuplicateGroupDTO defines the data structure for a group of likely duplicate people.
+type DuplicateGroupDTO struct {
+	People []any `json:"people"` // People that are likely the same individual, as ResponseDTOs or PersonV2DTOs
+}
+
+// ImportResponseDTO defines the data structure for returning the progress and report of an import.
+type ImportResponseDTO struct {
+	ID         uuid.UUID      `json:"id"`                    // Unique identifier of the import
+	Status     string         `json:"status"`                // running, completed or failed
+	DryRun     bool           `json:"dry_run"`               // Whether the rows are only validated
+	Processed  int            `json:"processed"`             // Number of rows processed so far
+	Succeeded  int            `json:"succeeded"`             // Number of rows that succeeded
+	Failed     int            `json:"failed"`                // Number of rows that failed
+	Rows       []ImportRowDTO `json:"rows"`                  // Outcome of every row processed so far
+	Reason     string         `json:"reason,omitempty"`      // Why a failed import couldn't be read to its end
+	CreatedAt  time.Time      `json:"created_at"`            // Time at which the import started
+	FinishedAt *time.Time     `json:"finished_at,omitempty"` // Time at which the import completed or failed
+}
+
+// ImportRowDTO defines the data structure for the outcome of a row of an import.
+type ImportRowDTO struct {
+	Line  int        `json:"line"`            // Line of the row in the upload, starting at 1
+	ID    *uuid.UUID `json:"id,omitempty"`    // ID of the person created, or that a dry run would create
+	Error string     `json:"error,omitempty"` // Why the row failed
+}
+
+// newResponseDTO maps a Person to its ResponseDTO.
+func newResponseDTO(p *model.Person) ResponseDTO {
+	return ResponseDTO{
+		ID:      p.Id(),
+		Name:    p.Name(),
+		Age:     p.Age(),
+		Hobbies: p.Hobbies(),
+		Email:   p.Email().String(),
+		Phone:   p.Phone().String(),
+		Address: newAddressDTO(p.Address()),
+
+		Attributes: p.Attributes(),
+		Owner:      p.Owner(),
+	}
+}
+
+// newAddressDTO maps an Address to its AddressDTO, returning nil when there is no address.
+func newAddressDTO(a *model.Address) *AddressDTO {
+	if a == nil {
+		return nil
+	}
+	return &AddressDTO{
+		Street:     a.Street(),
+		City:       a.City(),
+		Region:     a.Region(),
+		PostalCode: a.PostalCode(),
+		Country:    a.Country(),
+	}
+}
+
+// toAddressConfig maps an AddressDTO to the configuration of an Address, returning nil when there is no address.
+func (a *AddressDTO) toAddressConfig() *model.AddressConfig {
+	if a == nil {
+		return nil
+	}
+	return &model.AddressConfig{
+		Street:     a.Street,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+	}
+}
+
+// newImportResponseDTO maps an Import to its ImportResponseDTO.
+func newImportResponseDTO(imp *model.Import) ImportResponseDTO {
+	rows := make([]ImportRowDTO, 0, imp.Processed())
+	for _, row := range imp.Rows() {
+		dto := ImportRowDTO{Line: row.Line, Error: row.Error}
+		if row.ID != uuid.Nil {
+			id := row.ID
+			dto.ID = &id
+		}
+		rows = append(rows, dto)
+	}
+
+	return ImportResponseDTO{
+		ID:         imp.Id(),
+		Status:     string(imp.Status()),
+		DryRun:     imp.DryRun(),
+		Processed:  imp.Processed(),
+		Succeeded:  imp.Succeeded(),
+		Failed:     imp.Failed(),
+		Rows:       rows,
+		Reason:     imp.Reason(),
+		CreatedAt:  imp.CreatedAt(),
+		FinishedAt: imp.FinishedAt(),
+	}
 }
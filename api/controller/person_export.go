@@ -0,0 +1,321 @@
+package controller
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	"github.com/Efamamo/GoCrudChallange/application/people/query"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/gin-gonic/gin"
+)
+
+// exportFlushInterval is the number of people written between two flushes of an export to the client.
+const exportFlushInterval = 100
+
+// exportFormats maps the values of the format query parameter of exports to their Format.
+var exportFormats = map[string]Format{"ndjson": NDJSON, "csv": CSV, "json": JSON}
+
+// exportMediaTypes maps the media types of the Accept header of exports to their Format.
+var exportMediaTypes = map[string]Format{
+	"application/x-ndjson": NDJSON,
+	"application/ndjson":   NDJSON,
+	"application/jsonl":    NDJSON,
+	"text/csv":             CSV,
+	"application/json":     JSON,
+	"*/*":                  NDJSON,
+	"application/*":        NDJSON,
+	"text/*":               CSV,
+}
+
+// exportExtensions maps the Format of exports to the extension of the file they are downloaded as.
+var exportExtensions = map[Format]string{NDJSON: "ndjson", CSV: "csv", JSON: "json"}
+
+// exportColumns lists the columns of CSV exports preceding the custom attributes, in the order of the fields of ResponseDTO.
+var exportColumns = []string{
+	"id", "name", "age", "hobbies", "email", "phone",
+	"address.street", "address.city", "address.region", "address.postal_code", "address.country",
+}
+
+// Export streams every Person owned by the caller, or every one of them for admins, as NDJSON, CSV or a JSON
+// array, in the format of the format query parameter or else of the Accept header, NDJSON by default.
+// People are written as they are read from the repository with chunked transfer encoding, rather than
+// held in memory, and the body is compressed with gzip when the Accept-Encoding header or the gzip query
+// parameter asks for it. Custom attributes can be filtered on as for GetAll. Responds with a 200 status
+// code, 400 if the query parameters are invalid, or 406 if none of the accepted media types is supported.
+func (pc *PersonController) Export(c *gin.Context) {
+	if !pc.Authorize(c, auth.PersonRead) {
+		return
+	}
+
+	c.Header("Vary", "Accept, Accept-Encoding")
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+	compressed, err := exportCompressed(c)
+	if err != nil {
+		e := errapi.NewBadRequest(err.Error())
+		render(c, e.StatusCode(), errorBody(e))
+		return
+	}
+
+	export := &exporter{c: c, format: format, compressed: compressed}
+	_, err = pc.ExportHandler.Handle(&query.ExportPeopleQuery{
+		Context:    c.Request.Context(),
+		Attributes: c.QueryMap("attributes"),
+		Start:      export.start,
+		Each:       export.write,
+	})
+	if err != nil && !export.started {
+		if customErr, ok := err.(ierr.IErr); ok {
+			e := errapi.Map(customErr)
+			render(c, e.StatusCode(), errorBody(e))
+			return
+		}
+		render(c, 500, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		// The status is already sent: the truncated body is all the client gets
+		c.Error(err)
+		return
+	}
+
+	if err := export.close(); err != nil {
+		c.Error(err)
+	}
+}
+
+// exportFormat returns the Format of an export, from the format query parameter or else the Accept header.
+// It responds with a 400 or a 406 and returns false when neither names a supported format.
+func exportFormat(c *gin.Context) (Format, bool) {
+	if raw := c.Query("format"); raw != "" {
+		format, ok := exportFormats[strings.ToLower(raw)]
+		if !ok {
+			e := errapi.NewBadRequest("format should be ndjson, csv or json")
+			render(c, e.StatusCode(), errorBody(e))
+		}
+		return format, ok
+	}
+
+	accept := c.GetHeader("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return NDJSON, true
+	}
+	format, ok := preferred(accept, func(mediaType string) (Format, bool) {
+		if format, ok := exportMediaTypes[mediaType]; ok {
+			return format, true
+		}
+		// Vendor media types selecting a version, e.g. application/vnd.gocrud.v2+json
+		if strings.HasPrefix(mediaType, "application/vnd.") && strings.HasSuffix(mediaType, "+json") {
+			return JSON, true
+		}
+		return "", false
+	})
+	if !ok {
+		e := errapi.NewNotAcceptable(fmt.Sprintf("Accept should include one of %s, %s or %s", NDJSON, CSV, JSON))
+		render(c, e.StatusCode(), errorBody(e))
+	}
+	return format, ok
+}
+
+// exportCompressed reports whether an export is compressed with gzip: as the gzip query parameter says,
+// or else when the Accept-Encoding header accepts gzip.
+func exportCompressed(c *gin.Context) (bool, error) {
+	if c.Query("gzip") != "" {
+		return boolQuery(c, "gzip")
+	}
+
+	for _, coding := range strings.Split(c.GetHeader("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(coding, ";")
+		if !strings.EqualFold(strings.TrimSpace(name), "gzip") {
+			continue
+		}
+		// gzip;q=0 refuses gzip
+		if raw, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			quality, err := strconv.ParseFloat(raw, 64)
+			return err == nil && quality > 0, nil
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// exporter writes the people of an export to the response as they are streamed, flushing them to the client
+// every exportFlushInterval people. The status and headers are only sent once the export starts, so that
+// errors happening before can still be responded with.
+type exporter struct {
+	c          *gin.Context
+	format     Format
+	compressed bool
+
+	started bool
+	gzip    *gzip.Writer
+	buffer  *bufio.Writer
+	csv     *csv.Writer
+	columns []string // Columns of CSV exports
+	count   int      // Number of people written so far
+}
+
+// start sends the status and headers of the export, and writes what precedes the first person.
+// The names of the custom attributes become the last columns of CSV exports.
+func (e *exporter) start(attributes []string) error {
+	e.started = true
+
+	contentType := string(e.format)
+	if e.format != NDJSON {
+		contentType += "; charset=utf-8"
+	}
+	e.c.Header("Content-Type", contentType)
+	e.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="people.%s"`, exportExtensions[e.format]))
+	e.c.Header("X-Accel-Buffering", "no")
+
+	var sink io.Writer = e.c.Writer
+	if e.compressed {
+		e.c.Header("Content-Encoding", "gzip")
+		e.gzip = gzip.NewWriter(e.c.Writer)
+		sink = e.gzip
+	}
+	e.c.Status(200)
+	e.buffer = bufio.NewWriter(sink)
+
+	switch e.format {
+	case CSV:
+		e.columns = append([]string(nil), exportColumns...)
+		for _, name := range attributes {
+			e.columns = append(e.columns, "attributes."+name)
+		}
+		e.columns = append(e.columns, "owner")
+		if versionOf(e.c) == V2 {
+			e.columns = append(e.columns, "created_at", "updated_at")
+		}
+		e.csv = csv.NewWriter(e.buffer)
+		return e.csv.Write(e.columns)
+	case JSON:
+		_, err := e.buffer.WriteString("[")
+		return err
+	}
+	return nil
+}
+
+// write writes a person in the format of the export, as the DTO of the version of the request.
+func (e *exporter) write(p *model.Person) error {
+	if !e.started {
+		if err := e.start(nil); err != nil {
+			return err
+		}
+	}
+
+	dto := personDTO(e.c, p)
+	switch e.format {
+	case CSV:
+		record, err := e.record(dto)
+		if err != nil {
+			return err
+		}
+		if err := e.csv.Write(record); err != nil {
+			return err
+		}
+	case JSON:
+		separator := "\n"
+		if e.count > 0 {
+			separator = ",\n"
+		}
+		if _, err := e.buffer.WriteString(separator); err != nil {
+			return err
+		}
+		fallthrough
+	default:
+		raw, err := json.Marshal(dto)
+		if err != nil {
+			return err
+		}
+		if e.format == NDJSON {
+			raw = append(raw, '\n')
+		}
+		if _, err := e.buffer.Write(raw); err != nil {
+			return err
+		}
+	}
+
+	e.count++
+	if e.count%exportFlushInterval == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+// record returns the CSV record of a person, with a cell per column. Lists are joined with semicolons as in
+// CSV responses, and custom attributes that are no longer in the schema are left out.
+func (e *exporter) record(dto any) ([]string, error) {
+	tree, err := toTree(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	cells := make(map[string]string, len(e.columns))
+	for _, field := range flatten("", tree) {
+		cells[field.key] = fmt.Sprint(field.value)
+	}
+	record := make([]string, 0, len(e.columns))
+	for _, column := range e.columns {
+		record = append(record, cells[column])
+	}
+	return record, nil
+}
+
+// flush sends what was written so far to the client.
+func (e *exporter) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if err := e.buffer.Flush(); err != nil {
+		return err
+	}
+	if e.gzip != nil {
+		if err := e.gzip.Flush(); err != nil {
+			return err
+		}
+	}
+	e.c.Writer.Flush()
+	return nil
+}
+
+// close writes what follows the last person, and sends the rest of the export. Exports of nobody
+// are an empty body, a header row or an empty array.
+func (e *exporter) close() error {
+	if !e.started {
+		if err := e.start(nil); err != nil {
+			return err
+		}
+	}
+
+	if e.format == JSON {
+		closing := "]\n"
+		if e.count > 0 {
+			closing = "\n]\n"
+		}
+		if _, err := e.buffer.WriteString(closing); err != nil {
+			return err
+		}
+	}
+	if err := e.flush(); err != nil {
+		return err
+	}
+	if e.gzip != nil {
+		return e.gzip.Close()
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Default limits of the size of imports.
+const (
+	defaultImportSyncLimit = 1 << 20  // Size of the largest upload imported before responding
+	defaultImportMaxSize   = 32 << 20 // Size of the largest upload accepted
+)
+
+// importFormats maps the media types and file extensions of the uploads of imports to their Format.
+var importFormats = map[string]Format{
+	"text/csv":             CSV,
+	".csv":                 CSV,
+	"application/x-ndjson": NDJSON,
+	"application/ndjson":   NDJSON,
+	"application/jsonl":    NDJSON,
+	".ndjson":              NDJSON,
+	".jsonl":               NDJSON,
+}
+
+// Import handles the bulk import of people from a CSV or NDJSON upload, sent as the request body or as the
+// file field of a multipart form. CSV uploads have the columns of CSV responses, and NDJSON uploads a body
+// of Create per line. Every row is validated like a creation, and failing rows don't stop the import.
+// With the dry_run query parameter, rows are only validated. Uploads larger than ImportSyncLimit, or
+// imported with the async query parameter, are imported by a people.import job: it responds with a 202
+// status code and the job, whose progress and result are polled at its Location. Otherwise it responds with
+// a 200 status code and the report of every row, or a 400, 413 or 415 when the upload can't be read.
+func (pc *PersonController) Import(c *gin.Context) {
+	if !pc.Authorize(c, auth.PersonWrite) {
+		return
+	}
+
+	dryRun, err := boolQuery(c, "dry_run")
+	if err != nil {
+		e := errapi.NewBadRequest(err.Error())
+		render(c, e.StatusCode(), errorBody(e))
+		return
+	}
+	async, err := boolQuery(c, "async")
+	if err != nil {
+		e := errapi.NewBadRequest(err.Error())
+		render(c, e.StatusCode(), errorBody(e))
+		return
+	}
+
+	body, format, ok := pc.upload(c)
+	if !ok {
+		return
+	}
+
+	syncLimit := pc.ImportSyncLimit
+	if syncLimit <= 0 {
+		syncLimit = defaultImportSyncLimit
+	}
+	if async || int64(len(body)) > syncLimit {
+		pc.importInBackground(c, body, format, dryRun)
+		return
+	}
+
+	rows, err := ImportRows(string(format), body)
+	if err != nil {
+		e := errapi.NewUnsupported(err.Error())
+		render(c, e.StatusCode(), errorBody(e))
+		return
+	}
+	imp, cerr := pc.ImportHandler.Handle(&command.ImportPeopleCommand{
+		Context: c.Request.Context(),
+		Rows:    rows,
+		DryRun:  dryRun,
+	})
+	if cerr != nil {
+		e := errapi.Map(cerr)
+		render(c, e.StatusCode(), errorBody(e))
+		return
+	}
+
+	respond(c, 200, newImportResponseDTO(imp))
+}
+
+// importInBackground submits a people.import job importing the upload, and responds with a 202 status code,
+// the queued job and its Location.
+func (pc *PersonController) importInBackground(c *gin.Context, body []byte, format Format, dryRun bool) {
+	j, cerr := pc.StartImportHandler.Handle(&command.StartImportCommand{
+		Context: c.Request.Context(),
+		Format:  string(format),
+		Upload:  body,
+		DryRun:  dryRun,
+	})
+	if cerr != nil {
+		e := errapi.Map(cerr)
+		render(c, e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/jobs/%s", j.Id()))
+	respond(c, 202, newJobResponseDTO(j))
+}
+
+// ImportRows returns a reader of the rows of an upload in the format, text/csv or application/x-ndjson.
+// It is the RowDecoder of the jobs importing people.
+func ImportRows(format string, upload []byte) (command.IRowReader, error) {
+	switch Format(format) {
+	case CSV:
+		return newCSVRows(upload), nil
+	case NDJSON:
+		return newNDJSONRows(upload), nil
+	}
+	return nil, fmt.Errorf("format should be %s or %s", CSV, NDJSON)
+}
+
+// upload reads the upload of an import, from the file field of a multipart form or from the body,
+// and returns it with its Format, found from its media type or else from the extension of its file name.
+// It responds with a 400, 413 or 415 and returns false when the upload can't be read.
+func (pc *PersonController) upload(c *gin.Context) ([]byte, Format, bool) {
+	maxSize := pc.ImportMaxSize
+	if maxSize <= 0 {
+		maxSize = defaultImportMaxSize
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize)
+
+	reader := io.Reader(c.Request.Body)
+	contentType, name := c.ContentType(), ""
+	if contentType == binding.MIMEMultipartPOSTForm {
+		file, header, err := c.Request.FormFile("file")
+		if err != nil {
+			e := uploadError(err, maxSize, "upload should be sent as the file field of the form")
+			render(c, e.StatusCode(), errorBody(e))
+			return nil, "", false
+		}
+		defer file.Close()
+		reader, contentType, name = file, header.Header.Get("Content-Type"), header.Filename
+	}
+
+	format, ok := importFormats[mediaType(contentType)]
+	if !ok {
+		format, ok = importFormats[strings.ToLower(filepath.Ext(name))]
+	}
+	if !ok {
+		e := errapi.NewUnsupported(fmt.Sprintf("upload should be %s or %s", CSV, NDJSON))
+		render(c, e.StatusCode(), errorBody(e))
+		return nil, "", false
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		e := uploadError(err, maxSize, "upload can't be read")
+		render(c, e.StatusCode(), errorBody(e))
+		return nil, "", false
+	}
+	return body, format, true
+}
+
+// uploadError maps an error reading an upload to a 413 when it is too large, or else a 400 with the message.
+func uploadError(err error, maxSize int64, message string) errapi.Error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return errapi.NewTooLarge(fmt.Sprintf("upload should be at most %d bytes", maxSize))
+	}
+	return errapi.NewBadRequest(message)
+}
+
+// mediaType returns the media type of a Content-Type, without its parameters.
+func mediaType(contentType string) string {
+	parsed, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return parsed
+}
+
+// boolQuery returns the boolean value of a query parameter, false when it is missing.
+func boolQuery(c *gin.Context, key string) (bool, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s should be true or false", key)
+	}
+	return value, nil
+}
+
+// csvRows reads the rows of a CSV upload, whose first line is a header naming the columns.
+type csvRows struct {
+	reader *csv.Reader
+	header []string
+}
+
+// newCSVRows creates a reader of the rows of a CSV upload.
+func newCSVRows(body []byte) *csvRows {
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1 // Rows with missing or extra cells fail on their own
+	return &csvRows{reader: reader}
+}
+
+// Next reads the next row of the upload. Malformed rows fail on their own, and reading goes on.
+func (r *csvRows) Next() (*command.ImportRow, error) {
+	if r.header == nil {
+		header, err := r.reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		// Spreadsheets often start their exports with a byte order mark
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+		r.header = header
+	}
+
+	record, err := r.reader.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &command.ImportRow{Line: parseErr.Line, Err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	if len(record) != len(r.header) {
+		return &command.ImportRow{Line: line, Err: fmt.Errorf("row should have %d cells, one per column", len(r.header))}, nil
+	}
+
+	person, err := bindRow(csvRecord(r.header, record))
+	return &command.ImportRow{Line: line, Person: person, Err: err}, nil
+}
+
+// ndjsonRows reads the rows of an NDJSON upload, skipping blank lines.
+type ndjsonRows struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// newNDJSONRows creates a reader of the rows of an NDJSON upload.
+func newNDJSONRows(body []byte) *ndjsonRows {
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	return &ndjsonRows{scanner: scanner}
+}
+
+// Next reads the next row of the upload. Malformed rows fail on their own, and reading goes on.
+func (r *ndjsonRows) Next() (*command.ImportRow, error) {
+	for r.scanner.Scan() {
+		r.line++
+		if line := bytes.TrimSpace(r.scanner.Bytes()); len(line) > 0 {
+			var dto CreateDTO
+			if err := binding.JSON.BindBody(line, &dto); err != nil {
+				return &command.ImportRow{Line: r.line, Err: err}, nil
+			}
+			return &command.ImportRow{Line: r.line, Person: newCreateCommand(dto)}, nil
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// bindRow binds the tree of a row, whose values are text, to the command creating its person.
+func bindRow(tree any) (*command.CreatePersonCommand, error) {
+	var dto CreateDTO
+	raw, err := json.Marshal(coerce(tree, reflect.TypeOf(&dto)))
+	if err != nil {
+		return nil, err
+	}
+	if err := binding.JSON.BindBody(raw, &dto); err != nil {
+		return nil, err
+	}
+	return newCreateCommand(dto), nil
+}
+
+// newCreateCommand maps the CreateDTO of a row to the command creating its person, whose Context is set by the import.
+func newCreateCommand(dto CreateDTO) *command.CreatePersonCommand {
+	return &command.CreatePersonCommand{
+		ID:      dto.ID,
+		Name:    dto.Name,
+		Age:     dto.Age,
+		Hobbies: dto.Hobbies,
+		Email:   dto.Email,
+		Phone:   dto.Phone,
+		Address: dto.Address.toAddressConfig(),
+
+		Attributes: dto.Attributes,
+	}
+}
@@ -0,0 +1,225 @@
+package controller
+
+import (
+	"strconv"
+
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	relcmd "github.com/Efamamo/GoCrudChallange/application/relations/command"
+	relquery "github.com/Efamamo/GoCrudChallange/application/relations/query"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/Efamamo/GoCrudChallange/domain/model/relation"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RelationController defines handlers for managing the relations between people.
+type RelationController struct {
+	BaseController
+
+	AddHandler    icmd.IHandler[*relcmd.AddRelationCommand, *relation.Relation]
+	RemoveHandler icmd.IHandler[*relcmd.RemoveRelationCommand, bool]
+	GetAllHandler iquery.IHandler[*relquery.GetRelationsQuery, []*relation.Relation]
+	MutualHandler iquery.IHandler[*relquery.GetMutualQuery, []*model.Person]
+	PathHandler   iquery.IHandler[*relquery.GetPathQuery, []*model.Person]
+}
+
+// Add handles relating a person to another one.
+// It parses the person ID from the URL and the relation from the request body.
+// Responds with a 201 status code if successful, or 409 if the same relation already exists.
+func (rc *RelationController) Add(c *gin.Context) {
+	if !rc.Authorize(c, auth.PersonWrite) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	var dto CreateRelationDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		e := errapi.NewBadRequest("Invalid input data format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	command := &relcmd.AddRelationCommand{
+		Context: c.Request.Context(),
+
+		From:     id,
+		To:       dto.To,
+		Type:     dto.Type,
+		Directed: dto.Directed,
+	}
+
+	// Calls AddHandler to process the relation command
+	rel, cerr := rc.AddHandler.Handle(command)
+	if cerr != nil {
+		e := errapi.Map(cerr)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	respond(c, 201, newRelationResponseDTO(rel))
+}
+
+// GetAll retrieves every relation a person takes part in.
+// Responds with a 200 status code and the relations, or 404 if the person was not found.
+func (rc *RelationController) GetAll(c *gin.Context) {
+	if !rc.Authorize(c, auth.PersonRead) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	relations, err := rc.GetAllHandler.Handle(&relquery.GetRelationsQuery{Context: c.Request.Context(), PersonID: id})
+	if err != nil {
+		if customErr, ok := err.(ierr.IErr); ok {
+			e := errapi.Map(customErr)
+			c.IndentedJSON(e.StatusCode(), errorBody(e))
+			return
+		}
+		c.IndentedJSON(404, gin.H{"error": err.Error()})
+		return
+	}
+
+	var responses = make([]RelationResponseDTO, 0, len(relations))
+	for _, rel := range relations {
+		responses = append(responses, newRelationResponseDTO(rel))
+	}
+
+	respond(c, 200, responses)
+}
+
+// Remove handles the removal of a relation of a person.
+// Responds with a 204 status code if successful, or 404 if the relation was not found.
+func (rc *RelationController) Remove(c *gin.Context) {
+	if !rc.Authorize(c, auth.PersonWrite) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	relationID, err := uuid.Parse(c.Param("relationId"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid relation id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	_, cerr := rc.RemoveHandler.Handle(&relcmd.RemoveRelationCommand{
+		Context:    c.Request.Context(),
+		PersonID:   id,
+		RelationID: relationID,
+	})
+	if cerr != nil {
+		e := errapi.Map(cerr)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(204, nil)
+}
+
+// Mutual retrieves the people that two people are both connected to.
+// Responds with a 200 status code and the mutual connections.
+func (rc *RelationController) Mutual(c *gin.Context) {
+	if !rc.Authorize(c, auth.PersonRead) {
+		return
+	}
+
+	id, otherID, ok := rc.parsePair(c)
+	if !ok {
+		return
+	}
+
+	people, err := rc.MutualHandler.Handle(&relquery.GetMutualQuery{
+		Context:  c.Request.Context(),
+		PersonID: id,
+		OtherID:  otherID,
+	})
+	if err != nil {
+		rc.respondQueryError(c, err)
+		return
+	}
+
+	respond(c, 200, peopleDTOs(c, people))
+}
+
+// Path retrieves the shortest chain of relations leading from one person to another one.
+// The optional max_depth query parameter bounds the number of hops.
+// Responds with a 200 status code and the people along the path, or 404 if there is no path.
+func (rc *RelationController) Path(c *gin.Context) {
+	if !rc.Authorize(c, auth.PersonRead) {
+		return
+	}
+
+	id, otherID, ok := rc.parsePair(c)
+	if !ok {
+		return
+	}
+
+	q := &relquery.GetPathQuery{Context: c.Request.Context(), From: id, To: otherID}
+	if raw := c.Query("max_depth"); raw != "" {
+		depth, err := strconv.Atoi(raw)
+		if err != nil || depth <= 0 {
+			e := errapi.NewBadRequest("max_depth should be a positive integer")
+			c.IndentedJSON(e.StatusCode(), errorBody(e))
+			return
+		}
+		q.MaxDepth = depth
+	}
+
+	people, err := rc.PathHandler.Handle(q)
+	if err != nil {
+		rc.respondQueryError(c, err)
+		return
+	}
+
+	respond(c, 200, peopleDTOs(c, people))
+}
+
+// parsePair parses the IDs of the two people a graph query is about, responding with 400 if either is invalid.
+func (rc *RelationController) parsePair(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	otherID, err := uuid.Parse(c.Param("otherId"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, otherID, true
+}
+
+// respondQueryError maps errors returned by query handlers to HTTP responses.
+func (rc *RelationController) respondQueryError(c *gin.Context, err error) {
+	if customErr, ok := err.(ierr.IErr); ok {
+		e := errapi.Map(customErr)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+	c.IndentedJSON(500, gin.H{"error": err.Error()})
+}
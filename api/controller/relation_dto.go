@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/domain/model/relation"
+	"github.com/google/uuid"
+)
+
+// CreateRelationDTO represents the data structure for relating a person to another one.
+type CreateRelationDTO struct {
+	To       uuid.UUID `json:"to" binding:"required"`   // ID of the related person; required
+	Type     string    `json:"type" binding:"required"` // Type of the relation (e.g. friend, family); required
+	Directed bool      `json:"directed"`                // Whether the relation only goes from the person to the related one; optional
+}
+
+// RelationResponseDTO defines the data structure for returning relations in responses.
+type RelationResponseDTO struct {
+	ID        uuid.UUID `json:"id"`         // Unique identifier of the relation
+	From      uuid.UUID `json:"from"`       // ID of the person the relation starts from
+	To        uuid.UUID `json:"to"`         // ID of the person the relation points to
+	Type      string    `json:"type"`       // Type of the relation
+	Directed  bool      `json:"directed"`   // Whether the relation only goes from From to To
+	CreatedAt time.Time `json:"created_at"` // Time at which the relation was created
+}
+
+// newRelationResponseDTO maps a Relation to its RelationResponseDTO.
+func newRelationResponseDTO(r *relation.Relation) RelationResponseDTO {
+	return RelationResponseDTO{
+		ID:        r.Id(),
+		From:      r.From(),
+		To:        r.To(),
+		Type:      r.Type(),
+		Directed:  r.Directed(),
+		CreatedAt: r.CreatedAt(),
+	}
+}
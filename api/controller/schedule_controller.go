@@ -0,0 +1,43 @@
+package controller
+
+import (
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/schedule"
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduleController defines handlers for reading the schedules of the maintenance tasks run by the service.
+type ScheduleController struct {
+	BaseController
+
+	GetAllHandler iquery.IHandler[struct{}, []*schedule.Schedule]
+}
+
+// GetAll retrieves the schedule of every maintenance task, with the status of its last run.
+// Responds with a 200 status code and the schedules sorted by name.
+func (sc *ScheduleController) GetAll(c *gin.Context) {
+	if !sc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	schedules, err := sc.GetAllHandler.Handle(struct{}{})
+	if err != nil {
+		if customErr, ok := err.(ierr.IErr); ok {
+			e := errapi.Map(customErr)
+			c.IndentedJSON(e.StatusCode(), errorBody(e))
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	var responses = make([]ScheduleResponseDTO, 0, len(schedules))
+	for _, s := range schedules {
+		responses = append(responses, newScheduleResponseDTO(s))
+	}
+
+	c.IndentedJSON(200, responses)
+}
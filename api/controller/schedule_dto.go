@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/domain/model/schedule"
+)
+
+// ScheduleResponseDTO defines the data structure for returning the schedules of maintenance tasks in responses.
+type ScheduleResponseDTO struct {
+	Name      string          `json:"name"`                  // Name of the task, e.g. jobs.compact
+	Cron      string          `json:"cron,omitempty"`        // Cron expression telling when the task runs, when enabled
+	Enabled   bool            `json:"enabled"`               // Whether the task runs
+	Running   bool            `json:"running"`               // Whether the task is running
+	NextRunAt *time.Time      `json:"next_run_at,omitempty"` // Time of the next run, jitter included, when planned
+	LastRun   *RunResponseDTO `json:"last_run,omitempty"`    // Last run of the task, when it ran
+	Runs      int             `json:"runs"`                  // Number of runs since the service started
+	Failures  int             `json:"failures"`              // Number of runs that failed
+	Skipped   int             `json:"skipped"`               // Number of runs skipped because the previous one was still running
+}
+
+// RunResponseDTO defines the data structure for returning a run of a maintenance task in responses.
+type RunResponseDTO struct {
+	StartedAt  time.Time `json:"started_at"`        // Time the run started
+	FinishedAt time.Time `json:"finished_at"`       // Time the run finished
+	Outcome    string    `json:"outcome"`           // succeeded or failed
+	Message    string    `json:"message,omitempty"` // Summary of a succeeded run, or the error of a failed one
+}
+
+// newScheduleResponseDTO maps a Schedule to its response DTO.
+func newScheduleResponseDTO(s *schedule.Schedule) ScheduleResponseDTO {
+	dto := ScheduleResponseDTO{
+		Name:     s.Name(),
+		Enabled:  s.Enabled(),
+		Running:  s.Running(),
+		Runs:     s.Runs(),
+		Failures: s.Failures(),
+		Skipped:  s.Skipped(),
+	}
+	if s.Enabled() {
+		dto.Cron = s.Cron().String()
+	}
+	if next := s.NextRun(); !next.IsZero() {
+		dto.NextRunAt = &next
+	}
+	if run := s.LastRun(); run != nil {
+		dto.LastRun = &RunResponseDTO{
+			StartedAt:  run.StartedAt,
+			FinishedAt: run.FinishedAt,
+			Outcome:    string(run.Outcome),
+			Message:    run.Message,
+		}
+	}
+	return dto
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	usercmd "github.com/Efamamo/GoCrudChallange/application/users/command"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/user"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UserController defines handlers for administering local user accounts.
+type UserController struct {
+	BaseController
+
+	CreateHandler icmd.IHandler[*usercmd.CreateUserCommand, *user.User]
+	DeleteHandler icmd.IHandler[uuid.UUID, bool]
+	GetAllHandler iquery.IHandler[struct{}, []*user.User]
+}
+
+// Create handles the creation of a local user.
+// The user belongs to the tenant of the body, or else of the request, and its tokens are confined to it.
+// Responds with a 201 status code and the user, 403 if the caller belongs to another tenant, or 409 if the username is taken.
+func (uc *UserController) Create(c *gin.Context) {
+	if !uc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	var dto UserDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		e := errapi.NewBadRequest("Invalid input data format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	t, ok := uc.tenantOf(c, dto.Tenant)
+	if !ok {
+		return
+	}
+
+	command := &usercmd.CreateUserCommand{
+		Username: dto.Username,
+		Password: dto.Password,
+		Roles:    dto.Roles,
+		Tenant:   t,
+	}
+
+	u, err := uc.CreateHandler.Handle(command)
+	if err != nil {
+		e := errapi.Map(err)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(201, newUserResponseDTO(u))
+}
+
+// GetAll retrieves every local user.
+// Responds with a 200 status code and the users sorted by username.
+func (uc *UserController) GetAll(c *gin.Context) {
+	if !uc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	users, err := uc.GetAllHandler.Handle(struct{}{})
+	if err != nil {
+		if customErr, ok := err.(ierr.IErr); ok {
+			e := errapi.Map(customErr)
+			c.IndentedJSON(e.StatusCode(), errorBody(e))
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	var responses = make([]UserResponseDTO, 0, len(users))
+	for _, u := range users {
+		responses = append(responses, newUserResponseDTO(u))
+	}
+
+	c.IndentedJSON(200, responses)
+}
+
+// Delete handles the removal of a local user by its ID.
+// Responds with a 204 status code if successful, or 404 if the user was not found.
+func (uc *UserController) Delete(c *gin.Context) {
+	if !uc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	if _, err := uc.DeleteHandler.Handle(id); err != nil {
+		e := errapi.Map(err)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(204, nil)
+}
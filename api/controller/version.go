@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Version is a version of the API, selected by the route group a request reaches.
+type Version int
+
+const (
+	V1 Version = 1 // Bare JSON bodies; the version of requests reaching no versioned group
+	V2 Version = 2 // Bodies wrapped in an envelope, people with timestamps, and paginated lists
+)
+
+// VersionKey is the key under which the Version of the request is stored in the Gin context.
+const VersionKey = "api_version"
+
+// Default and maximum number of people in a page of a v2 list.
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// PersonV2DTO defines the data structure for returning Person data in v2 responses.
+type PersonV2DTO struct {
+	ResponseDTO
+	CreatedAt time.Time `json:"created_at"` // Time the person was created
+	UpdatedAt time.Time `json:"updated_at"` // Time the person was last changed
+}
+
+// EnvelopeDTO wraps the body of every successful v2 response.
+type EnvelopeDTO struct {
+	Data any      `json:"data"`           // The requested resource or list of resources
+	Meta *PageDTO `json:"meta,omitempty"` // Position of the page, for paginated lists
+}
+
+// PageDTO describes a page of a paginated list.
+type PageDTO struct {
+	Total  int `json:"total"`  // Number of items across every page
+	Limit  int `json:"limit"`  // Maximum number of items in the page
+	Offset int `json:"offset"` // Number of items preceding the page
+}
+
+// versionOf returns the Version of the request, V1 unless a versioned group selected another one.
+func versionOf(c *gin.Context) Version {
+	if v, ok := c.Get(VersionKey); ok {
+		if version, ok := v.(Version); ok {
+			return version
+		}
+	}
+	return V1
+}
+
+// respond writes a successful response, wrapped in an envelope for v2.
+func respond(c *gin.Context, status int, data any) {
+	if versionOf(c) == V2 {
+		data = EnvelopeDTO{Data: data}
+	}
+	render(c, status, data)
+}
+
+// personDTO maps a Person to the DTO of the version of the request.
+func personDTO(c *gin.Context, p *model.Person) any {
+	if versionOf(c) == V2 {
+		return PersonV2DTO{ResponseDTO: newResponseDTO(p), CreatedAt: p.CreatedAt(), UpdatedAt: p.UpdatedAt()}
+	}
+	return newResponseDTO(p)
+}
+
+// peopleDTOs maps a list of Persons to the DTOs of the version of the request.
+func peopleDTOs(c *gin.Context, people []*model.Person) []any {
+	responses := make([]any, 0, len(people))
+	for _, p := range people {
+		responses = append(responses, personDTO(c, p))
+	}
+	return responses
+}
+
+// personPath returns the location of a person under the route group of the request, e.g. /v2/person/:id.
+func personPath(c *gin.Context, id uuid.UUID) string {
+	prefix, _, _ := strings.Cut(c.FullPath(), "/person")
+	return fmt.Sprintf("%s/person/%s", prefix, id)
+}
+
+// respondPage writes a page of people for v2, read from the limit and offset query parameters,
+// or the whole list for v1. It responds with a 400 when the parameters are invalid.
+func respondPage(c *gin.Context, people []*model.Person) {
+	if versionOf(c) != V2 {
+		render(c, 200, peopleDTOs(c, people))
+		return
+	}
+
+	page := PageDTO{Total: len(people), Limit: defaultPageLimit}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > maxPageLimit {
+			e := errapi.NewBadRequest(fmt.Sprintf("limit should be an integer between 1 and %d", maxPageLimit))
+			render(c, e.StatusCode(), errorBody(e))
+			return
+		}
+		page.Limit = limit
+	}
+	if raw := c.Query("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			e := errapi.NewBadRequest("offset should be a non-negative integer")
+			render(c, e.StatusCode(), errorBody(e))
+			return
+		}
+		page.Offset = offset
+	}
+
+	start := min(page.Offset, len(people))
+	end := min(start+page.Limit, len(people))
+	render(c, 200, EnvelopeDTO{Data: peopleDTOs(c, people[start:end]), Meta: &page})
+}
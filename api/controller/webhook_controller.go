@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"fmt"
+
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	hookcmd "github.com/Efamamo/GoCrudChallange/application/webhooks/command"
+	hookquery "github.com/Efamamo/GoCrudChallange/application/webhooks/query"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/webhook"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookController defines handlers for administering webhooks and reading their delivery logs.
+type WebhookController struct {
+	BaseController
+
+	CreateHandler     icmd.IHandler[*hookcmd.CreateWebhookCommand, *hookcmd.CreateWebhookResult]
+	UpdateHandler     icmd.IHandler[*hookcmd.UpdateWebhookCommand, *webhook.Webhook]
+	DeleteHandler     icmd.IHandler[uuid.UUID, bool]
+	GetHandler        iquery.IHandler[uuid.UUID, *webhook.Webhook]
+	GetAllHandler     iquery.IHandler[struct{}, []*webhook.Webhook]
+	DeliveriesHandler iquery.IHandler[*hookquery.GetDeliveriesQuery, []*webhook.Delivery]
+}
+
+// Create handles subscribing a URL to the changes of people.
+// Responds with a 201 status code and the webhook along with its secret, which is only ever shown here,
+// or 400 if the URL or an event type is invalid.
+func (wc *WebhookController) Create(c *gin.Context) {
+	if !wc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	var dto WebhookDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		e := errapi.NewBadRequest("Invalid input data format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	command := &hookcmd.CreateWebhookCommand{
+		URL:    dto.URL,
+		Events: dto.Events,
+	}
+
+	result, err := wc.CreateHandler.Handle(command)
+	if err != nil {
+		e := errapi.Map(err)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(201, CreatedWebhookResponseDTO{
+		WebhookResponseDTO: newWebhookResponseDTO(result.Webhook),
+		Secret:             result.Secret,
+	})
+}
+
+// GetAll retrieves every webhook, without their secrets.
+// Responds with a 200 status code and the webhooks.
+func (wc *WebhookController) GetAll(c *gin.Context) {
+	if !wc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	hooks, err := wc.GetAllHandler.Handle(struct{}{})
+	if err != nil {
+		wc.respondQueryError(c, err)
+		return
+	}
+
+	var responses = make([]WebhookResponseDTO, 0, len(hooks))
+	for _, w := range hooks {
+		responses = append(responses, newWebhookResponseDTO(w))
+	}
+
+	c.IndentedJSON(200, responses)
+}
+
+// Get retrieves a webhook by its ID.
+// Responds with a 200 status code and the webhook, or 404 if it was not found.
+func (wc *WebhookController) Get(c *gin.Context) {
+	if !wc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	hook, qerr := wc.GetHandler.Handle(id)
+	if qerr != nil {
+		wc.respondQueryError(c, qerr)
+		return
+	}
+
+	c.IndentedJSON(200, newWebhookResponseDTO(hook))
+}
+
+// Update handles changing the URL and the event types of a webhook; its secret is kept.
+// Responds with a 200 status code and the webhook, 400 if the input is invalid, or 404 if it was not found.
+func (wc *WebhookController) Update(c *gin.Context) {
+	if !wc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	var dto WebhookDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		e := errapi.NewBadRequest("Invalid input data format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	command := &hookcmd.UpdateWebhookCommand{
+		ID:     id,
+		URL:    dto.URL,
+		Events: dto.Events,
+	}
+
+	hook, cerr := wc.UpdateHandler.Handle(command)
+	if cerr != nil {
+		e := errapi.Map(cerr)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(200, newWebhookResponseDTO(hook))
+}
+
+// Delete handles the removal of a webhook by its ID, along with its deliveries.
+// Responds with a 204 status code if successful, or 404 if the webhook was not found.
+func (wc *WebhookController) Delete(c *gin.Context) {
+	if !wc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	if _, err := wc.DeleteHandler.Handle(id); err != nil {
+		e := errapi.Map(err)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	c.IndentedJSON(204, nil)
+}
+
+// Deliveries retrieves the delivery log of a webhook, newest first, optionally only the deliveries
+// in the state of the status query parameter: pending, succeeded or dead_lettered.
+// Responds with a 200 status code and the deliveries, 400 if the status is unknown, or 404 if the webhook was not found.
+func (wc *WebhookController) Deliveries(c *gin.Context) {
+	if !wc.Authorize(c, auth.Admin) {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errapi.NewBadRequest("Invalid id format")
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	status := webhook.DeliveryStatus(c.Query("status"))
+	switch status {
+	case "", webhook.Pending, webhook.Succeeded, webhook.DeadLettered:
+	default:
+		e := errapi.NewBadRequest(fmt.Sprintf("unknown delivery status %q", status))
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+
+	deliveries, qerr := wc.DeliveriesHandler.Handle(&hookquery.GetDeliveriesQuery{WebhookID: id, Status: status})
+	if qerr != nil {
+		wc.respondQueryError(c, qerr)
+		return
+	}
+
+	var responses = make([]DeliveryResponseDTO, 0, len(deliveries))
+	for _, d := range deliveries {
+		responses = append(responses, newDeliveryResponseDTO(d))
+	}
+
+	c.IndentedJSON(200, responses)
+}
+
+// respondQueryError writes the error returned by a query handler.
+func (wc *WebhookController) respondQueryError(c *gin.Context, err error) {
+	if customErr, ok := err.(ierr.IErr); ok {
+		e := errapi.Map(customErr)
+		c.IndentedJSON(e.StatusCode(), errorBody(e))
+		return
+	}
+	c.IndentedJSON(500, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/domain/model/webhook"
+	"github.com/google/uuid"
+)
+
+// WebhookDTO represents the data structure for creating or updating a webhook.
+type WebhookDTO struct {
+	URL    string   `json:"url" binding:"required"` // URL the deliveries are posted to; required
+	Events []string `json:"events"`                 // Types of the events delivered, e.g. person.created; at least one
+}
+
+// WebhookResponseDTO defines the data structure for returning webhooks in responses.
+// The secret is never part of it.
+type WebhookResponseDTO struct {
+	ID        uuid.UUID `json:"id"`         // Unique identifier of the webhook
+	URL       string    `json:"url"`        // URL the deliveries are posted to
+	Events    []string  `json:"events"`     // Types of the events delivered
+	CreatedAt time.Time `json:"created_at"` // Time the webhook was created
+	UpdatedAt time.Time `json:"updated_at"` // Time the webhook was last changed
+}
+
+// CreatedWebhookResponseDTO is returned once when a webhook is created, along with its secret.
+type CreatedWebhookResponseDTO struct {
+	WebhookResponseDTO
+	Secret string `json:"secret"` // Secret signing the deliveries; never shown again
+}
+
+// DeliveryResponseDTO defines the data structure for returning the deliveries of a webhook in responses.
+type DeliveryResponseDTO struct {
+	ID            uuid.UUID  `json:"id"`                        // Unique identifier of the delivery, sent in the X-Webhook-Delivery header
+	Event         string     `json:"event"`                     // Type of the delivered event
+	EventID       uint64     `json:"event_id"`                  // Sequence number of the delivered event
+	Status        string     `json:"status"`                    // pending, succeeded or dead_lettered
+	Attempts      int        `json:"attempts"`                  // Number of times the delivery was attempted
+	StatusCode    int        `json:"status_code,omitempty"`     // Status code of the response to the last attempt
+	LastError     string     `json:"last_error,omitempty"`      // Reason the last attempt failed
+	CreatedAt     time.Time  `json:"created_at"`                // Time the delivery was queued
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"` // Time of the next attempt of a pending delivery
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`    // Time the receiver acknowledged the delivery
+}
+
+// newWebhookResponseDTO maps a Webhook to its WebhookResponseDTO.
+func newWebhookResponseDTO(w *webhook.Webhook) WebhookResponseDTO {
+	return WebhookResponseDTO{
+		ID:        w.Id(),
+		URL:       w.URL(),
+		Events:    w.Events(),
+		CreatedAt: w.CreatedAt(),
+		UpdatedAt: w.UpdatedAt(),
+	}
+}
+
+// newDeliveryResponseDTO maps a Delivery to its DeliveryResponseDTO.
+func newDeliveryResponseDTO(d *webhook.Delivery) DeliveryResponseDTO {
+	dto := DeliveryResponseDTO{
+		ID:          d.Id(),
+		Event:       d.Event(),
+		EventID:     d.EventID(),
+		Status:      string(d.Status()),
+		Attempts:    d.Attempts(),
+		StatusCode:  d.StatusCode(),
+		LastError:   d.LastError(),
+		CreatedAt:   d.CreatedAt(),
+		DeliveredAt: d.DeliveredAt(),
+	}
+	if d.Status() == webhook.Pending {
+		next := d.NextAttemptAt()
+		dto.NextAttemptAt = &next
+	}
+	return dto
+}
@@ -0,0 +1,30 @@
+// Package docs serves the OpenAPI document of the API, maintained alongside the controllers, and a Swagger UI to browse it.
+package docs
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPI is the OpenAPI 3.1 document describing every route of the API.
+// It should be updated along with the routes and the DTOs of api/controller.
+//
+//go:embed openapi.json
+var OpenAPI []byte
+
+// swaggerUI is the page rendering the OpenAPI document with Swagger UI.
+//
+//go:embed swagger.html
+var swaggerUI []byte
+
+// Spec serves the OpenAPI document.
+func Spec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", OpenAPI)
+}
+
+// UI serves the Swagger UI page browsing the OpenAPI document.
+func UI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", swaggerUI)
+}
@@ -13,12 +13,16 @@ const (
 	Authentication = 401 // Unauthorized
 	Forbidden      = 403 // Forbidden
 	NotFound       = 404 // Not Found
+	NotAcceptable  = 406 // Not Acceptable
+	TooLarge       = 413 // Content Too Large
+	Unsupported    = 415 // Unsupported Media Type
 )
 
 // Error represents an API error with an associated HTTP status code and message.
 type Error struct {
 	statusCode int    // HTTP status code for the error
 	message    string // Detailed error message
+	existingID string // ID of the existing resource a 409 Conflict refers to, when known
 }
 
 // NewBadRequest creates a new Error with a 400 Bad Request status code
@@ -57,6 +61,24 @@ func NewForbidden(message string) Error {
 	return Error{statusCode: Forbidden, message: message}
 }
 
+// NewNotAcceptable creates a new Error with a 406 Not Acceptable status code
+// and the provided message.
+func NewNotAcceptable(message string) Error {
+	return Error{statusCode: NotAcceptable, message: message}
+}
+
+// NewTooLarge creates a new Error with a 413 Content Too Large status code
+// and the provided message.
+func NewTooLarge(message string) Error {
+	return Error{statusCode: TooLarge, message: message}
+}
+
+// NewUnsupported creates a new Error with a 415 Unsupported Media Type status code
+// and the provided message.
+func NewUnsupported(message string) Error {
+	return Error{statusCode: Unsupported, message: message}
+}
+
 // Error returns the error message as a string.
 func (e Error) Error() string {
 	return e.message
@@ -67,6 +89,11 @@ func (e Error) StatusCode() int {
 	return e.statusCode
 }
 
+// ExistingID returns the ID of the existing resource a 409 Conflict refers to, or an empty string.
+func (e Error) ExistingID() string {
+	return e.existingID
+}
+
 func Map(err ierr.IErr) Error {
 	switch err.Type() {
 	case ierr.NotFound:
@@ -74,11 +101,17 @@ func Map(err ierr.IErr) Error {
 	case ierr.Validation:
 		return NewBadRequest(err.Error())
 	case ierr.Conflict:
-		return NewConflict(err.Error())
+		e := NewConflict(err.Error())
+		if existing, ok := err.(interface{ ExistingID() string }); ok {
+			e.existingID = existing.ExistingID()
+		}
+		return e
 	case ierr.Unexpected:
 		return NewServerError(err.Error())
 	case apperror.Authentication:
 		return NewAuthentication(err.Error())
+	case apperror.Forbidden, apperror.QuotaExceeded:
+		return NewForbidden(err.Error())
 	default:
 		return NewServerError("unknown error occurred while patching expense")
 	}
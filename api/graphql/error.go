@@ -0,0 +1,55 @@
+package graphqlapi
+
+import (
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+)
+
+// codes maps the HTTP status of errors to the code reported in their extensions.
+var codes = map[int]string{
+	errapi.BadRequest:     "BAD_USER_INPUT",
+	errapi.Authentication: "UNAUTHENTICATED",
+	errapi.Forbidden:      "FORBIDDEN",
+	errapi.NotFound:       "NOT_FOUND",
+	errapi.Conflict:       "CONFLICT",
+	errapi.ServerError:    "INTERNAL_SERVER_ERROR",
+}
+
+// Error is an error of a resolver, reported with its code and the HTTP status the /person routes respond
+// with in the extensions of the GraphQL error.
+type Error struct {
+	err errapi.Error
+}
+
+// Map maps an error of the domain or application layers to a GraphQL error, through the HTTP status errapi.Map maps it to.
+func Map(err ierr.IErr) *Error {
+	return &Error{err: errapi.Map(err)}
+}
+
+// Error returns the error message as a string.
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// Extensions returns the extensions of the GraphQL error: its code, its HTTP status, and the ID of the
+// existing person a conflict refers to, when known.
+func (e *Error) Extensions() map[string]any {
+	extensions := map[string]any{"code": codes[e.err.StatusCode()], "status": e.err.StatusCode()}
+	if id := e.err.ExistingID(); id != "" {
+		extensions["existingId"] = id
+	}
+	return extensions
+}
+
+// mapError maps the error returned by a handler, which may not be an ierr.IErr.
+func mapError(err error) error {
+	if customErr, ok := err.(ierr.IErr); ok {
+		return Map(customErr)
+	}
+	return &Error{err: errapi.NewServerError(err.Error())}
+}
+
+// badInput returns a GraphQL error for invalid arguments.
+func badInput(message string) error {
+	return &Error{err: errapi.NewBadRequest(message)}
+}
@@ -0,0 +1,195 @@
+package graphqlapi
+
+import (
+	"math"
+
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// PersonResolver resolves the fields of a Person.
+type PersonResolver struct {
+	person *model.Person
+}
+
+// ID resolves the unique identifier of the person.
+func (r *PersonResolver) ID() graphql.ID {
+	return graphql.ID(r.person.Id().String())
+}
+
+// Name resolves the name of the person.
+func (r *PersonResolver) Name() string {
+	return r.person.Name()
+}
+
+// Age resolves the age of the person.
+func (r *PersonResolver) Age() int32 {
+	return int32(r.person.Age())
+}
+
+// Email resolves the email of the person, null when not set.
+func (r *PersonResolver) Email() *string {
+	return optional(r.person.Email().String())
+}
+
+// Phone resolves the phone number of the person, null when not set.
+func (r *PersonResolver) Phone() *string {
+	return optional(r.person.Phone().String())
+}
+
+// Owner resolves the subject of the principal owning the person, null when not owned.
+func (r *PersonResolver) Owner() *string {
+	return optional(r.person.Owner())
+}
+
+// CreatedAt resolves the time the person was created.
+func (r *PersonResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.person.CreatedAt()}
+}
+
+// UpdatedAt resolves the time the person was last changed.
+func (r *PersonResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.person.UpdatedAt()}
+}
+
+// Hobbies resolves the hobbies of the person, an empty list when they have none.
+func (r *PersonResolver) Hobbies() []string {
+	if hobbies := r.person.Hobbies(); hobbies != nil {
+		return hobbies
+	}
+	return []string{}
+}
+
+// Address resolves the postal address of the person, null when they have none.
+func (r *PersonResolver) Address() *AddressResolver {
+	if a := r.person.Address(); a != nil {
+		return &AddressResolver{address: a}
+	}
+	return nil
+}
+
+// Attributes resolves the custom attributes of the person, null when they have none.
+func (r *PersonResolver) Attributes() *JSON {
+	if attributes := r.person.Attributes(); len(attributes) > 0 {
+		return &JSON{Value: attributes}
+	}
+	return nil
+}
+
+// AddressResolver resolves the fields of an Address.
+type AddressResolver struct {
+	address *model.Address
+}
+
+// Street resolves the street line of the address.
+func (r *AddressResolver) Street() string {
+	return r.address.Street()
+}
+
+// City resolves the city of the address.
+func (r *AddressResolver) City() string {
+	return r.address.City()
+}
+
+// Region resolves the region of the address, null when not set.
+func (r *AddressResolver) Region() *string {
+	return optional(r.address.Region())
+}
+
+// PostalCode resolves the postal code of the address, null when not set.
+func (r *AddressResolver) PostalCode() *string {
+	return optional(r.address.PostalCode())
+}
+
+// Country resolves the country code of the address.
+func (r *AddressResolver) Country() string {
+	return r.address.Country()
+}
+
+// PageResolver resolves the fields of a PersonPage.
+type PageResolver struct {
+	nodes  []*PersonResolver
+	total  int32
+	limit  int32
+	offset int32
+}
+
+// Nodes resolves the people of the page.
+func (r *PageResolver) Nodes() []*PersonResolver {
+	if r.nodes == nil {
+		return []*PersonResolver{}
+	}
+	return r.nodes
+}
+
+// Total resolves the number of people matching the filter, across every page.
+func (r *PageResolver) Total() int32 {
+	return r.total
+}
+
+// Limit resolves the maximum number of people in the page.
+func (r *PageResolver) Limit() int32 {
+	return r.limit
+}
+
+// Offset resolves the number of people preceding the page.
+func (r *PageResolver) Offset() int32 {
+	return r.offset
+}
+
+// toAddressConfig maps an AddressInput to the configuration of an Address, returning nil when there is no address.
+func (a *AddressInput) toAddressConfig() *model.AddressConfig {
+	if a == nil {
+		return nil
+	}
+	return &model.AddressConfig{
+		Street:     a.Street,
+		City:       a.City,
+		Region:     deref(a.Region),
+		PostalCode: deref(a.PostalCode),
+		Country:    a.Country,
+	}
+}
+
+// toMap returns the attributes of the input, or nil when there are none.
+func (j *JSON) toMap() map[string]any {
+	if j == nil {
+		return nil
+	}
+	return j.Value
+}
+
+// parseID parses the ID of a person given as an argument.
+func parseID(id graphql.ID) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, badInput("invalid id format")
+	}
+	return parsed, nil
+}
+
+// parseAge narrows the age given as an argument to the range of ages of people.
+func parseAge(age int32) (int16, error) {
+	if age < math.MinInt16 || age > math.MaxInt16 {
+		return 0, badInput("invalid age")
+	}
+	return int16(age), nil
+}
+
+// optional returns nil for empty strings, which resolve to null.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// deref returns the value of an optional argument, or its zero value when omitted.
+func deref[T any](v *T) T {
+	var zero T
+	if v == nil {
+		return zero
+	}
+	return *v
+}
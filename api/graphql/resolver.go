@@ -0,0 +1,273 @@
+package graphqlapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/Efamamo/GoCrudChallange/application/people/query"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// maxPageLimit is the maximum number of people in a page of the people query, which holds 20 by default.
+const maxPageLimit = 100
+
+// Resolver resolves the queries and mutations of the schema with the same command and query handlers
+// as the PersonController.
+type Resolver struct {
+	Authorizer *auth.Authorizer // Checks the permissions of callers; every caller is allowed when nil
+
+	CreateHandler icmd.IHandler[*command.CreatePersonCommand, *model.Person]
+	UpdateHandler icmd.IHandler[*command.UpdatePersonCommand, *model.Person]
+	DeleteHandler icmd.IHandler[*command.DeletePersonCommand, bool]
+	GetHandler    iquery.IHandler[*query.GetPersonQuery, *model.Person]
+	GetAllHandler iquery.IHandler[*query.GetPeopleQuery, []*model.Person]
+}
+
+// PeopleFilter holds the arguments of the filter of the people query.
+type PeopleFilter struct {
+	IDs        *[]graphql.ID
+	Hobby      *string
+	Attributes *[]AttributeFilter
+}
+
+// AttributeFilter holds a custom attribute people should have.
+type AttributeFilter struct {
+	Name  string
+	Value string
+}
+
+// PersonInput holds the details of a person to create or update.
+type PersonInput struct {
+	Name       string
+	Age        int32
+	Hobbies    *[]string
+	Email      *string
+	Phone      *string
+	Address    *AddressInput
+	Attributes *JSON
+}
+
+// AddressInput holds the postal address of a person to create or update.
+type AddressInput struct {
+	Street     string
+	City       string
+	Region     *string
+	PostalCode *string
+	Country    string
+}
+
+// authorize checks that the caller holds the permission before a command or query handler is invoked.
+func (r *Resolver) authorize(ctx context.Context, permission auth.Permission) error {
+	if r.Authorizer == nil {
+		return nil
+	}
+	if err := r.Authorizer.Authorize(ctx, permission); err != nil {
+		return Map(err)
+	}
+	return nil
+}
+
+// Person resolves the person query. People that don't exist, or that the caller may not access, resolve to null.
+func (r *Resolver) Person(ctx context.Context, args struct{ ID graphql.ID }) (*PersonResolver, error) {
+	if err := r.authorize(ctx, auth.PersonRead); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := r.GetHandler.Handle(&query.GetPersonQuery{Context: ctx, ID: id})
+	if customErr, ok := err.(ierr.IErr); ok && customErr.Type() == ierr.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &PersonResolver{person: p}, nil
+}
+
+// People resolves the people query.
+func (r *Resolver) People(ctx context.Context, args struct {
+	Filter *PeopleFilter
+	Limit  int32
+	Offset int32
+}) (*PageResolver, error) {
+	if err := r.authorize(ctx, auth.PersonRead); err != nil {
+		return nil, err
+	}
+
+	if args.Limit <= 0 || args.Limit > maxPageLimit {
+		return nil, badInput(fmt.Sprintf("limit should be an integer between 1 and %d", maxPageLimit))
+	}
+	if args.Offset < 0 {
+		return nil, badInput("offset should be a non-negative integer")
+	}
+	page := &PageResolver{limit: args.Limit, offset: args.Offset}
+
+	q := &query.GetPeopleQuery{Context: ctx}
+	if args.Filter != nil && args.Filter.Attributes != nil {
+		q.Attributes = make(map[string]string, len(*args.Filter.Attributes))
+		for _, attribute := range *args.Filter.Attributes {
+			q.Attributes[attribute.Name] = attribute.Value
+		}
+	}
+
+	people, err := r.GetAllHandler.Handle(q)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if args.Filter != nil {
+		if people, err = args.Filter.apply(people); err != nil {
+			return nil, err
+		}
+	}
+
+	page.total = int32(len(people))
+	start := min(int(page.offset), len(people))
+	end := min(start+int(page.limit), len(people))
+	for _, p := range people[start:end] {
+		page.nodes = append(page.nodes, &PersonResolver{person: p})
+	}
+	return page, nil
+}
+
+// apply keeps the people with one of the IDs and the hobby of the filter, when set.
+func (f *PeopleFilter) apply(people []*model.Person) ([]*model.Person, error) {
+	var ids map[uuid.UUID]bool
+	if f.IDs != nil {
+		ids = make(map[uuid.UUID]bool, len(*f.IDs))
+		for _, raw := range *f.IDs {
+			id, err := parseID(raw)
+			if err != nil {
+				return nil, err
+			}
+			ids[id] = true
+		}
+	}
+
+	filtered := make([]*model.Person, 0, len(people))
+	for _, p := range people {
+		if ids != nil && !ids[p.Id()] {
+			continue
+		}
+		if f.Hobby != nil && !hasHobby(p, *f.Hobby) {
+			continue
+		}
+		filtered = append(filtered, p)
+	}
+	return filtered, nil
+}
+
+// hasHobby reports whether the person has the hobby, compared case-insensitively.
+func hasHobby(p *model.Person, hobby string) bool {
+	for _, h := range p.Hobbies() {
+		if strings.EqualFold(h, hobby) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreatePerson resolves the createPerson mutation.
+func (r *Resolver) CreatePerson(ctx context.Context, args struct {
+	ID    *graphql.ID
+	Input PersonInput
+}) (*PersonResolver, error) {
+	if err := r.authorize(ctx, auth.PersonWrite); err != nil {
+		return nil, err
+	}
+
+	id := uuid.Nil
+	if args.ID != nil {
+		var err error
+		if id, err = parseID(*args.ID); err != nil {
+			return nil, err
+		}
+	}
+	age, err := parseAge(args.Input.Age)
+	if err != nil {
+		return nil, err
+	}
+
+	p, cerr := r.CreateHandler.Handle(&command.CreatePersonCommand{
+		Context: ctx,
+
+		ID:      id,
+		Name:    args.Input.Name,
+		Age:     age,
+		Hobbies: deref(args.Input.Hobbies),
+		Email:   deref(args.Input.Email),
+		Phone:   deref(args.Input.Phone),
+		Address: args.Input.Address.toAddressConfig(),
+
+		Attributes: args.Input.Attributes.toMap(),
+	})
+	if cerr != nil {
+		return nil, Map(cerr)
+	}
+	return &PersonResolver{person: p}, nil
+}
+
+// UpdatePerson resolves the updatePerson mutation.
+func (r *Resolver) UpdatePerson(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input PersonInput
+}) (*PersonResolver, error) {
+	if err := r.authorize(ctx, auth.PersonWrite); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	age, err := parseAge(args.Input.Age)
+	if err != nil {
+		return nil, err
+	}
+
+	p, cerr := r.UpdateHandler.Handle(&command.UpdatePersonCommand{
+		Context: ctx,
+
+		ID:      id,
+		Name:    args.Input.Name,
+		Age:     age,
+		Hobbies: deref(args.Input.Hobbies),
+		Email:   deref(args.Input.Email),
+		Phone:   deref(args.Input.Phone),
+		Address: args.Input.Address.toAddressConfig(),
+
+		Attributes: args.Input.Attributes.toMap(),
+	})
+	if cerr != nil {
+		return nil, Map(cerr)
+	}
+	return &PersonResolver{person: p}, nil
+}
+
+// DeletePerson resolves the deletePerson mutation.
+func (r *Resolver) DeletePerson(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	if err := r.authorize(ctx, auth.PersonDelete); err != nil {
+		return false, err
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if _, cerr := r.DeleteHandler.Handle(&command.DeletePersonCommand{Context: ctx, ID: id}); cerr != nil {
+		return false, Map(cerr)
+	}
+	return true, nil
+}
@@ -0,0 +1,31 @@
+package graphqlapi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// JSON implements the JSON scalar of the schema, holding the custom attributes of a person.
+type JSON struct {
+	Value map[string]any
+}
+
+// ImplementsGraphQLType maps the type to the JSON scalar.
+func (JSON) ImplementsGraphQLType(name string) bool {
+	return name == "JSON"
+}
+
+// UnmarshalGraphQL reads an object given as an argument.
+func (j *JSON) UnmarshalGraphQL(input any) error {
+	value, ok := input.(map[string]any)
+	if !ok {
+		return fmt.Errorf("JSON should be an object, got %T", input)
+	}
+	j.Value = value
+	return nil
+}
+
+// MarshalJSON writes the object in responses.
+func (j JSON) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Value)
+}
@@ -0,0 +1,20 @@
+package graphqlapi
+
+import (
+	_ "embed"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+// maxDepth is the maximum nesting of the selections of a query.
+const maxDepth = 10
+
+// schema is the GraphQL schema of the API.
+//
+//go:embed schema.graphql
+var schema string
+
+// NewSchema parses the schema of the API, resolved by the resolver.
+func NewSchema(resolver *Resolver) (*graphql.Schema, error) {
+	return graphql.ParseSchema(schema, resolver, graphql.MaxDepth(maxDepth))
+}
@@ -0,0 +1,92 @@
+package grpcapi
+
+import (
+	"math"
+
+	"github.com/Efamamo/GoCrudChallange/api/grpc/personpb"
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// eventTypes maps the types of events to their protobuf counterparts.
+var eventTypes = map[ievents.EventType]personpb.PersonEvent_Type{
+	ievents.PersonCreated: personpb.PersonEvent_CREATED,
+	ievents.PersonUpdated: personpb.PersonEvent_UPDATED,
+	ievents.PersonDeleted: personpb.PersonEvent_DELETED,
+}
+
+// newPerson maps a Person to its protobuf message.
+func newPerson(p *model.Person) (*personpb.Person, error) {
+	attributes, err := structpb.NewStruct(p.Attributes())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "attributes of person %s can't be encoded: %v", p.Id(), err)
+	}
+
+	return &personpb.Person{
+		Id:      p.Id().String(),
+		Name:    p.Name(),
+		Age:     int32(p.Age()),
+		Hobbies: p.Hobbies(),
+		Email:   p.Email().String(),
+		Phone:   p.Phone().String(),
+		Address: newAddress(p.Address()),
+
+		Attributes: attributes,
+		Owner:      p.Owner(),
+		CreatedAt:  timestamppb.New(p.CreatedAt()),
+		UpdatedAt:  timestamppb.New(p.UpdatedAt()),
+	}, nil
+}
+
+// newAddress maps an Address to its protobuf message, returning nil when there is no address.
+func newAddress(a *model.Address) *personpb.Address {
+	if a == nil {
+		return nil
+	}
+	return &personpb.Address{
+		Street:     a.Street(),
+		City:       a.City(),
+		Region:     a.Region(),
+		PostalCode: a.PostalCode(),
+		Country:    a.Country(),
+	}
+}
+
+// addressConfig maps an Address message to the configuration of an Address, returning nil when there is no address.
+func addressConfig(a *personpb.Address) *model.AddressConfig {
+	if a == nil {
+		return nil
+	}
+	return &model.AddressConfig{
+		Street:     a.GetStreet(),
+		City:       a.GetCity(),
+		Region:     a.GetRegion(),
+		PostalCode: a.GetPostalCode(),
+		Country:    a.GetCountry(),
+	}
+}
+
+// parseID parses the ID of a request; an empty ID is parsed as uuid.Nil when optional.
+func parseID(id string, optional bool) (uuid.UUID, error) {
+	if id == "" && optional {
+		return uuid.Nil, nil
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid id format")
+	}
+	return parsed, nil
+}
+
+// parseAge narrows the age of a request to the range of ages of people.
+func parseAge(age int32) (int16, error) {
+	if age < math.MinInt16 || age > math.MaxInt16 {
+		return 0, status.Error(codes.InvalidArgument, "invalid age")
+	}
+	return int16(age), nil
+}
@@ -0,0 +1,46 @@
+package grpcapi
+
+import (
+	apperror "github.com/Efamamo/GoCrudChallange/application/error"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Map maps an error of the domain or application layers to a gRPC status, the way errapi.Map maps it
+// to an HTTP status. Conflicts caused by an existing person carry its ID in a ResourceInfo detail.
+func Map(err ierr.IErr) error {
+	switch err.Type() {
+	case ierr.NotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case ierr.Validation:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case ierr.Conflict:
+		s := status.New(codes.AlreadyExists, err.Error())
+		if existing, ok := err.(interface{ ExistingID() string }); ok && existing.ExistingID() != "" {
+			if detailed, derr := s.WithDetails(&errdetails.ResourceInfo{ResourceType: "person", ResourceName: existing.ExistingID()}); derr == nil {
+				s = detailed
+			}
+		}
+		return s.Err()
+	case ierr.Unexpected:
+		return status.Error(codes.Internal, err.Error())
+	case apperror.Authentication:
+		return status.Error(codes.Unauthenticated, err.Error())
+	case apperror.Forbidden:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case apperror.QuotaExceeded:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Error(codes.Internal, "unknown error occurred")
+	}
+}
+
+// mapError maps the error returned by a query handler, which may not be an ierr.IErr.
+func mapError(err error) error {
+	if customErr, ok := err.(ierr.IErr); ok {
+		return Map(customErr)
+	}
+	return status.Error(codes.Internal, err.Error())
+}
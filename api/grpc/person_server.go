@@ -0,0 +1,219 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/api/grpc/personpb"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/Efamamo/GoCrudChallange/application/people/query"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// PersonServer implements the PersonService of person.proto with the same command and query handlers
+// as the PersonController.
+type PersonServer struct {
+	personpb.UnimplementedPersonServiceServer
+
+	Authorizer *auth.Authorizer // Checks the permissions of callers; every caller is allowed when nil
+
+	CreateHandler icmd.IHandler[*command.CreatePersonCommand, *model.Person]
+	UpdateHandler icmd.IHandler[*command.UpdatePersonCommand, *model.Person]
+	DeleteHandler icmd.IHandler[*command.DeletePersonCommand, bool]
+	GetHandler    iquery.IHandler[*query.GetPersonQuery, *model.Person]
+	GetAllHandler iquery.IHandler[*query.GetPeopleQuery, []*model.Person]
+
+	Events ievents.IBroker // Source of the changes streamed by Watch; Watch is unavailable when nil
+}
+
+// authorize checks that the caller holds the permission before a command or query handler is invoked.
+func (s *PersonServer) authorize(ctx context.Context, permission auth.Permission) error {
+	if s.Authorizer == nil {
+		return nil
+	}
+	if err := s.Authorizer.Authorize(ctx, permission); err != nil {
+		return Map(err)
+	}
+	return nil
+}
+
+// Create creates a person owned by the caller, with the optional ID of the request or a new one.
+func (s *PersonServer) Create(ctx context.Context, req *personpb.CreatePersonRequest) (*personpb.Person, error) {
+	if err := s.authorize(ctx, auth.PersonWrite); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(req.GetId(), true)
+	if err != nil {
+		return nil, err
+	}
+	input := req.GetPerson()
+	age, err := parseAge(input.GetAge())
+	if err != nil {
+		return nil, err
+	}
+
+	p, cerr := s.CreateHandler.Handle(&command.CreatePersonCommand{
+		Context: ctx,
+
+		ID:      id,
+		Name:    input.GetName(),
+		Age:     age,
+		Hobbies: input.GetHobbies(),
+		Email:   input.GetEmail(),
+		Phone:   input.GetPhone(),
+		Address: addressConfig(input.GetAddress()),
+
+		Attributes: input.GetAttributes().AsMap(),
+	})
+	if cerr != nil {
+		return nil, Map(cerr)
+	}
+	return newPerson(p)
+}
+
+// Get returns a person by their ID.
+func (s *PersonServer) Get(ctx context.Context, req *personpb.GetPersonRequest) (*personpb.Person, error) {
+	if err := s.authorize(ctx, auth.PersonRead); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(req.GetId(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.GetHandler.Handle(&query.GetPersonQuery{Context: ctx, ID: id})
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return newPerson(p)
+}
+
+// List returns the people the caller may access, filtered by the custom attributes of the request.
+func (s *PersonServer) List(ctx context.Context, req *personpb.ListPeopleRequest) (*personpb.ListPeopleResponse, error) {
+	if err := s.authorize(ctx, auth.PersonRead); err != nil {
+		return nil, err
+	}
+
+	people, err := s.GetAllHandler.Handle(&query.GetPeopleQuery{Context: ctx, Attributes: req.GetAttributes()})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	response := &personpb.ListPeopleResponse{People: make([]*personpb.Person, 0, len(people))}
+	for _, p := range people {
+		person, err := newPerson(p)
+		if err != nil {
+			return nil, err
+		}
+		response.People = append(response.People, person)
+	}
+	return response, nil
+}
+
+// Update replaces the details of a person.
+func (s *PersonServer) Update(ctx context.Context, req *personpb.UpdatePersonRequest) (*personpb.Person, error) {
+	if err := s.authorize(ctx, auth.PersonWrite); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(req.GetId(), false)
+	if err != nil {
+		return nil, err
+	}
+	input := req.GetPerson()
+	age, err := parseAge(input.GetAge())
+	if err != nil {
+		return nil, err
+	}
+
+	p, cerr := s.UpdateHandler.Handle(&command.UpdatePersonCommand{
+		Context: ctx,
+
+		ID:      id,
+		Name:    input.GetName(),
+		Age:     age,
+		Hobbies: input.GetHobbies(),
+		Email:   input.GetEmail(),
+		Phone:   input.GetPhone(),
+		Address: addressConfig(input.GetAddress()),
+
+		Attributes: input.GetAttributes().AsMap(),
+	})
+	if cerr != nil {
+		return nil, Map(cerr)
+	}
+	return newPerson(p)
+}
+
+// Delete deletes a person, along with every relation they take part in.
+func (s *PersonServer) Delete(ctx context.Context, req *personpb.DeletePersonRequest) (*personpb.DeletePersonResponse, error) {
+	if err := s.authorize(ctx, auth.PersonDelete); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(req.GetId(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, cerr := s.DeleteHandler.Handle(&command.DeletePersonCommand{Context: ctx, ID: id}); cerr != nil {
+		return nil, Map(cerr)
+	}
+	return &personpb.DeletePersonResponse{}, nil
+}
+
+// Watch streams the changes of the people of the tenant that the caller may access, until the call is cancelled.
+// The response headers are sent once the subscription is in place, so clients waiting for them miss no change.
+// Callers that fall too far behind get an Unavailable status, and should call Watch again.
+func (s *PersonServer) Watch(req *personpb.WatchPeopleRequest, stream personpb.PersonService_WatchServer) error {
+	ctx := stream.Context()
+	if err := s.authorize(ctx, auth.PersonRead); err != nil {
+		return err
+	}
+	if s.Events == nil {
+		return status.Error(codes.Unimplemented, "changes of people are not published")
+	}
+
+	types := make(map[personpb.PersonEvent_Type]bool, len(req.GetTypes()))
+	for _, t := range req.GetTypes() {
+		types[t] = true
+	}
+
+	events := s.Events.Subscribe(ctx, 0)
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for event := range events {
+		eventType := eventTypes[event.Type]
+		if event.Tenant != tenant.From(ctx) || (len(types) > 0 && !types[eventType]) {
+			continue
+		}
+		if !s.Authorizer.CanAccess(ctx, event.Person.Owner()) {
+			continue
+		}
+
+		person, err := newPerson(event.Person)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&personpb.PersonEvent{Type: eventType, Person: person, OccurredAt: timestamppb.New(event.OccurredAt)}); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Unavailable, "watcher fell behind, call Watch again")
+}
@@ -0,0 +1,185 @@
+package grpcapi
+
+import (
+	"context"
+	"log"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/Efamamo/GoCrudChallange/api/grpc/personpb"
+	"github.com/Efamamo/GoCrudChallange/api/middleware"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// ServerConfig holds configuration settings for creating the gRPC server.
+type ServerConfig struct {
+	Person         *PersonServer              // Implementation of the PersonService
+	Authentication *middleware.Authentication // Authenticates the API key or bearer token of calls; calls are open when nil
+	Tenancy        *middleware.Tenancy        // Resolves the tenant of calls; every call uses the default tenant when nil
+	RateLimit      *middleware.RateLimit      // Quota of each client, shared with the /person routes; calls aren't limited when nil
+	AddressLimit   *middleware.RateLimit      // Quota of each address, taken before authentication and shared with the HTTP routes
+	Reflection     bool                       // Whether the reflection service describes the services to clients such as grpcurl
+}
+
+// NewServer creates a gRPC server serving the PersonService, along with the reflection service so that
+// tools such as grpcurl can discover it when the configuration enables it. Calls carry their credentials and tenant in the same metadata
+// keys as the headers of HTTP requests, e.g. "authorization", "x-api-key" and "x-tenant-id".
+func NewServer(config ServerConfig) *grpc.Server {
+	i := interceptor{authentication: config.Authentication, tenancy: config.Tenancy, rateLimit: config.RateLimit, addressLimit: config.AddressLimit}
+	server := grpc.NewServer(grpc.UnaryInterceptor(i.unary), grpc.StreamInterceptor(i.stream))
+
+	personpb.RegisterPersonServiceServer(server, config.Person)
+	if config.Reflection {
+		reflection.Register(server)
+	}
+	return server
+}
+
+// interceptor limits the calls of each address, then stores the principal and the tenant of each call in
+// its context, as the authentication and tenancy middleware do for HTTP requests, then limits the calls of
+// each client as the rate limit middleware does.
+type interceptor struct {
+	authentication *middleware.Authentication
+	tenancy        *middleware.Tenancy
+	rateLimit      *middleware.RateLimit
+	addressLimit   *middleware.RateLimit
+}
+
+// unary intercepts unary calls.
+func (i interceptor) unary(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	identified, header, err := i.admit(ctx)
+	if header != nil {
+		grpc.SetHeader(ctx, header)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return handler(identified, req)
+}
+
+// stream intercepts streaming calls.
+func (i interceptor) stream(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	identified, header, err := i.admit(ss.Context())
+	if header != nil {
+		ss.SetHeader(header)
+	}
+	if err != nil {
+		return err
+	}
+	return handler(srv, &identifiedStream{ServerStream: ss, ctx: identified})
+}
+
+// admit limits the calls of the address before checking the credentials of the call, so that floods of
+// invalid credentials are turned away before they are verified, then identifies the caller and limits
+// their calls. It returns the context of the identified call, and the rate limit headers of the last quota taken.
+func (i interceptor) admit(ctx context.Context) (context.Context, metadata.MD, error) {
+	header, err := i.limit(ctx, i.addressLimit, "ip:"+address(ctx))
+	if err != nil {
+		return nil, header, err
+	}
+
+	ctx, err = i.identify(ctx)
+	if err != nil {
+		return nil, header, err
+	}
+
+	if clientHeader, err := i.limit(ctx, i.rateLimit, middleware.ClientKeyOf(ctx, address(ctx))); clientHeader != nil || err != nil {
+		return ctx, clientHeader, err
+	}
+	return ctx, header, nil
+}
+
+// limit takes a token from the bucket of the client in the rate limit, keyed as the rate limit middleware keys
+// the clients of HTTP requests. It returns the rate limit headers of the call, and a ResourceExhausted error when
+// the quota is exceeded. When there is no rate limit or the store fails, calls are let through rather than rejected.
+func (i interceptor) limit(ctx context.Context, rateLimit *middleware.RateLimit, client string) (metadata.MD, error) {
+	if rateLimit == nil {
+		return nil, nil
+	}
+
+	result, err := rateLimit.Take(ctx, client)
+	if err != nil {
+		log.Printf("rate limit of %s unavailable: %v", client, err)
+		return nil, nil
+	}
+
+	header := metadata.Pairs(
+		"x-ratelimit-limit", strconv.Itoa(result.Limit),
+		"x-ratelimit-remaining", strconv.Itoa(result.Remaining),
+		"x-ratelimit-reset", strconv.Itoa(int(math.Ceil(result.ResetAfter.Seconds()))),
+	)
+	if !result.Allowed {
+		header.Set("retry-after", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+		return header, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	return header, nil
+}
+
+// address returns the IP address the call comes from.
+func address(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// identify authenticates the caller and resolves the tenant of the call from its metadata.
+func (i interceptor) identify(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if i.authentication != nil && i.authentication.Enabled() {
+		principal, err := i.authentication.Authenticate(first(md, middleware.APIKeyHeader), first(md, "Authorization"))
+		if err != nil {
+			return nil, Map(err)
+		}
+		ctx = auth.WithPrincipal(ctx, principal)
+	}
+
+	id := tenant.Default
+	if i.tenancy != nil {
+		var header string
+		if i.tenancy.Header() != "" {
+			header = first(md, i.tenancy.Header())
+		}
+
+		resolved, err := i.tenancy.Resolve(ctx, first(md, ":authority"), header)
+		if err != nil {
+			return nil, Map(err)
+		}
+		id = resolved
+	}
+	return tenant.WithTenant(ctx, id), nil
+}
+
+// first returns the first value of the metadata key, whose name is case-insensitive like HTTP headers.
+func first(md metadata.MD, key string) string {
+	if values := md.Get(strings.ToLower(key)); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// identifiedStream is a server stream whose context carries the principal and the tenant of the call.
+type identifiedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the call.
+func (s *identifiedStream) Context() context.Context {
+	return s.ctx
+}
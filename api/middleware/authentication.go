@@ -0,0 +1,234 @@
+package middleware
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	apperror "github.com/Efamamo/GoCrudChallange/application/error"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// APIKeyHeader is the header carrying an API key, accepted as an alternative to a bearer token.
+const APIKeyHeader = "X-API-Key"
+
+// PrincipalKey is the key under which the authenticated Principal is stored in the Gin context.
+const PrincipalKey = "principal"
+
+// AuthenticationConfig holds the keys and expectations used to validate bearer tokens.
+type AuthenticationConfig struct {
+	HS256Secret  string   // Shared secret for HS256 tokens; HS256 is disabled when empty
+	RS256Key     string   // PEM encoded RSA public key, or a path to one, for RS256 tokens; RS256 is disabled when empty
+	Issuer       string   // Expected "iss" claim; not checked when empty
+	Audience     string   // Expected "aud" claim; not checked when empty
+	PublicRoutes []string // Routes that don't require a token, written as "METHOD /path/:param"
+	TenantClaim  string   // Claim naming the tenant of the caller; tenant.Claim when empty
+
+	APIKeys     icmd.IHandler[string, *auth.Principal] // Authenticates X-API-Key headers; API keys are rejected when nil
+	Revocations iquery.IHandler[string, bool]          // Reports whether a token ID was revoked; not checked when nil
+}
+
+// Authentication is a middleware validating HS256 and RS256 JWT bearer tokens.
+type Authentication struct {
+	secret    []byte
+	publicKey *rsa.PublicKey
+	parser    *jwt.Parser
+	public    map[string]struct{}
+	claim     string
+	apiKeys   icmd.IHandler[string, *auth.Principal]
+	revoked   iquery.IHandler[string, bool]
+}
+
+// NewAuthentication creates a new Authentication middleware with the given configuration.
+func NewAuthentication(config AuthenticationConfig) (*Authentication, error) {
+	a := &Authentication{public: make(map[string]struct{}), claim: config.TenantClaim, apiKeys: config.APIKeys, revoked: config.Revocations}
+	if a.claim == "" {
+		a.claim = tenant.Claim
+	}
+
+	methods := make([]string, 0, 2)
+	if config.HS256Secret != "" {
+		a.secret = []byte(config.HS256Secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if config.RS256Key != "" {
+		key, err := parseRSAPublicKey(config.RS256Key)
+		if err != nil {
+			return nil, err
+		}
+		a.publicKey = key
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	options := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+	a.parser = jwt.NewParser(options...)
+
+	for _, route := range config.PublicRoutes {
+		if route = strings.TrimSpace(route); route != "" {
+			method, path, _ := strings.Cut(route, " ")
+			a.Public(method, strings.TrimSpace(path))
+		}
+	}
+
+	return a, nil
+}
+
+// parseRSAPublicKey parses a PEM encoded RSA public key, reading it from a file when given a path.
+func parseRSAPublicKey(key string) (*rsa.PublicKey, error) {
+	pem := []byte(key)
+	if !strings.Contains(key, "-----BEGIN") {
+		content, err := os.ReadFile(key)
+		if err != nil {
+			return nil, fmt.Errorf("reading RS256 public key: %w", err)
+		}
+		pem = content
+	}
+
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+	if err != nil {
+		return nil, fmt.Errorf("parsing RS256 public key: %w", err)
+	}
+	return publicKey, nil
+}
+
+// Enabled reports whether at least one signing method, or API key authentication, is configured.
+func (a *Authentication) Enabled() bool {
+	return a.secret != nil || a.publicKey != nil || a.apiKeys != nil
+}
+
+// Public marks a route, as registered in the router (e.g. "GET", "/person/:id"), as not requiring a token.
+func (a *Authentication) Public(method string, path string) {
+	a.public[strings.ToUpper(method)+" "+path] = struct{}{}
+}
+
+// isPublic reports whether the matched route of the request was marked public.
+func (a *Authentication) isPublic(c *gin.Context) bool {
+	_, ok := a.public[c.Request.Method+" "+c.FullPath()]
+	return ok
+}
+
+// Handler returns the Gin middleware. Requests to non-public routes need a valid bearer token
+// or API key, otherwise they are rejected with a 401. The subject and claims of the token are stored as an
+// auth.Principal both in the Gin context and in the request context. Requests to public routes presenting
+// no credentials get the anonymous principal, whose permissions are granted by the policy.
+func (a *Authentication) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !a.Enabled() {
+			c.Next()
+			return
+		}
+
+		apiKey, authorization := c.GetHeader(APIKeyHeader), c.GetHeader("Authorization")
+		if a.isPublic(c) && apiKey == "" && authorization == "" {
+			principal := auth.Anonymous()
+			c.Set(PrincipalKey, principal)
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+			c.Next()
+			return
+		}
+
+		principal, err := a.Authenticate(apiKey, authorization)
+		if err != nil {
+			e := errapi.Map(err)
+			c.Header("WWW-Authenticate", `Bearer realm="api"`)
+			c.AbortWithStatusJSON(e.StatusCode(), gin.H{"error": e.Error()})
+			return
+		}
+
+		c.Set(PrincipalKey, principal)
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// Authenticate returns the principal of a caller presenting the API key, or else the bearer token of
+// the Authorization header. It lets transports other than HTTP, e.g. gRPC, authenticate their callers.
+func (a *Authentication) Authenticate(apiKey string, authorization string) (*auth.Principal, ierr.IErr) {
+	if apiKey != "" && a.apiKeys != nil {
+		return a.apiKeys.Handle(apiKey)
+	}
+	return a.authenticate(authorization)
+}
+
+// authenticate validates the bearer token of the Authorization header and returns its principal.
+func (a *Authentication) authenticate(header string) (*auth.Principal, ierr.IErr) {
+	if a.secret == nil && a.publicKey == nil {
+		return nil, apperror.InvalidCredential("missing api key")
+	}
+
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, apperror.InvalidCredential("missing bearer token")
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := a.parser.ParseWithClaims(token, claims, a.key); err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, apperror.InvalidCredential("token has expired")
+		}
+		return nil, apperror.InvalidCredential("invalid token")
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, apperror.InvalidCredential("token has no subject")
+	}
+
+	// Tokens ended by a logout carry their ID in the "jti" claim.
+	if id, ok := claims["jti"].(string); ok && id != "" && a.revoked != nil {
+		if revoked, err := a.revoked.Handle(id); err != nil || revoked {
+			return nil, apperror.InvalidCredential("token has been revoked")
+		}
+	}
+
+	belongs, _ := claims[a.claim].(string)
+	return &auth.Principal{Subject: subject, Claims: claims, Roles: roles(claims), Tenant: belongs}, nil
+}
+
+// roles reads the roles of the caller from the "roles" claim, or from a single "role" claim.
+func roles(claims jwt.MapClaims) []string {
+	roles := make([]string, 0)
+	switch value := claims["roles"].(type) {
+	case []any:
+		for _, role := range value {
+			if role, ok := role.(string); ok {
+				roles = append(roles, role)
+			}
+		}
+	case string:
+		roles = append(roles, strings.Fields(value)...)
+	}
+
+	if role, ok := claims["role"].(string); ok && role != "" {
+		roles = append(roles, role)
+	}
+	return roles
+}
+
+// key returns the key matching the signing method of the token.
+func (a *Authentication) key(token *jwt.Token) (any, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return a.secret, nil
+	case jwt.SigningMethodRS256.Alg():
+		return a.publicKey, nil
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecationConfig describes the retirement of a deprecated version of the API.
+type DeprecationConfig struct {
+	Sunset    time.Time // Time after which the version stops being served; not announced when zero
+	Successor string    // Path of the version replacing it, e.g. "/v2/person"; not announced when empty
+}
+
+// Deprecation returns a Gin middleware announcing that the routes it guards are deprecated,
+// with the Deprecation, Sunset and Link headers of RFC 9745 and RFC 8594.
+func Deprecation(config DeprecationConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		if !config.Sunset.IsZero() {
+			c.Header("Sunset", config.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if config.Successor != "" {
+			c.Header("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, config.Successor))
+		}
+		c.Next()
+	}
+}
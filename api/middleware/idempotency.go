@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	iidempotency "github.com/Efamamo/GoCrudChallange/application/common/interface/idempotency"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyKeyHeader is the header carrying the idempotency key of a request.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader marks the responses replayed from a previous request with the same key.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+const (
+	maxIdempotencyKeyLength       = 255      // Maximum length of an idempotency key
+	defaultIdempotencyMaxBody     = 32 << 20 // Size of the largest body read to fingerprint a request
+	defaultIdempotencyMaxResponse = 1 << 20  // Size of the largest response stored
+)
+
+// IdempotencyConfig holds the store keeping the responses of idempotent requests, how long they are kept,
+// and the size of the requests and responses it handles.
+type IdempotencyConfig struct {
+	Store           iidempotency.IStore // Backend keeping the responses
+	TTL             time.Duration       // Time during which a key is replayed rather than processed again
+	MaxBodySize     int64               // Size in bytes of the largest body of a request with a key; 32 MiB when zero
+	MaxResponseSize int64               // Size in bytes of the largest response stored; 1 MiB when zero
+}
+
+// Idempotency is a middleware replaying the response of POST requests retried with the same Idempotency-Key.
+type Idempotency struct {
+	config IdempotencyConfig
+}
+
+// NewIdempotency creates a new Idempotency middleware with the given configuration.
+func NewIdempotency(config IdempotencyConfig) *Idempotency {
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = defaultIdempotencyMaxBody
+	}
+	if config.MaxResponseSize <= 0 {
+		config.MaxResponseSize = defaultIdempotencyMaxResponse
+	}
+	return &Idempotency{config: config}
+}
+
+// Handler returns the Gin middleware. It should run after the authentication and tenancy middleware,
+// as keys are scoped to the client and the tenant of the request.
+//
+// The first response to a POST request with an Idempotency-Key is stored, and retries with the same key
+// and the same body get it back with an Idempotent-Replayed header. Reusing the key for another request gets
+// a 422, and retrying while the first request is still in progress gets a 409. Server errors, and responses
+// larger than MaxResponseSize, aren't stored, so that the request can be retried. Bodies larger than MaxBodySize
+// are rejected with a 413 before being read in full.
+func (i *Idempotency) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idempotencyKey := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || idempotencyKey == "" {
+			c.Next()
+			return
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "idempotency key is too long"})
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, i.config.MaxBodySize))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("request body should be at most %d bytes", tooLarge.Limit)})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "unreadable request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		key := tenant.From(ctx) + "|" + ClientKey(c) + "|" + idempotencyKey
+		fingerprint := digest(c.Request.Method, c.Request.URL.Path, body)
+
+		record, err := i.config.Store.Begin(ctx, key, fingerprint, i.config.TTL)
+		if err != nil {
+			log.Printf("idempotency key %q unavailable: %v", idempotencyKey, err)
+			c.Next()
+			return
+		}
+		if record != nil {
+			replay(c, record, fingerprint)
+			return
+		}
+
+		// Free the key when the request panics, as its response is unknown.
+		defer func() {
+			if p := recover(); p != nil {
+				_ = i.config.Store.Release(ctx, key)
+				panic(p)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, limit: i.config.MaxResponseSize}
+		c.Writer = recorder
+		c.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError || recorder.truncated {
+			err = i.config.Store.Release(ctx, key)
+		} else {
+			err = i.config.Store.Complete(ctx, key, &iidempotency.Response{
+				Status:      recorder.Status(),
+				ContentType: recorder.Header().Get("Content-Type"),
+				Body:        recorder.body.Bytes(),
+			})
+		}
+		if err != nil {
+			log.Printf("idempotency key %q not stored: %v", idempotencyKey, err)
+		}
+	}
+}
+
+// replay answers a request whose key is already reserved or answered.
+func replay(c *gin.Context, record *iidempotency.Record, fingerprint string) {
+	switch {
+	case record.Fingerprint != fingerprint:
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "idempotency key was used for another request"})
+	case record.Response == nil:
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "request with this idempotency key is in progress"})
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(record.Response.Status, record.Response.ContentType, record.Response.Body)
+		c.Abort()
+	}
+}
+
+// digest returns the fingerprint of a request.
+func digest(method string, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder copies the body written to the response, so it can be stored, up to a limit.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	limit     int64 // Size in bytes of the largest copy
+	truncated bool  // Whether the body outgrew the limit, the copy being dropped
+}
+
+// Write writes the data to the response and to the copy.
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.copy(data)
+	return r.ResponseWriter.Write(data)
+}
+
+// WriteString writes the string to the response and to the copy.
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.copy([]byte(s))
+	return r.ResponseWriter.WriteString(s)
+}
+
+// copy appends the data to the copy, or drops the copy once the body outgrew the limit.
+func (r *responseRecorder) copy(data []byte) {
+	if r.truncated {
+		return
+	}
+	if int64(r.body.Len()+len(data)) > r.limit {
+		r.truncated = true
+		r.body.Reset()
+		return
+	}
+	r.body.Write(data)
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	iratelimit "github.com/Efamamo/GoCrudChallange/application/common/interface/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitConfig holds the quota of a route group and the store keeping the buckets of its clients.
+type RateLimitConfig struct {
+	Name    string                     // Name of the route group, keeping its buckets apart from the ones of other groups
+	Rate    iratelimit.Rate            // Quota of each client
+	Clients map[string]iratelimit.Rate // Quotas of specific clients, keyed as returned by ClientKey
+	Store   iratelimit.IStore          // Backend keeping the token buckets
+
+	// ByAddress keys every client by its address, as "ip:<address>", whether it is authenticated or not.
+	// Limits running before authentication use it, so that callers flooding the API with invalid
+	// credentials are turned away before their credentials are checked.
+	ByAddress bool
+}
+
+// RateLimit is a middleware limiting the requests of each client with a token bucket.
+type RateLimit struct {
+	config RateLimitConfig
+}
+
+// NewRateLimit creates a new RateLimit middleware with the given configuration.
+func NewRateLimit(config RateLimitConfig) *RateLimit {
+	return &RateLimit{config: config}
+}
+
+// Handler returns the Gin middleware. It should run after the authentication middleware, so that
+// authenticated callers are limited by API key or user rather than by address, unless it keys clients
+// by their address.
+//
+// Every response carries the X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset headers.
+// Requests exceeding the quota are rejected with a 429 and a Retry-After header. When the store fails,
+// requests are let through rather than rejected.
+func (l *RateLimit) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client := ClientKey(c)
+		if l.config.ByAddress {
+			client = "ip:" + c.ClientIP()
+		}
+		result, err := l.Take(c.Request.Context(), client)
+		if err != nil {
+			log.Printf("rate limit of %s unavailable: %v", client, err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(seconds(result.ResetAfter)))
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(seconds(result.RetryAfter)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Take takes a token from the bucket of the client, refilled at the quota of the client when one is configured.
+// Other transports, such as gRPC, limit their calls with it so that they share the buckets of the HTTP routes.
+func (l *RateLimit) Take(ctx context.Context, client string) (iratelimit.Result, error) {
+	rate, ok := l.config.Clients[client]
+	if !ok {
+		rate = l.config.Rate
+	}
+	return l.config.Store.Take(ctx, l.config.Name+"|"+client, rate)
+}
+
+// ClientKey identifies the client of a request: "apikey:<id>" for API keys, the subject for bearer tokens,
+// or "ip:<address>" for unauthenticated callers.
+func ClientKey(c *gin.Context) string {
+	return ClientKeyOf(c.Request.Context(), c.ClientIP())
+}
+
+// ClientKeyOf identifies a client the way ClientKey does, from the principal carried by the context
+// or else from the address the call comes from.
+func ClientKeyOf(ctx context.Context, address string) string {
+	if principal, ok := auth.PrincipalFrom(ctx); ok && principal.Subject != "" {
+		return principal.Subject
+	}
+	return "ip:" + address
+}
+
+// seconds rounds a duration up to whole seconds, as expected by the Retry-After header.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// ParseRate parses a quota written as "limit/period", optionally followed by "/burst", e.g. "100/1m/20".
+func ParseRate(value string) (iratelimit.Rate, error) {
+	parts := strings.Split(strings.TrimSpace(value), "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return iratelimit.Rate{}, fmt.Errorf("invalid rate limit %q, expected limit/period[/burst]", value)
+	}
+
+	limit, err := strconv.Atoi(parts[0])
+	if err != nil || limit <= 0 {
+		return iratelimit.Rate{}, fmt.Errorf("invalid rate limit %q, the limit must be a positive number", value)
+	}
+	period, err := time.ParseDuration(parts[1])
+	if err != nil || period <= 0 {
+		return iratelimit.Rate{}, fmt.Errorf("invalid rate limit %q, the period must be a positive duration", value)
+	}
+
+	rate := iratelimit.Rate{Limit: limit, Period: period}
+	if len(parts) == 3 {
+		if rate.Burst, err = strconv.Atoi(parts[2]); err != nil || rate.Burst <= 0 {
+			return iratelimit.Rate{}, fmt.Errorf("invalid rate limit %q, the burst must be a positive number", value)
+		}
+	}
+	return rate, nil
+}
+
+// ParseClientRates parses a list of client quotas written as "client=limit/period[/burst]".
+func ParseClientRates(values []string) (map[string]iratelimit.Rate, error) {
+	rates := make(map[string]iratelimit.Rate, len(values))
+	for _, value := range values {
+		client, raw, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(client) == "" {
+			return nil, fmt.Errorf("invalid client rate limit %q, expected client=limit/period[/burst]", value)
+		}
+		rate, err := ParseRate(raw)
+		if err != nil {
+			return nil, err
+		}
+		rates[strings.TrimSpace(client)] = rate
+	}
+	return rates, nil
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	apperror "github.com/Efamamo/GoCrudChallange/application/error"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/gin-gonic/gin"
+)
+
+// TenantKey is the key under which the tenant of the request is stored in the Gin context.
+const TenantKey = "tenant"
+
+// TenancyConfig holds the places the tenant of a request is read from.
+type TenancyConfig struct {
+	Header     string // Header naming the tenant, e.g. "X-Tenant-ID"; not read when empty
+	BaseDomain string // Domain whose subdomains name the tenant, e.g. "example.com"; not read when empty
+	Claim      string // Token claim naming the tenant of authenticated callers, who are rejected without one; optional
+	Required   bool   // Whether requests naming no tenant are rejected instead of using the default tenant
+}
+
+// Tenancy is a middleware resolving the tenant of each request and carrying it in the request context.
+type Tenancy struct {
+	config TenancyConfig
+}
+
+// NewTenancy creates a new Tenancy middleware with the given configuration.
+func NewTenancy(config TenancyConfig) *Tenancy {
+	config.BaseDomain = strings.Trim(strings.ToLower(config.BaseDomain), ".")
+	return &Tenancy{config: config}
+}
+
+// Handler returns the Gin middleware. It should run after the authentication middleware, so that
+// the tenant claim of the caller is known.
+//
+// Authenticated callers belonging to a tenant, through their user, API key or token claim, are confined to it:
+// naming another tenant in the header or the subdomain is rejected with a 403. Authenticated callers belonging to
+// no tenant are rejected with a 403 when they name one, or whenever a claim is configured. Otherwise the subdomain,
+// then the header, name the tenant. Requests naming no tenant use the default tenant, unless one is required.
+func (t *Tenancy) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := t.resolve(c)
+		if err != nil {
+			e := errapi.Map(err)
+			c.AbortWithStatusJSON(e.StatusCode(), gin.H{"error": e.Error()})
+			return
+		}
+
+		c.Set(TenantKey, id)
+		c.Request = c.Request.WithContext(tenant.WithTenant(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// Header returns the name of the header naming the tenant, or an empty string when it isn't read.
+func (t *Tenancy) Header() string {
+	return t.config.Header
+}
+
+// resolve returns the tenant of the request.
+func (t *Tenancy) resolve(c *gin.Context) (string, ierr.IErr) {
+	var header string
+	if t.config.Header != "" {
+		header = c.GetHeader(t.config.Header)
+	}
+	return t.Resolve(c.Request.Context(), c.Request.Host, header)
+}
+
+// Resolve returns the tenant of a request to the host, whose tenant header has the given value, made by
+// the principal of the context. It lets transports other than HTTP, e.g. gRPC, resolve the tenant of their calls.
+func (t *Tenancy) Resolve(ctx context.Context, host string, header string) (string, ierr.IErr) {
+	requested := t.subdomain(host)
+	if requested == "" && t.config.Header != "" {
+		requested = strings.ToLower(strings.TrimSpace(header))
+	}
+
+	if principal, ok := auth.PrincipalFrom(ctx); ok && (!principal.IsAnonymous() || t.config.Claim != "") {
+		belongs := principal.Tenant
+		if belongs == "" && t.config.Claim != "" {
+			belongs, _ = principal.Claims[t.config.Claim].(string)
+		}
+		belongs = strings.ToLower(strings.TrimSpace(belongs))
+		switch {
+		case belongs != "":
+			if requested != "" && requested != belongs {
+				return "", apperror.AccessDenied("caller doesn't belong to the tenant")
+			}
+			requested = belongs
+		case t.config.Claim != "":
+			return "", apperror.AccessDenied("caller belongs to no tenant")
+		case requested != "":
+			return "", apperror.AccessDenied("caller belongs to no tenant, and can't name one")
+		}
+	}
+
+	if requested == "" {
+		if t.config.Required {
+			return "", ierr.NewValidation("tenant is required")
+		}
+		return tenant.Default, nil
+	}
+
+	if !tenant.Valid(requested) {
+		return "", ierr.NewValidation("invalid tenant")
+	}
+	return requested, nil
+}
+
+// subdomain returns the label preceding the base domain in the host, e.g. "acme" for acme.example.com.
+func (t *Tenancy) subdomain(host string) string {
+	if t.config.BaseDomain == "" {
+		return ""
+	}
+
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+
+	label, ok := strings.CutSuffix(host, "."+t.config.BaseDomain)
+	if !ok || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
@@ -1,57 +1,226 @@
 package router
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Efamamo/GoCrudChallange/api/controller"
+	"github.com/Efamamo/GoCrudChallange/api/docs"
+	"github.com/Efamamo/GoCrudChallange/api/middleware"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
+// shutdownTimeout bounds the wait for the requests in progress when the server stops.
+const shutdownTimeout = 10 * time.Second
+
+// allowedHeaders are the request headers clients from other origins may send.
+var allowedHeaders = []string{"Origin", "Content-Type", "Authorization", "X-API-Key", "Idempotency-Key", "Last-Event-ID", "X-Tenant-ID"}
+
+// exposedHeaders are the response headers readable by clients from other origins.
+var exposedHeaders = []string{"Content-Length", "Idempotent-Replayed", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"}
+
 type Router struct {
-	host        string
-	port        string
-	controllers []any
+	host           string
+	port           string
+	controllers    []any
+	authentication *middleware.Authentication
+	tenancy        *middleware.Tenancy
+	rateLimits     RateLimits
+	idempotency    *middleware.Idempotency
+	trustedProxies []string
+	v1Sunset       time.Time
+}
+
+// RateLimits holds the rate limit of each route group; a group is not limited when its rate limit is nil.
+type RateLimits struct {
+	Address *middleware.RateLimit // Every route authenticating callers, keyed by address before authentication
+	Auth    *middleware.RateLimit // Routes issuing and revoking tokens of local users
+	Person  *middleware.RateLimit // Routes reaching people and their relations
+	Admin   *middleware.RateLimit // Routes administering attributes, API keys, users, webhooks and schedules
 }
 
 // Config holds configuration settings for creating a new Router instance.
 type Config struct {
-	Host        string
-	Port        string
-	Controllers []any // List of controllers
+	Host           string
+	Port           string
+	Controllers    []any                      // List of controllers
+	Authentication *middleware.Authentication // Bearer token authentication; routes are open when nil
+	Tenancy        *middleware.Tenancy        // Tenant resolution; every request uses the default tenant when nil
+	RateLimits     RateLimits                 // Rate limits of the route groups
+	Idempotency    *middleware.Idempotency    // Replay of POST requests retried with an Idempotency-Key; keys are ignored when nil
+	TrustedProxies []string                   // Proxies whose X-Forwarded-For headers name the client; headers are ignored when empty
+	V1Sunset       time.Time                  // Time after which v1 stops being served, announced in its responses; optional
 }
 
 // NewRouter creates a new Router instance with the given configuration.
 func NewRouter(config Config) *Router {
 	return &Router{
-		port:        config.Port,
-		host:        config.Host,
-		controllers: config.Controllers,
+		port:           config.Port,
+		host:           config.Host,
+		controllers:    config.Controllers,
+		authentication: config.Authentication,
+		tenancy:        config.Tenancy,
+		rateLimits:     config.RateLimits,
+		idempotency:    config.Idempotency,
+		trustedProxies: config.TrustedProxies,
+		v1Sunset:       config.V1Sunset,
+	}
+}
+
+// StartRouter initializes the Gin router with the given controllers and serves HTTP until the context is done.
+// It then stops accepting connections and waits for the requests in progress, cutting the ones still in progress
+// after the shutdown timeout, and returns the error that stopped the server, if any.
+func (router *Router) StartRouter(ctx context.Context, pc controller.PersonController, rc controller.RelationController, ac controller.AttributeController, kc controller.APIKeyController, auc controller.AuthController, uc controller.UserController, gc controller.GraphQLController, ec controller.EventController, wc controller.WebhookController, jc controller.JobController, sc controller.ScheduleController) error {
+	server := &http.Server{
+		Addr:    fmt.Sprintf("%s:%s", router.host, router.port),
+		Handler: router.Engine(pc, rc, ac, kc, auc, uc, gc, ec, wc, jc, sc),
+	}
+
+	stopped := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdown); err != nil {
+			// Streams such as change feeds don't end by themselves, so they are cut once the timeout is over
+			log.Printf("Warning: Cut the requests still in progress after %s.", shutdownTimeout)
+			stopped <- server.Close()
+			return
+		}
+		stopped <- nil
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
 	}
+	return <-stopped
 }
 
-// StartRouter initializes the Gin router, sets up CORS, defines route handlers, and starts the HTTP server.
-func (router *Router) StartRouter(pc controller.PersonController) {
+// Engine builds the Gin router: it sets up CORS and the middleware, and defines the route handlers.
+// Every route should be described in the OpenAPI document of the docs package.
+func (router *Router) Engine(pc controller.PersonController, rc controller.RelationController, ac controller.AttributeController, kc controller.APIKeyController, auc controller.AuthController, uc controller.UserController, gc controller.GraphQLController, ec controller.EventController, wc controller.WebhookController, jc controller.JobController, sc controller.ScheduleController) *gin.Engine {
 	r := gin.Default()
 
+	// Only trust the client address forwarded by the configured proxies, so that callers can't pick the
+	// address their requests are rate limited by. No proxy is trusted when the configuration is invalid.
+	if err := r.SetTrustedProxies(router.trustedProxies); err != nil {
+		log.Printf("Warning: Trusting no proxy, %s.", err)
+		r.SetTrustedProxies(nil)
+	}
+
 	// Configure CORS settings to allow requests from any origin, specify allowed methods and headers.
 	corsConfiguration := cors.New(cors.Config{
 		AllowAllOrigins: true,                                     // Allow requests from all origins
 		AllowMethods:    []string{"GET", "POST", "DELETE", "PUT"}, // Allowed HTTP methods
-		AllowHeaders:    []string{"Origin", "Content-Type"},       // Allowed HTTP headers
-		ExposeHeaders:   []string{"Content-Length"},               // Headers exposed to the client
+		AllowHeaders:    router.allowedHeaders(),                  // Allowed HTTP headers
+		ExposeHeaders:   exposedHeaders,                           // Headers exposed to the client
 	})
 
 	r.Use(corsConfiguration)
 
-	// Group all routes related to person operations
-	personRoutes := r.Group("/person")
+	// Limit the requests of each address before checking their credentials, so that floods of invalid
+	// credentials are turned away before they are hashed or verified.
+	screened := limited(nil, router.rateLimits.Address)
+
+	// Require a bearer token on the API routes, except for the ones marked public.
+	var credentials []gin.HandlerFunc
+	if router.authentication != nil {
+		credentials = append(credentials, router.authentication.Handler())
+	}
+	authenticated := append(append([]gin.HandlerFunc{}, screened...), credentials...)
+
+	// Resolve the tenant of the routes reaching people, once the caller is authenticated.
+	tenanted := append([]gin.HandlerFunc{}, authenticated...)
+	if router.tenancy != nil {
+		tenanted = append(tenanted, router.tenancy.Handler())
+	}
+
+	// Serve the OpenAPI document and the Swagger UI browsing it, without authentication
+	r.GET("/openapi.json", docs.Spec) // GET /openapi.json
+	r.GET("/docs", docs.UI)           // GET /docs
+
+	// Group all routes issuing and revoking tokens of local users; logging out needs the token being revoked
+	authRoutes := r.Group("/auth", limited(screened, router.rateLimits.Auth)...)
+	{
+		authRoutes.POST("/login", auc.Login)                             // POST /auth/login
+		authRoutes.POST("/refresh", auc.Refresh)                         // POST /auth/refresh
+		authRoutes.Group("", credentials...).POST("/logout", auc.Logout) // POST /auth/logout
+	}
+
+	// Group all routes related to person operations, in every version of the API. The bare /person routes
+	// serve v1, unless the Accept header selects another version, and v1 is deprecated in favor of v2.
+	people := router.idempotent(limited(tenanted, router.rateLimits.Person))
+	deprecated := middleware.Deprecation(middleware.DeprecationConfig{Sunset: router.v1Sunset, Successor: "/v2/person"})
+
+	mountPeople(r.Group("/person", append([]gin.HandlerFunc{negotiate(r), versioned(controller.V1), deprecated}, people...)...), pc, rc, ec)
+	mountPeople(r.Group("/v1/person", append([]gin.HandlerFunc{versioned(controller.V1), deprecated}, people...)...), pc, rc, ec)
+	mountPeople(r.Group("/v2/person", append([]gin.HandlerFunc{versioned(controller.V2)}, people...)...), pc, rc, ec)
+
+	// Serve the GraphQL schema of people, limited and replayed along with the routes of people
+	r.Group("/graphql", people...).POST("", gc.Serve) // POST /graphql
+
+	// Group all routes submitting background jobs and following them, limited and replayed along with the routes of people
+	jobRoutes := r.Group("/jobs", people...)
+	{
+		jobRoutes.POST("", jc.Submit)            // POST /jobs
+		jobRoutes.GET("", jc.GetAll)             // GET /jobs
+		jobRoutes.GET("/:id", jc.Get)            // GET /jobs/:id
+		jobRoutes.POST("/:id/cancel", jc.Cancel) // POST /jobs/:id/cancel
+	}
+
+	// Limit the administration routes together, and replay their retries, once the caller is authenticated.
+	administered := router.idempotent(limited(authenticated, router.rateLimits.Admin))
+
+	// Group all routes administering the custom attribute schema
+	attributeRoutes := r.Group("/admin/attributes", administered...)
+	{
+		attributeRoutes.GET("", ac.GetAll)          // GET /admin/attributes
+		attributeRoutes.PUT("/:name", ac.Save)      // PUT /admin/attributes/:name
+		attributeRoutes.DELETE("/:name", ac.Delete) // DELETE /admin/attributes/:name
+	}
+
+	// Group all routes administering API keys
+	apiKeyRoutes := r.Group("/admin/apikeys", administered...)
+	{
+		apiKeyRoutes.POST("", kc.Create)            // POST /admin/apikeys
+		apiKeyRoutes.GET("", kc.GetAll)             // GET /admin/apikeys
+		apiKeyRoutes.GET("/:id", kc.Get)            // GET /admin/apikeys/:id
+		apiKeyRoutes.PUT("/:id", kc.Update)         // PUT /admin/apikeys/:id
+		apiKeyRoutes.DELETE("/:id", kc.Delete)      // DELETE /admin/apikeys/:id
+		apiKeyRoutes.POST("/:id/revoke", kc.Revoke) // POST /admin/apikeys/:id/revoke
+	}
+
+	// Group all routes administering local users
+	userRoutes := r.Group("/admin/users", administered...)
+	{
+		userRoutes.POST("", uc.Create)       // POST /admin/users
+		userRoutes.GET("", uc.GetAll)        // GET /admin/users
+		userRoutes.DELETE("/:id", uc.Delete) // DELETE /admin/users/:id
+	}
+
+	// Group all routes administering webhooks and reading their delivery logs
+	webhookRoutes := r.Group("/admin/webhooks", administered...)
+	{
+		webhookRoutes.POST("", wc.Create)                   // POST /admin/webhooks
+		webhookRoutes.GET("", wc.GetAll)                    // GET /admin/webhooks
+		webhookRoutes.GET("/:id", wc.Get)                   // GET /admin/webhooks/:id
+		webhookRoutes.PUT("/:id", wc.Update)                // PUT /admin/webhooks/:id
+		webhookRoutes.DELETE("/:id", wc.Delete)             // DELETE /admin/webhooks/:id
+		webhookRoutes.GET("/:id/deliveries", wc.Deliveries) // GET /admin/webhooks/:id/deliveries
+	}
+
+	// Group all routes reading the schedules of the maintenance tasks
+	scheduleRoutes := r.Group("/admin/schedules", administered...)
 	{
-		personRoutes.POST("", pc.Create)       // POST /person
-		personRoutes.GET("", pc.GetAll)        // GET /person
-		personRoutes.GET("/:id", pc.Get)       // GET /person/:id
-		personRoutes.PUT("/:id", pc.Update)    // PUT /person/:id
-		personRoutes.DELETE("/:id", pc.Delete) // DELETE /person/:id
+		scheduleRoutes.GET("", sc.GetAll) // GET /admin/schedules
 	}
 
 	// Handler for undefined routes (404 Not Found)
@@ -61,5 +230,127 @@ func (router *Router) StartRouter(pc controller.PersonController) {
 		})
 	})
 
-	r.Run(fmt.Sprintf("%s:%s", router.host, router.port))
+	return r
+}
+
+// limited returns a copy of the middleware followed by the rate limit, when there is one.
+func limited(handlers []gin.HandlerFunc, rateLimit *middleware.RateLimit) []gin.HandlerFunc {
+	handlers = append([]gin.HandlerFunc{}, handlers...)
+	if rateLimit != nil {
+		handlers = append(handlers, rateLimit.Handler())
+	}
+	return handlers
+}
+
+// allowedHeaders returns the request headers clients from other origins may send, along with the header
+// naming the tenant when another one is configured.
+func (router *Router) allowedHeaders() []string {
+	headers := append([]string{}, allowedHeaders...)
+	if router.tenancy != nil && router.tenancy.Header() != "" {
+		for _, header := range headers {
+			if strings.EqualFold(header, router.tenancy.Header()) {
+				return headers
+			}
+		}
+		headers = append(headers, router.tenancy.Header())
+	}
+	return headers
+}
+
+// idempotent returns the middleware followed by the replay of retried requests, when enabled.
+func (router *Router) idempotent(handlers []gin.HandlerFunc) []gin.HandlerFunc {
+	if router.idempotency != nil {
+		handlers = append(handlers, router.idempotency.Handler())
+	}
+	return handlers
+}
+
+// mountPeople registers the routes related to person operations on the group of a version of the API.
+func mountPeople(personRoutes *gin.RouterGroup, pc controller.PersonController, rc controller.RelationController, ec controller.EventController) {
+	// Responses of people are rendered in the format negotiated with the Accept header
+	people := personRoutes.Group("", formatted)
+	people.POST("", pc.Create)               // POST /person
+	people.GET("", pc.GetAll)                // GET /person
+	people.GET("/duplicates", pc.Duplicates) // GET /person/duplicates
+	people.POST("/merge", pc.Merge)          // POST /person/merge
+	people.POST("/import", pc.Import)        // POST /person/import
+	people.GET("/:id", pc.Get)               // GET /person/:id
+	people.PUT("/:id", pc.Update)            // PUT /person/:id
+	people.DELETE("/:id", pc.Delete)         // DELETE /person/:id
+	people.PUT("/:id/owner", pc.Transfer)    // PUT /person/:id/owner
+	people.GET("/:id/merges", pc.Merges)     // GET /person/:id/merges
+
+	// Exports are streamed in their own formats, negotiated by the action
+	personRoutes.GET("/export", pc.Export) // GET /person/export
+
+	personRoutes.GET("/events", ec.Stream)       // GET /person/events
+	personRoutes.GET("/events/ws", ec.WebSocket) // GET /person/events/ws
+
+	personRoutes.POST("/:id/relations", rc.Add)                  // POST /person/:id/relations
+	personRoutes.GET("/:id/relations", rc.GetAll)                // GET /person/:id/relations
+	personRoutes.DELETE("/:id/relations/:relationId", rc.Remove) // DELETE /person/:id/relations/:relationId
+	personRoutes.GET("/:id/mutual/:otherId", rc.Mutual)          // GET /person/:id/mutual/:otherId
+	personRoutes.GET("/:id/path/:otherId", rc.Path)              // GET /person/:id/path/:otherId
+}
+
+// versioned returns a middleware recording the version of the API served by a route group.
+func versioned(version controller.Version) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(controller.VersionKey, version)
+		c.Next()
+	}
+}
+
+// negotiate returns a middleware serving the requests to unversioned routes that select another version
+// with their Accept header, e.g. "application/vnd.gocrud.v2+json" or "application/json; version=2",
+// from the route group of that version.
+func negotiate(engine *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Vary", "Accept")
+
+		version := acceptedVersion(c.GetHeader("Accept"))
+		if version == 0 || version == controller.V1 {
+			c.Next()
+			return
+		}
+
+		c.Request.URL.Path = fmt.Sprintf("/v%d%s", version, c.Request.URL.Path)
+		engine.HandleContext(c)
+		c.Abort()
+	}
+}
+
+// formatted is a middleware recording the Format of the responses negotiated with the Accept header,
+// and responding with a 406 when none of the accepted media types is supported.
+func formatted(c *gin.Context) {
+	c.Header("Vary", "Accept")
+
+	format, ok := controller.NegotiateFormat(c.GetHeader("Accept"))
+	if !ok {
+		e := controller.ErrNotAcceptable
+		c.AbortWithStatusJSON(e.StatusCode(), gin.H{"error": e.Error()})
+		return
+	}
+
+	c.Set(controller.FormatKey, format)
+	c.Next()
+}
+
+// acceptedVersion returns the version of the API selected by an Accept header, or zero when it selects none.
+func acceptedVersion(accept string) controller.Version {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		raw := params["version"]
+		if rest, ok := strings.CutPrefix(mediaType, "application/vnd.gocrud.v"); ok {
+			raw, _, _ = strings.Cut(rest, "+")
+		}
+		if version, err := strconv.Atoi(raw); err == nil && (version == int(controller.V1) || version == int(controller.V2)) {
+			return controller.Version(version)
+		}
+	}
+	return 0
 }
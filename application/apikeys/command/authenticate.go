@@ -0,0 +1,56 @@
+package command
+
+import (
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	apperror "github.com/Efamamo/GoCrudChallange/application/error"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/apikey"
+)
+
+// AuthenticateAPIKeyHandler is a command handler for authenticating a caller by the secret of an API key.
+type AuthenticateAPIKeyHandler struct {
+	repo irepo.IAPIKey // Repository interface for API key operations.
+}
+
+// Ensure AuthenticateAPIKeyHandler implements the IHandler interface for handling commands.
+var _ icmd.IHandler[string, *auth.Principal] = &AuthenticateAPIKeyHandler{}
+
+// NewAuthenticateAPIKeyHandler creates a new instance of AuthenticateAPIKeyHandler with the provided repository.
+func NewAuthenticateAPIKeyHandler(repo irepo.IAPIKey) *AuthenticateAPIKeyHandler {
+	return &AuthenticateAPIKeyHandler{repo: repo}
+}
+
+// Handle processes the command to authenticate a secret, recording the use of the matching key.
+// The returned principal holds the scopes of the key as permissions, and belongs to the tenant of the key.
+func (h *AuthenticateAPIKeyHandler) Handle(secret string) (*auth.Principal, ierr.IErr) {
+	now := time.Now()
+	key, err := h.repo.GetByHash(apikey.Hash(secret))
+	if err != nil || !key.Usable(now) {
+		return nil, apperror.InvalidCredential("invalid api key")
+	}
+
+	// Only the last use is written, by a repository checking again that the key is usable, so that a revocation
+	// landing meanwhile is neither undone nor ignored.
+	if err := h.repo.Touch(key.Id(), now); err != nil {
+		if err.Type() == ierr.NotFound {
+			return nil, apperror.InvalidCredential("invalid api key")
+		}
+		return nil, err
+	}
+
+	permissions := make([]auth.Permission, 0, len(key.Scopes()))
+	for _, scope := range key.Scopes() {
+		permissions = append(permissions, auth.Permission(scope))
+	}
+
+	return &auth.Principal{
+		Subject:     "apikey:" + key.Id().String(),
+		Claims:      map[string]any{"api_key": key.Id().String(), "name": key.Name()},
+		Permissions: permissions,
+		Tenant:      key.Tenant(),
+	}, nil
+}
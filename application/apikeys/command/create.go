@@ -0,0 +1,66 @@
+package command
+
+import (
+	"time"
+
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/apikey"
+)
+
+// CreateAPIKeyCommand holds the data required to generate a new API key.
+type CreateAPIKeyCommand struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+	Secret    string // Optional; used to import a known secret instead of generating one
+	Tenant    string // Tenant the key belongs to, and its requests are confined to; optional
+}
+
+// CreateAPIKeyResult holds a generated key together with its secret, which is never available again.
+type CreateAPIKeyResult struct {
+	Key    *apikey.APIKey
+	Secret string
+}
+
+// CreateAPIKeyHandler is responsible for handling the logic of generating a new API key.
+type CreateAPIKeyHandler struct {
+	repo irepo.IAPIKey
+}
+
+// Compile-time check to ensure CreateAPIKeyHandler implements IHandler for CreateAPIKeyCommand.
+var _ icmd.IHandler[*CreateAPIKeyCommand, *CreateAPIKeyResult] = &CreateAPIKeyHandler{}
+
+// NewCreateAPIKeyHandler initializes a new CreateAPIKeyHandler with a given IAPIKey repository.
+func NewCreateAPIKeyHandler(repo irepo.IAPIKey) *CreateAPIKeyHandler {
+	return &CreateAPIKeyHandler{repo: repo}
+}
+
+// Handle processes the CreateAPIKeyCommand to generate and store a new API key.
+func (h *CreateAPIKeyHandler) Handle(command *CreateAPIKeyCommand) (*CreateAPIKeyResult, ierr.IErr) {
+	if err := validateScopes(command.Scopes); err != nil {
+		return nil, err
+	}
+	if command.Tenant != "" && !tenant.Valid(command.Tenant) {
+		return nil, ierr.NewValidation("invalid tenant")
+	}
+
+	key, secret, err := apikey.GenerateAPIKey(&apikey.APIKeyConfig{
+		Name:      command.Name,
+		Scopes:    command.Scopes,
+		ExpiresAt: command.ExpiresAt,
+		Secret:    command.Secret,
+		Tenant:    command.Tenant,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.repo.Save(key); err != nil {
+		return nil, err
+	}
+
+	return &CreateAPIKeyResult{Key: key, Secret: secret}, nil
+}
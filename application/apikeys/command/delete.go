@@ -0,0 +1,29 @@
+package command
+
+import (
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/google/uuid"
+)
+
+// DeleteAPIKeyHandler is a command handler for deleting an API key by its ID.
+type DeleteAPIKeyHandler struct {
+	repo irepo.IAPIKey // Repository interface for API key operations.
+}
+
+// Ensure DeleteAPIKeyHandler implements the IHandler interface for handling commands.
+var _ icmd.IHandler[uuid.UUID, bool] = &DeleteAPIKeyHandler{}
+
+// NewDeleteAPIKeyHandler creates a new instance of DeleteAPIKeyHandler with the provided repository.
+func NewDeleteAPIKeyHandler(repo irepo.IAPIKey) *DeleteAPIKeyHandler {
+	return &DeleteAPIKeyHandler{repo: repo}
+}
+
+// Handle processes the command to delete an API key by its ID.
+func (h *DeleteAPIKeyHandler) Handle(id uuid.UUID) (bool, ierr.IErr) {
+	if err := h.repo.Delete(id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
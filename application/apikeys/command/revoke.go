@@ -0,0 +1,37 @@
+package command
+
+import (
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/apikey"
+	"github.com/google/uuid"
+)
+
+// RevokeAPIKeyHandler is a command handler for permanently disabling an API key while keeping its record.
+type RevokeAPIKeyHandler struct {
+	repo irepo.IAPIKey // Repository interface for API key operations.
+}
+
+// Ensure RevokeAPIKeyHandler implements the IHandler interface for handling commands.
+var _ icmd.IHandler[uuid.UUID, *apikey.APIKey] = &RevokeAPIKeyHandler{}
+
+// NewRevokeAPIKeyHandler creates a new instance of RevokeAPIKeyHandler with the provided repository.
+func NewRevokeAPIKeyHandler(repo irepo.IAPIKey) *RevokeAPIKeyHandler {
+	return &RevokeAPIKeyHandler{repo: repo}
+}
+
+// Handle processes the command to revoke an API key by its ID.
+func (h *RevokeAPIKeyHandler) Handle(id uuid.UUID) (*apikey.APIKey, ierr.IErr) {
+	key, err := h.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Revoke()
+
+	if err := h.repo.Save(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,18 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+)
+
+// validateScopes makes sure every scope of a key is a known permission.
+func validateScopes(scopes []string) ierr.IErr {
+	for _, scope := range scopes {
+		if !auth.Permission(scope).Valid() {
+			return ierr.NewValidation(fmt.Sprintf("unknown scope %s", scope))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"time"
+
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/apikey"
+	"github.com/google/uuid"
+)
+
+// UpdateAPIKeyCommand represents the command to update the name, scopes and expiry of an API key.
+type UpdateAPIKeyCommand struct {
+	ID        uuid.UUID
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+// UpdateAPIKeyHandler is a command handler for updating an API key.
+type UpdateAPIKeyHandler struct {
+	repo irepo.IAPIKey // Repository interface for API key operations.
+}
+
+// Ensure UpdateAPIKeyHandler implements the IHandler interface for handling commands.
+var _ icmd.IHandler[*UpdateAPIKeyCommand, *apikey.APIKey] = &UpdateAPIKeyHandler{}
+
+// NewUpdateAPIKeyHandler creates a new instance of UpdateAPIKeyHandler with the provided repository.
+func NewUpdateAPIKeyHandler(repo irepo.IAPIKey) *UpdateAPIKeyHandler {
+	return &UpdateAPIKeyHandler{repo: repo}
+}
+
+// Handle processes the command to update an API key.
+func (h *UpdateAPIKeyHandler) Handle(command *UpdateAPIKeyCommand) (*apikey.APIKey, ierr.IErr) {
+	key, err := h.repo.Get(command.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateScopes(command.Scopes); err != nil {
+		return nil, err
+	}
+
+	if err := key.SetName(command.Name); err != nil {
+		return nil, err
+	}
+
+	if err := key.SetScopes(command.Scopes); err != nil {
+		return nil, err
+	}
+
+	if err := key.SetExpiresAt(command.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	if err := h.repo.Save(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
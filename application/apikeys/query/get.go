@@ -0,0 +1,30 @@
+package query
+
+import (
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	"github.com/Efamamo/GoCrudChallange/domain/model/apikey"
+	"github.com/google/uuid"
+)
+
+// Ensure GetAPIKeyHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[uuid.UUID, *apikey.APIKey] = &GetAPIKeyHandler{}
+
+// GetAPIKeyHandler is a query handler for retrieving an API key by its ID.
+type GetAPIKeyHandler struct {
+	repo irepo.IAPIKey // Repository interface for API key operations.
+}
+
+// NewGetAPIKeyHandler creates a new instance of GetAPIKeyHandler with the provided repository.
+func NewGetAPIKeyHandler(repo irepo.IAPIKey) *GetAPIKeyHandler {
+	return &GetAPIKeyHandler{repo: repo}
+}
+
+// Handle processes the query to retrieve an API key by its ID.
+func (h *GetAPIKeyHandler) Handle(id uuid.UUID) (*apikey.APIKey, error) {
+	key, err := h.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,29 @@
+package query
+
+import (
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	"github.com/Efamamo/GoCrudChallange/domain/model/apikey"
+)
+
+// Ensure GetAPIKeysHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[struct{}, []*apikey.APIKey] = &GetAPIKeysHandler{}
+
+// GetAPIKeysHandler is a query handler for retrieving every API key.
+type GetAPIKeysHandler struct {
+	repo irepo.IAPIKey // Repository interface for API key operations.
+}
+
+// NewGetAPIKeysHandler creates a new instance of GetAPIKeysHandler with the provided repository.
+func NewGetAPIKeysHandler(repo irepo.IAPIKey) *GetAPIKeysHandler {
+	return &GetAPIKeysHandler{repo: repo}
+}
+
+// Handle processes the query to retrieve every API key.
+func (h *GetAPIKeysHandler) Handle(_ struct{}) ([]*apikey.APIKey, error) {
+	keys, err := h.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
@@ -0,0 +1,28 @@
+package command
+
+import (
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+)
+
+// DeleteAttributeHandler is a command handler for removing a custom attribute from the schema by its name.
+type DeleteAttributeHandler struct {
+	repo irepo.IAttribute // Repository interface for attribute schema operations.
+}
+
+// Ensure DeleteAttributeHandler implements the IHandler interface for handling commands.
+var _ icmd.IHandler[string, bool] = &DeleteAttributeHandler{}
+
+// NewDeleteAttributeHandler creates a new instance of DeleteAttributeHandler with the provided repository.
+func NewDeleteAttributeHandler(repo irepo.IAttribute) *DeleteAttributeHandler {
+	return &DeleteAttributeHandler{repo: repo}
+}
+
+// Handle processes the command to delete an attribute definition by its name.
+func (h *DeleteAttributeHandler) Handle(name string) (bool, ierr.IErr) {
+	if err := h.repo.Delete(name); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,52 @@
+package command
+
+import (
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/attribute"
+)
+
+// SaveAttributeCommand holds the data required to define or redefine a custom attribute.
+type SaveAttributeCommand struct {
+	Name     string
+	Type     string
+	Required bool
+	Enum     []string
+	Min      *float64
+	Max      *float64
+}
+
+// SaveAttributeHandler is responsible for handling the logic of defining a custom attribute.
+type SaveAttributeHandler struct {
+	repo irepo.IAttribute
+}
+
+// Compile-time check to ensure SaveAttributeHandler implements IHandler for SaveAttributeCommand.
+var _ icmd.IHandler[*SaveAttributeCommand, *attribute.Definition] = &SaveAttributeHandler{}
+
+// NewSaveAttributeHandler initializes a new SaveAttributeHandler with a given IAttribute repository.
+func NewSaveAttributeHandler(repo irepo.IAttribute) *SaveAttributeHandler {
+	return &SaveAttributeHandler{repo: repo}
+}
+
+// Handle processes the SaveAttributeCommand to create or replace an attribute definition.
+func (h *SaveAttributeHandler) Handle(command *SaveAttributeCommand) (*attribute.Definition, ierr.IErr) {
+	definition, err := attribute.CreateDefinition(&attribute.DefinitionConfig{
+		Name:     command.Name,
+		Type:     command.Type,
+		Required: command.Required,
+		Enum:     command.Enum,
+		Min:      command.Min,
+		Max:      command.Max,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.repo.Save(definition); err != nil {
+		return nil, err
+	}
+
+	return definition, nil
+}
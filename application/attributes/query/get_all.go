@@ -0,0 +1,29 @@
+package query
+
+import (
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	"github.com/Efamamo/GoCrudChallange/domain/model/attribute"
+)
+
+// Ensure GetAttributesHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[struct{}, []*attribute.Definition] = &GetAttributesHandler{}
+
+// GetAttributesHandler is a query handler for retrieving the custom attribute schema.
+type GetAttributesHandler struct {
+	repo irepo.IAttribute // Repository interface for attribute schema operations.
+}
+
+// NewGetAttributesHandler creates a new instance of GetAttributesHandler with the provided repository.
+func NewGetAttributesHandler(repo irepo.IAttribute) *GetAttributesHandler {
+	return &GetAttributesHandler{repo: repo}
+}
+
+// Handle processes the query to retrieve every attribute definition.
+func (h *GetAttributesHandler) Handle(_ struct{}) ([]*attribute.Definition, error) {
+	definitions, err := h.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return definitions, nil
+}
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	apperror "github.com/Efamamo/GoCrudChallange/application/error"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+)
+
+// Authorizer checks the permissions of the principal performing a command or query.
+type Authorizer struct {
+	policy *Policy
+}
+
+// NewAuthorizer creates a new Authorizer enforcing the given policy.
+func NewAuthorizer(policy *Policy) *Authorizer {
+	return &Authorizer{policy: policy}
+}
+
+// Authorize returns an Authentication error when the context carries no principal,
+// and a Forbidden error when the principal does not hold the permission.
+func (a *Authorizer) Authorize(ctx context.Context, permission Permission) ierr.IErr {
+	principal, ok := PrincipalFrom(ctx)
+	if !ok {
+		return apperror.InvalidCredential("missing principal")
+	}
+
+	if !a.policy.Allows(principal, permission) {
+		return apperror.AccessDenied(fmt.Sprintf("%s permission is required", permission))
+	}
+	return nil
+}
+
+// Unrestricted reports whether the caller may access the records of every owner, which is the case
+// when no Authorizer enforces a policy or when the caller holds the admin permission. Anonymous callers
+// own no records, so they only reach the ones of others when the policy grants them the admin permission.
+func (a *Authorizer) Unrestricted(ctx context.Context) bool {
+	if a == nil {
+		return true
+	}
+
+	principal, ok := PrincipalFrom(ctx)
+	return ok && a.policy.Allows(principal, Admin)
+}
+
+// CanAccess reports whether the caller may access a record belonging to the given owner.
+func (a *Authorizer) CanAccess(ctx context.Context, owner string) bool {
+	if a.Unrestricted(ctx) {
+		return true
+	}
+
+	principal, ok := PrincipalFrom(ctx)
+	return ok && owner != "" && principal.Subject == owner
+}
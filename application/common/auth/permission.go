@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Permission is an action a principal may be allowed to perform.
+type Permission string
+
+// Permissions checked by the application.
+const (
+	PersonRead   Permission = "person:read"   // Read people and their relations
+	PersonWrite  Permission = "person:write"  // Create, update and merge people and their relations
+	PersonDelete Permission = "person:delete" // Delete people
+	Admin        Permission = "admin"         // Every permission, including administration
+)
+
+// Valid reports whether the permission is one checked by the application.
+func (p Permission) Valid() bool {
+	switch p {
+	case PersonRead, PersonWrite, PersonDelete, Admin:
+		return true
+	default:
+		return false
+	}
+}
+
+// Policy maps roles to the permissions they grant.
+type Policy struct {
+	Roles map[string][]Permission `json:"roles"`
+}
+
+// DefaultPolicy returns the policy used when no policy file is configured.
+func DefaultPolicy() *Policy {
+	return &Policy{Roles: map[string][]Permission{
+		"viewer": {PersonRead},
+		"editor": {PersonRead, PersonWrite},
+		"owner":  {PersonRead, PersonWrite, PersonDelete},
+		"admin":  {Admin},
+
+		AnonymousRole: {PersonRead},
+	}}
+}
+
+// LoadPolicy reads a JSON policy file such as {"roles": {"viewer": ["person:read"]}}.
+func LoadPolicy(path string) (*Policy, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading policy: %w", err)
+	}
+
+	var policy Policy
+	if err := json.Unmarshal(content, &policy); err != nil {
+		return nil, fmt.Errorf("parsing policy: %w", err)
+	}
+
+	for role, permissions := range policy.Roles {
+		for _, permission := range permissions {
+			if !permission.Valid() {
+				return nil, fmt.Errorf("parsing policy: role %s grants unknown permission %s", role, permission)
+			}
+		}
+	}
+	return &policy, nil
+}
+
+// Permissions returns every permission granted to the principal, directly or by its roles.
+func (p *Policy) Permissions(principal *Principal) []Permission {
+	permissions := append([]Permission{}, principal.Permissions...)
+	for _, role := range principal.Roles {
+		permissions = append(permissions, p.Roles[role]...)
+	}
+	return permissions
+}
+
+// Allows reports whether the principal holds the permission, or the admin permission.
+func (p *Policy) Allows(principal *Principal, permission Permission) bool {
+	for _, granted := range p.Permissions(principal) {
+		if granted == permission || granted == Admin {
+			return true
+		}
+	}
+	return false
+}
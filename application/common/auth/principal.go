@@ -0,0 +1,46 @@
+// Package auth describes who is performing a request and carries it through the request context.
+package auth
+
+import "context"
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject string         // Unique identifier of the caller, e.g. the "sub" claim of a token
+	Claims  map[string]any // Every claim the caller was authenticated with
+	Roles   []string       // Roles of the caller, granting permissions through the Policy
+	Tenant  string         // Tenant the caller belongs to, e.g. the one of its user or API key; empty when it belongs to none
+
+	Permissions []Permission // Permissions granted directly, e.g. the scopes of an API key
+}
+
+// AnonymousRole is the role of the anonymous principal, given to callers of public routes who present no credentials.
+// Policies grant it permissions like any other role.
+const AnonymousRole = "anonymous"
+
+// Anonymous returns the principal of a caller who presented no credentials on a public route.
+// It has no subject and only holds the anonymous role.
+func Anonymous() *Principal {
+	return &Principal{Roles: []string{AnonymousRole}}
+}
+
+// IsAnonymous reports whether the principal is the one of a caller who presented no credentials.
+func (p *Principal) IsAnonymous() bool {
+	return p.Subject == ""
+}
+
+// principalKey is the context key under which the Principal is stored.
+type principalKey struct{}
+
+// WithPrincipal returns a copy of the context carrying the principal.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal carried by the context, if any.
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
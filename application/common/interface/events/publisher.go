@@ -0,0 +1,42 @@
+package ievents
+
+import (
+	"context"
+	"time"
+
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+)
+
+// EventType is the kind of change a PersonEvent reports.
+type EventType string
+
+const (
+	PersonCreated EventType = "person.created" // A person was created
+	PersonUpdated EventType = "person.updated" // The details of a person were changed
+	PersonDeleted EventType = "person.deleted" // A person was deleted
+)
+
+// PersonEvent reports a change of a person, published once the change is saved.
+type PersonEvent struct {
+	ID         uint64 // Sequence number assigned by the broker, increasing with each event from 1
+	Type       EventType
+	Tenant     string        // Tenant the person belongs to
+	Person     *model.Person // Copy of the person after the change, or before their deletion
+	OccurredAt time.Time
+}
+
+// IPublisher defines the interface for publishing the changes of people.
+type IPublisher interface {
+	// Publish notifies the subscribers of the event. It should not block on slow subscribers.
+	Publish(ctx context.Context, event PersonEvent)
+}
+
+// IBroker defines the interface for a publisher whose events can be subscribed to.
+type IBroker interface {
+	IPublisher
+
+	// Subscribe returns a channel receiving the events published from now on, preceded by the events
+	// published after the event with the given ID that the broker still holds; nothing is replayed when
+	// the ID is zero. The channel is closed when the context is done, or when the subscriber falls too far behind.
+	Subscribe(ctx context.Context, after uint64) <-chan PersonEvent
+}
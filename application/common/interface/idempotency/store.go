@@ -0,0 +1,33 @@
+package iidempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Response is the response stored for an idempotency key, replayed to retries of the request.
+type Response struct {
+	Status      int    // HTTP status code
+	ContentType string // Content-Type header
+	Body        []byte // Body
+}
+
+// Record is what is stored under an idempotency key.
+type Record struct {
+	Fingerprint string    // Digest of the request, telling retries apart from other requests reusing the key
+	Response    *Response // Response of the request; nil while the request is in progress
+	ExpiresAt   time.Time // Time after which the key can be reused
+}
+
+// IStore defines the interface for the backend keeping the responses of idempotent requests.
+type IStore interface {
+	// Begin reserves the key for a request with the given fingerprint until the TTL elapses.
+	// It returns the existing record instead when the key is already reserved or answered.
+	Begin(ctx context.Context, key string, fingerprint string, ttl time.Duration) (*Record, error)
+
+	// Complete stores the response of the request holding the key.
+	Complete(ctx context.Context, key string, response *Response) error
+
+	// Release frees the key, so that the request can be retried.
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,46 @@
+package ijobs
+
+import (
+	"context"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
+	"github.com/google/uuid"
+)
+
+// Report records the progress of a running job. It is cheap to call: the progress is only saved from time to time.
+type Report func(progress job.Progress)
+
+// ITask runs the jobs of a kind, such as purging people.
+type ITask interface {
+	// Validate checks the JSON parameters of a job before it is queued, which may be nil.
+	// The context carries the principal and the tenant of the submitter.
+	Validate(ctx context.Context, params []byte) error
+
+	// Run runs a job with its JSON parameters, and returns its result, which is stored as JSON.
+	// The context carries the principal and the tenant of the submitter, so that the job is authorized as
+	// they would be, and is done when the job is cancelled or the runner stops: the task should then stop
+	// early and return the error of the context.
+	Run(ctx context.Context, params []byte, report Report) (any, error)
+}
+
+// ICleaner is implemented by the tasks holding resources for their jobs until they finish, such as the uploads
+// of imports. Cleanup is called once a job of the task finished, whether it ran or not, with its parameters
+// and a context carrying the principal and the tenant of the submitter.
+type ICleaner interface {
+	Cleanup(ctx context.Context, params []byte)
+}
+
+// IRunner runs background jobs with a bounded pool of workers, in the order they were submitted.
+type IRunner interface {
+	// Submit queues a job of the kind, run on behalf of the principal and in the tenant carried by the context.
+	// It returns a Validation error when no task runs the kind, or when the task rejects the parameters.
+	Submit(ctx context.Context, kind string, params []byte) (*job.Job, ierr.IErr)
+
+	// Cancel cancels a queued job right away, or asks a running job to stop, in which case the job is returned
+	// still running with its cancellation requested. It returns a Conflict error when the job already finished.
+	Cancel(id uuid.UUID) (*job.Job, ierr.IErr)
+
+	// Kinds lists the kinds of jobs the runner has a task for, in alphabetical order.
+	Kinds() []string
+}
@@ -0,0 +1,37 @@
+package iratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Rate is the quota of a token bucket: Limit requests per Period, with bursts of up to Burst requests.
+type Rate struct {
+	Limit  int           // Number of requests allowed per period
+	Period time.Duration // Period over which Limit requests are allowed
+	Burst  int           // Capacity of the bucket; Limit is used when zero
+}
+
+// Capacity returns the maximum number of requests allowed at once.
+func (r Rate) Capacity() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+	return r.Limit
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed    bool          // Whether the request may proceed
+	Limit      int           // Capacity of the bucket
+	Remaining  int           // Tokens left in the bucket
+	RetryAfter time.Duration // Time until a token is available; zero when allowed
+	ResetAfter time.Duration // Time until the bucket is full again
+}
+
+// IStore defines the interface for the backend keeping the token buckets of clients.
+// The in-memory store limits a single instance; a shared store limits every instance together.
+type IStore interface {
+	// Take takes a token from the bucket of the key, refilled at the given rate.
+	Take(ctx context.Context, key string, rate Rate) (Result, error)
+}
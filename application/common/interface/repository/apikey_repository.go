@@ -0,0 +1,32 @@
+package irepo
+
+import (
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/apikey"
+	"github.com/google/uuid"
+)
+
+// IAPIKey defines the interface for the repository layer responsible for API keys.
+type IAPIKey interface {
+	// Save adds a new APIKey to the repository or updates an existing one.
+	Save(*apikey.APIKey) ierr.IErr
+
+	// Get retrieves an APIKey by its unique UUID.
+	Get(uuid.UUID) (*apikey.APIKey, ierr.IErr)
+
+	// GetByHash retrieves an APIKey by the hash of its secret.
+	GetByHash(string) (*apikey.APIKey, ierr.IErr)
+
+	// Touch records the last use of an APIKey by its UUID, leaving the rest of it untouched,
+	// so that a concurrent change such as a revocation is never overwritten. It fails with an
+	// invalid credential when the key is no longer usable at the time of the use.
+	Touch(uuid.UUID, time.Time) ierr.IErr
+
+	// Delete removes an APIKey from the repository by its UUID.
+	Delete(uuid.UUID) ierr.IErr
+
+	// GetAll retrieves every APIKey in the repository.
+	GetAll() ([]*apikey.APIKey, ierr.IErr)
+}
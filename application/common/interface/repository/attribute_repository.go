@@ -0,0 +1,21 @@
+package irepo
+
+import (
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/attribute"
+)
+
+// IAttribute defines the interface for the repository layer responsible for the custom attribute schema.
+type IAttribute interface {
+	// Save adds a new attribute Definition to the repository or replaces the one with the same name.
+	Save(*attribute.Definition) ierr.IErr
+
+	// Get retrieves an attribute Definition by its name.
+	Get(string) (*attribute.Definition, ierr.IErr)
+
+	// Delete removes an attribute Definition from the repository by its name.
+	Delete(string) ierr.IErr
+
+	// GetAll retrieves every attribute Definition in the repository.
+	GetAll() ([]*attribute.Definition, ierr.IErr)
+}
@@ -0,0 +1,29 @@
+package irepo
+
+import (
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/webhook"
+	"github.com/google/uuid"
+)
+
+// IDelivery defines the interface for the repository layer responsible for the deliveries of webhooks,
+// which is both their retry queue and their delivery log.
+type IDelivery interface {
+	// Save adds a new Delivery to the repository or updates an existing one.
+	Save(*webhook.Delivery) ierr.IErr
+
+	// GetByWebhook retrieves the deliveries of a webhook, newest first.
+	GetByWebhook(uuid.UUID) ([]*webhook.Delivery, ierr.IErr)
+
+	// GetDue retrieves the pending deliveries due for an attempt at the given time, oldest first.
+	GetDue(time.Time) ([]*webhook.Delivery, ierr.IErr)
+
+	// DeleteByWebhook removes every delivery of a webhook.
+	DeleteByWebhook(uuid.UUID) ierr.IErr
+
+	// DeleteFinishedBefore removes the succeeded and dead-lettered deliveries queued before the given time,
+	// and returns how many it removed.
+	DeleteFinishedBefore(time.Time) (int, ierr.IErr)
+}
@@ -0,0 +1,25 @@
+package irepo
+
+import (
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
+	"github.com/google/uuid"
+)
+
+// IJob defines the interface for the repository layer responsible for the background jobs, which is
+// both their queue and their history. Jobs of every tenant share the repository, and record their tenant.
+type IJob interface {
+	// Save adds a new Job to the repository or updates an existing one.
+	Save(*job.Job) ierr.IErr
+
+	// Get retrieves a Job by its unique UUID.
+	Get(uuid.UUID) (*job.Job, ierr.IErr)
+
+	// GetAll retrieves every Job in the repository, oldest first.
+	GetAll() ([]*job.Job, ierr.IErr)
+
+	// DeleteFinishedBefore removes the jobs that finished before the given time, and returns how many it removed.
+	DeleteFinishedBefore(time.Time) (int, ierr.IErr)
+}
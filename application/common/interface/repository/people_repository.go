@@ -1,22 +1,51 @@
 package irepo
 
 import (
+	"context"
+
 	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
 	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
 	"github.com/google/uuid"
 )
 
 // IPerson defines the interface for the repository layer responsible for CRUD operations on Person entities.
+// People are partitioned by the tenant carried by the context: every method only ever sees the people
+// of that tenant.
 type IPerson interface {
 	// Save adds a new Person to the repository or updates an existing one.
-	Save(*model.Person) ierr.IErr
+	// It returns a QuotaExceeded error when the tenant already stores as many people as allowed.
+	Save(context.Context, *model.Person) ierr.IErr
+
+	// Create adds a new Person to the repository, failing with a Conflict when a person, current or retired
+	// by a merge, already holds its UUID. Unlike Save, it never overwrites an existing Person.
+	// It returns a QuotaExceeded error when the tenant already stores as many people as allowed.
+	Create(context.Context, *model.Person) ierr.IErr
 
 	// Get retrieves a Person by their unique UUID.
-	Get(uuid.UUID) (*model.Person, ierr.IErr)
+	Get(context.Context, uuid.UUID) (*model.Person, ierr.IErr)
 
 	// Delete removes a Person from the repository by their UUID.
-	Delete(uuid.UUID) ierr.IErr
+	Delete(context.Context, uuid.UUID) ierr.IErr
 
 	// GetAll retrieves all Person entities in the repository.
-	GetAll() ([]*model.Person, ierr.IErr)
+	GetAll(context.Context) ([]*model.Person, ierr.IErr)
+
+	// Stream calls the function with every Person in the repository, one at a time in the order they were
+	// first saved, without holding them all in memory. It stops when the function returns false or the
+	// context is done. People saved or deleted while streaming may or may not be seen.
+	Stream(context.Context, func(*model.Person) bool) ierr.IErr
+
+	// Tenants lists the tenants that stored people, in alphabetical order, regardless of the tenant of the context.
+	Tenants(context.Context) ([]string, ierr.IErr)
+
+	// Merge atomically saves the survivor of a merge, removes the retired people
+	// and records the merge so that the retired IDs redirect to the survivor.
+	Merge(context.Context, *model.Person, *model.Merge) ierr.IErr
+
+	// Redirect returns the UUID of the Person that a retired UUID was merged into.
+	Redirect(context.Context, uuid.UUID) (uuid.UUID, ierr.IErr)
+
+	// Merges lists the merges that folded people into the Person with the given UUID, directly or through
+	// someone later merged into them, in the order they happened.
+	Merges(context.Context, uuid.UUID) ([]*model.Merge, ierr.IErr)
 }
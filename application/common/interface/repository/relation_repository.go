@@ -0,0 +1,33 @@
+package irepo
+
+import (
+	"context"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/relation"
+	"github.com/google/uuid"
+)
+
+// IRelation defines the interface for the repository layer responsible for relations between people.
+// Relations are partitioned by the tenant carried by the context, as people are: every method only ever
+// sees the relations of that tenant.
+type IRelation interface {
+	// Save adds a new Relation to the repository, failing with a Conflict if the same edge already exists.
+	Save(context.Context, *relation.Relation) ierr.IErr
+
+	// Get retrieves a Relation by its unique UUID.
+	Get(context.Context, uuid.UUID) (*relation.Relation, ierr.IErr)
+
+	// Delete removes a Relation from the repository by its UUID.
+	Delete(context.Context, uuid.UUID) ierr.IErr
+
+	// GetByPerson retrieves every Relation that involves the Person with the given UUID.
+	GetByPerson(context.Context, uuid.UUID) ([]*relation.Relation, ierr.IErr)
+
+	// DeleteByPerson removes every Relation that involves the Person with the given UUID.
+	DeleteByPerson(context.Context, uuid.UUID) ierr.IErr
+
+	// Reassign moves every Relation of the first Person over to the second one,
+	// dropping the relations that would become self-references or duplicates.
+	Reassign(ctx context.Context, from uuid.UUID, to uuid.UUID) ierr.IErr
+}
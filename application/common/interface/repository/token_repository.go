@@ -0,0 +1,32 @@
+package irepo
+
+import (
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/user"
+	"github.com/google/uuid"
+)
+
+// IToken defines the interface for the repository layer responsible for refresh tokens
+// and revoked access tokens.
+type IToken interface {
+	// SaveRefreshToken adds a new RefreshToken to the repository.
+	SaveRefreshToken(*user.RefreshToken) ierr.IErr
+
+	// GetRefreshToken retrieves a RefreshToken by the hash of its secret.
+	GetRefreshToken(string) (*user.RefreshToken, ierr.IErr)
+
+	// DeleteRefreshToken removes a RefreshToken by its UUID.
+	// It returns NotFound when the token was already removed, so a token can only be consumed once.
+	DeleteRefreshToken(uuid.UUID) ierr.IErr
+
+	// DeleteRefreshTokensOf removes every RefreshToken issued to the user with the given UUID.
+	DeleteRefreshTokensOf(uuid.UUID) ierr.IErr
+
+	// RevokeAccessToken rejects the access token with the given ID until it expires.
+	RevokeAccessToken(string, time.Time) ierr.IErr
+
+	// IsAccessTokenRevoked reports whether the access token with the given ID was revoked.
+	IsAccessTokenRevoked(string) (bool, ierr.IErr)
+}
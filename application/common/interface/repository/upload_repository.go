@@ -0,0 +1,24 @@
+package irepo
+
+import (
+	"context"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/google/uuid"
+)
+
+// IUpload defines the interface for the repository layer holding the uploads of the imports run as background
+// jobs, until their job finishes. Uploads are partitioned by the tenant carried by the context.
+type IUpload interface {
+	// Save stores the content of an upload under its unique UUID.
+	Save(ctx context.Context, id uuid.UUID, content []byte) ierr.IErr
+
+	// Get retrieves the content of an upload by its unique UUID.
+	Get(ctx context.Context, id uuid.UUID) ([]byte, ierr.IErr)
+
+	// Size returns the size in bytes of an upload, without reading it.
+	Size(ctx context.Context, id uuid.UUID) (int64, ierr.IErr)
+
+	// Delete removes an upload by its unique UUID.
+	Delete(ctx context.Context, id uuid.UUID) ierr.IErr
+}
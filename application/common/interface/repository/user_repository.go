@@ -0,0 +1,26 @@
+package irepo
+
+import (
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/user"
+	"github.com/google/uuid"
+)
+
+// IUser defines the interface for the repository layer responsible for local user accounts.
+type IUser interface {
+	// Save adds a new User to the repository or updates an existing one.
+	// It returns a Conflict when another user already has the same username.
+	Save(*user.User) ierr.IErr
+
+	// Get retrieves a User by its unique UUID.
+	Get(uuid.UUID) (*user.User, ierr.IErr)
+
+	// GetByUsername retrieves a User by its username.
+	GetByUsername(string) (*user.User, ierr.IErr)
+
+	// Delete removes a User from the repository by its UUID.
+	Delete(uuid.UUID) ierr.IErr
+
+	// GetAll retrieves every User in the repository.
+	GetAll() ([]*user.User, ierr.IErr)
+}
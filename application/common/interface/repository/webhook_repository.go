@@ -0,0 +1,22 @@
+package irepo
+
+import (
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/webhook"
+	"github.com/google/uuid"
+)
+
+// IWebhook defines the interface for the repository layer responsible for webhook subscriptions.
+type IWebhook interface {
+	// Save adds a new Webhook to the repository or updates an existing one.
+	Save(*webhook.Webhook) ierr.IErr
+
+	// Get retrieves a Webhook by its unique UUID.
+	Get(uuid.UUID) (*webhook.Webhook, ierr.IErr)
+
+	// Delete removes a Webhook from the repository by its UUID.
+	Delete(uuid.UUID) ierr.IErr
+
+	// GetAll retrieves every Webhook in the repository.
+	GetAll() ([]*webhook.Webhook, ierr.IErr)
+}
@@ -0,0 +1,20 @@
+package ischedule
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/domain/model/schedule"
+)
+
+// ITask is a maintenance task run on a schedule, such as compacting the job table.
+type ITask interface {
+	// Run runs the task once, and returns a summary of what it did, e.g. "deleted 3 jobs".
+	// The context is done when the scheduler stops: the task should then stop early and return its error.
+	Run(ctx context.Context) (string, error)
+}
+
+// IScheduler runs the tasks registered with it on their schedules.
+type IScheduler interface {
+	// Schedules returns the schedule of every registered task, with the status of its last run, in alphabetical order.
+	Schedules() []*schedule.Schedule
+}
@@ -0,0 +1,17 @@
+package itoken
+
+import "time"
+
+// AccessToken is a signed, short-lived credential sent as a bearer token.
+type AccessToken struct {
+	Token     string    // Encoded token
+	ID        string    // Unique identifier of the token, used to revoke it
+	ExpiresAt time.Time // Time after which the token is rejected
+}
+
+// IIssuer defines the interface for signing access tokens.
+type IIssuer interface {
+	// Issue signs an access token for the subject belonging to the tenant, granting it the given roles.
+	// The tenant is left out of the token when it is empty.
+	Issue(subject string, tenant string, roles []string) (*AccessToken, error)
+}
@@ -0,0 +1,45 @@
+package tenant
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Quotas limits the number of people each tenant may store.
+type Quotas struct {
+	Default int            // Limit of tenants without their own; unlimited when zero
+	Tenants map[string]int // Limits of specific tenants; unlimited when zero
+}
+
+// Limit returns the maximum number of people the tenant may store, or zero when unlimited.
+func (q Quotas) Limit(id string) int {
+	if limit, ok := q.Tenants[id]; ok {
+		return limit
+	}
+	return q.Default
+}
+
+// ParseQuotas parses the default quota and a list of per-tenant quotas written as "tenant=limit".
+func ParseQuotas(defaultLimit string, limits []string) (Quotas, error) {
+	quotas := Quotas{Tenants: make(map[string]int, len(limits))}
+
+	if defaultLimit != "" {
+		limit, err := strconv.Atoi(defaultLimit)
+		if err != nil || limit < 0 {
+			return Quotas{}, fmt.Errorf("invalid default tenant quota %q", defaultLimit)
+		}
+		quotas.Default = limit
+	}
+
+	for _, entry := range limits {
+		id, raw, ok := strings.Cut(entry, "=")
+		id = strings.TrimSpace(id)
+		limit, err := strconv.Atoi(strings.TrimSpace(raw))
+		if !ok || !Valid(id) || err != nil || limit < 0 {
+			return Quotas{}, fmt.Errorf("invalid tenant quota %q, expected tenant=limit", entry)
+		}
+		quotas.Tenants[id] = limit
+	}
+	return quotas, nil
+}
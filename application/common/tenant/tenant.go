@@ -0,0 +1,40 @@
+// Package tenant identifies the customer organisation a request belongs to and carries it through the request context.
+package tenant
+
+import (
+	"context"
+	"regexp"
+)
+
+// Default is the tenant of requests that don't name one.
+const Default = "default"
+
+// Claim is the token claim naming the tenant of the caller, unless another claim is configured.
+const Claim = "tenant"
+
+// pattern restricts tenant identifiers to lowercase DNS labels, so they can also be used as subdomains.
+var pattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Valid reports whether the identifier is a valid tenant identifier.
+func Valid(id string) bool {
+	return pattern.MatchString(id)
+}
+
+// tenantKey is the context key under which the tenant is stored.
+type tenantKey struct{}
+
+// WithTenant returns a copy of the context carrying the tenant.
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// From returns the tenant carried by the context, or the Default tenant.
+func From(ctx context.Context) string {
+	if ctx == nil {
+		return Default
+	}
+	if id, ok := ctx.Value(tenantKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Default
+}
@@ -15,6 +15,12 @@ import (
 const (
 	// AuthenticationErrorType is used for authentication-related errors.
 	Authentication = "Authentication"
+
+	// Forbidden is used for errors caused by missing permissions.
+	Forbidden = "Forbidden"
+
+	// QuotaExceeded is used for errors caused by a tenant reaching its quota.
+	QuotaExceeded = "QuotaExceeded"
 )
 
 // Error represents a combined application error with a type and message.
@@ -41,5 +47,15 @@ func new(kind string, message string) Error {
 
 // InvalidCredential return Error of type Authentication with the message recieved.
 func InvalidCredential(message string) Error {
-	return new(Authentication, "invalid credentials")
+	return new(Authentication, message)
+}
+
+// AccessDenied return Error of type Forbidden with the message recieved.
+func AccessDenied(message string) Error {
+	return new(Forbidden, message)
+}
+
+// QuotaReached return Error of type QuotaExceeded with the message recieved.
+func QuotaReached(message string) Error {
+	return new(QuotaExceeded, message)
 }
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	ijobs "github.com/Efamamo/GoCrudChallange/application/common/interface/jobs"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
+	"github.com/google/uuid"
+)
+
+// CancelJobCommand represents the command to cancel a job by its ID.
+type CancelJobCommand struct {
+	Context context.Context // Carries the principal, who should own the job, and its tenant
+
+	ID uuid.UUID
+}
+
+// CancelJobHandler is a command handler for cancelling a queued or running job.
+type CancelJobHandler struct {
+	runner     ijobs.IRunner    // Runner of the jobs, which stops them.
+	jobs       irepo.IJob       // Repository of the jobs, to check who may cancel them.
+	authorizer *auth.Authorizer // Decides whether the caller may access the job.
+}
+
+// Ensure CancelJobHandler implements the IHandler interface for handling commands.
+var _ icmd.IHandler[*CancelJobCommand, *job.Job] = &CancelJobHandler{}
+
+// NewCancelJobHandler creates a new instance of CancelJobHandler with the provided runner and repository.
+// Every caller may cancel every job of their tenant when the authorizer is nil.
+func NewCancelJobHandler(runner ijobs.IRunner, jobs irepo.IJob, authorizer *auth.Authorizer) *CancelJobHandler {
+	return &CancelJobHandler{runner: runner, jobs: jobs, authorizer: authorizer}
+}
+
+// Handle processes the command to cancel a job by its ID. Only the submitter of the job, or an admin, may cancel it;
+// jobs of other tenants or owners are reported as not found.
+func (h *CancelJobHandler) Handle(command *CancelJobCommand) (*job.Job, ierr.IErr) {
+	j, err := h.jobs.Get(command.ID)
+	if err != nil {
+		return nil, err
+	}
+	if j.Submitter().Tenant != tenant.From(command.Context) || !h.authorizer.CanAccess(command.Context, j.Owner()) {
+		return nil, ierr.NewNotFound("job not found")
+	}
+
+	return h.runner.Cancel(command.ID)
+}
@@ -0,0 +1,38 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ischedule "github.com/Efamamo/GoCrudChallange/application/common/interface/schedule"
+)
+
+// CompactKind is the name of the scheduled task compacting the job table.
+const CompactKind = "jobs.compact"
+
+// CompactJobsHandler is the scheduled task removing the jobs that finished longer ago than the retention,
+// so that the job table doesn't grow forever.
+type CompactJobsHandler struct {
+	jobs      irepo.IJob
+	retention time.Duration
+}
+
+// Compile-time check to ensure CompactJobsHandler implements ITask.
+var _ ischedule.ITask = &CompactJobsHandler{}
+
+// NewCompactJobsHandler initializes a new CompactJobsHandler with a given IJob repository,
+// keeping the finished jobs for the retention.
+func NewCompactJobsHandler(jobs irepo.IJob, retention time.Duration) *CompactJobsHandler {
+	return &CompactJobsHandler{jobs: jobs, retention: retention}
+}
+
+// Run removes the jobs that finished before the retention, and reports how many it removed.
+func (h *CompactJobsHandler) Run(_ context.Context) (string, error) {
+	deleted, err := h.jobs.DeleteFinishedBefore(time.Now().UTC().Add(-h.retention))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("deleted %d jobs", deleted), nil
+}
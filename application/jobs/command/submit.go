@@ -0,0 +1,36 @@
+package command
+
+import (
+	"context"
+
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	ijobs "github.com/Efamamo/GoCrudChallange/application/common/interface/jobs"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
+)
+
+// SubmitJobCommand holds the data required to run a job in the background.
+type SubmitJobCommand struct {
+	Context context.Context // Carries the principal and the tenant the job runs on behalf of
+
+	Kind   string // Kind of the job, naming the task that runs it, e.g. people.purge
+	Params []byte // JSON parameters of the job, checked by its task; may be nil
+}
+
+// SubmitJobHandler is responsible for handling the logic of submitting a job.
+type SubmitJobHandler struct {
+	runner ijobs.IRunner
+}
+
+// Compile-time check to ensure SubmitJobHandler implements IHandler for SubmitJobCommand.
+var _ icmd.IHandler[*SubmitJobCommand, *job.Job] = &SubmitJobHandler{}
+
+// NewSubmitJobHandler initializes a new SubmitJobHandler with a given IRunner.
+func NewSubmitJobHandler(runner ijobs.IRunner) *SubmitJobHandler {
+	return &SubmitJobHandler{runner: runner}
+}
+
+// Handle processes the SubmitJobCommand, queuing the job, which is returned before it runs.
+func (h *SubmitJobHandler) Handle(command *SubmitJobCommand) (*job.Job, ierr.IErr) {
+	return h.runner.Submit(command.Context, command.Kind, command.Params)
+}
@@ -0,0 +1,54 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
+	"github.com/google/uuid"
+)
+
+// GetJobQuery represents the query to retrieve a job by its ID.
+type GetJobQuery struct {
+	Context context.Context // Carries the principal, who should own the job, and its tenant
+
+	ID uuid.UUID
+}
+
+// Ensure GetJobHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[*GetJobQuery, *job.Job] = &GetJobHandler{}
+
+// GetJobHandler is a query handler for retrieving a job, to follow its progress.
+type GetJobHandler struct {
+	repo       irepo.IJob       // Repository interface for job operations.
+	authorizer *auth.Authorizer // Decides whether the caller may access the job.
+}
+
+// NewGetJobHandler creates a new instance of GetJobHandler with the provided repository.
+// Every caller may retrieve every job of their tenant when the authorizer is nil.
+func NewGetJobHandler(repo irepo.IJob, authorizer *auth.Authorizer) *GetJobHandler {
+	return &GetJobHandler{repo: repo, authorizer: authorizer}
+}
+
+// Handle processes the query to retrieve a job by its ID.
+// Jobs of other tenants, or submitted by someone else unless the caller is an admin, are reported as not found.
+func (h *GetJobHandler) Handle(q *GetJobQuery) (*job.Job, error) {
+	j, err := h.repo.Get(q.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible(q.Context, h.authorizer, j) {
+		return nil, ierr.NewNotFound("job not found")
+	}
+	return j, nil
+}
+
+// visible reports whether the caller may see the job, which belongs to their tenant and, unless
+// they are an admin, was submitted by them.
+func visible(ctx context.Context, authorizer *auth.Authorizer, j *job.Job) bool {
+	return j.Submitter().Tenant == tenant.From(ctx) && authorizer.CanAccess(ctx, j.Owner())
+}
@@ -0,0 +1,52 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
+)
+
+// GetJobsQuery holds the optional filters applied when listing jobs.
+type GetJobsQuery struct {
+	Context context.Context // Carries the principal, whose jobs are listed, and its tenant
+
+	Status job.Status // Optional; only the jobs in this state are listed
+}
+
+// Ensure GetJobsHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[*GetJobsQuery, []*job.Job] = &GetJobsHandler{}
+
+// GetJobsHandler is a query handler for listing jobs.
+type GetJobsHandler struct {
+	repo       irepo.IJob       // Repository interface for job operations.
+	authorizer *auth.Authorizer // Decides which jobs the caller may access.
+}
+
+// NewGetJobsHandler creates a new instance of GetJobsHandler with the provided repository.
+// Every caller may list every job of their tenant when the authorizer is nil.
+func NewGetJobsHandler(repo irepo.IJob, authorizer *auth.Authorizer) *GetJobsHandler {
+	return &GetJobsHandler{repo: repo, authorizer: authorizer}
+}
+
+// Handle processes the query to list the jobs of the tenant matching the filters, oldest first.
+// Only the jobs submitted by the caller are listed, unless the caller is an admin.
+func (h *GetJobsHandler) Handle(q *GetJobsQuery) ([]*job.Job, error) {
+	jobs, err := h.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*job.Job, 0, len(jobs))
+	for _, j := range jobs {
+		if !visible(q.Context, h.authorizer, j) {
+			continue
+		}
+		if q.Status == "" || j.Status() == q.Status {
+			filtered = append(filtered, j)
+		}
+	}
+	return filtered, nil
+}
@@ -1,46 +1,111 @@
 package command
 
 import (
+	"context"
+	"fmt"
+
 	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
 	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
 	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
 	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
 )
 
 // CreatePersonCommand holds the data required to create a new Person entity.
 type CreatePersonCommand struct {
+	Context context.Context // Carries the principal, who becomes the owner of the person
+
+	ID      uuid.UUID // Identifier chosen by the client, e.g. to preserve it when migrating; generated when nil
 	Name    string
 	Age     int16
 	Hobbies []string
+	Email   string
+	Phone   string
+	Address *model.AddressConfig
+
+	Attributes map[string]any
 }
 
 // CreatePersonHandler is responsible for handling the logic of creating a new Person entity.
 type CreatePersonHandler struct {
-	repo irepo.IPerson
+	repo       irepo.IPerson
+	attributes irepo.IAttribute
+	events     ievents.IPublisher
 }
 
 // Compile-time check to ensure CreatePersonHandler implements IHandler for CreatePersonCommand.
 var _ icmd.IHandler[*CreatePersonCommand, *model.Person] = &CreatePersonHandler{}
 
-// NewCreatePersonHandler initializes a new CreatePersonHandler with a given IPerson repository.
-func NewCreatePersonHandler(repo irepo.IPerson) *CreatePersonHandler {
-	return &CreatePersonHandler{repo: repo}
+// NewCreatePersonHandler initializes a new CreatePersonHandler with the given IPerson and IAttribute repositories.
+// Creations are published to the events publisher, unless it is nil.
+func NewCreatePersonHandler(repo irepo.IPerson, attributes irepo.IAttribute, events ievents.IPublisher) *CreatePersonHandler {
+	return &CreatePersonHandler{repo: repo, attributes: attributes, events: events}
 }
 
-// Handle processes the CreatePersonCommand to create a new Person entity.
+// Handle processes the CreatePersonCommand to create a new Person entity owned by the caller.
+// It returns a Conflict error when the ID chosen by the client is already taken, including by a person
+// created concurrently.
 func (h *CreatePersonHandler) Handle(command *CreatePersonCommand) (*model.Person, ierr.IErr) {
+	person, err := h.validate(command)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.repo.Create(command.Context, person); err != nil {
+		return nil, err
+	}
+	publish(command.Context, h.events, ievents.PersonCreated, person)
+
+	return person, nil
+}
+
+// validate builds the Person of the command without saving it, checking that its ID is available
+// and that it satisfies the attribute schema.
+func (h *CreatePersonHandler) validate(command *CreatePersonCommand) (*model.Person, ierr.IErr) {
+	if command.ID != uuid.Nil {
+		if err := h.checkAvailable(command.Context, command.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	schema, err := schemaOf(h.attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	person, err := model.CreatePerson(&model.PersonConfig{
+		ID:      command.ID,
 		Name:    command.Name,
 		Age:     command.Age,
 		Hobbies: command.Hobbies,
+		Email:   command.Email,
+		Phone:   command.Phone,
+		Address: command.Address,
+
+		Attributes: command.Attributes,
+		Schema:     schema,
+
+		Owner: ownerOf(command.Context),
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	if err := h.repo.Save(person); err != nil {
-		return nil, err
+	return person, nil
+}
+
+// checkAvailable checks that no person, current or retired by a merge, has the ID. It lets validation
+// report a taken ID early; the repository checks it again when the person is created.
+func (h *CreatePersonHandler) checkAvailable(ctx context.Context, id uuid.UUID) ierr.IErr {
+	if _, err := h.repo.Get(ctx, id); err == nil {
+		return ierr.NewConflictWith(fmt.Sprintf("person with id %s already exists", id), id.String())
+	} else if err.Type() != ierr.NotFound {
+		return err
 	}
 
-	return person, nil
+	if to, err := h.repo.Redirect(ctx, id); err == nil {
+		return ierr.NewConflictWith(fmt.Sprintf("person with id %s was merged into another person", id), to.String())
+	}
+	return nil
 }
@@ -1,29 +1,55 @@
 package command
 
 import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
 	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
 	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
 	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
 	"github.com/google/uuid"
 )
 
+// DeletePersonCommand represents the command to delete a person by their ID.
+type DeletePersonCommand struct {
+	Context context.Context // Carries the principal, who should own the person
+
+	ID uuid.UUID
+}
+
 // DeletePersonHandler is a command handler for deleting a person by their ID.
 type DeletePersonHandler struct {
-	repo irepo.IPerson // Repository interface for person operations.
+	repo       irepo.IPerson      // Repository interface for person operations.
+	relations  irepo.IRelation    // Repository interface for relation operations.
+	authorizer *auth.Authorizer   // Decides whether the caller may access the person.
+	events     ievents.IPublisher // Notified of the deletions.
 }
 
 // Ensure DeletePersonHandler implements the IHandler interface for handling commands.
-var _ icmd.IHandler[uuid.UUID, bool] = &DeletePersonHandler{}
+var _ icmd.IHandler[*DeletePersonCommand, bool] = &DeletePersonHandler{}
 
-// NewDeletePersonHandler creates a new instance of DeletePersonHandler with the provided repository.
-func NewDeletePersonHandler(repo irepo.IPerson) *DeletePersonHandler {
-	return &DeletePersonHandler{repo: repo}
+// NewDeletePersonHandler creates a new instance of DeletePersonHandler with the provided repositories.
+// Every caller may delete every person when the authorizer is nil, and deletions aren't published when events is nil.
+func NewDeletePersonHandler(repo irepo.IPerson, relations irepo.IRelation, authorizer *auth.Authorizer, events ievents.IPublisher) *DeletePersonHandler {
+	return &DeletePersonHandler{repo: repo, relations: relations, authorizer: authorizer, events: events}
 }
 
-// Handle processes the command to delete a person by their ID.
-func (h *DeletePersonHandler) Handle(id uuid.UUID) (bool, ierr.IErr) {
-	if err := h.repo.Delete(id); err != nil {
+// Handle processes the command to delete a person by their ID, along with every relation they take part in.
+// Only the owner of the person, or an admin, may delete it.
+func (h *DeletePersonHandler) Handle(command *DeletePersonCommand) (bool, ierr.IErr) {
+	person, err := owned(command.Context, h.repo, h.authorizer, command.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := h.repo.Delete(command.Context, command.ID); err != nil {
+		return false, err
+	}
+
+	if err := h.relations.DeleteByPerson(command.Context, command.ID); err != nil {
 		return false, err
 	}
+	publish(command.Context, h.events, ievents.PersonDeleted, person)
 	return true, nil
 }
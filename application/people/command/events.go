@@ -0,0 +1,23 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+)
+
+// publish notifies the publisher of a saved change of the person; nothing is published when the publisher is nil.
+func publish(ctx context.Context, publisher ievents.IPublisher, eventType ievents.EventType, person *model.Person) {
+	if publisher == nil {
+		return
+	}
+	publisher.Publish(ctx, ievents.PersonEvent{
+		Type:       eventType,
+		Tenant:     tenant.From(ctx),
+		Person:     person.Clone(),
+		OccurredAt: time.Now().UTC(),
+	})
+}
@@ -0,0 +1,242 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
+	ijobs "github.com/Efamamo/GoCrudChallange/application/common/interface/jobs"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
+)
+
+// ImportKind is the kind of the jobs importing people in the background.
+const ImportKind = "people.import"
+
+// ImportParams holds the parameters of a job importing people.
+type ImportParams struct {
+	Format   string    `json:"format"`    // Media type of the upload, e.g. text/csv or application/x-ndjson
+	DryRun   bool      `json:"dry_run"`   // Whether the rows are only validated, without creating anyone
+	UploadID uuid.UUID `json:"upload_id"` // ID of the upload in the IUpload repository, deleted once the job finished
+}
+
+// ImportResult holds the result of a job importing people.
+type ImportResult struct {
+	DryRun    bool               `json:"dry_run"`   // Whether the rows were only validated
+	Processed int                `json:"processed"` // Number of rows processed
+	Succeeded int                `json:"succeeded"` // Number of rows that succeeded
+	Failed    int                `json:"failed"`    // Number of rows that failed
+	Rows      []ImportRowOutcome `json:"rows"`      // Outcome of every row
+}
+
+// ImportRowOutcome is the outcome of a row in the result of a job importing people.
+type ImportRowOutcome struct {
+	Line  int        `json:"line"`            // Line of the row in the upload, starting at 1
+	ID    *uuid.UUID `json:"id,omitempty"`    // ID of the person created, or that a dry run would create
+	Error string     `json:"error,omitempty"` // Why the row failed
+}
+
+// ImportRow is a row of an upload, decoded into the person it creates.
+type ImportRow struct {
+	Line   int                  // Line of the row in the upload, starting at 1
+	Person *CreatePersonCommand // Person created by the row; its Context is the one of the import
+	Err    error                // Why the row couldn't be decoded, in which case it fails without a Person
+}
+
+// IRowReader reads the rows of an upload one at a time.
+// Next returns io.EOF after the last row, and any other error when the rest of the upload can't be read.
+type IRowReader interface {
+	Next() (*ImportRow, error)
+}
+
+// RowDecoder returns a reader of the rows of an upload in the format, a media type such as text/csv,
+// or an error when the format isn't one uploads are imported from.
+type RowDecoder func(format string, upload []byte) (IRowReader, error)
+
+// ImportPeopleCommand holds the data required to import people in bulk.
+type ImportPeopleCommand struct {
+	Context context.Context // Carries the principal, who becomes the owner of the people
+
+	Rows   IRowReader
+	DryRun bool // Whether the rows are only validated, without creating anyone
+}
+
+// ImportPeopleHandler is responsible for handling the logic of importing people in bulk, right away or as
+// the task of the jobs importing them in the background.
+type ImportPeopleHandler struct {
+	people  *CreatePersonHandler
+	uploads irepo.IUpload
+	decode  RowDecoder
+	maxSize int64 // Size in bytes of the largest upload imported by a job; unlimited when zero
+}
+
+// Compile-time check to ensure ImportPeopleHandler implements IHandler for ImportPeopleCommand.
+var _ icmd.IHandler[*ImportPeopleCommand, *model.Import] = &ImportPeopleHandler{}
+
+// Compile-time check to ensure ImportPeopleHandler implements ITask, and releases the uploads of its jobs.
+var (
+	_ ijobs.ITask    = &ImportPeopleHandler{}
+	_ ijobs.ICleaner = &ImportPeopleHandler{}
+)
+
+// NewImportPeopleHandler initializes a new ImportPeopleHandler with the given IPerson, IAttribute and IUpload
+// repositories, the RowDecoder reading the uploads of jobs, and the size in bytes of the largest upload they import,
+// unlimited when zero. Creations are published to the events publisher, unless it is nil.
+func NewImportPeopleHandler(repo irepo.IPerson, attributes irepo.IAttribute, uploads irepo.IUpload, events ievents.IPublisher, decode RowDecoder, maxSize int64) *ImportPeopleHandler {
+	return &ImportPeopleHandler{people: NewCreatePersonHandler(repo, attributes, events), uploads: uploads, decode: decode, maxSize: maxSize}
+}
+
+// Handle processes the ImportPeopleCommand, creating the person of every valid row as the CreatePersonCommand would.
+// A failing row doesn't stop the import: its outcome is recorded on the returned Import, which is completed,
+// or failed when the rest of the upload couldn't be read.
+func (h *ImportPeopleHandler) Handle(command *ImportPeopleCommand) (*model.Import, ierr.IErr) {
+	imp := model.NewImport(ownerOf(command.Context), command.DryRun)
+	h.run(command.Context, command.Rows, imp, func(job.Progress) {})
+	return imp, nil
+}
+
+// Validate makes sure the parameters name an upload of the tenant of the context, no larger than the maximum size,
+// in a format people are imported from.
+func (h *ImportPeopleHandler) Validate(ctx context.Context, params []byte) error {
+	p, err := parseImportParams(params)
+	if err != nil {
+		return err
+	}
+	if _, err := h.decode(p.Format, nil); err != nil {
+		return err
+	}
+	size, serr := h.uploads.Size(ctx, p.UploadID)
+	if serr != nil {
+		return fmt.Errorf("upload %s: %s", p.UploadID, serr.Error())
+	}
+	if h.maxSize > 0 && size > h.maxSize {
+		return fmt.Errorf("upload %s should be at most %d bytes", p.UploadID, h.maxSize)
+	}
+	return nil
+}
+
+// Run imports the upload of the parameters, reporting the progress after every row, and returns the outcome
+// of every row. The job fails when the rest of the upload can't be read, or when it is cancelled, the people
+// created by the rows processed so far being kept.
+func (h *ImportPeopleHandler) Run(ctx context.Context, params []byte, report ijobs.Report) (any, error) {
+	p, err := parseImportParams(params)
+	if err != nil {
+		return nil, err
+	}
+	upload, uerr := h.uploads.Get(ctx, p.UploadID)
+	if uerr != nil {
+		return nil, uerr
+	}
+	rows, err := h.decode(p.Format, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := model.NewImport(ownerOf(ctx), p.DryRun)
+	h.run(ctx, rows, imp, report)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if imp.Status() == model.ImportFailed {
+		return nil, fmt.Errorf("%s, after %d rows", imp.Reason(), imp.Processed())
+	}
+	report(job.Progress{Done: imp.Processed(), Total: imp.Processed(), Message: "imported people"})
+	return newImportResult(imp), nil
+}
+
+// Cleanup deletes the upload of a finished job.
+func (h *ImportPeopleHandler) Cleanup(ctx context.Context, params []byte) {
+	if p, err := parseImportParams(params); err == nil {
+		h.uploads.Delete(ctx, p.UploadID)
+	}
+}
+
+// run processes the rows of the upload, recording their outcome on the import, until the context is done.
+func (h *ImportPeopleHandler) run(ctx context.Context, rows IRowReader, imp *model.Import, report ijobs.Report) {
+	seen := make(map[uuid.UUID]int) // IDs chosen by the rows of a dry run, mapped to the first line choosing them
+
+	for ctx.Err() == nil {
+		row, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			imp.Complete(time.Now().UTC())
+			return
+		}
+		if err != nil {
+			imp.Fail(err.Error(), time.Now().UTC())
+			return
+		}
+
+		result := model.ImportRowResult{Line: row.Line}
+		if row.Err != nil {
+			result.Error = row.Err.Error()
+		} else if person, err := h.create(withContext(ctx, row.Person), imp.DryRun()); err != nil {
+			result.Error = err.Error()
+		} else if line, taken := seen[person.Id()]; taken && imp.DryRun() {
+			result.Error = fmt.Sprintf("person with id %s is already imported by line %d", person.Id(), line)
+		} else {
+			seen[person.Id()] = row.Line
+			result.ID = person.Id()
+		}
+		imp.Record(result)
+		report(job.Progress{Done: imp.Processed(), Message: "importing people"})
+	}
+}
+
+// create creates the person of a row, or only builds it for a dry run.
+func (h *ImportPeopleHandler) create(command *CreatePersonCommand, dryRun bool) (*model.Person, ierr.IErr) {
+	if dryRun {
+		return h.people.validate(command)
+	}
+	return h.people.Handle(command)
+}
+
+// withContext returns the creation of a row, carrying the context of the import.
+func withContext(ctx context.Context, command *CreatePersonCommand) *CreatePersonCommand {
+	row := *command
+	row.Context = ctx
+	return &row
+}
+
+// parseImportParams decodes the parameters of a job importing people.
+func parseImportParams(params []byte) (*ImportParams, error) {
+	p := &ImportParams{}
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(p); err != nil {
+		return nil, fmt.Errorf("invalid params: %s", err.Error())
+	}
+	if p.UploadID == uuid.Nil {
+		return nil, errors.New("params should have the upload_id of the upload to import")
+	}
+	return p, nil
+}
+
+// newImportResult maps a completed Import to the result of its job.
+func newImportResult(imp *model.Import) ImportResult {
+	rows := make([]ImportRowOutcome, 0, imp.Processed())
+	for _, row := range imp.Rows() {
+		outcome := ImportRowOutcome{Line: row.Line, Error: row.Error}
+		if row.ID != uuid.Nil {
+			id := row.ID
+			outcome.ID = &id
+		}
+		rows = append(rows, outcome)
+	}
+
+	return ImportResult{
+		DryRun:    imp.DryRun(),
+		Processed: imp.Processed(),
+		Succeeded: imp.Succeeded(),
+		Failed:    imp.Failed(),
+		Rows:      rows,
+	}
+}
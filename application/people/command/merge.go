@@ -0,0 +1,105 @@
+package command
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
+)
+
+// MergePeopleCommand represents the command to merge a set of people into a single survivor.
+type MergePeopleCommand struct {
+	Context context.Context // Carries the tenant of the people and the principal, who should own them
+
+	IDs        []uuid.UUID
+	SurvivorID uuid.UUID // Defaults to the first of IDs when nil
+	Name       string    // Name kept by the survivor; optional
+	Age        *int16    // Age kept by the survivor; optional
+}
+
+// MergePeopleResult holds the surviving person together with the recorded merge.
+type MergePeopleResult struct {
+	Survivor *model.Person
+	Merge    *model.Merge
+}
+
+// MergePeopleHandler is a command handler for merging duplicate people.
+type MergePeopleHandler struct {
+	repo       irepo.IPerson      // Repository interface for person operations.
+	relations  irepo.IRelation    // Repository interface for relation operations.
+	authorizer *auth.Authorizer   // Decides whether the caller may access the people.
+	events     ievents.IPublisher // Notified of the updated survivor and the retired people.
+}
+
+// Ensure MergePeopleHandler implements the IHandler interface for handling commands.
+var _ icmd.IHandler[*MergePeopleCommand, *MergePeopleResult] = &MergePeopleHandler{}
+
+// NewMergePeopleHandler creates a new instance of MergePeopleHandler with the provided repositories.
+// Every caller may merge every person when the authorizer is nil, and merges aren't published when events is nil.
+func NewMergePeopleHandler(repo irepo.IPerson, relations irepo.IRelation, authorizer *auth.Authorizer, events ievents.IPublisher) *MergePeopleHandler {
+	return &MergePeopleHandler{repo: repo, relations: relations, authorizer: authorizer, events: events}
+}
+
+// Handle processes the command to merge people into the survivor.
+// The relations of the retired people are carried over to the survivor. Every person merged should be
+// accessible to the caller; the others are reported as not found.
+func (h *MergePeopleHandler) Handle(command *MergePeopleCommand) (*MergePeopleResult, ierr.IErr) {
+	if len(command.IDs) == 0 {
+		return nil, ierr.NewValidation("ids can't be empty")
+	}
+
+	survivorID := command.SurvivorID
+	if survivorID == uuid.Nil {
+		survivorID = command.IDs[0]
+	}
+
+	survivor, err := owned(command.Context, h.repo, h.authorizer, survivorID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[uuid.UUID]struct{}{survivorID: {}}
+	retired := make([]*model.Person, 0, len(command.IDs))
+	for _, id := range command.IDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		person, err := owned(command.Context, h.repo, h.authorizer, id)
+		if err != nil {
+			return nil, err
+		}
+		retired = append(retired, person)
+	}
+
+	merge, err := model.MergePeople(survivor, retired, &model.MergeConfig{
+		Name: command.Name,
+		Age:  command.Age,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.repo.Merge(command.Context, survivor, merge); err != nil {
+		return nil, err
+	}
+
+	for _, id := range merge.RetiredIDs() {
+		if err := h.relations.Reassign(command.Context, id, survivor.Id()); err != nil {
+			return nil, err
+		}
+	}
+
+	publish(command.Context, h.events, ievents.PersonUpdated, survivor)
+	for _, person := range retired {
+		publish(command.Context, h.events, ievents.PersonDeleted, person)
+	}
+
+	return &MergePeopleResult{Survivor: survivor, Merge: merge}, nil
+}
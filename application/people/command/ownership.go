@@ -0,0 +1,33 @@
+package command
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
+)
+
+// owned retrieves a person the caller may access. People belonging to someone else are reported
+// as not found, so that their existence isn't revealed.
+func owned(ctx context.Context, repo irepo.IPerson, authorizer *auth.Authorizer, id uuid.UUID) (*model.Person, ierr.IErr) {
+	person, err := repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !authorizer.CanAccess(ctx, person.Owner()) {
+		return nil, ierr.NewNotFound("person not found")
+	}
+	return person, nil
+}
+
+// ownerOf returns the subject of the caller, who owns the people it creates.
+func ownerOf(ctx context.Context) string {
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		return principal.Subject
+	}
+	return ""
+}
@@ -0,0 +1,127 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
+	ijobs "github.com/Efamamo/GoCrudChallange/application/common/interface/jobs"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
+)
+
+// PurgeKind is the kind of the jobs purging people.
+const PurgeKind = "people.purge"
+
+// PurgeParams holds the parameters of a job purging people.
+type PurgeParams struct {
+	Attributes map[string]string `json:"attributes"` // Custom attribute names mapped to the value purged people have
+	All        bool              `json:"all"`        // Whether every person is purged, when no attribute is given
+}
+
+// PurgeResult holds the result of a job purging people.
+type PurgeResult struct {
+	Deleted int `json:"deleted"` // Number of people deleted
+}
+
+// PurgePeopleHandler is the task of the jobs deleting every person matching custom attributes in the background,
+// along with their relations, as the DeletePersonCommand would.
+type PurgePeopleHandler struct {
+	repo       irepo.IPerson
+	people     *DeletePersonHandler
+	authorizer *auth.Authorizer
+}
+
+// Compile-time check to ensure PurgePeopleHandler implements ITask.
+var _ ijobs.ITask = &PurgePeopleHandler{}
+
+// NewPurgePeopleHandler initializes a new PurgePeopleHandler with the given IPerson and IRelation repositories.
+// Every person may be purged when the authorizer is nil, and deletions aren't published when events is nil.
+func NewPurgePeopleHandler(repo irepo.IPerson, relations irepo.IRelation, authorizer *auth.Authorizer, events ievents.IPublisher) *PurgePeopleHandler {
+	return &PurgePeopleHandler{
+		repo:       repo,
+		people:     NewDeletePersonHandler(repo, relations, authorizer, events),
+		authorizer: authorizer,
+	}
+}
+
+// Validate makes sure the parameters name the attributes of the people to purge, or explicitly purge everyone.
+func (h *PurgePeopleHandler) Validate(_ context.Context, params []byte) error {
+	_, err := parsePurgeParams(params)
+	return err
+}
+
+// Run deletes the people matching the parameters one at a time, reporting the progress after every deletion.
+// The submitter needs the person:delete permission, and only purges the people they own unless they are an admin.
+func (h *PurgePeopleHandler) Run(ctx context.Context, params []byte, report ijobs.Report) (any, error) {
+	p, err := parsePurgeParams(params)
+	if err != nil {
+		return nil, err
+	}
+	if h.authorizer != nil {
+		if err := h.authorizer.Authorize(ctx, auth.PersonDelete); err != nil {
+			return nil, err
+		}
+	}
+
+	report(job.Progress{Message: "finding people"})
+	ids := make([]uuid.UUID, 0)
+	unrestricted := h.authorizer.Unrestricted(ctx)
+	if err := h.repo.Stream(ctx, func(person *model.Person) bool {
+		if (unrestricted || h.authorizer.CanAccess(ctx, person.Owner())) && person.HasAttributes(p.Attributes) {
+			ids = append(ids, person.Id())
+		}
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := PurgeResult{}
+	for i, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		report(job.Progress{Done: i, Total: len(ids), Message: "deleting people"})
+
+		// People deleted since they were found are skipped
+		if _, err := h.people.Handle(&DeletePersonCommand{Context: ctx, ID: id}); err != nil {
+			if err.Type() == ierr.NotFound {
+				continue
+			}
+			return nil, err
+		}
+		result.Deleted++
+	}
+	report(job.Progress{Done: len(ids), Total: len(ids), Message: "deleted people"})
+	return result, nil
+}
+
+// parsePurgeParams decodes the parameters of a job purging people.
+func parsePurgeParams(params []byte) (*PurgeParams, error) {
+	p := &PurgeParams{}
+	if len(params) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(params))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(p); err != nil {
+			return nil, fmt.Errorf("invalid params: %s", err.Error())
+		}
+	}
+
+	if len(p.Attributes) == 0 && !p.All {
+		return nil, errors.New("params should name the attributes of the people to purge, or set all to purge everyone")
+	}
+	if len(p.Attributes) > 0 && p.All {
+		return nil, errors.New("params can't both name attributes and purge everyone")
+	}
+	return p, nil
+}
@@ -0,0 +1,71 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	ijobs "github.com/Efamamo/GoCrudChallange/application/common/interface/jobs"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ischedule "github.com/Efamamo/GoCrudChallange/application/common/interface/schedule"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+)
+
+// SchedulerSubject is the subject of the principal the scheduled purges are submitted as.
+const SchedulerSubject = "scheduler"
+
+// SchedulePurgeHandler is the scheduled task submitting a job of the PurgeKind per tenant, deleting the people
+// whose custom attributes mark them for deletion, e.g. deleted=true, since people are never soft deleted otherwise.
+type SchedulePurgeHandler struct {
+	repo       irepo.IPerson
+	runner     ijobs.IRunner
+	attributes map[string]string
+}
+
+// Compile-time check to ensure SchedulePurgeHandler implements ITask.
+var _ ischedule.ITask = &SchedulePurgeHandler{}
+
+// NewSchedulePurgeHandler initializes a new SchedulePurgeHandler with the given IPerson repository and IRunner,
+// purging the people with the custom attributes.
+func NewSchedulePurgeHandler(repo irepo.IPerson, runner ijobs.IRunner, attributes map[string]string) *SchedulePurgeHandler {
+	return &SchedulePurgeHandler{repo: repo, runner: runner, attributes: attributes}
+}
+
+// Run submits a purge job for every tenant, as an admin so that the people of every owner are purged,
+// and reports how many it submitted. The jobs are followed at /jobs like the ones submitted there.
+func (h *SchedulePurgeHandler) Run(ctx context.Context) (string, error) {
+	params, err := json.Marshal(PurgeParams{Attributes: h.attributes})
+	if err != nil {
+		return "", err
+	}
+	tenants, terr := h.repo.Tenants(ctx)
+	if terr != nil {
+		return "", terr
+	}
+
+	principal := &auth.Principal{Subject: SchedulerSubject, Permissions: []auth.Permission{auth.Admin}}
+	ctx = auth.WithPrincipal(ctx, principal)
+	for _, t := range tenants {
+		if _, err := h.runner.Submit(tenant.WithTenant(ctx, t), PurgeKind, params); err != nil {
+			return "", fmt.Errorf("failed to submit the purge of tenant %s: %s", t, err.Error())
+		}
+	}
+	return fmt.Sprintf("submitted %d purge jobs", len(tenants)), nil
+}
+
+// ParsePurgeAttributes parses the name=value pairs of the custom attributes marking the people to purge.
+func ParsePurgeAttributes(values []string) (map[string]string, ierr.IErr) {
+	attributes := make(map[string]string, len(values))
+	for _, value := range values {
+		name, attribute, ok := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, ierr.NewValidation(fmt.Sprintf("purge attribute %q should be name=value", value))
+		}
+		attributes[name] = strings.TrimSpace(attribute)
+	}
+	return attributes, nil
+}
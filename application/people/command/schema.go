@@ -0,0 +1,16 @@
+package command
+
+import (
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/attribute"
+)
+
+// schemaOf builds the current custom attribute schema out of the definitions in the repository.
+func schemaOf(repo irepo.IAttribute) (*attribute.Schema, ierr.IErr) {
+	definitions, err := repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return attribute.NewSchema(definitions), nil
+}
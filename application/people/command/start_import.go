@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	ijobs "github.com/Efamamo/GoCrudChallange/application/common/interface/jobs"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
+	"github.com/google/uuid"
+)
+
+// StartImportCommand holds the data required to import people in bulk in the background.
+type StartImportCommand struct {
+	Context context.Context // Carries the principal and the tenant the import runs on behalf of
+
+	Format string // Media type of the upload, e.g. text/csv or application/x-ndjson
+	Upload []byte // Content of the upload
+	DryRun bool   // Whether the rows are only validated, without creating anyone
+}
+
+// StartImportHandler is responsible for handling the logic of starting an import in the background,
+// as a job of the ImportKind.
+type StartImportHandler struct {
+	uploads irepo.IUpload
+	runner  ijobs.IRunner
+}
+
+// Compile-time check to ensure StartImportHandler implements IHandler for StartImportCommand.
+var _ icmd.IHandler[*StartImportCommand, *job.Job] = &StartImportHandler{}
+
+// NewStartImportHandler initializes a new StartImportHandler with the given IUpload repository and IRunner.
+func NewStartImportHandler(uploads irepo.IUpload, runner ijobs.IRunner) *StartImportHandler {
+	return &StartImportHandler{uploads: uploads, runner: runner}
+}
+
+// Handle processes the StartImportCommand, storing the upload apart from the job table, and queuing the job
+// importing it, which is returned before it runs. The upload is deleted once the job finished.
+func (h *StartImportHandler) Handle(command *StartImportCommand) (*job.Job, ierr.IErr) {
+	id := uuid.New()
+	if err := h.uploads.Save(command.Context, id, command.Upload); err != nil {
+		return nil, err
+	}
+
+	params, err := json.Marshal(ImportParams{Format: command.Format, DryRun: command.DryRun, UploadID: id})
+	if err != nil {
+		h.uploads.Delete(command.Context, id)
+		return nil, ierr.NewUnexpected(fmt.Sprintf("failed to encode the params: %s", err.Error()))
+	}
+	j, serr := h.runner.Submit(command.Context, ImportKind, params)
+	if serr != nil {
+		h.uploads.Delete(command.Context, id)
+		return nil, serr
+	}
+	return j, nil
+}
@@ -0,0 +1,56 @@
+package command
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
+)
+
+// TransferOwnershipCommand represents the command to hand a person over to another principal.
+type TransferOwnershipCommand struct {
+	Context context.Context // Carries the principal, who should own the person
+
+	ID    uuid.UUID
+	Owner string // Subject of the new owner
+}
+
+// TransferOwnershipHandler is a command handler for changing the owner of a person.
+type TransferOwnershipHandler struct {
+	repo       irepo.IPerson      // Repository interface for person operations.
+	authorizer *auth.Authorizer   // Decides whether the caller may access the person.
+	events     ievents.IPublisher // Notified of the transfers.
+}
+
+// Ensure TransferOwnershipHandler implements the IHandler interface for handling commands.
+var _ icmd.IHandler[*TransferOwnershipCommand, *model.Person] = &TransferOwnershipHandler{}
+
+// NewTransferOwnershipHandler creates a new instance of TransferOwnershipHandler with the provided repository.
+// Every caller may transfer every person when the authorizer is nil, and transfers aren't published when events is nil.
+func NewTransferOwnershipHandler(repo irepo.IPerson, authorizer *auth.Authorizer, events ievents.IPublisher) *TransferOwnershipHandler {
+	return &TransferOwnershipHandler{repo: repo, authorizer: authorizer, events: events}
+}
+
+// Handle processes the command to transfer a person. Only the current owner, or an admin, may transfer it.
+func (h *TransferOwnershipHandler) Handle(command *TransferOwnershipCommand) (*model.Person, ierr.IErr) {
+	person, err := owned(command.Context, h.repo, h.authorizer, command.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := person.SetOwner(command.Owner); err != nil {
+		return nil, err
+	}
+	person.Touch()
+
+	if err := h.repo.Save(command.Context, person); err != nil {
+		return nil, err
+	}
+	publish(command.Context, h.events, ievents.PersonUpdated, person)
+	return person, nil
+}
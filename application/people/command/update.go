@@ -1,7 +1,11 @@
 package command
 
 import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
 	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
 	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
 	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
 	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
@@ -10,28 +14,40 @@ import (
 
 // UpdatePersonCommand represents the command to update a person's details.
 type UpdatePersonCommand struct {
+	Context context.Context // Carries the principal, who should own the person
+
 	ID      uuid.UUID
 	Name    string
 	Age     int16
 	Hobbies []string
+	Email   string
+	Phone   string
+	Address *model.AddressConfig
+
+	Attributes map[string]any
 }
 
 // UpdatePersonHandler is a command handler for updating a person's information.
 type UpdatePersonHandler struct {
-	repo irepo.IPerson // Repository interface for person operations.
+	repo       irepo.IPerson      // Repository interface for person operations.
+	attributes irepo.IAttribute   // Repository interface for the custom attribute schema.
+	authorizer *auth.Authorizer   // Decides whether the caller may access the person.
+	events     ievents.IPublisher // Notified of the updates.
 }
 
 // Ensure UpdatePersonHandler implements the IHandler interface for handling commands.
 var _ icmd.IHandler[*UpdatePersonCommand, *model.Person] = &UpdatePersonHandler{}
 
-// NewUpdatePersonHandler creates a new instance of UpdatePersonHandler with the provided repository.
-func NewUpdatePersonHandler(repo irepo.IPerson) *UpdatePersonHandler {
-	return &UpdatePersonHandler{repo: repo}
+// NewUpdatePersonHandler creates a new instance of UpdatePersonHandler with the provided repositories.
+// Every caller may update every person when the authorizer is nil, and updates aren't published when events is nil.
+func NewUpdatePersonHandler(repo irepo.IPerson, attributes irepo.IAttribute, authorizer *auth.Authorizer, events ievents.IPublisher) *UpdatePersonHandler {
+	return &UpdatePersonHandler{repo: repo, attributes: attributes, authorizer: authorizer, events: events}
 }
 
 // Handle processes the command to update a person's information.
+// Only the owner of the person, or an admin, may update it.
 func (h *UpdatePersonHandler) Handle(command *UpdatePersonCommand) (*model.Person, ierr.IErr) {
-	person, err := h.repo.Get(command.ID)
+	person, err := owned(command.Context, h.repo, h.authorizer, command.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -46,9 +62,24 @@ func (h *UpdatePersonHandler) Handle(command *UpdatePersonCommand) (*model.Perso
 
 	person.SetHobbies(command.Hobbies)
 
-	if err := h.repo.Save(person); err != nil {
+	if err := person.SetContact(command.Email, command.Phone, command.Address); err != nil {
+		return nil, err
+	}
+
+	schema, err := schemaOf(h.attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := person.SetAttributes(command.Attributes, schema); err != nil {
+		return nil, err
+	}
+	person.Touch()
+
+	if err := h.repo.Save(command.Context, person); err != nil {
 		return nil, err
 	}
+	publish(command.Context, h.events, ievents.PersonUpdated, person)
 
 	return person, nil
 }
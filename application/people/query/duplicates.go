@@ -0,0 +1,106 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+)
+
+// DefaultDuplicateThreshold is the name similarity above which two people of the same age are considered duplicates.
+const DefaultDuplicateThreshold = 0.8
+
+// GetDuplicatesQuery holds the parameters used to detect likely duplicate people.
+type GetDuplicatesQuery struct {
+	Context context.Context // Carries the tenant of the people and the principal, who should own them
+
+	Threshold float64 // Minimum name similarity between 0 and 1; DefaultDuplicateThreshold when zero
+}
+
+// Ensure GetDuplicatesHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[*GetDuplicatesQuery, [][]*model.Person] = &GetDuplicatesHandler{}
+
+// GetDuplicatesHandler is a query handler for grouping people that are likely duplicates.
+type GetDuplicatesHandler struct {
+	repo       irepo.IPerson    // Repository interface for person operations.
+	authorizer *auth.Authorizer // Decides which people the caller may access.
+}
+
+// NewGetDuplicatesHandler creates a new instance of GetDuplicatesHandler with the provided repository.
+// Every person is compared when the authorizer is nil.
+func NewGetDuplicatesHandler(repo irepo.IPerson, authorizer *auth.Authorizer) *GetDuplicatesHandler {
+	return &GetDuplicatesHandler{repo: repo, authorizer: authorizer}
+}
+
+// Handle processes the query to group likely duplicates.
+// People with the same age whose names are similar enough end up in the same group,
+// and only groups with more than one person are returned. Only the people the caller may access are compared.
+func (h *GetDuplicatesHandler) Handle(q *GetDuplicatesQuery) ([][]*model.Person, error) {
+	all, err := h.repo.GetAll(q.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	unrestricted := h.authorizer.Unrestricted(q.Context)
+	people := make([]*model.Person, 0, len(all))
+	for _, p := range all {
+		if !unrestricted && !h.authorizer.CanAccess(q.Context, p.Owner()) {
+			continue
+		}
+		people = append(people, p)
+	}
+
+	threshold := q.Threshold
+	if threshold == 0 {
+		threshold = DefaultDuplicateThreshold
+	}
+
+	// Union-find over the indexes of people, linking every similar pair.
+	parent := make([]int, len(people))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range people {
+		for j := i + 1; j < len(people); j++ {
+			if people[i].Age() != people[j].Age() {
+				continue
+			}
+			if model.NameSimilarity(people[i].Name(), people[j].Name()) >= threshold {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	// Collect the groups in the order their first member appears.
+	groups := make([][]*model.Person, 0)
+	positions := make(map[int]int)
+	for i, p := range people {
+		root := find(i)
+		pos, ok := positions[root]
+		if !ok {
+			pos = len(groups)
+			positions[root] = pos
+			groups = append(groups, nil)
+		}
+		groups[pos] = append(groups[pos], p)
+	}
+
+	duplicates := make([][]*model.Person, 0)
+	for _, group := range groups {
+		if len(group) > 1 {
+			duplicates = append(duplicates, group)
+		}
+	}
+
+	return duplicates, nil
+}
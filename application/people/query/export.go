@@ -0,0 +1,96 @@
+package query
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+)
+
+// ExportPeopleQuery holds the optional filters applied when exporting people, and where they are exported to.
+type ExportPeopleQuery struct {
+	Context context.Context // Carries the principal, whose people are exported; exporting stops once it is done
+
+	Attributes map[string]string // Custom attribute names mapped to the value people should have
+
+	// Start is called once before any person with the sorted names of the custom attributes of the schema,
+	// so that exports with fixed columns can lay them out; it may be nil. An error stops the export.
+	Start func(attributes []string) error
+	// Each is called with every exported person; an error stops the export.
+	Each func(person *model.Person) error
+}
+
+// Ensure ExportPeopleHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[*ExportPeopleQuery, int] = &ExportPeopleHandler{}
+
+// ExportPeopleHandler is a query handler for streaming people from the repository, without holding them all in memory.
+type ExportPeopleHandler struct {
+	repo       irepo.IPerson    // Repository interface for person operations.
+	attributes irepo.IAttribute // Repository interface for the custom attribute schema.
+	authorizer *auth.Authorizer // Decides which people the caller may access.
+}
+
+// NewExportPeopleHandler creates a new instance of ExportPeopleHandler with the provided repositories.
+// Every caller may export every person when the authorizer is nil, and Start gets no attributes when
+// the attribute repository is nil.
+func NewExportPeopleHandler(repo irepo.IPerson, attributes irepo.IAttribute, authorizer *auth.Authorizer) *ExportPeopleHandler {
+	return &ExportPeopleHandler{repo: repo, attributes: attributes, authorizer: authorizer}
+}
+
+// Handle streams the people matching the filters to Each, as the GetPeopleQuery would list them,
+// and returns how many were exported. It returns the first error of Each, which stops the export.
+func (h *ExportPeopleHandler) Handle(q *ExportPeopleQuery) (int, error) {
+	if q.Start != nil {
+		names, err := h.attributeNames()
+		if err != nil {
+			return 0, err
+		}
+		if err := q.Start(names); err != nil {
+			return 0, err
+		}
+	}
+
+	unrestricted := h.authorizer.Unrestricted(q.Context)
+
+	var exported int
+	var eachErr error
+	err := h.repo.Stream(q.Context, func(p *model.Person) bool {
+		if !unrestricted && !h.authorizer.CanAccess(q.Context, p.Owner()) {
+			return true
+		}
+		if !p.HasAttributes(q.Attributes) {
+			return true
+		}
+
+		if eachErr = q.Each(p); eachErr != nil {
+			return false
+		}
+		exported++
+		return true
+	})
+	if err != nil {
+		return exported, err
+	}
+	return exported, eachErr
+}
+
+// attributeNames returns the sorted names of the custom attributes of the schema.
+func (h *ExportPeopleHandler) attributeNames() ([]string, error) {
+	if h.attributes == nil {
+		return nil, nil
+	}
+
+	definitions, err := h.attributes.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(definitions))
+	for _, d := range definitions {
+		names = append(names, d.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
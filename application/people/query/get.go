@@ -1,31 +1,49 @@
 package query
 
 import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
 	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
 	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
 	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
 	"github.com/google/uuid"
 )
 
+// GetPersonQuery represents the query to retrieve a person by their ID.
+type GetPersonQuery struct {
+	Context context.Context // Carries the principal, who should own the person
+
+	ID uuid.UUID
+}
+
 // Ensure GetPersonHandler implements the IHandler interface for handling queries.
-var _ iquery.IHandler[uuid.UUID, *model.Person] = &GetPersonHandler{}
+var _ iquery.IHandler[*GetPersonQuery, *model.Person] = &GetPersonHandler{}
 
 // GetPersonHandler is a query handler for retrieving a specific person by their ID.
 type GetPersonHandler struct {
-	repo irepo.IPerson // Repository interface for person operations.
+	repo       irepo.IPerson    // Repository interface for person operations.
+	authorizer *auth.Authorizer // Decides whether the caller may access the person.
 }
 
 // NewGetPersonHandler creates a new instance of GetPersonHandler with the provided repository.
-func NewGetPersonHandler(repo irepo.IPerson) *GetPersonHandler {
-	return &GetPersonHandler{repo: repo}
+// Every caller may retrieve every person when the authorizer is nil.
+func NewGetPersonHandler(repo irepo.IPerson, authorizer *auth.Authorizer) *GetPersonHandler {
+	return &GetPersonHandler{repo: repo, authorizer: authorizer}
 }
 
 // Handle processes the query to retrieve a person by their ID.
-func (h *GetPersonHandler) Handle(id uuid.UUID) (*model.Person, error) {
-	person, err := h.repo.Get(id)
+// People belonging to someone else than the caller are reported as not found, unless the caller is an admin.
+func (h *GetPersonHandler) Handle(q *GetPersonQuery) (*model.Person, error) {
+	person, err := h.repo.Get(q.Context, q.ID)
 	if err != nil {
 		return nil, err
 	}
 
+	if !h.authorizer.CanAccess(q.Context, person.Owner()) {
+		return nil, ierr.NewNotFound("person not found")
+	}
+
 	return person, nil
 }
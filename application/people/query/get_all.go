@@ -1,29 +1,57 @@
 package query
 
 import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
 	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
 	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
 	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
 )
 
+// GetPeopleQuery holds the optional filters applied when listing people.
+type GetPeopleQuery struct {
+	Context context.Context // Carries the principal, whose people are listed
+
+	Attributes map[string]string // Custom attribute names mapped to the value people should have
+}
+
 // Ensure GetPeopleHandler implements the IHandler interface for handling queries.
-var _ iquery.IHandler[struct{}, []*model.Person] = &GetPeopleHandler{}
+var _ iquery.IHandler[*GetPeopleQuery, []*model.Person] = &GetPeopleHandler{}
 
 // GetPeopleHandler is a query handler for retrieving all people from the repository.
 type GetPeopleHandler struct {
-	repo irepo.IPerson // Repository interface for person operations.
+	repo       irepo.IPerson    // Repository interface for person operations.
+	authorizer *auth.Authorizer // Decides which people the caller may access.
 }
 
 // NewGetPeopleHandler creates a new instance of GetPeopleHandler with the provided repository.
-func NewGetPeopleHandler(repo irepo.IPerson) *GetPeopleHandler {
-	return &GetPeopleHandler{repo: repo}
+// Every caller may list every person when the authorizer is nil.
+func NewGetPeopleHandler(repo irepo.IPerson, authorizer *auth.Authorizer) *GetPeopleHandler {
+	return &GetPeopleHandler{repo: repo, authorizer: authorizer}
 }
 
-// Handle processes the query to retrieve all people.
-func (h *GetPeopleHandler) Handle(_ struct{}) ([]*model.Person, error) {
-	people, err := h.repo.GetAll()
+// Handle processes the query to retrieve all people matching the filters.
+// Only the people owned by the caller are listed, unless the caller is an admin.
+func (h *GetPeopleHandler) Handle(q *GetPeopleQuery) ([]*model.Person, error) {
+	people, err := h.repo.GetAll(q.Context)
 	if err != nil {
 		return nil, err
 	}
-	return people, nil
+
+	unrestricted := h.authorizer.Unrestricted(q.Context)
+	if unrestricted && len(q.Attributes) == 0 {
+		return people, nil
+	}
+
+	filtered := make([]*model.Person, 0, len(people))
+	for _, p := range people {
+		if !unrestricted && !h.authorizer.CanAccess(q.Context, p.Owner()) {
+			continue
+		}
+		if p.HasAttributes(q.Attributes) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered, nil
 }
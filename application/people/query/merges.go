@@ -0,0 +1,52 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
+)
+
+// GetMergesQuery represents the query to list the merges that folded people into a person.
+type GetMergesQuery struct {
+	Context context.Context // Carries the tenant of the people and the principal, who should own the person
+
+	ID uuid.UUID
+}
+
+// Ensure GetMergesHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[*GetMergesQuery, []*model.Merge] = &GetMergesHandler{}
+
+// GetMergesHandler is a query handler for listing the merge history of a person.
+type GetMergesHandler struct {
+	repo       irepo.IPerson    // Repository interface for person operations.
+	authorizer *auth.Authorizer // Decides whether the caller may access the person.
+}
+
+// NewGetMergesHandler creates a new instance of GetMergesHandler with the provided repository.
+// Every caller may list the merges of every person when the authorizer is nil.
+func NewGetMergesHandler(repo irepo.IPerson, authorizer *auth.Authorizer) *GetMergesHandler {
+	return &GetMergesHandler{repo: repo, authorizer: authorizer}
+}
+
+// Handle processes the query to list the merges of a person, oldest first.
+// People belonging to someone else than the caller are reported as not found, unless the caller is an admin.
+func (h *GetMergesHandler) Handle(q *GetMergesQuery) ([]*model.Merge, error) {
+	person, err := h.repo.Get(q.Context, q.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !h.authorizer.CanAccess(q.Context, person.Owner()) {
+		return nil, ierr.NewNotFound("person not found")
+	}
+
+	merges, err := h.repo.Merges(q.Context, q.ID)
+	if err != nil {
+		return nil, err
+	}
+	return merges, nil
+}
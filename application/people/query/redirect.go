@@ -0,0 +1,51 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/google/uuid"
+)
+
+// GetRedirectQuery represents the query to resolve a retired person to their survivor.
+type GetRedirectQuery struct {
+	Context context.Context // Carries the tenant of the people and the principal, who should own the survivor
+
+	ID uuid.UUID
+}
+
+// Ensure GetRedirectHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[*GetRedirectQuery, uuid.UUID] = &GetRedirectHandler{}
+
+// GetRedirectHandler is a query handler for resolving the survivor that a retired person was merged into.
+type GetRedirectHandler struct {
+	repo       irepo.IPerson    // Repository interface for person operations.
+	authorizer *auth.Authorizer // Decides whether the caller may access the survivor.
+}
+
+// NewGetRedirectHandler creates a new instance of GetRedirectHandler with the provided repository.
+// Every caller may resolve every retired person when the authorizer is nil.
+func NewGetRedirectHandler(repo irepo.IPerson, authorizer *auth.Authorizer) *GetRedirectHandler {
+	return &GetRedirectHandler{repo: repo, authorizer: authorizer}
+}
+
+// Handle processes the query to resolve a retired ID to its survivor's ID.
+// Survivors belonging to someone else than the caller are reported as not found, unless the caller is an admin.
+func (h *GetRedirectHandler) Handle(q *GetRedirectQuery) (uuid.UUID, error) {
+	to, err := h.repo.Redirect(q.Context, q.ID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	survivor, err := h.repo.Get(q.Context, to)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !h.authorizer.CanAccess(q.Context, survivor.Owner()) {
+		return uuid.Nil, ierr.NewNotFound("person not found")
+	}
+	return to, nil
+}
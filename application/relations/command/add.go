@@ -0,0 +1,64 @@
+package command
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/relation"
+	"github.com/google/uuid"
+)
+
+// AddRelationCommand holds the data required to relate two people.
+type AddRelationCommand struct {
+	Context context.Context // Carries the tenant of the people and the principal, who should own them
+
+	From     uuid.UUID
+	To       uuid.UUID
+	Type     string
+	Directed bool
+}
+
+// AddRelationHandler is responsible for handling the logic of relating two people.
+type AddRelationHandler struct {
+	people     irepo.IPerson
+	relations  irepo.IRelation
+	authorizer *auth.Authorizer
+}
+
+// Compile-time check to ensure AddRelationHandler implements IHandler for AddRelationCommand.
+var _ icmd.IHandler[*AddRelationCommand, *relation.Relation] = &AddRelationHandler{}
+
+// NewAddRelationHandler initializes a new AddRelationHandler with the given repositories.
+// Every caller may relate every person when the authorizer is nil.
+func NewAddRelationHandler(people irepo.IPerson, relations irepo.IRelation, authorizer *auth.Authorizer) *AddRelationHandler {
+	return &AddRelationHandler{people: people, relations: relations, authorizer: authorizer}
+}
+
+// Handle processes the AddRelationCommand to create a relation between two existing people.
+func (h *AddRelationHandler) Handle(command *AddRelationCommand) (*relation.Relation, ierr.IErr) {
+	rel, err := relation.CreateRelation(&relation.RelationConfig{
+		From:     command.From,
+		To:       command.To,
+		Type:     command.Type,
+		Directed: command.Directed,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Both ends of the relation should be existing people the caller may access.
+	for _, id := range []uuid.UUID{command.From, command.To} {
+		if _, err := owned(command.Context, h.people, h.authorizer, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := h.relations.Save(command.Context, rel); err != nil {
+		return nil, err
+	}
+
+	return rel, nil
+}
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/google/uuid"
+)
+
+// RemoveRelationCommand represents the command to remove a relation of a person.
+type RemoveRelationCommand struct {
+	Context context.Context // Carries the tenant of the person and the principal, who should own them
+
+	PersonID   uuid.UUID
+	RelationID uuid.UUID
+}
+
+// RemoveRelationHandler is a command handler for removing a relation between two people.
+type RemoveRelationHandler struct {
+	people     irepo.IPerson    // Repository interface for person operations.
+	relations  irepo.IRelation  // Repository interface for relation operations.
+	authorizer *auth.Authorizer // Decides which people the caller may access.
+}
+
+// Ensure RemoveRelationHandler implements the IHandler interface for handling commands.
+var _ icmd.IHandler[*RemoveRelationCommand, bool] = &RemoveRelationHandler{}
+
+// NewRemoveRelationHandler creates a new instance of RemoveRelationHandler with the provided repositories.
+// Every caller may remove the relations of every person when the authorizer is nil.
+func NewRemoveRelationHandler(people irepo.IPerson, relations irepo.IRelation, authorizer *auth.Authorizer) *RemoveRelationHandler {
+	return &RemoveRelationHandler{people: people, relations: relations, authorizer: authorizer}
+}
+
+// Handle processes the command to remove a relation, making sure it belongs to the given person.
+// Both people related should be accessible to the caller.
+func (h *RemoveRelationHandler) Handle(command *RemoveRelationCommand) (bool, ierr.IErr) {
+	// The person should belong to the tenant of the caller, who should own them.
+	if _, err := owned(command.Context, h.people, h.authorizer, command.PersonID); err != nil {
+		return false, err
+	}
+
+	rel, err := h.relations.Get(command.Context, command.RelationID)
+	if err != nil {
+		return false, err
+	}
+
+	if !rel.Involves(command.PersonID) {
+		return false, ierr.NewNotFound("relation not found")
+	}
+
+	other := rel.From()
+	if other == command.PersonID {
+		other = rel.To()
+	}
+	if _, err := owned(command.Context, h.people, h.authorizer, other); err != nil {
+		return false, ierr.NewNotFound("relation not found")
+	}
+
+	if err := h.relations.Delete(command.Context, rel.Id()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
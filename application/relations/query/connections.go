@@ -0,0 +1,52 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/google/uuid"
+)
+
+// connections returns the IDs of the people a person is connected to, following relation directions.
+// People the caller may not access are left out.
+func connections(ctx context.Context, people irepo.IPerson, relations irepo.IRelation, authorizer *auth.Authorizer, id uuid.UUID) ([]uuid.UUID, ierr.IErr) {
+	rels, err := relations.GetByPerson(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]struct{}, len(rels))
+	ids := make([]uuid.UUID, 0, len(rels))
+	for _, rel := range rels {
+		other, ok := rel.Connects(id)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[other]; ok {
+			continue
+		}
+		seen[other] = struct{}{}
+
+		visible, err := accessible(ctx, people, authorizer, other)
+		if err != nil {
+			return nil, err
+		}
+		if visible {
+			ids = append(ids, other)
+		}
+	}
+	return ids, nil
+}
+
+// accessible reports whether the person exists and the caller may access them.
+func accessible(ctx context.Context, people irepo.IPerson, authorizer *auth.Authorizer, id uuid.UUID) (bool, ierr.IErr) {
+	if _, err := owned(ctx, people, authorizer, id); err != nil {
+		if err.Type() == ierr.NotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,64 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	"github.com/Efamamo/GoCrudChallange/domain/model/relation"
+	"github.com/google/uuid"
+)
+
+// GetRelationsQuery represents the query to retrieve every relation of a person.
+type GetRelationsQuery struct {
+	Context context.Context // Carries the tenant of the person and the principal, who should own them
+
+	PersonID uuid.UUID
+}
+
+// Ensure GetRelationsHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[*GetRelationsQuery, []*relation.Relation] = &GetRelationsHandler{}
+
+// GetRelationsHandler is a query handler for retrieving every relation of a person.
+type GetRelationsHandler struct {
+	people     irepo.IPerson    // Repository interface for person operations.
+	relations  irepo.IRelation  // Repository interface for relation operations.
+	authorizer *auth.Authorizer // Decides which people the caller may access.
+}
+
+// NewGetRelationsHandler creates a new instance of GetRelationsHandler with the provided repositories.
+// Every caller may retrieve the relations of every person when the authorizer is nil.
+func NewGetRelationsHandler(people irepo.IPerson, relations irepo.IRelation, authorizer *auth.Authorizer) *GetRelationsHandler {
+	return &GetRelationsHandler{people: people, relations: relations, authorizer: authorizer}
+}
+
+// Handle processes the query to retrieve the relations of a person by their ID.
+// Relations to people the caller may not access are left out.
+func (h *GetRelationsHandler) Handle(q *GetRelationsQuery) ([]*relation.Relation, error) {
+	if _, err := owned(q.Context, h.people, h.authorizer, q.PersonID); err != nil {
+		return nil, err
+	}
+
+	relations, err := h.relations.GetByPerson(q.Context, q.PersonID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*relation.Relation, 0, len(relations))
+	for _, rel := range relations {
+		other := rel.From()
+		if other == q.PersonID {
+			other = rel.To()
+		}
+
+		ok, err := accessible(q.Context, h.people, h.authorizer, other)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			visible = append(visible, rel)
+		}
+	}
+	return visible, nil
+}
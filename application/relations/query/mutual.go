@@ -0,0 +1,73 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
+)
+
+// GetMutualQuery holds the two people whose mutual connections are requested.
+type GetMutualQuery struct {
+	Context context.Context // Carries the tenant of the people and the principal, who should own them
+
+	PersonID uuid.UUID
+	OtherID  uuid.UUID
+}
+
+// Ensure GetMutualHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[*GetMutualQuery, []*model.Person] = &GetMutualHandler{}
+
+// GetMutualHandler is a query handler for retrieving the connections two people have in common.
+type GetMutualHandler struct {
+	people     irepo.IPerson    // Repository interface for person operations.
+	relations  irepo.IRelation  // Repository interface for relation operations.
+	authorizer *auth.Authorizer // Decides which people the caller may access.
+}
+
+// NewGetMutualHandler creates a new instance of GetMutualHandler with the provided repositories.
+// Every caller may retrieve the connections of every person when the authorizer is nil.
+func NewGetMutualHandler(people irepo.IPerson, relations irepo.IRelation, authorizer *auth.Authorizer) *GetMutualHandler {
+	return &GetMutualHandler{people: people, relations: relations, authorizer: authorizer}
+}
+
+// Handle processes the query to retrieve the people both given people are connected to.
+// Only the people the caller may access are returned.
+func (h *GetMutualHandler) Handle(q *GetMutualQuery) ([]*model.Person, error) {
+	for _, id := range []uuid.UUID{q.PersonID, q.OtherID} {
+		if _, err := owned(q.Context, h.people, h.authorizer, id); err != nil {
+			return nil, err
+		}
+	}
+
+	first, err := connections(q.Context, h.people, h.relations, h.authorizer, q.PersonID)
+	if err != nil {
+		return nil, err
+	}
+
+	second, err := connections(q.Context, h.people, h.relations, h.authorizer, q.OtherID)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := make(map[uuid.UUID]struct{}, len(second))
+	for _, id := range second {
+		shared[id] = struct{}{}
+	}
+
+	mutual := make([]*model.Person, 0)
+	for _, id := range first {
+		if _, ok := shared[id]; !ok {
+			continue
+		}
+		person, err := h.people.Get(q.Context, id)
+		if err != nil {
+			return nil, err
+		}
+		mutual = append(mutual, person)
+	}
+	return mutual, nil
+}
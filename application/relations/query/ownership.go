@@ -0,0 +1,25 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
+)
+
+// owned retrieves a person the caller may access. People belonging to someone else are reported
+// as not found, so that neither their existence nor their relations are revealed.
+func owned(ctx context.Context, people irepo.IPerson, authorizer *auth.Authorizer, id uuid.UUID) (*model.Person, ierr.IErr) {
+	person, err := people.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !authorizer.CanAccess(ctx, person.Owner()) {
+		return nil, ierr.NewNotFound("person not found")
+	}
+	return person, nil
+}
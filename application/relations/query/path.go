@@ -0,0 +1,107 @@
+package query
+
+import (
+	"context"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
+)
+
+// DefaultMaxDepth is the number of hops after which the search for a path gives up.
+const DefaultMaxDepth = 6
+
+// GetPathQuery holds the two people between whom the shortest path is requested.
+type GetPathQuery struct {
+	Context context.Context // Carries the tenant of the people and the principal, who should own them
+
+	From     uuid.UUID
+	To       uuid.UUID
+	MaxDepth int // Maximum number of hops; DefaultMaxDepth when zero
+}
+
+// Ensure GetPathHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[*GetPathQuery, []*model.Person] = &GetPathHandler{}
+
+// GetPathHandler is a query handler for finding the shortest chain of relations between two people.
+type GetPathHandler struct {
+	people     irepo.IPerson    // Repository interface for person operations.
+	relations  irepo.IRelation  // Repository interface for relation operations.
+	authorizer *auth.Authorizer // Decides which people the caller may access.
+}
+
+// NewGetPathHandler creates a new instance of GetPathHandler with the provided repositories.
+// Every caller may search paths through every person when the authorizer is nil.
+func NewGetPathHandler(people irepo.IPerson, relations irepo.IRelation, authorizer *auth.Authorizer) *GetPathHandler {
+	return &GetPathHandler{people: people, relations: relations, authorizer: authorizer}
+}
+
+// Handle processes the query with a breadth-first search over the relations.
+// The returned path starts with the first person and ends with the second one, and only goes
+// through people the caller may access.
+func (h *GetPathHandler) Handle(q *GetPathQuery) ([]*model.Person, error) {
+	for _, id := range []uuid.UUID{q.From, q.To} {
+		if _, err := owned(q.Context, h.people, h.authorizer, id); err != nil {
+			return nil, err
+		}
+	}
+
+	maxDepth := q.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxDepth
+	}
+
+	previous := map[uuid.UUID]uuid.UUID{q.From: uuid.Nil}
+	frontier := []uuid.UUID{q.From}
+
+	for depth := 0; depth < maxDepth && len(frontier) > 0; depth++ {
+		next := make([]uuid.UUID, 0)
+		for _, id := range frontier {
+			if id == q.To {
+				return h.path(q.Context, previous, q.To)
+			}
+
+			ids, err := connections(q.Context, h.people, h.relations, h.authorizer, id)
+			if err != nil {
+				return nil, err
+			}
+			for _, other := range ids {
+				if _, ok := previous[other]; ok {
+					continue
+				}
+				previous[other] = id
+				next = append(next, other)
+			}
+		}
+		frontier = next
+	}
+
+	for _, id := range frontier {
+		if id == q.To {
+			return h.path(q.Context, previous, q.To)
+		}
+	}
+
+	return nil, ierr.NewNotFound("no path found between the people")
+}
+
+// path walks back from the destination to build the path of people.
+func (h *GetPathHandler) path(ctx context.Context, previous map[uuid.UUID]uuid.UUID, to uuid.UUID) ([]*model.Person, error) {
+	ids := make([]uuid.UUID, 0)
+	for id := to; id != uuid.Nil; id = previous[id] {
+		ids = append([]uuid.UUID{id}, ids...)
+	}
+
+	people := make([]*model.Person, 0, len(ids))
+	for _, id := range ids {
+		person, err := h.people.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		people = append(people, person)
+	}
+	return people, nil
+}
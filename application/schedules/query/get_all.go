@@ -0,0 +1,25 @@
+package query
+
+import (
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	ischedule "github.com/Efamamo/GoCrudChallange/application/common/interface/schedule"
+	"github.com/Efamamo/GoCrudChallange/domain/model/schedule"
+)
+
+// Ensure GetSchedulesHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[struct{}, []*schedule.Schedule] = &GetSchedulesHandler{}
+
+// GetSchedulesHandler is a query handler for retrieving the schedule of every maintenance task.
+type GetSchedulesHandler struct {
+	scheduler ischedule.IScheduler // Scheduler running the maintenance tasks.
+}
+
+// NewGetSchedulesHandler creates a new instance of GetSchedulesHandler with the provided scheduler.
+func NewGetSchedulesHandler(scheduler ischedule.IScheduler) *GetSchedulesHandler {
+	return &GetSchedulesHandler{scheduler: scheduler}
+}
+
+// Handle processes the query to retrieve every schedule, with the status of its last run.
+func (h *GetSchedulesHandler) Handle(_ struct{}) ([]*schedule.Schedule, error) {
+	return h.scheduler.Schedules(), nil
+}
@@ -0,0 +1,53 @@
+package command
+
+import (
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/user"
+)
+
+// CreateUserCommand holds the data required to create a new local user.
+type CreateUserCommand struct {
+	Username string
+	Password string
+	Roles    []string
+	Tenant   string // Tenant the user belongs to, and its tokens are confined to; optional
+}
+
+// CreateUserHandler is responsible for handling the logic of creating a new user.
+type CreateUserHandler struct {
+	repo irepo.IUser
+}
+
+// Compile-time check to ensure CreateUserHandler implements IHandler for CreateUserCommand.
+var _ icmd.IHandler[*CreateUserCommand, *user.User] = &CreateUserHandler{}
+
+// NewCreateUserHandler initializes a new CreateUserHandler with a given IUser repository.
+func NewCreateUserHandler(repo irepo.IUser) *CreateUserHandler {
+	return &CreateUserHandler{repo: repo}
+}
+
+// Handle processes the CreateUserCommand to create and store a new user.
+// It returns a Conflict when the username is already taken.
+func (h *CreateUserHandler) Handle(command *CreateUserCommand) (*user.User, ierr.IErr) {
+	if command.Tenant != "" && !tenant.Valid(command.Tenant) {
+		return nil, ierr.NewValidation("invalid tenant")
+	}
+
+	u, err := user.CreateUser(&user.UserConfig{
+		Username: command.Username,
+		Password: command.Password,
+		Roles:    command.Roles,
+		Tenant:   command.Tenant,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.repo.Save(u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
@@ -0,0 +1,35 @@
+package command
+
+import (
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/google/uuid"
+)
+
+// DeleteUserHandler is a command handler for deleting a user and ending its sessions.
+type DeleteUserHandler struct {
+	repo   irepo.IUser  // Repository interface for user operations.
+	tokens irepo.IToken // Repository interface for token operations.
+}
+
+// Ensure DeleteUserHandler implements the IHandler interface for handling commands.
+var _ icmd.IHandler[uuid.UUID, bool] = &DeleteUserHandler{}
+
+// NewDeleteUserHandler creates a new instance of DeleteUserHandler with the provided repositories.
+func NewDeleteUserHandler(repo irepo.IUser, tokens irepo.IToken) *DeleteUserHandler {
+	return &DeleteUserHandler{repo: repo, tokens: tokens}
+}
+
+// Handle processes the command to delete a user by its ID, removing its refresh tokens.
+// Access tokens already issued stay valid until they expire.
+func (h *DeleteUserHandler) Handle(id uuid.UUID) (bool, ierr.IErr) {
+	if err := h.repo.Delete(id); err != nil {
+		return false, err
+	}
+
+	if err := h.tokens.DeleteRefreshTokensOf(id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
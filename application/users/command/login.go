@@ -0,0 +1,52 @@
+package command
+
+import (
+	"strings"
+	"time"
+
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	itoken "github.com/Efamamo/GoCrudChallange/application/common/interface/token"
+	apperror "github.com/Efamamo/GoCrudChallange/application/error"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/user"
+)
+
+// LoginCommand holds the credentials of a user logging in.
+type LoginCommand struct {
+	Username string
+	Password string
+}
+
+// LoginHandler is responsible for exchanging the credentials of a user for a pair of tokens.
+type LoginHandler struct {
+	users irepo.IUser
+	tokenIssuer
+}
+
+// Compile-time check to ensure LoginHandler implements IHandler for LoginCommand.
+var _ icmd.IHandler[*LoginCommand, *TokenPair] = &LoginHandler{}
+
+// NewLoginHandler initializes a new LoginHandler issuing refresh tokens valid for refreshTTL.
+func NewLoginHandler(users irepo.IUser, tokens irepo.IToken, issuer itoken.IIssuer, refreshTTL time.Duration) *LoginHandler {
+	return &LoginHandler{
+		users:       users,
+		tokenIssuer: tokenIssuer{issuer: issuer, tokens: tokens, refreshTTL: refreshTTL},
+	}
+}
+
+// Handle processes the LoginCommand. Unknown usernames and wrong passwords fail the same way,
+// so the response doesn't reveal which usernames exist.
+func (h *LoginHandler) Handle(command *LoginCommand) (*TokenPair, ierr.IErr) {
+	u, err := h.users.GetByUsername(strings.ToLower(strings.TrimSpace(command.Username)))
+	if err != nil {
+		// Spend the time of a password check, so that unknown usernames don't answer faster
+		user.CheckNoPassword(command.Password)
+		return nil, apperror.InvalidCredential("invalid username or password")
+	}
+	if !u.CheckPassword(command.Password) {
+		return nil, apperror.InvalidCredential("invalid username or password")
+	}
+
+	return h.issue(u)
+}
@@ -0,0 +1,50 @@
+package command
+
+import (
+	"time"
+
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/user"
+)
+
+// LogoutCommand holds the tokens of the session being ended.
+type LogoutCommand struct {
+	UserID          string    // Subject of the access token
+	AccessTokenID   string    // ID of the access token; not revoked when empty
+	AccessExpiresAt time.Time // Expiry of the access token
+	RefreshToken    string    // Refresh token of the session; optional
+}
+
+// LogoutHandler is responsible for revoking the tokens of a session.
+type LogoutHandler struct {
+	tokens irepo.IToken
+}
+
+// Compile-time check to ensure LogoutHandler implements IHandler for LogoutCommand.
+var _ icmd.IHandler[*LogoutCommand, bool] = &LogoutHandler{}
+
+// NewLogoutHandler initializes a new LogoutHandler with a given IToken repository.
+func NewLogoutHandler(tokens irepo.IToken) *LogoutHandler {
+	return &LogoutHandler{tokens: tokens}
+}
+
+// Handle processes the LogoutCommand. The access token is rejected until it expires, and the refresh
+// token is removed if it belongs to the same user.
+func (h *LogoutHandler) Handle(command *LogoutCommand) (bool, ierr.IErr) {
+	if command.AccessTokenID != "" {
+		if err := h.tokens.RevokeAccessToken(command.AccessTokenID, command.AccessExpiresAt); err != nil {
+			return false, err
+		}
+	}
+
+	if command.RefreshToken != "" {
+		refresh, err := h.tokens.GetRefreshToken(user.HashToken(command.RefreshToken))
+		if err == nil && refresh.UserID().String() == command.UserID {
+			h.tokens.DeleteRefreshToken(refresh.Id())
+		}
+	}
+
+	return true, nil
+}
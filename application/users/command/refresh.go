@@ -0,0 +1,50 @@
+package command
+
+import (
+	"time"
+
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	itoken "github.com/Efamamo/GoCrudChallange/application/common/interface/token"
+	apperror "github.com/Efamamo/GoCrudChallange/application/error"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/user"
+)
+
+// RefreshHandler is responsible for exchanging a refresh token for a new pair of tokens.
+type RefreshHandler struct {
+	users irepo.IUser
+	tokenIssuer
+}
+
+// Compile-time check to ensure RefreshHandler implements IHandler for refresh tokens.
+var _ icmd.IHandler[string, *TokenPair] = &RefreshHandler{}
+
+// NewRefreshHandler initializes a new RefreshHandler issuing refresh tokens valid for refreshTTL.
+func NewRefreshHandler(users irepo.IUser, tokens irepo.IToken, issuer itoken.IIssuer, refreshTTL time.Duration) *RefreshHandler {
+	return &RefreshHandler{
+		users:       users,
+		tokenIssuer: tokenIssuer{issuer: issuer, tokens: tokens, refreshTTL: refreshTTL},
+	}
+}
+
+// Handle processes the refresh token. The token is consumed, so it can't be used twice,
+// and a new one is issued along with the access token.
+func (h *RefreshHandler) Handle(secret string) (*TokenPair, ierr.IErr) {
+	refresh, err := h.tokens.GetRefreshToken(user.HashToken(secret))
+	if err != nil {
+		return nil, apperror.InvalidCredential("invalid refresh token")
+	}
+
+	// Deleting fails when a concurrent refresh consumed the token first.
+	if err := h.tokens.DeleteRefreshToken(refresh.Id()); err != nil || refresh.Expired(time.Now()) {
+		return nil, apperror.InvalidCredential("invalid refresh token")
+	}
+
+	u, err := h.users.Get(refresh.UserID())
+	if err != nil {
+		return nil, apperror.InvalidCredential("invalid refresh token")
+	}
+
+	return h.issue(u)
+}
@@ -0,0 +1,48 @@
+package command
+
+import (
+	"time"
+
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	itoken "github.com/Efamamo/GoCrudChallange/application/common/interface/token"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/user"
+)
+
+// TokenPair holds the tokens issued on login and on refresh.
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// tokenIssuer issues and stores the pair of tokens of a session.
+type tokenIssuer struct {
+	issuer     itoken.IIssuer
+	tokens     irepo.IToken
+	refreshTTL time.Duration
+}
+
+// issue signs an access token for the user and stores a new refresh token.
+func (t *tokenIssuer) issue(u *user.User) (*TokenPair, ierr.IErr) {
+	access, err := t.issuer.Issue(u.Id().String(), u.Tenant(), u.Roles())
+	if err != nil {
+		return nil, ierr.NewUnexpected(err.Error())
+	}
+
+	refresh, secret, cerr := user.IssueRefreshToken(u.Id(), t.refreshTTL)
+	if cerr != nil {
+		return nil, cerr
+	}
+	if err := t.tokens.SaveRefreshToken(refresh); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      access.Token,
+		AccessExpiresAt:  access.ExpiresAt,
+		RefreshToken:     secret,
+		RefreshExpiresAt: refresh.ExpiresAt(),
+	}, nil
+}
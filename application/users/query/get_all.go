@@ -0,0 +1,29 @@
+package query
+
+import (
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	"github.com/Efamamo/GoCrudChallange/domain/model/user"
+)
+
+// Ensure GetUsersHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[struct{}, []*user.User] = &GetUsersHandler{}
+
+// GetUsersHandler is a query handler for retrieving every local user.
+type GetUsersHandler struct {
+	repo irepo.IUser // Repository interface for user operations.
+}
+
+// NewGetUsersHandler creates a new instance of GetUsersHandler with the provided repository.
+func NewGetUsersHandler(repo irepo.IUser) *GetUsersHandler {
+	return &GetUsersHandler{repo: repo}
+}
+
+// Handle processes the query to retrieve every user.
+func (h *GetUsersHandler) Handle(_ struct{}) ([]*user.User, error) {
+	users, err := h.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
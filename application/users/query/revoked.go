@@ -0,0 +1,28 @@
+package query
+
+import (
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+)
+
+// Ensure IsTokenRevokedHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[string, bool] = &IsTokenRevokedHandler{}
+
+// IsTokenRevokedHandler is a query handler checking whether an access token was revoked on logout.
+type IsTokenRevokedHandler struct {
+	tokens irepo.IToken // Repository interface for token operations.
+}
+
+// NewIsTokenRevokedHandler creates a new instance of IsTokenRevokedHandler with the provided repository.
+func NewIsTokenRevokedHandler(tokens irepo.IToken) *IsTokenRevokedHandler {
+	return &IsTokenRevokedHandler{tokens: tokens}
+}
+
+// Handle processes the query for the access token with the given ID.
+func (h *IsTokenRevokedHandler) Handle(id string) (bool, error) {
+	revoked, err := h.tokens.IsAccessTokenRevoked(id)
+	if err != nil {
+		return false, err
+	}
+	return revoked, nil
+}
@@ -0,0 +1,38 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ischedule "github.com/Efamamo/GoCrudChallange/application/common/interface/schedule"
+)
+
+// CompactKind is the name of the scheduled task compacting the delivery log of webhooks.
+const CompactKind = "webhooks.compact"
+
+// CompactDeliveriesHandler is the scheduled task removing the succeeded and dead-lettered deliveries queued
+// longer ago than the retention, so that the delivery log doesn't grow forever. Pending deliveries are kept.
+type CompactDeliveriesHandler struct {
+	deliveries irepo.IDelivery
+	retention  time.Duration
+}
+
+// Compile-time check to ensure CompactDeliveriesHandler implements ITask.
+var _ ischedule.ITask = &CompactDeliveriesHandler{}
+
+// NewCompactDeliveriesHandler initializes a new CompactDeliveriesHandler with a given IDelivery repository,
+// keeping the finished deliveries for the retention.
+func NewCompactDeliveriesHandler(deliveries irepo.IDelivery, retention time.Duration) *CompactDeliveriesHandler {
+	return &CompactDeliveriesHandler{deliveries: deliveries, retention: retention}
+}
+
+// Run removes the finished deliveries queued before the retention, and reports how many it removed.
+func (h *CompactDeliveriesHandler) Run(_ context.Context) (string, error) {
+	deleted, err := h.deliveries.DeleteFinishedBefore(time.Now().UTC().Add(-h.retention))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("deleted %d deliveries", deleted), nil
+}
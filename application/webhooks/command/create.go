@@ -0,0 +1,56 @@
+package command
+
+import (
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/webhook"
+)
+
+// CreateWebhookCommand holds the data required to subscribe a URL to the changes of people.
+type CreateWebhookCommand struct {
+	URL    string
+	Events []string
+	Secret string // Optional; a random secret is generated when empty
+}
+
+// CreateWebhookResult holds a created webhook together with its secret, which is never shown again.
+type CreateWebhookResult struct {
+	Webhook *webhook.Webhook
+	Secret  string
+}
+
+// CreateWebhookHandler is responsible for handling the logic of creating a new webhook.
+type CreateWebhookHandler struct {
+	repo irepo.IWebhook
+}
+
+// Compile-time check to ensure CreateWebhookHandler implements IHandler for CreateWebhookCommand.
+var _ icmd.IHandler[*CreateWebhookCommand, *CreateWebhookResult] = &CreateWebhookHandler{}
+
+// NewCreateWebhookHandler initializes a new CreateWebhookHandler with a given IWebhook repository.
+func NewCreateWebhookHandler(repo irepo.IWebhook) *CreateWebhookHandler {
+	return &CreateWebhookHandler{repo: repo}
+}
+
+// Handle processes the CreateWebhookCommand to create and store a new webhook.
+func (h *CreateWebhookHandler) Handle(command *CreateWebhookCommand) (*CreateWebhookResult, ierr.IErr) {
+	if err := validateEvents(command.Events); err != nil {
+		return nil, err
+	}
+
+	hook, secret, err := webhook.CreateWebhook(&webhook.WebhookConfig{
+		URL:    command.URL,
+		Events: command.Events,
+		Secret: command.Secret,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.repo.Save(hook); err != nil {
+		return nil, err
+	}
+
+	return &CreateWebhookResult{Webhook: hook, Secret: secret}, nil
+}
@@ -0,0 +1,33 @@
+package command
+
+import (
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/google/uuid"
+)
+
+// DeleteWebhookHandler is a command handler for deleting a webhook along with its deliveries.
+type DeleteWebhookHandler struct {
+	repo       irepo.IWebhook  // Repository interface for webhook operations.
+	deliveries irepo.IDelivery // Repository of the deliveries removed with the webhook.
+}
+
+// Ensure DeleteWebhookHandler implements the IHandler interface for handling commands.
+var _ icmd.IHandler[uuid.UUID, bool] = &DeleteWebhookHandler{}
+
+// NewDeleteWebhookHandler creates a new instance of DeleteWebhookHandler with the provided repositories.
+func NewDeleteWebhookHandler(repo irepo.IWebhook, deliveries irepo.IDelivery) *DeleteWebhookHandler {
+	return &DeleteWebhookHandler{repo: repo, deliveries: deliveries}
+}
+
+// Handle processes the command to delete a webhook by its ID; its pending deliveries are dropped.
+func (h *DeleteWebhookHandler) Handle(id uuid.UUID) (bool, ierr.IErr) {
+	if err := h.repo.Delete(id); err != nil {
+		return false, err
+	}
+	if err := h.deliveries.DeleteByWebhook(id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
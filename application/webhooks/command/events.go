@@ -0,0 +1,20 @@
+package command
+
+import (
+	"fmt"
+
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+)
+
+// validateEvents makes sure every event type a webhook subscribes to is published.
+func validateEvents(events []string) ierr.IErr {
+	for _, event := range events {
+		switch ievents.EventType(event) {
+		case ievents.PersonCreated, ievents.PersonUpdated, ievents.PersonDeleted:
+		default:
+			return ierr.NewValidation(fmt.Sprintf("unknown event type %s", event))
+		}
+	}
+	return nil
+}
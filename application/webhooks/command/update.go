@@ -0,0 +1,55 @@
+package command
+
+import (
+	icmd "github.com/Efamamo/GoCrudChallange/application/common/cqrs/command"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/webhook"
+	"github.com/google/uuid"
+)
+
+// UpdateWebhookCommand represents the command to change the URL and event types of a webhook.
+type UpdateWebhookCommand struct {
+	ID     uuid.UUID
+	URL    string
+	Events []string
+}
+
+// UpdateWebhookHandler is a command handler for updating a webhook.
+type UpdateWebhookHandler struct {
+	repo irepo.IWebhook // Repository interface for webhook operations.
+}
+
+// Ensure UpdateWebhookHandler implements the IHandler interface for handling commands.
+var _ icmd.IHandler[*UpdateWebhookCommand, *webhook.Webhook] = &UpdateWebhookHandler{}
+
+// NewUpdateWebhookHandler creates a new instance of UpdateWebhookHandler with the provided repository.
+func NewUpdateWebhookHandler(repo irepo.IWebhook) *UpdateWebhookHandler {
+	return &UpdateWebhookHandler{repo: repo}
+}
+
+// Handle processes the command to update a webhook; the deliveries still queued are posted to the new URL.
+func (h *UpdateWebhookHandler) Handle(command *UpdateWebhookCommand) (*webhook.Webhook, ierr.IErr) {
+	hook, err := h.repo.Get(command.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateEvents(command.Events); err != nil {
+		return nil, err
+	}
+
+	if err := hook.SetURL(command.URL); err != nil {
+		return nil, err
+	}
+
+	if err := hook.SetEvents(command.Events); err != nil {
+		return nil, err
+	}
+
+	if err := h.repo.Save(hook); err != nil {
+		return nil, err
+	}
+
+	return hook, nil
+}
@@ -0,0 +1,30 @@
+package query
+
+import (
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	"github.com/Efamamo/GoCrudChallange/domain/model/webhook"
+	"github.com/google/uuid"
+)
+
+// Ensure GetWebhookHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[uuid.UUID, *webhook.Webhook] = &GetWebhookHandler{}
+
+// GetWebhookHandler is a query handler for retrieving a webhook by its ID.
+type GetWebhookHandler struct {
+	repo irepo.IWebhook // Repository interface for webhook operations.
+}
+
+// NewGetWebhookHandler creates a new instance of GetWebhookHandler with the provided repository.
+func NewGetWebhookHandler(repo irepo.IWebhook) *GetWebhookHandler {
+	return &GetWebhookHandler{repo: repo}
+}
+
+// Handle processes the query to retrieve a webhook by its ID.
+func (h *GetWebhookHandler) Handle(id uuid.UUID) (*webhook.Webhook, error) {
+	hook, err := h.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
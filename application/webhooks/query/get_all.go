@@ -0,0 +1,29 @@
+package query
+
+import (
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	"github.com/Efamamo/GoCrudChallange/domain/model/webhook"
+)
+
+// Ensure GetWebhooksHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[struct{}, []*webhook.Webhook] = &GetWebhooksHandler{}
+
+// GetWebhooksHandler is a query handler for retrieving every webhook.
+type GetWebhooksHandler struct {
+	repo irepo.IWebhook // Repository interface for webhook operations.
+}
+
+// NewGetWebhooksHandler creates a new instance of GetWebhooksHandler with the provided repository.
+func NewGetWebhooksHandler(repo irepo.IWebhook) *GetWebhooksHandler {
+	return &GetWebhooksHandler{repo: repo}
+}
+
+// Handle processes the query to retrieve every webhook.
+func (h *GetWebhooksHandler) Handle(_ struct{}) ([]*webhook.Webhook, error) {
+	hooks, err := h.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
@@ -0,0 +1,51 @@
+package query
+
+import (
+	iquery "github.com/Efamamo/GoCrudChallange/application/common/cqrs/query"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	"github.com/Efamamo/GoCrudChallange/domain/model/webhook"
+	"github.com/google/uuid"
+)
+
+// GetDeliveriesQuery represents the query to retrieve the delivery log of a webhook.
+type GetDeliveriesQuery struct {
+	WebhookID uuid.UUID
+	Status    webhook.DeliveryStatus // Optional; only the deliveries in this state are returned
+}
+
+// Ensure GetDeliveriesHandler implements the IHandler interface for handling queries.
+var _ iquery.IHandler[*GetDeliveriesQuery, []*webhook.Delivery] = &GetDeliveriesHandler{}
+
+// GetDeliveriesHandler is a query handler for retrieving the deliveries of a webhook.
+type GetDeliveriesHandler struct {
+	repo       irepo.IWebhook  // Repository of the webhooks, to report unknown ones as not found.
+	deliveries irepo.IDelivery // Repository interface for delivery operations.
+}
+
+// NewGetDeliveriesHandler creates a new instance of GetDeliveriesHandler with the provided repositories.
+func NewGetDeliveriesHandler(repo irepo.IWebhook, deliveries irepo.IDelivery) *GetDeliveriesHandler {
+	return &GetDeliveriesHandler{repo: repo, deliveries: deliveries}
+}
+
+// Handle processes the query to retrieve the deliveries of a webhook, newest first.
+func (h *GetDeliveriesHandler) Handle(query *GetDeliveriesQuery) ([]*webhook.Delivery, error) {
+	if _, err := h.repo.Get(query.WebhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := h.deliveries.GetByWebhook(query.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	if query.Status == "" {
+		return deliveries, nil
+	}
+
+	filtered := make([]*webhook.Delivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		if delivery.Status() == query.Status {
+			filtered = append(filtered, delivery)
+		}
+	}
+	return filtered, nil
+}
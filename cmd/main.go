@@ -1,46 +1,549 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
 	"github.com/Efamamo/GoCrudChallange/api/controller"
+	graphqlapi "github.com/Efamamo/GoCrudChallange/api/graphql"
+	grpcapi "github.com/Efamamo/GoCrudChallange/api/grpc"
+	"github.com/Efamamo/GoCrudChallange/api/middleware"
 	"github.com/Efamamo/GoCrudChallange/api/router"
+	keycmd "github.com/Efamamo/GoCrudChallange/application/apikeys/command"
+	keyquery "github.com/Efamamo/GoCrudChallange/application/apikeys/query"
+	attrcmd "github.com/Efamamo/GoCrudChallange/application/attributes/command"
+	attrquery "github.com/Efamamo/GoCrudChallange/application/attributes/query"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	ischedule "github.com/Efamamo/GoCrudChallange/application/common/interface/schedule"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	jobcmd "github.com/Efamamo/GoCrudChallange/application/jobs/command"
+	jobquery "github.com/Efamamo/GoCrudChallange/application/jobs/query"
 	"github.com/Efamamo/GoCrudChallange/application/people/command"
 	"github.com/Efamamo/GoCrudChallange/application/people/query"
+	relcmd "github.com/Efamamo/GoCrudChallange/application/relations/command"
+	relquery "github.com/Efamamo/GoCrudChallange/application/relations/query"
+	schedquery "github.com/Efamamo/GoCrudChallange/application/schedules/query"
+	usercmd "github.com/Efamamo/GoCrudChallange/application/users/command"
+	userquery "github.com/Efamamo/GoCrudChallange/application/users/query"
+	hookcmd "github.com/Efamamo/GoCrudChallange/application/webhooks/command"
+	hookquery "github.com/Efamamo/GoCrudChallange/application/webhooks/query"
 	"github.com/Efamamo/GoCrudChallange/config"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/events"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/idempotency"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/jobs"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/ratelimit"
 	"github.com/Efamamo/GoCrudChallange/infrastructure/repository"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/schedule"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/snapshot"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/token"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/webhook"
 )
 
+// shutdownTimeout bounds the wait for the gRPC calls in progress when the service stops.
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	cfg := config.Envs
 
-	// Initialize the person repository.
-	personRepo := repository.NewPersonRepo()
+	// Stop the servers and the background work gracefully on an interrupt or a termination signal.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Initialize the person repository with the configured uniqueness policy.
+	policy, err := model.ParseUniquenessPolicy(cfg.PersonUniqueness)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	quotas, quotaErr := tenant.ParseQuotas(cfg.TenantDefaultQuota, cfg.TenantQuotas)
+	if quotaErr != nil {
+		log.Fatal(quotaErr.Error())
+	}
+	personRepo := repository.NewPersonRepo(policy, quotas)
+	relationRepo := repository.NewRelationRepo()
+	attributeRepo := repository.NewAttributeRepo()
+	apiKeyRepo := repository.NewAPIKeyRepo()
+	userRepo := repository.NewUserRepo()
+	tokenRepo := repository.NewTokenRepo()
+
+	// Create the bootstrap admin key, so that the first keys can be provisioned without a token.
+	createAPIKeyHandler := keycmd.NewCreateAPIKeyHandler(apiKeyRepo)
+	if cfg.APIKeyBootstrap != "" {
+		if _, err := createAPIKeyHandler.Handle(&keycmd.CreateAPIKeyCommand{
+			Name:   "bootstrap",
+			Scopes: []string{string(auth.Admin)},
+			Secret: cfg.APIKeyBootstrap,
+		}); err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
+	// Create the bootstrap admin user, so that someone can log in before any user is created.
+	createUserHandler := usercmd.NewCreateUserHandler(userRepo)
+	if cfg.AdminUsername != "" {
+		if _, err := createUserHandler.Handle(&usercmd.CreateUserCommand{
+			Username: cfg.AdminUsername,
+			Password: cfg.AdminPassword,
+			Roles:    []string{"admin"},
+		}); err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
+	// Validate bearer tokens and API keys; authentication stays disabled without JWT keys or a bootstrap API key.
+	authConfig := middleware.AuthenticationConfig{
+		HS256Secret:  cfg.JWTSecret,
+		RS256Key:     cfg.JWTPublicKey,
+		Issuer:       cfg.JWTIssuer,
+		Audience:     cfg.JWTAudience,
+		PublicRoutes: cfg.AuthPublicRoutes,
+		TenantClaim:  cfg.TenantClaim,
+		Revocations:  userquery.NewIsTokenRevokedHandler(tokenRepo),
+	}
+	if cfg.JWTSecret != "" || cfg.JWTPublicKey != "" || cfg.APIKeyBootstrap != "" {
+		authConfig.APIKeys = keycmd.NewAuthenticateAPIKeyHandler(apiKeyRepo)
+	}
+	authentication, authErr := middleware.NewAuthentication(authConfig)
+	if authErr != nil {
+		log.Fatal(authErr.Error())
+	}
+	if !authentication.Enabled() {
+		log.Println("Warning: No JWT key or API key configured, the API is not protected by authentication.")
+	}
+
+	// Check the permissions granted by the roles of authenticated callers.
+	base := controller.BaseController{}
+	if authentication.Enabled() {
+		rolePolicy := auth.DefaultPolicy()
+		if cfg.AuthPolicyFile != "" {
+			if rolePolicy, authErr = auth.LoadPolicy(cfg.AuthPolicyFile); authErr != nil {
+				log.Fatal(authErr.Error())
+			}
+		}
+		base.Authorizer = auth.NewAuthorizer(rolePolicy)
+	}
+
+	// Publish the changes of people to the subscribers of this process, such as gRPC watchers and change feeds.
+	replay, replayErr := strconv.Atoi(cfg.EventsReplayBuffer)
+	if replayErr != nil || replay < 0 {
+		log.Fatalf("invalid EVENTS_REPLAY_BUFFER %q", cfg.EventsReplayBuffer)
+	}
+	broker := events.NewBroker(replay)
+
+	// Deliver the changes of people to the webhooks subscribed to them, retrying failed deliveries.
+	webhookRepo := repository.NewWebhookRepo()
+	deliveryRepo := repository.NewDeliveryRepo()
+	maxAttempts, attemptsErr := strconv.Atoi(cfg.WebhookMaxAttempts)
+	if attemptsErr != nil || maxAttempts < 1 {
+		log.Fatalf("invalid WEBHOOK_MAX_ATTEMPTS %q", cfg.WebhookMaxAttempts)
+	}
+	backoff, backoffErr := time.ParseDuration(cfg.WebhookBackoff)
+	if backoffErr != nil {
+		log.Fatal(backoffErr.Error())
+	}
+	maxBackoff, backoffErr := time.ParseDuration(cfg.WebhookMaxBackoff)
+	if backoffErr != nil {
+		log.Fatal(backoffErr.Error())
+	}
+	webhookTimeout, timeoutErr := time.ParseDuration(cfg.WebhookTimeout)
+	if timeoutErr != nil {
+		log.Fatal(timeoutErr.Error())
+	}
+	webhookConcurrency, concurrencyErr := strconv.Atoi(cfg.WebhookConcurrency)
+	if concurrencyErr != nil || webhookConcurrency < 1 {
+		log.Fatalf("invalid WEBHOOK_CONCURRENCY %q", cfg.WebhookConcurrency)
+	}
+	dispatcher := webhook.NewDispatcher(webhook.DispatcherConfig{
+		Webhooks:    webhookRepo,
+		Deliveries:  deliveryRepo,
+		Client:      &http.Client{Timeout: webhookTimeout},
+		MaxAttempts: maxAttempts,
+		Backoff:     backoff,
+		MaxBackoff:  maxBackoff,
+		Concurrency: webhookConcurrency,
+	})
+	dispatcher.Start(ctx, broker)
 
 	// Create command handlers for various person-related operations.
-	createPersonHandler := command.NewCreatePersonHandler(personRepo)
-	updatePersonHandler := command.NewUpdatePersonHandler(personRepo)
-	deletePersonHandler := command.NewDeletePersonHandler(personRepo)
+	createPersonHandler := command.NewCreatePersonHandler(personRepo, attributeRepo, broker)
+	updatePersonHandler := command.NewUpdatePersonHandler(personRepo, attributeRepo, base.Authorizer, broker)
+	deletePersonHandler := command.NewDeletePersonHandler(personRepo, relationRepo, base.Authorizer, broker)
+	transferOwnershipHandler := command.NewTransferOwnershipHandler(personRepo, base.Authorizer, broker)
+	mergePeopleHandler := command.NewMergePeopleHandler(personRepo, relationRepo, base.Authorizer, broker)
+
+	// Uploads larger than the sync limit are imported by a job.
+	importSyncLimit, limitErr := strconv.ParseInt(cfg.ImportSyncLimit, 10, 64)
+	if limitErr != nil || importSyncLimit < 0 {
+		log.Fatalf("invalid IMPORT_SYNC_LIMIT %q", cfg.ImportSyncLimit)
+	}
+	importMaxSize, limitErr := strconv.ParseInt(cfg.ImportMaxSize, 10, 64)
+	if limitErr != nil || importMaxSize < 0 {
+		log.Fatalf("invalid IMPORT_MAX_SIZE %q", cfg.ImportMaxSize)
+	}
+
+	// Keep the uploads of background imports apart from their jobs, until the jobs finished.
+	uploadRepo, uploadErr := repository.NewUploadRepo(cfg.UploadsDir)
+	if uploadErr != nil {
+		log.Fatal(uploadErr.Error())
+	}
+	importPeopleHandler := command.NewImportPeopleHandler(personRepo, attributeRepo, uploadRepo, broker, controller.ImportRows, importMaxSize)
+
+	// Run long operations on people as background jobs, persisted so that queued jobs survive restarts.
+	jobRepo, jobErr := repository.NewJobRepo(cfg.JobsFile)
+	if jobErr != nil {
+		log.Fatal(jobErr.Error())
+	}
+	workers, workersErr := strconv.Atoi(cfg.JobsWorkers)
+	if workersErr != nil || workers < 1 {
+		log.Fatalf("invalid JOBS_WORKERS %q", cfg.JobsWorkers)
+	}
+	runner := jobs.NewRunner(jobs.RunnerConfig{Jobs: jobRepo, Workers: workers})
+	runner.Register(command.PurgeKind, command.NewPurgePeopleHandler(personRepo, relationRepo, base.Authorizer, broker))
+	runner.Register(command.ImportKind, importPeopleHandler)
+	submitJobHandler := jobcmd.NewSubmitJobHandler(runner)
+	startImportHandler := command.NewStartImportHandler(uploadRepo, runner)
+	if err := runner.Start(ctx); err != nil {
+		log.Fatal(err.Error())
+	}
+
+	// Run the maintenance tasks on the schedules of the configuration, never overlapping themselves.
+	jitter, jitterErr := time.ParseDuration(cfg.ScheduleJitter)
+	if jitterErr != nil {
+		log.Fatal(jitterErr.Error())
+	}
+	jobsRetention, retentionErr := time.ParseDuration(cfg.JobsRetention)
+	if retentionErr != nil {
+		log.Fatal(retentionErr.Error())
+	}
+	webhookRetention, retentionErr := time.ParseDuration(cfg.WebhookRetention)
+	if retentionErr != nil {
+		log.Fatal(retentionErr.Error())
+	}
+	snapshotKeep, keepErr := strconv.Atoi(cfg.SnapshotKeep)
+	if keepErr != nil || snapshotKeep < 1 {
+		log.Fatalf("invalid SNAPSHOT_KEEP %q", cfg.SnapshotKeep)
+	}
+	purgeAttributes, purgeErr := command.ParsePurgeAttributes(cfg.PurgeAttributes)
+	if purgeErr != nil {
+		log.Fatal(purgeErr.Error())
+	}
+	if cfg.SchedulePeoplePurge != "" && len(purgeAttributes) == 0 {
+		log.Fatal("PURGE_ATTRIBUTES is required when SCHEDULE_PEOPLE_PURGE is set")
+	}
+	scheduler := schedule.NewScheduler(schedule.SchedulerConfig{Jitter: jitter})
+	tasks := []struct {
+		name string
+		spec string
+		task ischedule.ITask
+	}{
+		{jobcmd.CompactKind, cfg.ScheduleJobsCompact, jobcmd.NewCompactJobsHandler(jobRepo, jobsRetention)},
+		{hookcmd.CompactKind, cfg.ScheduleWebhooksCompact, hookcmd.NewCompactDeliveriesHandler(deliveryRepo, webhookRetention)},
+		{snapshot.PeopleKind, cfg.SchedulePeopleSnapshot, snapshot.NewPeopleSnapshotter(snapshot.PeopleConfig{People: personRepo, Dir: cfg.SnapshotDir, Keep: snapshotKeep})},
+		{command.PurgeKind, cfg.SchedulePeoplePurge, command.NewSchedulePurgeHandler(personRepo, runner, purgeAttributes)},
+	}
+	for _, t := range tasks {
+		if err := scheduler.Register(t.name, t.spec, t.task); err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+	scheduler.Start(ctx)
 
 	// Create query handlers for retrieving person data.
-	getPersonHandler := query.NewGetPersonHandler(personRepo)
-	getAllPersonsHandler := query.NewGetPeopleHandler(personRepo)
+	getPersonHandler := query.NewGetPersonHandler(personRepo, base.Authorizer)
+	getAllPersonsHandler := query.NewGetPeopleHandler(personRepo, base.Authorizer)
+	getDuplicatesHandler := query.NewGetDuplicatesHandler(personRepo, base.Authorizer)
+	getRedirectHandler := query.NewGetRedirectHandler(personRepo, base.Authorizer)
+	getMergesHandler := query.NewGetMergesHandler(personRepo, base.Authorizer)
+	exportPeopleHandler := query.NewExportPeopleHandler(personRepo, attributeRepo, base.Authorizer)
 
 	// Create a PersonController with the initialized handlers.
 	personController := controller.PersonController{
+		BaseController: base,
+
+		CreateHandler: createPersonHandler,
+		UpdateHandler: updatePersonHandler,
+		DeleteHandler: deletePersonHandler,
+		GetHandler:    getPersonHandler,
+		GetAllHandler: getAllPersonsHandler,
+
+		DuplicatesHandler: getDuplicatesHandler,
+		MergeHandler:      mergePeopleHandler,
+		RedirectHandler:   getRedirectHandler,
+		MergesHandler:     getMergesHandler,
+		TransferHandler:   transferOwnershipHandler,
+
+		ImportHandler:      importPeopleHandler,
+		StartImportHandler: startImportHandler,
+		ImportSyncLimit:    importSyncLimit,
+		ImportMaxSize:      importMaxSize,
+
+		ExportHandler: exportPeopleHandler,
+
+		Upsert: cfg.PersonUpsert,
+	}
+
+	// Create a GraphQLController resolving the schema of people with the same handlers as the PersonController.
+	schema, schemaErr := graphqlapi.NewSchema(&graphqlapi.Resolver{
+		Authorizer: base.Authorizer,
+
 		CreateHandler: createPersonHandler,
 		UpdateHandler: updatePersonHandler,
 		DeleteHandler: deletePersonHandler,
 		GetHandler:    getPersonHandler,
 		GetAllHandler: getAllPersonsHandler,
+	})
+	if schemaErr != nil {
+		log.Fatal(schemaErr.Error())
+	}
+	graphQLController := controller.GraphQLController{Schema: schema}
+
+	// Create an EventController streaming the changes published by the command handlers.
+	heartbeat, heartbeatErr := time.ParseDuration(cfg.EventsHeartbeat)
+	if heartbeatErr != nil {
+		log.Fatal(heartbeatErr.Error())
+	}
+	eventController := controller.EventController{
+		BaseController: base,
+
+		Events:    broker,
+		Heartbeat: heartbeat,
+	}
+
+	// Create a RelationController with the handlers managing relations between people.
+	relationController := controller.RelationController{
+		BaseController: base,
+
+		AddHandler:    relcmd.NewAddRelationHandler(personRepo, relationRepo, base.Authorizer),
+		RemoveHandler: relcmd.NewRemoveRelationHandler(personRepo, relationRepo, base.Authorizer),
+		GetAllHandler: relquery.NewGetRelationsHandler(personRepo, relationRepo, base.Authorizer),
+		MutualHandler: relquery.NewGetMutualHandler(personRepo, relationRepo, base.Authorizer),
+		PathHandler:   relquery.NewGetPathHandler(personRepo, relationRepo, base.Authorizer),
+	}
+
+	// Create an AttributeController with the handlers administering the custom attribute schema.
+	attributeController := controller.AttributeController{
+		BaseController: base,
+
+		SaveHandler:   attrcmd.NewSaveAttributeHandler(attributeRepo),
+		DeleteHandler: attrcmd.NewDeleteAttributeHandler(attributeRepo),
+		GetAllHandler: attrquery.NewGetAttributesHandler(attributeRepo),
+	}
+
+	// Sign access tokens for local users with the HS256 secret, so the authentication middleware accepts them.
+	accessTTL, ttlErr := time.ParseDuration(cfg.AccessTokenTTL)
+	if ttlErr != nil {
+		log.Fatal(ttlErr.Error())
+	}
+	refreshTTL, ttlErr := time.ParseDuration(cfg.RefreshTokenTTL)
+	if ttlErr != nil {
+		log.Fatal(ttlErr.Error())
+	}
+	if cfg.JWTSecret == "" {
+		log.Println("Warning: No JWT_SECRET configured, local users can't log in.")
+	}
+	issuer := token.NewJWTIssuer(token.JWTIssuerConfig{
+		HS256Secret: cfg.JWTSecret,
+		Issuer:      cfg.JWTIssuer,
+		Audience:    cfg.JWTAudience,
+		TenantClaim: cfg.TenantClaim,
+		TTL:         accessTTL,
+	})
+
+	// Create an AuthController with the handlers issuing and revoking tokens of local users.
+	authController := controller.AuthController{
+		BaseController: base,
+
+		LoginHandler:   usercmd.NewLoginHandler(userRepo, tokenRepo, issuer, refreshTTL),
+		RefreshHandler: usercmd.NewRefreshHandler(userRepo, tokenRepo, issuer, refreshTTL),
+		LogoutHandler:  usercmd.NewLogoutHandler(tokenRepo),
+	}
+
+	// Create a UserController with the handlers administering local users.
+	userController := controller.UserController{
+		BaseController: base,
+
+		CreateHandler: createUserHandler,
+		DeleteHandler: usercmd.NewDeleteUserHandler(userRepo, tokenRepo),
+		GetAllHandler: userquery.NewGetUsersHandler(userRepo),
+	}
+
+	// Create an APIKeyController with the handlers administering API keys.
+	apiKeyController := controller.APIKeyController{
+		BaseController: base,
+
+		CreateHandler: createAPIKeyHandler,
+		UpdateHandler: keycmd.NewUpdateAPIKeyHandler(apiKeyRepo),
+		RevokeHandler: keycmd.NewRevokeAPIKeyHandler(apiKeyRepo),
+		DeleteHandler: keycmd.NewDeleteAPIKeyHandler(apiKeyRepo),
+		GetHandler:    keyquery.NewGetAPIKeyHandler(apiKeyRepo),
+		GetAllHandler: keyquery.NewGetAPIKeysHandler(apiKeyRepo),
+	}
+
+	// Create a WebhookController with the handlers administering webhooks and reading their delivery logs.
+	webhookController := controller.WebhookController{
+		BaseController: base,
+
+		CreateHandler:     hookcmd.NewCreateWebhookHandler(webhookRepo),
+		UpdateHandler:     hookcmd.NewUpdateWebhookHandler(webhookRepo),
+		DeleteHandler:     hookcmd.NewDeleteWebhookHandler(webhookRepo, deliveryRepo),
+		GetHandler:        hookquery.NewGetWebhookHandler(webhookRepo),
+		GetAllHandler:     hookquery.NewGetWebhooksHandler(webhookRepo),
+		DeliveriesHandler: hookquery.NewGetDeliveriesHandler(webhookRepo, deliveryRepo),
+	}
+
+	// Create a JobController with the handlers submitting background jobs and following them.
+	jobController := controller.JobController{
+		BaseController: base,
+
+		SubmitHandler: submitJobHandler,
+		CancelHandler: jobcmd.NewCancelJobHandler(runner, jobRepo, base.Authorizer),
+		GetHandler:    jobquery.NewGetJobHandler(jobRepo, base.Authorizer),
+		GetAllHandler: jobquery.NewGetJobsHandler(jobRepo, base.Authorizer),
+	}
+
+	// Create a ScheduleController reading the schedules of the maintenance tasks.
+	scheduleController := controller.ScheduleController{
+		BaseController: base,
+
+		GetAllHandler: schedquery.NewGetSchedulesHandler(scheduler),
+	}
+
+	// Limit the requests of each client per route group, keeping the token buckets in memory.
+	clientRates, rateErr := middleware.ParseClientRates(cfg.RateLimitClients)
+	if rateErr != nil {
+		log.Fatal(rateErr.Error())
+	}
+	rateLimitStore := ratelimit.NewMemoryStore()
+	rateLimit := func(name string, value string) *middleware.RateLimit {
+		if value == "" {
+			return nil
+		}
+		rate, err := middleware.ParseRate(value)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		return middleware.NewRateLimit(middleware.RateLimitConfig{Name: name, Rate: rate, Clients: clientRates, Store: rateLimitStore})
+	}
+
+	// Replay the responses of POST requests retried with the same Idempotency-Key.
+	// The bodies of the requests with a key are bounded like the largest upload, and larger responses aren't stored.
+	idempotencyTTL, ttlErr := time.ParseDuration(cfg.IdempotencyTTL)
+	if ttlErr != nil {
+		log.Fatal(ttlErr.Error())
+	}
+	idempotencyMaxResponse, limitErr := strconv.ParseInt(cfg.IdempotencyMaxResponse, 10, 64)
+	if limitErr != nil || idempotencyMaxResponse < 0 {
+		log.Fatalf("invalid IDEMPOTENCY_MAX_RESPONSE %q", cfg.IdempotencyMaxResponse)
+	}
+
+	// Announce when the deprecated v1 of the API stops being served.
+	var v1Sunset time.Time
+	if cfg.APIV1Sunset != "" {
+		var sunsetErr error
+		if v1Sunset, sunsetErr = time.Parse(time.DateOnly, cfg.APIV1Sunset); sunsetErr != nil {
+			log.Fatal(sunsetErr.Error())
+		}
+	}
+
+	// Resolve the tenant of requests and gRPC calls from the same places.
+	tenancy := middleware.NewTenancy(middleware.TenancyConfig{
+		Header:     cfg.TenantHeader,
+		BaseDomain: cfg.TenantBaseDomain,
+		Claim:      cfg.TenantClaim,
+		Required:   cfg.TenantRequired,
+	})
+
+	// Limit the calls of each client reaching people, over HTTP and gRPC alike.
+	personRateLimit := rateLimit("person", cfg.RateLimitPerson)
+
+	// Limit the calls of each address before their credentials are checked, over HTTP and gRPC alike.
+	var addressRateLimit *middleware.RateLimit
+	if cfg.RateLimitIP != "" {
+		rate, err := middleware.ParseRate(cfg.RateLimitIP)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		addressRateLimit = middleware.NewRateLimit(middleware.RateLimitConfig{Name: "ip", Rate: rate, Clients: clientRates, Store: rateLimitStore, ByAddress: true})
+	}
+
+	// Serve the PersonService over gRPC with the same handlers as the PersonController.
+	stopGRPC := func() {}
+	if cfg.GRPCPort != "" {
+		listener, listenErr := net.Listen("tcp", fmt.Sprintf("%s:%s", cfg.Host, cfg.GRPCPort))
+		if listenErr != nil {
+			log.Fatal(listenErr.Error())
+		}
+		grpcServer := grpcapi.NewServer(grpcapi.ServerConfig{
+			Person: &grpcapi.PersonServer{
+				Authorizer: base.Authorizer,
+
+				CreateHandler: createPersonHandler,
+				UpdateHandler: updatePersonHandler,
+				DeleteHandler: deletePersonHandler,
+				GetHandler:    getPersonHandler,
+				GetAllHandler: getAllPersonsHandler,
+
+				Events: broker,
+			},
+			Authentication: authentication,
+			Tenancy:        tenancy,
+			RateLimit:      personRateLimit,
+			AddressLimit:   addressRateLimit,
+			Reflection:     cfg.GRPCReflection,
+		})
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatal(err.Error())
+			}
+		}()
+		stopGRPC = func() {
+			// Watches don't end by themselves, so the calls still in progress are cut once the timeout is over
+			timer := time.AfterFunc(shutdownTimeout, grpcServer.Stop)
+			defer timer.Stop()
+			grpcServer.GracefulStop()
+		}
 	}
 
 	// Start the API router with the person controller to handle requests.
-	controllers := []any{personController}
+	controllers := []any{personController, relationController, attributeController, apiKeyController, authController, userController, graphQLController, eventController, webhookController, jobController, scheduleController}
 	r := router.NewRouter(router.Config{
-		Host:cfg.Host,
-		Port: cfg.Port,
+		Host:        cfg.Host,
+		Port:        cfg.Port,
 		Controllers: controllers,
-	
+
+		Authentication: authentication,
+		Tenancy:        tenancy,
+		RateLimits: router.RateLimits{
+			Address: addressRateLimit,
+			Auth:    rateLimit("auth", cfg.RateLimitAuth),
+			Person:  personRateLimit,
+			Admin:   rateLimit("admin", cfg.RateLimitAdmin),
+		},
+		TrustedProxies: cfg.TrustedProxies,
+		Idempotency: middleware.NewIdempotency(middleware.IdempotencyConfig{
+			Store:           idempotency.NewMemoryStore(),
+			TTL:             idempotencyTTL,
+			MaxBodySize:     importMaxSize,
+			MaxResponseSize: idempotencyMaxResponse,
+		}),
+		V1Sunset: v1Sunset,
 	})
 
-	r.StartRouter(personController)
+	if err := r.StartRouter(ctx, personController, relationController, attributeController, apiKeyController, authController, userController, graphQLController, eventController, webhookController, jobController, scheduleController); err != nil {
+		log.Fatal(err.Error())
+	}
+
+	// Once the HTTP server stopped, let the gRPC calls, the jobs and the scheduled tasks in progress wind down.
+	stopGRPC()
+	runner.Wait()
+	scheduler.Wait()
+	log.Println("Stopped gracefully.")
 }
@@ -3,14 +3,81 @@ package config
 import (
 	"log"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds the application's configuration values.
 type Config struct {
-	Port string
-	Host string
+	Port           string
+	Host           string
+	GRPCPort       string // Port of the gRPC server; not started when empty
+	GRPCReflection bool   // Whether the gRPC server serves the reflection service
+
+	PersonUniqueness string // Uniqueness policy of people: none, name or name_age
+	PersonUpsert     bool   // Whether PUT /person/:id creates the person when it doesn't exist
+
+	ImportSyncLimit string // Size in bytes of the largest upload imported before responding; larger ones are imported by a job
+	ImportMaxSize   string // Size in bytes of the largest upload of an import
+
+	JWTSecret        string   // Shared secret validating HS256 tokens
+	JWTPublicKey     string   // PEM encoded RSA public key, or a path to one, validating RS256 tokens
+	JWTIssuer        string   // Expected issuer of tokens
+	JWTAudience      string   // Expected audience of tokens
+	AuthPublicRoutes []string // Routes reachable without a token, e.g. "GET /person"
+	AuthPolicyFile   string   // JSON file mapping roles to permissions; a default policy is used when empty
+
+	APIKeyBootstrap string // Secret of an admin API key created at startup, to provision the first keys
+
+	AccessTokenTTL  string // Lifetime of access tokens issued on login, e.g. "15m"
+	RefreshTokenTTL string // Lifetime of refresh tokens issued on login, e.g. "720h"
+	AdminUsername   string // Username of an admin user created at startup
+	AdminPassword   string // Password of the admin user created at startup
+
+	TenantHeader       string   // Header naming the tenant of a request
+	TenantBaseDomain   string   // Domain whose subdomains name the tenant of a request
+	TenantClaim        string   // Token claim naming the tenant of authenticated callers
+	TenantRequired     bool     // Whether requests naming no tenant are rejected
+	TenantDefaultQuota string   // Maximum number of people of each tenant; unlimited when 0
+	TenantQuotas       []string // Quotas of specific tenants, e.g. "acme=1000"
+
+	RateLimitIP      string   // Quota of each address on the routes checking credentials, taken before they are checked; unlimited when empty
+	RateLimitAuth    string   // Quota of each client on the /auth routes, e.g. "10/1m"; unlimited when empty
+	RateLimitPerson  string   // Quota of each client on the /person routes, e.g. "100/1m/20"; unlimited when empty
+	RateLimitAdmin   string   // Quota of each client on the /admin routes; unlimited when empty
+	RateLimitClients []string // Quotas of specific clients, e.g. "apikey:<id>=1000/1m"
+	TrustedProxies   []string // Addresses or CIDR ranges of the proxies whose X-Forwarded-For headers are trusted
+
+	IdempotencyTTL         string // Time during which responses to requests with an Idempotency-Key are replayed, e.g. "24h"
+	IdempotencyMaxResponse string // Size in bytes of the largest response stored for an Idempotency-Key
+
+	APIV1Sunset string // Date after which v1 of the API stops being served, e.g. "2027-01-31"; not announced when empty
+
+	EventsReplayBuffer string // Number of changes of people held for subscribers resuming their feed
+	EventsHeartbeat    string // Time between the heartbeats of idle change feeds, e.g. "15s"
+
+	WebhookMaxAttempts string // Attempts of a webhook delivery before it is dead-lettered
+	WebhookBackoff     string // Delay before the first retry of a webhook delivery, doubled after every failure, e.g. "30s"
+	WebhookMaxBackoff  string // Maximum delay between two attempts of a webhook delivery, e.g. "1h"
+	WebhookTimeout     string // Time a webhook receiver has to respond to a delivery, e.g. "10s"
+	WebhookRetention   string // Time the finished webhook deliveries are kept before being compacted, e.g. "168h"
+	WebhookConcurrency string // Webhooks posted to at the same time
+
+	JobsFile      string // NDJSON file the changes of the background jobs are appended to; kept in memory when empty
+	UploadsDir    string // Directory the uploads of background imports are stored in until their job finished; kept in memory when empty
+	JobsWorkers   string // Number of background jobs run at the same time
+	JobsRetention string // Time the finished background jobs are kept before being compacted, e.g. "168h"
+
+	ScheduleJitter          string // Maximum random delay added to every scheduled run, e.g. "30s"
+	ScheduleJobsCompact     string // Cron expression compacting the background jobs; disabled when empty
+	ScheduleWebhooksCompact string // Cron expression compacting the webhook deliveries; disabled when empty
+	SchedulePeopleSnapshot  string // Cron expression writing snapshots of people; disabled when empty
+	SnapshotDir             string // Directory the snapshots of people are written to, with a subdirectory per tenant
+	SnapshotKeep            string // Number of snapshots of people kept per tenant
+
+	SchedulePeoplePurge string   // Cron expression purging the people marked by the PurgeAttributes; disabled when empty
+	PurgeAttributes     []string // Custom attributes marking the people to purge, as name=value pairs
 }
 
 // Envs holds the application's configuration loaded from environment variables.
@@ -24,6 +91,73 @@ func initializeConfig() Config {
 	return Config{
 		Host: getEnv("HOST", "localhost"),
 		Port: getEnv("PORT", "8080"),
+
+		GRPCPort:       getEnv("GRPC_PORT", ""),
+		GRPCReflection: getEnv("GRPC_REFLECTION", "false") == "true",
+
+		PersonUniqueness: getEnv("PERSON_UNIQUENESS", "none"),
+		PersonUpsert:     getEnv("PERSON_UPSERT", "false") == "true",
+
+		ImportSyncLimit: getEnv("IMPORT_SYNC_LIMIT", "1048576"),
+		ImportMaxSize:   getEnv("IMPORT_MAX_SIZE", "33554432"),
+
+		JWTSecret:        getEnv("JWT_SECRET", ""),
+		JWTPublicKey:     getEnv("JWT_PUBLIC_KEY", ""),
+		JWTIssuer:        getEnv("JWT_ISSUER", ""),
+		JWTAudience:      getEnv("JWT_AUDIENCE", ""),
+		AuthPublicRoutes: getEnvList("AUTH_PUBLIC_ROUTES", nil),
+		AuthPolicyFile:   getEnv("AUTH_POLICY_FILE", ""),
+
+		APIKeyBootstrap: getEnv("API_KEY_BOOTSTRAP", ""),
+
+		AccessTokenTTL:  getEnv("ACCESS_TOKEN_TTL", "15m"),
+		RefreshTokenTTL: getEnv("REFRESH_TOKEN_TTL", "720h"),
+		AdminUsername:   getEnv("ADMIN_USERNAME", ""),
+		AdminPassword:   getEnv("ADMIN_PASSWORD", ""),
+
+		TenantHeader:       getEnv("TENANT_HEADER", "X-Tenant-ID"),
+		TenantBaseDomain:   getEnv("TENANT_BASE_DOMAIN", ""),
+		TenantClaim:        getEnv("TENANT_CLAIM", ""),
+		TenantRequired:     getEnv("TENANT_REQUIRED", "false") == "true",
+		TenantDefaultQuota: getEnv("TENANT_DEFAULT_QUOTA", "0"),
+		TenantQuotas:       getEnvList("TENANT_QUOTAS", nil),
+
+		RateLimitIP:      getEnv("RATE_LIMIT_IP", ""),
+		RateLimitAuth:    getEnv("RATE_LIMIT_AUTH", ""),
+		RateLimitPerson:  getEnv("RATE_LIMIT_PERSON", ""),
+		RateLimitAdmin:   getEnv("RATE_LIMIT_ADMIN", ""),
+		RateLimitClients: getEnvList("RATE_LIMIT_CLIENTS", nil),
+		TrustedProxies:   getEnvList("TRUSTED_PROXIES", nil),
+
+		IdempotencyTTL:         getEnv("IDEMPOTENCY_TTL", "24h"),
+		IdempotencyMaxResponse: getEnv("IDEMPOTENCY_MAX_RESPONSE", "1048576"),
+
+		APIV1Sunset: getEnv("API_V1_SUNSET", ""),
+
+		EventsReplayBuffer: getEnv("EVENTS_REPLAY_BUFFER", "1000"),
+		EventsHeartbeat:    getEnv("EVENTS_HEARTBEAT", "15s"),
+
+		WebhookMaxAttempts: getEnv("WEBHOOK_MAX_ATTEMPTS", "8"),
+		WebhookBackoff:     getEnv("WEBHOOK_BACKOFF", "30s"),
+		WebhookMaxBackoff:  getEnv("WEBHOOK_MAX_BACKOFF", "1h"),
+		WebhookTimeout:     getEnv("WEBHOOK_TIMEOUT", "10s"),
+		WebhookRetention:   getEnv("WEBHOOK_RETENTION", "168h"),
+		WebhookConcurrency: getEnv("WEBHOOK_CONCURRENCY", "8"),
+
+		JobsFile:      getEnv("JOBS_FILE", ""),
+		UploadsDir:    getEnv("UPLOADS_DIR", ""),
+		JobsWorkers:   getEnv("JOBS_WORKERS", "4"),
+		JobsRetention: getEnv("JOBS_RETENTION", "168h"),
+
+		ScheduleJitter:          getEnv("SCHEDULE_JITTER", "30s"),
+		ScheduleJobsCompact:     getEnv("SCHEDULE_JOBS_COMPACT", "@hourly"),
+		ScheduleWebhooksCompact: getEnv("SCHEDULE_WEBHOOKS_COMPACT", "@hourly"),
+		SchedulePeopleSnapshot:  getEnv("SCHEDULE_PEOPLE_SNAPSHOT", ""),
+		SnapshotDir:             getEnv("SNAPSHOT_DIR", "snapshots"),
+		SnapshotKeep:            getEnv("SNAPSHOT_KEEP", "7"),
+
+		SchedulePeoplePurge: getEnv("SCHEDULE_PEOPLE_PURGE", ""),
+		PurgeAttributes:     getEnvList("PURGE_ATTRIBUTES", nil),
 	}
 }
 
@@ -34,3 +168,19 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// getEnvList retrieves a comma-separated environment variable as a list or returns a fallback value if the variable is not set.
+func getEnvList(key string, fallback []string) []string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
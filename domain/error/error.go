@@ -17,8 +17,9 @@ const (
 
 // Error represents a custom domain error with a specific type and message.
 type Error struct {
-	kind    string // The type of the error (e.g., Validation, Conflict)
-	Message string // The detailed error message
+	kind       string // The type of the error (e.g., Validation, Conflict)
+	Message    string // The detailed error message
+	existingID string // The ID of the existing resource a Conflict error refers to, when known
 }
 
 // Ensure that Error implements the ierr.IErr interface.
@@ -39,6 +40,11 @@ func (e Error) Type() string {
 	return e.kind
 }
 
+// ExistingID returns the ID of the existing resource a Conflict error refers to, or an empty string.
+func (e Error) ExistingID() string {
+	return e.existingID
+}
+
 // NewValidation creates a new validation error with the given message.
 func NewValidation(message string) *Error {
 	return new(Validation, message)
@@ -49,6 +55,14 @@ func NewConflict(message string) *Error {
 	return new(Conflict, message)
 }
 
+// NewConflictWith creates a new conflict error with the given message,
+// referencing the ID of the existing resource that caused the conflict.
+func NewConflictWith(message string, existingID string) *Error {
+	err := new(Conflict, message)
+	err.existingID = existingID
+	return err
+}
+
 // NewUnexpected creates a new unexpected server error with the given message.
 func NewUnexpected(message string) *Error {
 	return new(Unexpected, message)
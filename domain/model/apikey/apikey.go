@@ -0,0 +1,199 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/google/uuid"
+)
+
+// secretPrefix starts every generated secret, making leaked keys easy to recognise.
+const secretPrefix = "gck_"
+
+// APIKey is a long-lived credential for non-interactive clients.
+// Only the hash of its secret is kept; the secret itself is shown once, when the key is generated.
+type APIKey struct {
+	id         uuid.UUID
+	name       string
+	prefix     string
+	hash       string
+	scopes     []string
+	tenant     string
+	createdAt  time.Time
+	expiresAt  *time.Time
+	revokedAt  *time.Time
+	lastUsedAt *time.Time
+}
+
+// APIKeyConfig is a configuration struct used to generate a new APIKey.
+type APIKeyConfig struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time // Optional; the key never expires when nil
+	Secret    string     // Optional; a random secret is generated when empty
+	Tenant    string     // Tenant the key belongs to; optional
+}
+
+// GenerateAPIKey creates a new APIKey and returns it together with its secret.
+func GenerateAPIKey(kc *APIKeyConfig) (*APIKey, string, ierr.IErr) {
+	key := &APIKey{
+		id:        uuid.New(),
+		tenant:    kc.Tenant,
+		createdAt: time.Now().UTC(),
+	}
+
+	if err := key.SetName(kc.Name); err != nil {
+		return nil, "", err
+	}
+
+	if err := key.SetScopes(kc.Scopes); err != nil {
+		return nil, "", err
+	}
+
+	if err := key.SetExpiresAt(kc.ExpiresAt); err != nil {
+		return nil, "", err
+	}
+
+	secret := kc.Secret
+	if secret == "" {
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return nil, "", ierr.NewUnexpected("failed to generate api key")
+		}
+		secret = secretPrefix + base64.RawURLEncoding.EncodeToString(random)
+	}
+
+	if len(secret) < 16 {
+		return nil, "", ierr.NewValidation("api key secret should be at least 16 characters long")
+	}
+
+	key.prefix = secret[:min(len(secret), len(secretPrefix)+6)]
+	key.hash = Hash(secret)
+
+	return key, secret, nil
+}
+
+// Hash returns the SHA-256 hash of a secret, as stored by the repository.
+// Secrets are long random strings, so a fast hash is enough to protect them at rest.
+func Hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// SetName sets the name of the key after validating its length.
+func (k *APIKey) SetName(name string) ierr.IErr {
+	name = strings.TrimSpace(name)
+	min := 1
+	max := 100
+	if len(name) < min || len(name) > max {
+		return ierr.NewValidation(fmt.Sprintf("name length should be between %d and %d", min, max))
+	}
+
+	k.name = name
+	return nil
+}
+
+// SetScopes sets the scopes of the key; a key should have at least one scope.
+func (k *APIKey) SetScopes(scopes []string) ierr.IErr {
+	if len(scopes) == 0 {
+		return ierr.NewValidation("api key should have at least one scope")
+	}
+
+	k.scopes = scopes
+	return nil
+}
+
+// SetExpiresAt sets the expiry of the key, which should be in the future. A nil expiry never expires.
+func (k *APIKey) SetExpiresAt(expiresAt *time.Time) ierr.IErr {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return ierr.NewValidation("expiry should be in the future")
+	}
+
+	k.expiresAt = expiresAt
+	return nil
+}
+
+// Revoke permanently disables the key.
+func (k *APIKey) Revoke() {
+	if k.revokedAt == nil {
+		now := time.Now().UTC()
+		k.revokedAt = &now
+	}
+}
+
+// MarkUsed records that the key was used at the given time.
+func (k *APIKey) MarkUsed(at time.Time) {
+	at = at.UTC()
+	k.lastUsedAt = &at
+}
+
+// Usable reports whether the key is neither revoked nor expired at the given time.
+func (k *APIKey) Usable(at time.Time) bool {
+	if k.revokedAt != nil {
+		return false
+	}
+	return k.expiresAt == nil || at.Before(*k.expiresAt)
+}
+
+// Clone returns a copy of the key that can be changed without affecting the original.
+func (k *APIKey) Clone() *APIKey {
+	clone := *k
+	clone.scopes = append([]string(nil), k.scopes...)
+	return &clone
+}
+
+// Id returns the unique identifier of the key.
+func (k *APIKey) Id() uuid.UUID {
+	return k.id
+}
+
+// Name returns the name of the key.
+func (k *APIKey) Name() string {
+	return k.name
+}
+
+// Prefix returns the first characters of the secret, which help identify the key.
+func (k *APIKey) Prefix() string {
+	return k.prefix
+}
+
+// Hash returns the hash of the secret of the key.
+func (k *APIKey) Hash() string {
+	return k.hash
+}
+
+// Scopes returns the permissions granted to the key.
+func (k *APIKey) Scopes() []string {
+	return k.scopes
+}
+
+// Tenant returns the tenant the key belongs to, or an empty string when it belongs to none.
+func (k *APIKey) Tenant() string {
+	return k.tenant
+}
+
+// CreatedAt returns the time at which the key was generated.
+func (k *APIKey) CreatedAt() time.Time {
+	return k.createdAt
+}
+
+// ExpiresAt returns the time at which the key expires, or nil when it never does.
+func (k *APIKey) ExpiresAt() *time.Time {
+	return k.expiresAt
+}
+
+// RevokedAt returns the time at which the key was revoked, or nil when it wasn't.
+func (k *APIKey) RevokedAt() *time.Time {
+	return k.revokedAt
+}
+
+// LastUsedAt returns the time at which the key was last used, or nil when it never was.
+func (k *APIKey) LastUsedAt() *time.Time {
+	return k.lastUsedAt
+}
@@ -0,0 +1,180 @@
+package attribute
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+)
+
+// Types of values a custom attribute can hold.
+const (
+	String  = "string"  // Text value
+	Number  = "number"  // Floating point value
+	Integer = "integer" // Whole number value
+	Boolean = "boolean" // True or false value
+)
+
+// namePattern restricts attribute names to lowercase identifiers such as shirt_size.
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// Definition describes a custom attribute that people can carry: its type and the constraints on its value.
+type Definition struct {
+	name     string
+	kind     string
+	required bool
+	enum     []string
+	min      *float64
+	max      *float64
+}
+
+// DefinitionConfig is a configuration struct used to create a new Definition.
+type DefinitionConfig struct {
+	Name     string
+	Type     string
+	Required bool
+	Enum     []string // Allowed values; only for string attributes
+	Min      *float64 // Inclusive lower bound; only for number and integer attributes
+	Max      *float64 // Inclusive upper bound; only for number and integer attributes
+}
+
+// CreateDefinition initializes a new Definition based on the provided configuration.
+func CreateDefinition(dc *DefinitionConfig) (*Definition, ierr.IErr) {
+	if !namePattern.MatchString(dc.Name) {
+		return nil, ierr.NewValidation("attribute name should start with a lowercase letter and only contain lowercase letters, digits and underscores")
+	}
+
+	switch dc.Type {
+	case String, Number, Integer, Boolean:
+	default:
+		return nil, ierr.NewValidation(fmt.Sprintf("attribute type should be one of %s, %s, %s, %s", String, Number, Integer, Boolean))
+	}
+
+	if len(dc.Enum) > 0 && dc.Type != String {
+		return nil, ierr.NewValidation("enum is only supported by string attributes")
+	}
+
+	if (dc.Min != nil || dc.Max != nil) && dc.Type != Number && dc.Type != Integer {
+		return nil, ierr.NewValidation("min and max are only supported by number and integer attributes")
+	}
+
+	if dc.Min != nil && dc.Max != nil && *dc.Min > *dc.Max {
+		return nil, ierr.NewValidation("min should be less than or equal to max")
+	}
+
+	return &Definition{
+		name:     dc.Name,
+		kind:     dc.Type,
+		required: dc.Required,
+		enum:     dc.Enum,
+		min:      dc.Min,
+		max:      dc.Max,
+	}, nil
+}
+
+// Validate checks a value against the definition and returns it in its canonical form:
+// string, float64, int64 or bool depending on the type of the attribute.
+func (d *Definition) Validate(value any) (any, ierr.IErr) {
+	switch d.kind {
+	case String:
+		s, ok := value.(string)
+		if !ok {
+			return nil, d.invalid("should be a string")
+		}
+		if len(d.enum) > 0 && !contains(d.enum, s) {
+			return nil, d.invalid(fmt.Sprintf("should be one of %s", strings.Join(d.enum, ", ")))
+		}
+		return s, nil
+
+	case Number, Integer:
+		n, ok := toFloat(value)
+		if !ok {
+			return nil, d.invalid(fmt.Sprintf("should be a %s", d.kind))
+		}
+		if d.kind == Integer && n != math.Trunc(n) {
+			return nil, d.invalid("should be an integer")
+		}
+		if d.min != nil && n < *d.min {
+			return nil, d.invalid(fmt.Sprintf("should be greater than or equal to %v", *d.min))
+		}
+		if d.max != nil && n > *d.max {
+			return nil, d.invalid(fmt.Sprintf("should be less than or equal to %v", *d.max))
+		}
+		if d.kind == Integer {
+			return int64(n), nil
+		}
+		return n, nil
+
+	default:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, d.invalid("should be a boolean")
+		}
+		return b, nil
+	}
+}
+
+// invalid builds the validation error for a value not matching the definition.
+func (d *Definition) invalid(reason string) ierr.IErr {
+	return ierr.NewValidation(fmt.Sprintf("attribute %s %s", d.name, reason))
+}
+
+// toFloat converts the numeric types produced by decoders into a float64.
+func toFloat(value any) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// contains reports whether the values contain the given one.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns the name of the attribute.
+func (d *Definition) Name() string {
+	return d.name
+}
+
+// Type returns the type of the attribute's value.
+func (d *Definition) Type() string {
+	return d.kind
+}
+
+// Required reports whether every person should carry the attribute.
+func (d *Definition) Required() bool {
+	return d.required
+}
+
+// Enum returns the allowed values of a string attribute, or nil when any value is allowed.
+func (d *Definition) Enum() []string {
+	return d.enum
+}
+
+// Min returns the inclusive lower bound of a numeric attribute, or nil when unbounded.
+func (d *Definition) Min() *float64 {
+	return d.min
+}
+
+// Max returns the inclusive upper bound of a numeric attribute, or nil when unbounded.
+func (d *Definition) Max() *float64 {
+	return d.max
+}
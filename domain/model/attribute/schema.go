@@ -0,0 +1,68 @@
+package attribute
+
+import (
+	"fmt"
+	"sort"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+)
+
+// Schema is the set of custom attributes people are allowed to carry.
+type Schema struct {
+	definitions map[string]*Definition
+}
+
+// NewSchema creates a Schema out of the given definitions.
+func NewSchema(definitions []*Definition) *Schema {
+	schema := &Schema{definitions: make(map[string]*Definition, len(definitions))}
+	for _, d := range definitions {
+		schema.definitions[d.Name()] = d
+	}
+	return schema
+}
+
+// Validate checks the attributes against the schema and returns them in their canonical form.
+// Unknown attributes are rejected and every required attribute should be present.
+func (s *Schema) Validate(attributes map[string]any) (map[string]any, ierr.IErr) {
+	validated := make(map[string]any, len(attributes))
+
+	// Go through the names in order so that the reported error does not depend on map iteration.
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		definition, ok := s.definitions[name]
+		if !ok {
+			return nil, ierr.NewValidation(fmt.Sprintf("attribute %s is not defined", name))
+		}
+
+		value, err := definition.Validate(attributes[name])
+		if err != nil {
+			return nil, err
+		}
+		validated[name] = value
+	}
+
+	for _, definition := range s.Definitions() {
+		if _, ok := validated[definition.Name()]; definition.Required() && !ok {
+			return nil, ierr.NewValidation(fmt.Sprintf("attribute %s is required", definition.Name()))
+		}
+	}
+
+	return validated, nil
+}
+
+// Definitions returns the definitions of the schema sorted by name.
+func (s *Schema) Definitions() []*Definition {
+	definitions := make([]*Definition, 0, len(s.definitions))
+	for _, d := range s.definitions {
+		definitions = append(definitions, d)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name() < definitions[j].Name()
+	})
+	return definitions
+}
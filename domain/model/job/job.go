@@ -0,0 +1,238 @@
+package job
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status is the state of a Job.
+type Status string
+
+const (
+	Queued    Status = "queued"    // Waiting for a worker
+	Running   Status = "running"   // Being run by a worker
+	Succeeded Status = "succeeded" // Ran to its end, with a result
+	Failed    Status = "failed"    // Stopped by an error, or interrupted by a shutdown
+	Cancelled Status = "cancelled" // Stopped at the request of a caller
+)
+
+// Statuses lists every Status, in the order a job goes through them.
+var Statuses = []Status{Queued, Running, Succeeded, Failed, Cancelled}
+
+// Progress is how far a running Job got.
+type Progress struct {
+	Done    int    // Number of items processed so far
+	Total   int    // Number of items to process; zero when unknown
+	Message string // What the job is doing, e.g. "deleting people"
+}
+
+// Submitter is the principal a Job runs on behalf of, so that it is authorized as they would be.
+type Submitter struct {
+	Subject     string   // Subject of the principal, who owns the job
+	Tenant      string   // Tenant the job runs in
+	Roles       []string // Roles of the principal
+	Permissions []string // Permissions granted directly to the principal
+}
+
+// Job is a long-running operation of a kind, such as purging people, run in the background by a worker.
+// Its parameters and result are JSON documents, which the task of its kind reads and writes.
+type Job struct {
+	id              uuid.UUID
+	kind            string
+	submitter       Submitter
+	params          []byte
+	status          Status
+	progress        Progress
+	result          []byte
+	reason          string
+	cancelRequested bool
+	createdAt       time.Time
+	startedAt       *time.Time
+	finishedAt      *time.Time
+}
+
+// NewJob creates a queued Job of the kind, with its parameters.
+func NewJob(kind string, submitter Submitter, params []byte) *Job {
+	return &Job{
+		id:        uuid.New(),
+		kind:      kind,
+		submitter: submitter,
+		params:    params,
+		status:    Queued,
+		createdAt: time.Now().UTC(),
+	}
+}
+
+// Snapshot holds every field of a Job, so that repositories can store jobs and restore them.
+type Snapshot struct {
+	ID              uuid.UUID
+	Kind            string
+	Submitter       Submitter
+	Params          []byte
+	Status          Status
+	Progress        Progress
+	Result          []byte
+	Reason          string
+	CancelRequested bool
+	CreatedAt       time.Time
+	StartedAt       *time.Time
+	FinishedAt      *time.Time
+}
+
+// Restore recreates a Job from its Snapshot.
+func Restore(s Snapshot) *Job {
+	return &Job{
+		id:              s.ID,
+		kind:            s.Kind,
+		submitter:       s.Submitter,
+		params:          s.Params,
+		status:          s.Status,
+		progress:        s.Progress,
+		result:          s.Result,
+		reason:          s.Reason,
+		cancelRequested: s.CancelRequested,
+		createdAt:       s.CreatedAt,
+		startedAt:       s.StartedAt,
+		finishedAt:      s.FinishedAt,
+	}
+}
+
+// Snapshot returns every field of the job.
+func (j *Job) Snapshot() Snapshot {
+	return Snapshot{
+		ID:              j.id,
+		Kind:            j.kind,
+		Submitter:       j.submitter,
+		Params:          j.params,
+		Status:          j.status,
+		Progress:        j.progress,
+		Result:          j.result,
+		Reason:          j.reason,
+		CancelRequested: j.cancelRequested,
+		CreatedAt:       j.createdAt,
+		StartedAt:       j.startedAt,
+		FinishedAt:      j.finishedAt,
+	}
+}
+
+// Start marks the job as running, once a worker picks it.
+func (j *Job) Start(at time.Time) {
+	j.status = Running
+	j.startedAt = &at
+}
+
+// Report records the progress of the running job.
+func (j *Job) Report(progress Progress) {
+	j.progress = progress
+}
+
+// Succeed marks the job as succeeded with its result.
+func (j *Job) Succeed(result []byte, at time.Time) {
+	j.status = Succeeded
+	j.result = result
+	j.finish(at)
+}
+
+// Fail marks the job as failed, for the reason.
+func (j *Job) Fail(reason string, at time.Time) {
+	j.status = Failed
+	j.reason = reason
+	j.finish(at)
+}
+
+// RequestCancel records that a caller asked to cancel the running job, which stops once its task notices.
+func (j *Job) RequestCancel() {
+	j.cancelRequested = true
+}
+
+// Cancel marks the job as cancelled.
+func (j *Job) Cancel(at time.Time) {
+	j.status = Cancelled
+	j.cancelRequested = true
+	j.finish(at)
+}
+
+// finish records the time at which the job finished, and drops its parameters, which are only needed to run it.
+func (j *Job) finish(at time.Time) {
+	j.params = nil
+	j.finishedAt = &at
+}
+
+// Finished reports whether the job succeeded, failed or was cancelled, after which it never changes.
+func (j *Job) Finished() bool {
+	return j.status == Succeeded || j.status == Failed || j.status == Cancelled
+}
+
+// Clone returns a copy of the job that can be changed without affecting the original.
+func (j *Job) Clone() *Job {
+	clone := *j
+	clone.submitter.Roles = append([]string(nil), j.submitter.Roles...)
+	clone.submitter.Permissions = append([]string(nil), j.submitter.Permissions...)
+	return &clone
+}
+
+// Id returns the unique identifier of the job.
+func (j *Job) Id() uuid.UUID {
+	return j.id
+}
+
+// Kind returns the kind of the job, naming the task that runs it, e.g. people.purge.
+func (j *Job) Kind() string {
+	return j.kind
+}
+
+// Owner returns the subject of the principal who submitted the job.
+func (j *Job) Owner() string {
+	return j.submitter.Subject
+}
+
+// Submitter returns the principal the job runs on behalf of.
+func (j *Job) Submitter() Submitter {
+	return j.submitter
+}
+
+// Params returns the JSON parameters of the job, or nil when it has none or finished.
+func (j *Job) Params() []byte {
+	return j.params
+}
+
+// Status returns the state of the job.
+func (j *Job) Status() Status {
+	return j.status
+}
+
+// Progress returns how far the job got.
+func (j *Job) Progress() Progress {
+	return j.progress
+}
+
+// Result returns the JSON result of a succeeded job, or nil.
+func (j *Job) Result() []byte {
+	return j.result
+}
+
+// Reason returns why a failed job stopped, or an empty string.
+func (j *Job) Reason() string {
+	return j.reason
+}
+
+// CancelRequested reports whether a caller asked to cancel the job.
+func (j *Job) CancelRequested() bool {
+	return j.cancelRequested
+}
+
+// CreatedAt returns the time at which the job was submitted.
+func (j *Job) CreatedAt() time.Time {
+	return j.createdAt
+}
+
+// StartedAt returns the time at which a worker picked the job, or nil while it is queued.
+func (j *Job) StartedAt() *time.Time {
+	return j.startedAt
+}
+
+// FinishedAt returns the time at which the job finished, or nil until then.
+func (j *Job) FinishedAt() *time.Time {
+	return j.finishedAt
+}
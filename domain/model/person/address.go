@@ -0,0 +1,85 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+)
+
+// Address is a value object holding a validated postal address.
+type Address struct {
+	street     string
+	city       string
+	region     string
+	postalCode string
+	country    string
+}
+
+// AddressConfig is a configuration struct used to create a new Address.
+type AddressConfig struct {
+	Street     string
+	City       string
+	Region     string // Optional
+	PostalCode string // Optional
+	Country    string // ISO 3166-1 alpha-2 country code
+}
+
+// NewAddress validates the configuration and returns it as an Address.
+func NewAddress(ac *AddressConfig) (*Address, ierr.IErr) {
+	address := &Address{
+		street:     strings.TrimSpace(ac.Street),
+		city:       strings.TrimSpace(ac.City),
+		region:     strings.TrimSpace(ac.Region),
+		postalCode: strings.TrimSpace(ac.PostalCode),
+		country:    strings.ToUpper(strings.TrimSpace(ac.Country)),
+	}
+
+	max := 100
+	if address.street == "" || len(address.street) > max {
+		return nil, ierr.NewValidation(fmt.Sprintf("address street length should be between 1 and %d", max))
+	}
+
+	if address.city == "" || len(address.city) > max {
+		return nil, ierr.NewValidation(fmt.Sprintf("address city length should be between 1 and %d", max))
+	}
+
+	if len(address.region) > max {
+		return nil, ierr.NewValidation(fmt.Sprintf("address region should be at most %d characters long", max))
+	}
+
+	if len(address.postalCode) > 16 {
+		return nil, ierr.NewValidation("address postal code should be at most 16 characters long")
+	}
+
+	if len(address.country) != 2 || strings.Trim(address.country, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return nil, ierr.NewValidation("address country should be an ISO 3166-1 alpha-2 code")
+	}
+
+	return address, nil
+}
+
+// Street returns the street line of the address.
+func (a *Address) Street() string {
+	return a.street
+}
+
+// City returns the city of the address.
+func (a *Address) City() string {
+	return a.city
+}
+
+// Region returns the region, state or province of the address.
+func (a *Address) Region() string {
+	return a.region
+}
+
+// PostalCode returns the postal code of the address.
+func (a *Address) PostalCode() string {
+	return a.postalCode
+}
+
+// Country returns the ISO 3166-1 alpha-2 country code of the address.
+func (a *Address) Country() string {
+	return a.country
+}
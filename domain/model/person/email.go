@@ -0,0 +1,39 @@
+package model
+
+import (
+	"net/mail"
+	"strings"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+)
+
+// Email is a value object holding a validated, lowercased email address.
+type Email struct {
+	value string
+}
+
+// NewEmail validates the address and returns it as an Email.
+func NewEmail(address string) (Email, ierr.IErr) {
+	address = strings.TrimSpace(address)
+
+	parsed, err := mail.ParseAddress(address)
+	if err != nil || parsed.Address != address || parsed.Name != "" {
+		return Email{}, ierr.NewValidation("email should be a valid email address")
+	}
+
+	if len(address) > 254 {
+		return Email{}, ierr.NewValidation("email should be at most 254 characters long")
+	}
+
+	return Email{value: strings.ToLower(address)}, nil
+}
+
+// String returns the email address.
+func (e Email) String() string {
+	return e.value
+}
+
+// IsZero reports whether the email is empty.
+func (e Email) IsZero() bool {
+	return e.value == ""
+}
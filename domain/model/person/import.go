@@ -0,0 +1,129 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportStatus is the state of an Import.
+type ImportStatus string
+
+const (
+	ImportRunning   ImportStatus = "running"   // Rows are still being processed
+	ImportCompleted ImportStatus = "completed" // Every row was processed, whether it succeeded or not
+	ImportFailed    ImportStatus = "failed"    // The upload couldn't be read to its end
+)
+
+// ImportRowResult is the outcome of a row of an Import.
+type ImportRowResult struct {
+	Line  int       // Line of the row in the upload, starting at 1
+	ID    uuid.UUID // ID of the person created, or that would be created by a dry run; nil when the row failed
+	Error string    // Why the row failed; empty when it succeeded
+}
+
+// Import records the progress and outcome of a bulk import of people.
+// A dry run validates every row without creating anyone.
+type Import struct {
+	id         uuid.UUID
+	owner      string
+	dryRun     bool
+	status     ImportStatus
+	succeeded  int
+	failed     int
+	rows       []ImportRowResult
+	reason     string
+	createdAt  time.Time
+	finishedAt *time.Time
+}
+
+// NewImport creates a running Import, started by the owner.
+func NewImport(owner string, dryRun bool) *Import {
+	return &Import{
+		id:        uuid.New(),
+		owner:     owner,
+		dryRun:    dryRun,
+		status:    ImportRunning,
+		rows:      make([]ImportRowResult, 0),
+		createdAt: time.Now().UTC(),
+	}
+}
+
+// Record records the outcome of a row.
+func (i *Import) Record(row ImportRowResult) {
+	if row.Error == "" {
+		i.succeeded++
+	} else {
+		i.failed++
+	}
+	i.rows = append(i.rows, row)
+}
+
+// Complete marks the import as completed once every row was processed.
+func (i *Import) Complete(at time.Time) {
+	i.status = ImportCompleted
+	i.finishedAt = &at
+}
+
+// Fail marks the import as failed, when the rest of the upload couldn't be read.
+// The rows processed so far keep their outcome.
+func (i *Import) Fail(reason string, at time.Time) {
+	i.status = ImportFailed
+	i.reason = reason
+	i.finishedAt = &at
+}
+
+// Id returns the unique identifier of the import.
+func (i *Import) Id() uuid.UUID {
+	return i.id
+}
+
+// Owner returns the subject of the principal who started the import.
+func (i *Import) Owner() string {
+	return i.owner
+}
+
+// DryRun reports whether the import only validates its rows.
+func (i *Import) DryRun() bool {
+	return i.dryRun
+}
+
+// Status returns the state of the import.
+func (i *Import) Status() ImportStatus {
+	return i.status
+}
+
+// Processed returns the number of rows processed so far.
+func (i *Import) Processed() int {
+	return len(i.rows)
+}
+
+// Succeeded returns the number of rows that succeeded.
+func (i *Import) Succeeded() int {
+	return i.succeeded
+}
+
+// Failed returns the number of rows that failed.
+func (i *Import) Failed() int {
+	return i.failed
+}
+
+// Rows returns the outcome of every row processed so far, in the order of the upload.
+func (i *Import) Rows() []ImportRowResult {
+	return i.rows
+}
+
+// Reason returns why a failed import couldn't be read to its end, or an empty string.
+func (i *Import) Reason() string {
+	return i.reason
+}
+
+// CreatedAt returns the time at which the import started.
+func (i *Import) CreatedAt() time.Time {
+	return i.createdAt
+}
+
+// FinishedAt returns the time at which the import completed or failed, or nil while it is running.
+func (i *Import) FinishedAt() *time.Time {
+	return i.finishedAt
+}
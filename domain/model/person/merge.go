@@ -0,0 +1,125 @@
+package model
+
+import (
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/google/uuid"
+)
+
+// Merge records the retirement of one or more people into a surviving Person.
+type Merge struct {
+	id         uuid.UUID
+	survivorID uuid.UUID
+	retiredIDs []uuid.UUID
+	mergedAt   time.Time
+}
+
+// MergeConfig is a configuration struct describing how people should be merged.
+type MergeConfig struct {
+	Name string // Name kept by the survivor; the survivor's current name is kept when empty
+	Age  *int16 // Age kept by the survivor; the survivor's current age is kept when nil
+}
+
+// MergePeople folds the retired people into the survivor.
+// The survivor keeps the union of all hobbies and the name and age chosen in the configuration.
+func MergePeople(survivor *Person, retired []*Person, mc *MergeConfig) (*Merge, ierr.IErr) {
+	if survivor == nil {
+		return nil, ierr.NewValidation("survivor can't be empty")
+	}
+
+	if len(retired) == 0 {
+		return nil, ierr.NewValidation("at least one person should be merged into the survivor")
+	}
+
+	retiredIDs := make([]uuid.UUID, 0, len(retired))
+	hobbies := append([]string{}, survivor.Hobbies()...)
+	for _, p := range retired {
+		if p.Id() == survivor.Id() {
+			return nil, ierr.NewValidation("survivor can't be merged into itself")
+		}
+		retiredIDs = append(retiredIDs, p.Id())
+		hobbies = append(hobbies, p.Hobbies()...)
+	}
+
+	if mc.Name != "" {
+		if err := survivor.SetName(mc.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	if mc.Age != nil {
+		if err := survivor.SetAge(*mc.Age); err != nil {
+			return nil, err
+		}
+	}
+
+	survivor.SetHobbies(unique(hobbies))
+
+	// Contact details missing on the survivor are taken from the first retired person that has them.
+	for _, p := range retired {
+		if survivor.email.IsZero() {
+			survivor.email = p.email
+		}
+		if survivor.phone.IsZero() {
+			survivor.phone = p.phone
+		}
+		if survivor.address == nil {
+			survivor.address = p.address
+		}
+	}
+
+	// Custom attributes are unioned, with the survivor's values taking precedence.
+	attributes := make(map[string]any)
+	for i := len(retired) - 1; i >= 0; i-- {
+		for name, value := range retired[i].attributes {
+			attributes[name] = value
+		}
+	}
+	for name, value := range survivor.attributes {
+		attributes[name] = value
+	}
+	survivor.attributes = attributes
+	survivor.Touch()
+
+	return &Merge{
+		id:         uuid.New(),
+		survivorID: survivor.Id(),
+		retiredIDs: retiredIDs,
+		mergedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// unique returns the values in their original order with duplicates removed.
+func unique(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
+
+// Id returns the unique identifier of the merge.
+func (m *Merge) Id() uuid.UUID {
+	return m.id
+}
+
+// SurvivorID returns the identifier of the person that survived the merge.
+func (m *Merge) SurvivorID() uuid.UUID {
+	return m.survivorID
+}
+
+// RetiredIDs returns the identifiers of the people retired by the merge.
+func (m *Merge) RetiredIDs() []uuid.UUID {
+	return m.retiredIDs
+}
+
+// MergedAt returns the time at which the merge happened.
+func (m *Merge) MergedAt() time.Time {
+	return m.mergedAt
+}
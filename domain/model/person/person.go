@@ -2,8 +2,10 @@ package model
 
 import (
 	"fmt"
+	"time"
 
 	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/attribute"
 	"github.com/google/uuid"
 )
 
@@ -13,19 +15,43 @@ type Person struct {
 	name    string
 	age     int16
 	hobbies []string
+	email   Email
+	phone   PhoneNumber
+	address *Address
+
+	attributes map[string]any
+	owner      string
+
+	createdAt time.Time
+	updatedAt time.Time
 }
 
 // PersonConfig is a configuration struct used to create a new Person.
 type PersonConfig struct {
+	ID      uuid.UUID // Optional; a new ID is generated when nil
 	Name    string
 	Age     int16
 	Hobbies []string
+	Email   string         // Optional
+	Phone   string         // Optional, in the E.164 format
+	Address *AddressConfig // Optional
+
+	Attributes map[string]any    // Custom attributes; validated against Schema
+	Schema     *attribute.Schema // Schema of the custom attributes; no attribute is allowed when nil
+
+	Owner string // Subject of the principal owning the person; optional
 }
 
 // CreatePerson initializes a new Person based on the provided configuration.
 func CreatePerson(pc *PersonConfig) (*Person, ierr.IErr) {
+	now := time.Now().UTC()
 	newPerson := &Person{
-		id: uuid.New(),
+		id:        pc.ID,
+		createdAt: now,
+		updatedAt: now,
+	}
+	if newPerson.id == uuid.Nil {
+		newPerson.id = uuid.New()
 	}
 
 	// Validate and set the name.
@@ -43,6 +69,18 @@ func CreatePerson(pc *PersonConfig) (*Person, ierr.IErr) {
 	// Set hobbies for the person.
 	newPerson.SetHobbies(pc.Hobbies)
 
+	// Validate and set the contact details.
+	if err := newPerson.SetContact(pc.Email, pc.Phone, pc.Address); err != nil {
+		return nil, err
+	}
+
+	// Validate and set the custom attributes.
+	if err := newPerson.SetAttributes(pc.Attributes, pc.Schema); err != nil {
+		return nil, err
+	}
+
+	newPerson.owner = pc.Owner
+
 	return newPerson, nil
 }
 
@@ -72,6 +110,119 @@ func (p *Person) SetHobbies(hobbies []string) {
 	p.hobbies = hobbies
 }
 
+// SetEmail sets the email of the person after validating it. An empty email clears it.
+func (p *Person) SetEmail(email string) ierr.IErr {
+	if email == "" {
+		p.email = Email{}
+		return nil
+	}
+
+	e, err := NewEmail(email)
+	if err != nil {
+		return err
+	}
+	p.email = e
+	return nil
+}
+
+// SetPhone sets the phone number of the person after validating it. An empty number clears it.
+func (p *Person) SetPhone(phone string) ierr.IErr {
+	if phone == "" {
+		p.phone = PhoneNumber{}
+		return nil
+	}
+
+	number, err := NewPhoneNumber(phone)
+	if err != nil {
+		return err
+	}
+	p.phone = number
+	return nil
+}
+
+// SetAddress sets the address of the person after validating it. A nil address clears it.
+func (p *Person) SetAddress(ac *AddressConfig) ierr.IErr {
+	if ac == nil {
+		p.address = nil
+		return nil
+	}
+
+	address, err := NewAddress(ac)
+	if err != nil {
+		return err
+	}
+	p.address = address
+	return nil
+}
+
+// SetContact sets the email, phone number and address of the person.
+// The contact details are only changed when all of them are valid.
+func (p *Person) SetContact(email string, phone string, ac *AddressConfig) ierr.IErr {
+	contact := *p
+	if err := contact.SetEmail(email); err != nil {
+		return err
+	}
+	if err := contact.SetPhone(phone); err != nil {
+		return err
+	}
+	if err := contact.SetAddress(ac); err != nil {
+		return err
+	}
+
+	p.email, p.phone, p.address = contact.email, contact.phone, contact.address
+	return nil
+}
+
+// SetAttributes sets the custom attributes of the person after validating them against the schema.
+func (p *Person) SetAttributes(attributes map[string]any, schema *attribute.Schema) ierr.IErr {
+	if schema == nil {
+		schema = attribute.NewSchema(nil)
+	}
+
+	validated, err := schema.Validate(attributes)
+	if err != nil {
+		return err
+	}
+
+	p.attributes = validated
+	return nil
+}
+
+// SetOwner transfers the person to the principal with the given subject.
+func (p *Person) SetOwner(owner string) ierr.IErr {
+	if owner == "" {
+		return ierr.NewValidation("owner can't be empty")
+	}
+
+	p.owner = owner
+	return nil
+}
+
+// Touch records that the person was just changed.
+func (p *Person) Touch() {
+	p.updatedAt = time.Now().UTC()
+}
+
+// Clone returns a copy of the person that can be changed without affecting the original.
+func (p *Person) Clone() *Person {
+	clone := *p
+	if p.hobbies != nil {
+		clone.hobbies = make([]string, len(p.hobbies))
+		copy(clone.hobbies, p.hobbies)
+	}
+	if p.address != nil {
+		address := *p.address
+		clone.address = &address
+	}
+	if p.attributes != nil {
+		clone.attributes = make(map[string]any, len(p.attributes))
+		for name, value := range p.attributes {
+			clone.attributes[name] = value
+		}
+	}
+	return &clone
+}
+
 // Id returns the unique identifier of the person.
 func (p *Person) Id() uuid.UUID {
 	return p.id
@@ -91,3 +242,50 @@ func (p *Person) Age() int16 {
 func (p *Person) Hobbies() []string {
 	return p.hobbies
 }
+
+// Email returns the email of the person; it is the zero Email when not set.
+func (p *Person) Email() Email {
+	return p.email
+}
+
+// Phone returns the phone number of the person; it is the zero PhoneNumber when not set.
+func (p *Person) Phone() PhoneNumber {
+	return p.phone
+}
+
+// Address returns the address of the person, or nil when not set.
+func (p *Person) Address() *Address {
+	return p.address
+}
+
+// Attributes returns the custom attributes of the person.
+func (p *Person) Attributes() map[string]any {
+	return p.attributes
+}
+
+// HasAttributes reports whether the person carries every attribute with the expected value.
+// Values are compared through their textual form, so that 42, true and sales can all be matched.
+func (p *Person) HasAttributes(expected map[string]string) bool {
+	for name, want := range expected {
+		value, ok := p.attributes[name]
+		if !ok || fmt.Sprint(value) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// Owner returns the subject of the principal owning the person, or an empty string.
+func (p *Person) Owner() string {
+	return p.owner
+}
+
+// CreatedAt returns the time the person was created.
+func (p *Person) CreatedAt() time.Time {
+	return p.createdAt
+}
+
+// UpdatedAt returns the time the person was last changed.
+func (p *Person) UpdatedAt() time.Time {
+	return p.updatedAt
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+)
+
+// e164 matches phone numbers in the E.164 format, e.g. +251911234567.
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+// PhoneNumber is a value object holding a phone number in the E.164 format.
+type PhoneNumber struct {
+	value string
+}
+
+// NewPhoneNumber validates the number and returns it as a PhoneNumber.
+// Spaces, dashes, dots and parentheses used to group digits are ignored.
+func NewPhoneNumber(number string) (PhoneNumber, ierr.IErr) {
+	number = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, number)
+
+	if !e164.MatchString(number) {
+		return PhoneNumber{}, ierr.NewValidation("phone should be a valid E.164 phone number, e.g. +251911234567")
+	}
+
+	return PhoneNumber{value: number}, nil
+}
+
+// String returns the phone number in the E.164 format.
+func (p PhoneNumber) String() string {
+	return p.value
+}
+
+// IsZero reports whether the phone number is empty.
+func (p PhoneNumber) IsZero() bool {
+	return p.value == ""
+}
@@ -0,0 +1,46 @@
+package model
+
+import "strings"
+
+// NameSimilarity returns a score between 0 and 1 describing how alike two names are.
+// Names are compared case-insensitively with surrounding and repeated whitespace ignored,
+// and the score is derived from the Levenshtein distance between the normalized forms.
+func NameSimilarity(a, b string) float64 {
+	x := []rune(normalizeName(a))
+	y := []rune(normalizeName(b))
+
+	longest := max(len(x), len(y))
+	if longest == 0 {
+		return 1
+	}
+
+	return 1 - float64(levenshtein(x, y))/float64(longest)
+}
+
+// normalizeName lowercases the name and collapses its whitespace.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// levenshtein computes the edit distance between two rune slices.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
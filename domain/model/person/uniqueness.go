@@ -0,0 +1,43 @@
+package model
+
+import (
+	"fmt"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+)
+
+// UniquenessPolicy decides which people are considered the same record and can't coexist.
+type UniquenessPolicy string
+
+// Supported uniqueness policies.
+const (
+	UniqueNone    UniquenessPolicy = "none"     // People are never considered the same record
+	UniqueName    UniquenessPolicy = "name"     // People with the same case-insensitive name are the same record
+	UniqueNameAge UniquenessPolicy = "name_age" // People with the same case-insensitive name and age are the same record
+)
+
+// ParseUniquenessPolicy converts the textual form of a policy into a UniquenessPolicy.
+// An empty value is treated as UniqueNone.
+func ParseUniquenessPolicy(value string) (UniquenessPolicy, ierr.IErr) {
+	switch policy := UniquenessPolicy(value); policy {
+	case "":
+		return UniqueNone, nil
+	case UniqueNone, UniqueName, UniqueNameAge:
+		return policy, nil
+	default:
+		return "", ierr.NewValidation(fmt.Sprintf("uniqueness policy should be one of %s, %s, %s", UniqueNone, UniqueName, UniqueNameAge))
+	}
+}
+
+// Key returns the value two people share when the policy considers them the same record.
+// The second return value is false when the policy does not constrain the person at all.
+func (u UniquenessPolicy) Key(p *Person) (string, bool) {
+	switch u {
+	case UniqueName:
+		return normalizeName(p.name), true
+	case UniqueNameAge:
+		return fmt.Sprintf("%s|%d", normalizeName(p.name), p.age), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,145 @@
+package relation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/google/uuid"
+)
+
+// Types of relationships that can exist between two people.
+const (
+	Friend       = "friend"       // The people are friends
+	Family       = "family"       // The people are relatives
+	Colleague    = "colleague"    // The people work together
+	Acquaintance = "acquaintance" // The people know each other
+	Parent       = "parent"       // The first person is a parent of the second
+	Manager      = "manager"      // The first person manages the second
+)
+
+var types = []string{Friend, Family, Colleague, Acquaintance, Parent, Manager}
+
+// Relation represents a typed edge between two people.
+// A directed relation only connects the first person to the second, an undirected one connects both ways.
+type Relation struct {
+	id        uuid.UUID
+	from      uuid.UUID
+	to        uuid.UUID
+	kind      string
+	directed  bool
+	createdAt time.Time
+}
+
+// RelationConfig is a configuration struct used to create a new Relation.
+type RelationConfig struct {
+	From     uuid.UUID
+	To       uuid.UUID
+	Type     string
+	Directed bool
+}
+
+// CreateRelation initializes a new Relation based on the provided configuration.
+func CreateRelation(rc *RelationConfig) (*Relation, ierr.IErr) {
+	if rc.From == uuid.Nil || rc.To == uuid.Nil {
+		return nil, ierr.NewValidation("both people of a relation are required")
+	}
+
+	if rc.From == rc.To {
+		return nil, ierr.NewValidation("a person can't be related to themselves")
+	}
+
+	kind := strings.ToLower(strings.TrimSpace(rc.Type))
+	if !isType(kind) {
+		return nil, ierr.NewValidation(fmt.Sprintf("relation type should be one of %s", strings.Join(types, ", ")))
+	}
+
+	return &Relation{
+		id:        uuid.New(),
+		from:      rc.From,
+		to:        rc.To,
+		kind:      kind,
+		directed:  rc.Directed,
+		createdAt: time.Now().UTC(),
+	}, nil
+}
+
+// isType reports whether kind is one of the known relation types.
+func isType(kind string) bool {
+	for _, t := range types {
+		if t == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Id returns the unique identifier of the relation.
+func (r *Relation) Id() uuid.UUID {
+	return r.id
+}
+
+// From returns the ID of the person the relation starts from.
+func (r *Relation) From() uuid.UUID {
+	return r.from
+}
+
+// To returns the ID of the person the relation points to.
+func (r *Relation) To() uuid.UUID {
+	return r.to
+}
+
+// Type returns the type of the relation.
+func (r *Relation) Type() string {
+	return r.kind
+}
+
+// Directed reports whether the relation only connects From to To.
+func (r *Relation) Directed() bool {
+	return r.directed
+}
+
+// CreatedAt returns the time at which the relation was created.
+func (r *Relation) CreatedAt() time.Time {
+	return r.createdAt
+}
+
+// Involves reports whether the person with the given ID is one of the ends of the relation.
+func (r *Relation) Involves(id uuid.UUID) bool {
+	return r.from == id || r.to == id
+}
+
+// Connects reports whether the relation leads from the person with the given ID to another person,
+// returning the ID of that other person.
+func (r *Relation) Connects(id uuid.UUID) (uuid.UUID, bool) {
+	switch {
+	case r.from == id:
+		return r.to, true
+	case r.to == id && !r.directed:
+		return r.from, true
+	default:
+		return uuid.Nil, false
+	}
+}
+
+// SameAs reports whether both relations describe the same edge between the same people.
+func (r *Relation) SameAs(other *Relation) bool {
+	if r.kind != other.kind || r.directed != other.directed {
+		return false
+	}
+	if r.from == other.from && r.to == other.to {
+		return true
+	}
+	return !r.directed && r.from == other.to && r.to == other.from
+}
+
+// Reassign moves the end of the relation held by one person over to another person.
+func (r *Relation) Reassign(from, to uuid.UUID) {
+	if r.from == from {
+		r.from = to
+	}
+	if r.to == from {
+		r.to = to
+	}
+}
@@ -0,0 +1,202 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+)
+
+// maxLookahead bounds the search of the next activation of a Cron, so that expressions that never
+// match, such as "0 0 30 2 *", don't loop forever.
+const maxLookahead = 5 * 366 * 24 * time.Hour
+
+// macros are the shorthands of common expressions.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field describes a field of a cron expression: its name, bounds and the names its values may be given by.
+type field struct {
+	name     string
+	min, max int
+	names    []string // Names of the values from min on, e.g. JAN for 1
+}
+
+// fields are the five fields of a cron expression, in order.
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// Cron is a parsed cron expression, telling when a schedule runs. It is either the five fields
+// "minute hour day-of-month month day-of-week", one of the @hourly, @daily, @weekly, @monthly
+// and @yearly shorthands, or "@every <duration>".
+type Cron struct {
+	spec    string
+	every   time.Duration // Interval of an @every expression; zero otherwise
+	sets    [5]uint64     // Values matched by each field, as bits
+	anyDay  bool          // Whether the day of month field is *, in which case only the day of week restricts days
+	anyWeek bool          // Whether the day of week field is *, in which case only the day of month restricts days
+}
+
+// ParseCron parses a cron expression, returning a Validation error when it is invalid.
+func ParseCron(spec string) (*Cron, ierr.IErr) {
+	spec = strings.TrimSpace(spec)
+	expression := spec
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || every <= 0 {
+			return nil, ierr.NewValidation(fmt.Sprintf("invalid interval in cron expression %q", spec))
+		}
+		return &Cron{spec: spec, every: every}, nil
+	}
+	if macro, ok := macros[strings.ToLower(spec)]; ok {
+		expression = macro
+	}
+
+	parts := strings.Fields(expression)
+	if len(parts) != len(fields) {
+		return nil, ierr.NewValidation(fmt.Sprintf("cron expression %q should have 5 fields", spec))
+	}
+
+	c := &Cron{spec: spec, anyDay: parts[2] == "*", anyWeek: parts[4] == "*"}
+	for i, part := range parts {
+		set, err := fields[i].parse(part)
+		if err != nil {
+			return nil, ierr.NewValidation(fmt.Sprintf("invalid %s in cron expression %q: %s", fields[i].name, spec, err.Error()))
+		}
+		c.sets[i] = set
+	}
+
+	// Sunday is both 0 and 7
+	if c.sets[4]&(1<<7) != 0 {
+		c.sets[4] |= 1
+	}
+	return c, nil
+}
+
+// parse parses a field made of comma-separated values, ranges and steps, e.g. "*/15", "1-5" or "MON,WED".
+func (f field) parse(part string) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(part, ",") {
+		rangePart, stepPart, stepped := strings.Cut(item, "/")
+		step := 1
+		if stepped {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		from, to := f.min, f.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			low, high, _ := strings.Cut(rangePart, "-")
+			var err error
+			if from, err = f.value(low); err != nil {
+				return 0, err
+			}
+			if to, err = f.value(high); err != nil {
+				return 0, err
+			}
+			if from > to {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			value, err := f.value(rangePart)
+			if err != nil {
+				return 0, err
+			}
+			from = value
+			if !stepped {
+				to = value
+			}
+		}
+
+		for v := from; v <= to; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// value parses a single value of the field, given as a number or a name.
+func (f field) value(raw string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(raw, name) {
+			return f.min + i, nil
+		}
+	}
+
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("%q should be between %d and %d", raw, f.min, f.max)
+	}
+	return n, nil
+}
+
+// String returns the expression the Cron was parsed from.
+func (c *Cron) String() string {
+	return c.spec
+}
+
+// Next returns the first activation of the Cron strictly after the given time, in its location,
+// or the zero time when there is none within five years.
+func (c *Cron) Next(after time.Time) time.Time {
+	if c.every > 0 {
+		return after.Add(c.every)
+	}
+
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Add(maxLookahead)
+	for t.Before(limit) {
+		switch {
+		case !c.has(3, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !c.has(1, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !c.has(0, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// matchesDay reports whether the day of the time matches the Cron. As in the classic cron, a day matches
+// either field when both the day of month and the day of week are restricted.
+func (c *Cron) matchesDay(t time.Time) bool {
+	day, weekday := c.has(2, t.Day()), c.has(4, int(t.Weekday()))
+	switch {
+	case c.anyDay && c.anyWeek:
+		return true
+	case c.anyDay:
+		return weekday
+	case c.anyWeek:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// has reports whether the field at the index matches the value.
+func (c *Cron) has(index int, value int) bool {
+	return c.sets[index]&(1<<value) != 0
+}
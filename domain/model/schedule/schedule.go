@@ -0,0 +1,120 @@
+package schedule
+
+import (
+	"time"
+)
+
+// Outcome is how the last run of a Schedule ended.
+type Outcome string
+
+const (
+	Succeeded Outcome = "succeeded" // The task returned without an error
+	Failed    Outcome = "failed"    // The task returned an error, or panicked
+)
+
+// Run is a run of the task of a Schedule.
+type Run struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Outcome    Outcome
+	Message    string // Summary returned by a succeeded task, or the error of a failed one
+}
+
+// Schedule is a maintenance task, such as compacting the job table, run on the activations of a Cron.
+// A disabled schedule, which has no Cron, is listed but never runs.
+type Schedule struct {
+	name     string
+	cron     *Cron
+	running  bool
+	nextRun  time.Time
+	lastRun  *Run
+	runs     int
+	failures int
+	skipped  int
+}
+
+// NewSchedule creates a Schedule of the named task, running on the activations of the Cron,
+// or disabled when the Cron is nil.
+func NewSchedule(name string, cron *Cron) *Schedule {
+	return &Schedule{name: name, cron: cron}
+}
+
+// Plan records the next run of the schedule, once it is computed from the Cron and the jitter.
+func (s *Schedule) Plan(at time.Time) {
+	s.nextRun = at
+}
+
+// Start records that the task started running.
+func (s *Schedule) Start() {
+	s.running = true
+}
+
+// Finish records the last run of the task.
+func (s *Schedule) Finish(run Run) {
+	s.running = false
+	s.lastRun = &run
+	s.runs++
+	if run.Outcome == Failed {
+		s.failures++
+	}
+}
+
+// Skip records an activation skipped because the previous run was still running.
+func (s *Schedule) Skip() {
+	s.skipped++
+}
+
+// Clone returns a copy of the schedule that can be read while the original keeps changing.
+func (s *Schedule) Clone() *Schedule {
+	clone := *s
+	if s.lastRun != nil {
+		run := *s.lastRun
+		clone.lastRun = &run
+	}
+	return &clone
+}
+
+// Name returns the name of the task of the schedule, e.g. jobs.compact.
+func (s *Schedule) Name() string {
+	return s.name
+}
+
+// Cron returns the expression telling when the schedule runs, or nil when it is disabled.
+func (s *Schedule) Cron() *Cron {
+	return s.cron
+}
+
+// Enabled reports whether the schedule runs.
+func (s *Schedule) Enabled() bool {
+	return s.cron != nil
+}
+
+// Running reports whether the task is running.
+func (s *Schedule) Running() bool {
+	return s.running
+}
+
+// NextRun returns the time of the next run, or the zero time when none is planned.
+func (s *Schedule) NextRun() time.Time {
+	return s.nextRun
+}
+
+// LastRun returns the last run of the task, or nil when it never ran.
+func (s *Schedule) LastRun() *Run {
+	return s.lastRun
+}
+
+// Runs returns the number of runs of the task.
+func (s *Schedule) Runs() int {
+	return s.runs
+}
+
+// Failures returns the number of runs of the task that failed.
+func (s *Schedule) Failures() int {
+	return s.failures
+}
+
+// Skipped returns the number of activations skipped because the previous run was still running.
+func (s *Schedule) Skipped() int {
+	return s.skipped
+}
@@ -0,0 +1,81 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/google/uuid"
+)
+
+// RefreshToken is a single-use credential exchanged for a new pair of tokens.
+// Only the hash of its secret is kept.
+type RefreshToken struct {
+	id        uuid.UUID
+	userID    uuid.UUID
+	hash      string
+	createdAt time.Time
+	expiresAt time.Time
+}
+
+// IssueRefreshToken creates a new RefreshToken for the user, valid for the given duration,
+// and returns it together with its secret.
+func IssueRefreshToken(userID uuid.UUID, ttl time.Duration) (*RefreshToken, string, ierr.IErr) {
+	if ttl <= 0 {
+		return nil, "", ierr.NewValidation("refresh token lifetime should be positive")
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, "", ierr.NewUnexpected("failed to generate refresh token")
+	}
+	secret := base64.RawURLEncoding.EncodeToString(random)
+
+	now := time.Now().UTC()
+	return &RefreshToken{
+		id:        uuid.New(),
+		userID:    userID,
+		hash:      HashToken(secret),
+		createdAt: now,
+		expiresAt: now.Add(ttl),
+	}, secret, nil
+}
+
+// HashToken returns the SHA-256 hash of a refresh token secret, as stored by the repository.
+func HashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Expired reports whether the token has expired at the given time.
+func (t *RefreshToken) Expired(at time.Time) bool {
+	return !at.Before(t.expiresAt)
+}
+
+// Id returns the token's ID.
+func (t *RefreshToken) Id() uuid.UUID {
+	return t.id
+}
+
+// UserID returns the ID of the user the token was issued to.
+func (t *RefreshToken) UserID() uuid.UUID {
+	return t.userID
+}
+
+// Hash returns the hash of the token's secret.
+func (t *RefreshToken) Hash() string {
+	return t.hash
+}
+
+// CreatedAt returns the time the token was issued.
+func (t *RefreshToken) CreatedAt() time.Time {
+	return t.createdAt
+}
+
+// ExpiresAt returns the time after which the token is rejected.
+func (t *RefreshToken) ExpiresAt() time.Time {
+	return t.expiresAt
+}
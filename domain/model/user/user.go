@@ -0,0 +1,145 @@
+package user
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// usernamePattern restricts usernames to lowercase letters, digits, dots, dashes and underscores.
+var usernamePattern = regexp.MustCompile(`^[a-z0-9._-]{3,50}$`)
+
+// User is a local account able to log in with a username and password.
+// Only the bcrypt hash of the password is kept.
+type User struct {
+	id           uuid.UUID
+	username     string
+	passwordHash []byte
+	roles        []string
+	tenant       string
+	createdAt    time.Time
+}
+
+// UserConfig is a configuration struct used to create a new User.
+type UserConfig struct {
+	Username string
+	Password string
+	Roles    []string
+	Tenant   string // Tenant the user belongs to; optional
+}
+
+// CreateUser creates a new User, hashing its password.
+func CreateUser(uc *UserConfig) (*User, ierr.IErr) {
+	user := &User{
+		id:        uuid.New(),
+		createdAt: time.Now().UTC(),
+	}
+
+	if err := user.SetUsername(uc.Username); err != nil {
+		return nil, err
+	}
+
+	if err := user.SetPassword(uc.Password); err != nil {
+		return nil, err
+	}
+
+	user.SetRoles(uc.Roles)
+	user.tenant = uc.Tenant
+
+	return user, nil
+}
+
+// SetUsername sets the username after normalizing it to lowercase and validating its format.
+func (u *User) SetUsername(username string) ierr.IErr {
+	username = strings.ToLower(strings.TrimSpace(username))
+	if !usernamePattern.MatchString(username) {
+		return ierr.NewValidation("username should be 3 to 50 lowercase letters, digits, dots, dashes or underscores")
+	}
+
+	u.username = username
+	return nil
+}
+
+// SetPassword hashes the password with bcrypt after validating its length.
+func (u *User) SetPassword(password string) ierr.IErr {
+	min := 8
+	max := 72 // bcrypt ignores anything past 72 bytes
+	if len(password) < min || len(password) > max {
+		return ierr.NewValidation(fmt.Sprintf("password length should be between %d and %d", min, max))
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return ierr.NewUnexpected("failed to hash password")
+	}
+
+	u.passwordHash = hash
+	return nil
+}
+
+// CheckPassword reports whether the password matches the one of the user.
+func (u *User) CheckPassword(password string) bool {
+	return bcrypt.CompareHashAndPassword(u.passwordHash, []byte(password)) == nil
+}
+
+// dummyHash is a bcrypt hash of the default cost, matching no password anyone would send.
+var dummyHash = []byte("$2a$10$PtcGrx7/MaAV..tq9HTngu5uiEBw3sPzUAzgE.kX7CX3UdrHrkOdO")
+
+// CheckNoPassword compares the password against a dummy hash and reports false. Checking the credentials of an
+// unknown username with it takes as long as checking the password of a user, so timing doesn't reveal usernames.
+func CheckNoPassword(password string) bool {
+	bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+	return false
+}
+
+// SetRoles sets the roles granted to the user, ignoring blank and repeated ones.
+func (u *User) SetRoles(roles []string) {
+	u.roles = make([]string, 0, len(roles))
+	seen := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		role = strings.TrimSpace(role)
+		if _, ok := seen[role]; ok || role == "" {
+			continue
+		}
+		seen[role] = struct{}{}
+		u.roles = append(u.roles, role)
+	}
+}
+
+// Clone returns a copy of the user that can be changed without affecting the original.
+func (u *User) Clone() *User {
+	clone := *u
+	clone.passwordHash = append([]byte(nil), u.passwordHash...)
+	clone.roles = append([]string(nil), u.roles...)
+	return &clone
+}
+
+// Id returns the user's ID.
+func (u *User) Id() uuid.UUID {
+	return u.id
+}
+
+// Username returns the user's username.
+func (u *User) Username() string {
+	return u.username
+}
+
+// Roles returns the roles granted to the user.
+func (u *User) Roles() []string {
+	return u.roles
+}
+
+// Tenant returns the tenant the user belongs to, or an empty string when it belongs to none.
+func (u *User) Tenant() string {
+	return u.tenant
+}
+
+// CreatedAt returns the time the user was created.
+func (u *User) CreatedAt() time.Time {
+	return u.createdAt
+}
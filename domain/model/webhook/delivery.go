@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeliveryStatus is the state of a Delivery in the retry queue.
+type DeliveryStatus string
+
+const (
+	Pending      DeliveryStatus = "pending"       // Waiting for its next attempt
+	Succeeded    DeliveryStatus = "succeeded"     // Acknowledged by the receiver with a 2xx status
+	DeadLettered DeliveryStatus = "dead_lettered" // Given up on after its last attempt failed
+)
+
+// Delivery is the notification of an event to a webhook, retried until the receiver acknowledges it.
+type Delivery struct {
+	id            uuid.UUID
+	webhookID     uuid.UUID
+	event         string
+	eventID       uint64
+	payload       []byte
+	status        DeliveryStatus
+	attempts      int
+	statusCode    int
+	lastError     string
+	createdAt     time.Time
+	nextAttemptAt time.Time
+	deliveredAt   *time.Time
+}
+
+// NewDelivery creates a pending Delivery of the event to the webhook, due immediately.
+func NewDelivery(webhookID uuid.UUID, event string, eventID uint64, payload []byte) *Delivery {
+	now := time.Now().UTC()
+	return &Delivery{
+		id:            uuid.New(),
+		webhookID:     webhookID,
+		event:         event,
+		eventID:       eventID,
+		payload:       payload,
+		status:        Pending,
+		createdAt:     now,
+		nextAttemptAt: now,
+	}
+}
+
+// Succeed records an attempt acknowledged by the receiver with the given status code.
+func (d *Delivery) Succeed(statusCode int, at time.Time) {
+	d.attempts++
+	d.statusCode = statusCode
+	d.lastError = ""
+	d.status = Succeeded
+	d.deliveredAt = &at
+}
+
+// Fail records a failed attempt, with the status code of the response or zero when there was none.
+// The delivery is retried after an exponential backoff, starting at the given one and capped at
+// maxBackoff, until maxAttempts attempts failed and it is dead-lettered.
+func (d *Delivery) Fail(statusCode int, reason string, at time.Time, maxAttempts int, backoff time.Duration, maxBackoff time.Duration) {
+	d.attempts++
+	d.statusCode = statusCode
+	d.lastError = reason
+	if d.attempts >= maxAttempts {
+		d.status = DeadLettered
+		return
+	}
+
+	delay := backoff
+	for i := 1; i < d.attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	d.nextAttemptAt = at.Add(min(delay, maxBackoff))
+}
+
+// DeadLetter gives up on the delivery without another attempt, e.g. when its webhook was deleted.
+func (d *Delivery) DeadLetter(reason string) {
+	d.lastError = reason
+	d.status = DeadLettered
+}
+
+// Due reports whether the delivery is waiting for an attempt at the given time.
+func (d *Delivery) Due(at time.Time) bool {
+	return d.status == Pending && !at.Before(d.nextAttemptAt)
+}
+
+// Clone returns a copy of the delivery that can be changed without affecting the original.
+func (d *Delivery) Clone() *Delivery {
+	clone := *d
+	return &clone
+}
+
+// Id returns the unique identifier of the delivery.
+func (d *Delivery) Id() uuid.UUID {
+	return d.id
+}
+
+// WebhookID returns the ID of the webhook the event is delivered to.
+func (d *Delivery) WebhookID() uuid.UUID {
+	return d.webhookID
+}
+
+// Event returns the type of the delivered event.
+func (d *Delivery) Event() string {
+	return d.event
+}
+
+// EventID returns the sequence number of the delivered event.
+func (d *Delivery) EventID() uint64 {
+	return d.eventID
+}
+
+// Payload returns the body posted to the webhook.
+func (d *Delivery) Payload() []byte {
+	return d.payload
+}
+
+// Status returns the state of the delivery in the retry queue.
+func (d *Delivery) Status() DeliveryStatus {
+	return d.status
+}
+
+// Attempts returns the number of times the delivery was attempted.
+func (d *Delivery) Attempts() int {
+	return d.attempts
+}
+
+// StatusCode returns the status code of the response to the last attempt, or zero when there was none.
+func (d *Delivery) StatusCode() int {
+	return d.statusCode
+}
+
+// LastError returns the reason the last attempt failed, or an empty string.
+func (d *Delivery) LastError() string {
+	return d.lastError
+}
+
+// CreatedAt returns the time at which the delivery was queued.
+func (d *Delivery) CreatedAt() time.Time {
+	return d.createdAt
+}
+
+// NextAttemptAt returns the time of the next attempt of a pending delivery.
+func (d *Delivery) NextAttemptAt() time.Time {
+	return d.nextAttemptAt
+}
+
+// DeliveredAt returns the time at which the receiver acknowledged the delivery, or nil when it didn't.
+func (d *Delivery) DeliveredAt() *time.Time {
+	return d.deliveredAt
+}
@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/google/uuid"
+)
+
+// secretPrefix starts every generated secret, making leaked secrets easy to recognise.
+const secretPrefix = "whsec_"
+
+// Webhook is a subscription of a partner system to the changes of people, delivered to its URL.
+// Unlike API keys, the secret is kept as is, since every delivery is signed with it.
+type Webhook struct {
+	id        uuid.UUID
+	url       string
+	events    []string
+	secret    string
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// WebhookConfig is a configuration struct used to create a new Webhook.
+type WebhookConfig struct {
+	URL    string
+	Events []string
+	Secret string // Optional; a random secret is generated when empty
+}
+
+// CreateWebhook creates a new Webhook and returns it together with its secret.
+func CreateWebhook(wc *WebhookConfig) (*Webhook, string, ierr.IErr) {
+	now := time.Now().UTC()
+	hook := &Webhook{
+		id:        uuid.New(),
+		createdAt: now,
+		updatedAt: now,
+	}
+
+	if err := hook.SetURL(wc.URL); err != nil {
+		return nil, "", err
+	}
+
+	if err := hook.SetEvents(wc.Events); err != nil {
+		return nil, "", err
+	}
+
+	secret := wc.Secret
+	if secret == "" {
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return nil, "", ierr.NewUnexpected("failed to generate webhook secret")
+		}
+		secret = secretPrefix + base64.RawURLEncoding.EncodeToString(random)
+	}
+
+	if len(secret) < 16 {
+		return nil, "", ierr.NewValidation("webhook secret should be at least 16 characters long")
+	}
+	hook.secret = secret
+
+	return hook, secret, nil
+}
+
+// Sign returns the signature of a delivery sent at the given time, as the hex-encoded HMAC-SHA256 of
+// the Unix timestamp and the body joined by a dot, keyed with the secret of the webhook.
+// Receivers recompute it to check that a delivery comes from this API and wasn't replayed later.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SetURL sets the URL deliveries are posted to, which should be an absolute http or https URL.
+func (w *Webhook) SetURL(raw string) ierr.IErr {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ierr.NewValidation("url should be an absolute http or https URL")
+	}
+
+	max := 2048
+	if len(raw) > max {
+		return ierr.NewValidation(fmt.Sprintf("url should be at most %d characters long", max))
+	}
+
+	w.url = raw
+	w.updatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetEvents sets the types of the events delivered to the webhook; a webhook should have at least one.
+func (w *Webhook) SetEvents(events []string) ierr.IErr {
+	if len(events) == 0 {
+		return ierr.NewValidation("webhook should subscribe to at least one event type")
+	}
+
+	w.events = events
+	w.updatedAt = time.Now().UTC()
+	return nil
+}
+
+// Subscribes reports whether events of the given type are delivered to the webhook.
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, event := range w.events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Clone returns a copy of the webhook that can be changed without affecting the original.
+func (w *Webhook) Clone() *Webhook {
+	clone := *w
+	clone.events = append([]string(nil), w.events...)
+	return &clone
+}
+
+// Id returns the unique identifier of the webhook.
+func (w *Webhook) Id() uuid.UUID {
+	return w.id
+}
+
+// URL returns the URL deliveries are posted to.
+func (w *Webhook) URL() string {
+	return w.url
+}
+
+// Events returns the types of the events delivered to the webhook.
+func (w *Webhook) Events() []string {
+	return w.events
+}
+
+// Secret returns the secret signing the deliveries of the webhook.
+func (w *Webhook) Secret() string {
+	return w.secret
+}
+
+// CreatedAt returns the time at which the webhook was created.
+func (w *Webhook) CreatedAt() time.Time {
+	return w.createdAt
+}
+
+// UpdatedAt returns the time at which the webhook was last changed.
+func (w *Webhook) UpdatedAt() time.Time {
+	return w.updatedAt
+}
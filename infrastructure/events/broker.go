@@ -0,0 +1,91 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
+)
+
+// subscriberBuffer is the number of events a subscriber may lag behind before it is dropped.
+const subscriberBuffer = 64
+
+// Broker is an in-memory broker fanning the changes of people out to the subscribers of this process.
+// It numbers the events and holds the latest ones, so that subscribers can resume after the last event they got.
+type Broker struct {
+	mutex       sync.Mutex
+	subscribers map[chan ievents.PersonEvent]struct{}
+	replay      []ievents.PersonEvent // Latest events, oldest first
+	size        int                   // Maximum number of events held for replay
+	lastID      uint64
+}
+
+// Compile-time check to ensure Broker implements IBroker.
+var _ ievents.IBroker = &Broker{}
+
+// NewBroker creates and returns a new instance of Broker, holding the given number of events for replay.
+func NewBroker(replay int) *Broker {
+	return &Broker{subscribers: make(map[chan ievents.PersonEvent]struct{}), size: max(replay, 0)}
+}
+
+// Publish numbers the event and sends it to every subscriber. Subscribers whose buffer is full are
+// dropped, rather than slowing down the command that published the event.
+func (b *Broker) Publish(_ context.Context, event ievents.PersonEvent) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+	if b.size > 0 {
+		if len(b.replay) == b.size {
+			b.replay = append(b.replay[:0], b.replay[1:]...)
+		}
+		b.replay = append(b.replay, event)
+	}
+
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			b.unsubscribe(subscriber)
+		}
+	}
+}
+
+// Subscribe registers a subscriber until the context is done, after queueing the events it missed.
+func (b *Broker) Subscribe(ctx context.Context, after uint64) <-chan ievents.PersonEvent {
+	b.mutex.Lock()
+	var missed []ievents.PersonEvent
+	if after > 0 {
+		for i, event := range b.replay {
+			if event.ID > after {
+				missed = b.replay[i:]
+				break
+			}
+		}
+	}
+
+	subscriber := make(chan ievents.PersonEvent, subscriberBuffer+len(missed))
+	for _, event := range missed {
+		subscriber <- event
+	}
+	b.subscribers[subscriber] = struct{}{}
+	b.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		b.unsubscribe(subscriber)
+	}()
+	return subscriber
+}
+
+// unsubscribe removes the subscriber and closes its channel, unless it was already removed.
+// The caller must hold the mutex.
+func (b *Broker) unsubscribe(subscriber chan ievents.PersonEvent) {
+	if _, ok := b.subscribers[subscriber]; ok {
+		delete(b.subscribers, subscriber)
+		close(subscriber)
+	}
+}
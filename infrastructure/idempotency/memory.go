@@ -0,0 +1,80 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	iidempotency "github.com/Efamamo/GoCrudChallange/application/common/interface/idempotency"
+)
+
+// sweepInterval is how often expired records are dropped.
+const sweepInterval = time.Minute
+
+// MemoryStore is an in-memory store of the responses of idempotent requests.
+type MemoryStore struct {
+	mutex   sync.Mutex
+	records map[string]*iidempotency.Record
+	swept   time.Time
+}
+
+// Compile-time check to ensure MemoryStore implements IStore.
+var _ iidempotency.IStore = &MemoryStore{}
+
+// NewMemoryStore creates and returns a new instance of MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]*iidempotency.Record), swept: time.Now()}
+}
+
+// Begin reserves the key, or returns a copy of the record already stored under it.
+func (s *MemoryStore) Begin(_ context.Context, key string, fingerprint string, ttl time.Duration) (*iidempotency.Record, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	if record, ok := s.records[key]; ok && now.Before(record.ExpiresAt) {
+		clone := *record
+		return &clone, nil
+	}
+
+	s.records[key] = &iidempotency.Record{Fingerprint: fingerprint, ExpiresAt: now.Add(ttl)}
+	return nil, nil
+}
+
+// Complete stores the response under the reserved key.
+func (s *MemoryStore) Complete(_ context.Context, key string, response *iidempotency.Response) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record, ok := s.records[key]
+	if !ok {
+		return errors.New("idempotency key is not reserved")
+	}
+	record.Response = response
+	return nil
+}
+
+// Release removes the key.
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+// sweep drops the records that expired.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < sweepInterval {
+		return
+	}
+	for key, record := range s.records {
+		if !now.Before(record.ExpiresAt) {
+			delete(s.records, key)
+		}
+	}
+	s.swept = now
+}
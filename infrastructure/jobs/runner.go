@@ -0,0 +1,361 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	ijobs "github.com/Efamamo/GoCrudChallange/application/common/interface/jobs"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
+	"github.com/google/uuid"
+)
+
+// Defaults of the RunnerConfig.
+const (
+	defaultWorkers        = 4
+	defaultReportInterval = time.Second
+)
+
+// Reasons of the jobs that failed without their task failing.
+const (
+	reasonRestart  = "interrupted by a restart"
+	reasonShutdown = "interrupted by a shutdown"
+)
+
+// RunnerConfig holds configuration settings for creating a new Runner.
+type RunnerConfig struct {
+	Jobs           irepo.IJob
+	Workers        int           // Number of jobs run at the same time; 4 when zero
+	ReportInterval time.Duration // Minimum time between two saves of the progress of a job; a second when zero
+}
+
+// Runner runs the jobs submitted to it in the background, with a bounded pool of workers picking the queued
+// jobs in the order they were submitted. Every change of a job is saved to the repository, which is read
+// back when the runner starts, so that queued jobs survive restarts.
+type Runner struct {
+	config  RunnerConfig
+	mutex   sync.Mutex
+	tasks   map[string]ijobs.ITask
+	queue   []uuid.UUID              // IDs of the queued jobs, oldest first
+	running map[uuid.UUID]*execution // Jobs being run by a worker
+	wake    chan struct{}            // Signals that jobs were queued, to an idle worker
+	workers sync.WaitGroup
+}
+
+// execution is a job being run by a worker.
+type execution struct {
+	job      *job.Job           // Current state of the job, saved to the repository along the way
+	ctx      context.Context    // Context the task runs with
+	cancel   context.CancelFunc // Cancels the context of the task
+	reported time.Time          // Time at which the progress was last saved
+}
+
+// Compile-time check to ensure Runner implements IRunner.
+var _ ijobs.IRunner = &Runner{}
+
+// NewRunner creates and returns a new instance of Runner, filling in the defaults of the configuration.
+func NewRunner(config RunnerConfig) *Runner {
+	if config.Workers <= 0 {
+		config.Workers = defaultWorkers
+	}
+	if config.ReportInterval <= 0 {
+		config.ReportInterval = defaultReportInterval
+	}
+	return &Runner{
+		config:  config,
+		tasks:   make(map[string]ijobs.ITask),
+		running: make(map[uuid.UUID]*execution),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Register registers the task running the jobs of the kind, e.g. people.purge, replacing any previous one.
+// Tasks should be registered before the runner starts.
+func (r *Runner) Register(kind string, task ijobs.ITask) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.tasks[kind] = task
+}
+
+// Kinds lists the kinds of jobs the runner has a task for, in alphabetical order.
+func (r *Runner) Kinds() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	kinds := make([]string, 0, len(r.tasks))
+	for kind := range r.tasks {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Start queues the jobs left queued by a previous run, fails the ones it left running, and starts the
+// workers, which run the queued jobs in the background until the context is done.
+func (r *Runner) Start(ctx context.Context) error {
+	jobs, err := r.config.Jobs.GetAll()
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	for _, j := range jobs {
+		switch j.Status() {
+		case job.Queued:
+			r.queue = append(r.queue, j.Id())
+		case job.Running:
+			params := j.Params()
+			j.Fail(reasonRestart, time.Now().UTC())
+			if err := r.config.Jobs.Save(j); err != nil {
+				log.Printf("jobs: failed to save job %s: %s", j.Id(), err.Error())
+			}
+			r.release(j, params)
+		}
+	}
+	r.mutex.Unlock()
+
+	for range r.config.Workers {
+		r.workers.Add(1)
+		go r.work(ctx)
+	}
+	r.signal()
+	return nil
+}
+
+// Wait blocks until every worker stopped, once the context the runner was started with is done.
+// The jobs that were running are failed as interrupted by a shutdown, and the queued ones stay queued.
+func (r *Runner) Wait() {
+	r.workers.Wait()
+}
+
+// Submit queues a job of the kind, run on behalf of the principal and in the tenant carried by the context.
+func (r *Runner) Submit(ctx context.Context, kind string, params []byte) (*job.Job, ierr.IErr) {
+	r.mutex.Lock()
+	task, ok := r.tasks[kind]
+	r.mutex.Unlock()
+	if !ok {
+		return nil, ierr.NewValidation(fmt.Sprintf("kind should be one of %s", strings.Join(r.Kinds(), ", ")))
+	}
+	if err := task.Validate(ctx, params); err != nil {
+		return nil, ierr.NewValidation(err.Error())
+	}
+
+	j := job.NewJob(kind, submitterOf(ctx), params)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.config.Jobs.Save(j); err != nil {
+		return nil, err
+	}
+	r.queue = append(r.queue, j.Id())
+	r.signal()
+	return j, nil
+}
+
+// Cancel cancels a queued job right away, or cancels the context of a running job, which is cancelled
+// once its task returns.
+func (r *Runner) Cancel(id uuid.UUID) (*job.Job, ierr.IErr) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if exec, ok := r.running[id]; ok {
+		exec.job.RequestCancel()
+		exec.cancel()
+		r.save(exec.job)
+		return exec.job.Clone(), nil
+	}
+
+	j, err := r.config.Jobs.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if j.Finished() {
+		return nil, ierr.NewConflict(fmt.Sprintf("job already %s", j.Status()))
+	}
+
+	params := j.Params()
+	j.Cancel(time.Now().UTC())
+	if err := r.config.Jobs.Save(j); err != nil {
+		return nil, err
+	}
+	r.release(j, params)
+	for i, queued := range r.queue {
+		if queued == id {
+			r.queue = append(r.queue[:i], r.queue[i+1:]...)
+			break
+		}
+	}
+	return j, nil
+}
+
+// work runs the queued jobs one at a time until the context is done.
+func (r *Runner) work(ctx context.Context) {
+	defer r.workers.Done()
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+		if exec, task := r.next(ctx); exec != nil {
+			r.run(ctx, exec, task)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		}
+	}
+}
+
+// next starts the oldest queued job, skipping the ones that are no longer queued, and returns it with its task.
+// It returns nil when no job is queued.
+func (r *Runner) next(ctx context.Context) (*execution, ijobs.ITask) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for len(r.queue) > 0 {
+		id := r.queue[0]
+		r.queue = r.queue[1:]
+
+		j, err := r.config.Jobs.Get(id)
+		if err != nil || j.Status() != job.Queued {
+			continue
+		}
+		task, ok := r.tasks[j.Kind()]
+		if !ok {
+			j.Fail(fmt.Sprintf("no task runs jobs of kind %s", j.Kind()), time.Now().UTC())
+			r.save(j)
+			continue
+		}
+
+		j.Start(time.Now().UTC())
+		r.save(j)
+		exec := &execution{job: j}
+		exec.ctx, exec.cancel = context.WithCancel(onBehalfOf(ctx, j.Submitter()))
+		r.running[id] = exec
+
+		// Let another idle worker pick the next queued job
+		if len(r.queue) > 0 {
+			r.signal()
+		}
+		return exec, task
+	}
+	return nil, nil
+}
+
+// run runs the task of a started job, and records how it finished.
+func (r *Runner) run(ctx context.Context, exec *execution, task ijobs.ITask) {
+	defer exec.cancel()
+
+	report := func(progress job.Progress) {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		if exec.job.Finished() {
+			return
+		}
+		exec.job.Report(progress)
+		if now := time.Now(); now.Sub(exec.reported) >= r.config.ReportInterval {
+			exec.reported = now
+			r.save(exec.job)
+		}
+	}
+	result, err := call(exec.ctx, task, exec.job.Params(), report)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.running, exec.job.Id())
+	params := exec.job.Params()
+	now := time.Now().UTC()
+	switch {
+	case exec.job.CancelRequested():
+		exec.job.Cancel(now)
+	case ctx.Err() != nil:
+		exec.job.Fail(reasonShutdown, now)
+	case err != nil:
+		exec.job.Fail(err.Error(), now)
+	default:
+		raw, err := json.Marshal(result)
+		if err != nil {
+			exec.job.Fail(fmt.Sprintf("failed to encode the result: %s", err.Error()), now)
+			break
+		}
+		exec.job.Succeed(raw, now)
+	}
+	r.save(exec.job)
+	r.release(exec.job, params)
+}
+
+// release lets the task of a finished job release what it held for the job, given the parameters the job had.
+func (r *Runner) release(j *job.Job, params []byte) {
+	if cleaner, ok := r.tasks[j.Kind()].(ijobs.ICleaner); ok {
+		cleaner.Cleanup(onBehalfOf(context.Background(), j.Submitter()), params)
+	}
+}
+
+// save saves the job, logging the failures since workers have nobody to report them to.
+func (r *Runner) save(j *job.Job) {
+	if err := r.config.Jobs.Save(j); err != nil {
+		log.Printf("jobs: failed to save job %s: %s", j.Id(), err.Error())
+	}
+}
+
+// signal wakes an idle worker up, unless one is already being woken up.
+func (r *Runner) signal() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// call runs the task, turning a panic into an error so that a faulty task doesn't take the service down.
+func call(ctx context.Context, task ijobs.ITask, params []byte, report ijobs.Report) (result any, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("task panicked: %v", recovered)
+		}
+	}()
+
+	return task.Run(ctx, params, report)
+}
+
+// submitterOf returns the principal and the tenant carried by the context, on whose behalf a job runs.
+func submitterOf(ctx context.Context) job.Submitter {
+	submitter := job.Submitter{Tenant: tenant.From(ctx)}
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		submitter.Subject = principal.Subject
+		submitter.Roles = append([]string(nil), principal.Roles...)
+		for _, permission := range principal.Permissions {
+			submitter.Permissions = append(submitter.Permissions, string(permission))
+		}
+	}
+	return submitter
+}
+
+// onBehalfOf returns a copy of the context carrying the tenant and the principal of the submitter,
+// so that the task is authorized as the request that submitted the job was.
+func onBehalfOf(ctx context.Context, submitter job.Submitter) context.Context {
+	ctx = tenant.WithTenant(ctx, submitter.Tenant)
+	if submitter.Subject == "" && len(submitter.Roles) == 0 && len(submitter.Permissions) == 0 {
+		return ctx
+	}
+
+	principal := &auth.Principal{Subject: submitter.Subject, Roles: submitter.Roles}
+	for _, permission := range submitter.Permissions {
+		principal.Permissions = append(principal.Permissions, auth.Permission(permission))
+	}
+	return auth.WithPrincipal(ctx, principal)
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	iratelimit "github.com/Efamamo/GoCrudChallange/application/common/interface/ratelimit"
+)
+
+// sweepInterval is how often buckets that refilled completely are dropped.
+const sweepInterval = time.Minute
+
+// bucket is the token bucket of one key.
+type bucket struct {
+	tokens  float64   // Tokens left at the last update
+	updated time.Time // Time of the last update
+	full    time.Time // Time at which the bucket is full again, and can be dropped
+}
+
+// MemoryStore is an in-memory store of token buckets, limiting the requests reaching a single instance.
+type MemoryStore struct {
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+// Compile-time check to ensure MemoryStore implements IStore.
+var _ iratelimit.IStore = &MemoryStore{}
+
+// NewMemoryStore creates and returns a new instance of MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), swept: time.Now()}
+}
+
+// Take takes a token from the bucket of the key, after refilling it for the time elapsed since the last request.
+func (s *MemoryStore) Take(_ context.Context, key string, rate iratelimit.Rate) (iratelimit.Result, error) {
+	if rate.Limit <= 0 || rate.Period <= 0 {
+		return iratelimit.Result{}, errors.New("rate limit must allow at least one request per period")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	capacity := float64(rate.Capacity())
+	perToken := rate.Period / time.Duration(rate.Limit)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(capacity, b.tokens+float64(now.Sub(b.updated))/float64(perToken))
+	b.updated = now
+
+	result := iratelimit.Result{Limit: rate.Capacity()}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(perToken))
+	}
+	result.Remaining = int(b.tokens)
+	result.ResetAfter = time.Duration((capacity - b.tokens) * float64(perToken))
+	b.full = now.Add(result.ResetAfter)
+
+	return result, nil
+}
+
+// sweep drops the buckets that refilled completely, as they are equivalent to new ones.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < sweepInterval {
+		return
+	}
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	s.swept = now
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	apperror "github.com/Efamamo/GoCrudChallange/application/error"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/apikey"
+	"github.com/google/uuid"
+)
+
+// APIKeyRepo is an in-memory repository for managing API keys.
+type APIKeyRepo struct {
+	mutex  sync.RWMutex
+	keys   map[uuid.UUID]*apikey.APIKey
+	hashes map[string]uuid.UUID // Secondary index of secret hashes mapped to key IDs
+}
+
+// NewAPIKeyRepo creates and returns a new instance of APIKeyRepo.
+func NewAPIKeyRepo() *APIKeyRepo {
+	return &APIKeyRepo{
+		keys:   make(map[uuid.UUID]*apikey.APIKey),
+		hashes: make(map[string]uuid.UUID),
+	}
+}
+
+// Save saves an APIKey to the repository.
+func (r *APIKeyRepo) Save(key *apikey.APIKey) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if key == nil {
+		return ierr.NewValidation("api key can't be empty")
+	}
+
+	if holder, ok := r.hashes[key.Hash()]; ok && holder != key.Id() {
+		return ierr.NewConflict("api key already exists")
+	}
+
+	r.keys[key.Id()] = key.Clone()
+	r.hashes[key.Hash()] = key.Id()
+	return nil
+}
+
+// Get retrieves an APIKey by its ID from the repository.
+func (r *APIKeyRepo) Get(id uuid.UUID) (*apikey.APIKey, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, ierr.NewNotFound("api key not found")
+	}
+	return key.Clone(), nil
+}
+
+// GetByHash retrieves an APIKey by the hash of its secret.
+func (r *APIKeyRepo) GetByHash(hash string) (*apikey.APIKey, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	id, ok := r.hashes[hash]
+	if !ok {
+		return nil, ierr.NewNotFound("api key not found")
+	}
+	return r.keys[id].Clone(), nil
+}
+
+// Touch records the last use of an APIKey by its ID, under the lock, without saving the rest of the key.
+// It fails with an invalid credential when the key is no longer usable, e.g. revoked since it was read.
+func (r *APIKeyRepo) Touch(id uuid.UUID, at time.Time) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return ierr.NewNotFound("api key not found")
+	}
+	if !key.Usable(at) {
+		return apperror.InvalidCredential("invalid api key")
+	}
+	key.MarkUsed(at)
+	return nil
+}
+
+// Delete removes an APIKey from the repository by its ID.
+func (r *APIKeyRepo) Delete(id uuid.UUID) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return ierr.NewNotFound("api key not found")
+	}
+
+	delete(r.hashes, key.Hash())
+	delete(r.keys, id)
+	return nil
+}
+
+// GetAll retrieves every APIKey from the repository, oldest first.
+func (r *APIKeyRepo) GetAll() ([]*apikey.APIKey, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	keys := make([]*apikey.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key.Clone())
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt().Before(keys[j].CreatedAt())
+	})
+	return keys, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/attribute"
+)
+
+// AttributeRepo is an in-memory repository for managing the custom attribute schema.
+type AttributeRepo struct {
+	mutex       sync.RWMutex
+	definitions map[string]*attribute.Definition
+}
+
+// NewAttributeRepo creates and returns a new instance of AttributeRepo.
+func NewAttributeRepo() *AttributeRepo {
+	return &AttributeRepo{
+		definitions: make(map[string]*attribute.Definition),
+	}
+}
+
+// Save saves an attribute Definition to the repository.
+func (r *AttributeRepo) Save(definition *attribute.Definition) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if definition == nil {
+		return ierr.NewValidation("attribute can't be empty")
+	}
+
+	r.definitions[definition.Name()] = definition
+	return nil
+}
+
+// Get retrieves an attribute Definition by its name from the repository.
+func (r *AttributeRepo) Get(name string) (*attribute.Definition, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	definition, ok := r.definitions[name]
+	if !ok {
+		return nil, ierr.NewNotFound("attribute not found")
+	}
+	return definition, nil
+}
+
+// Delete removes an attribute Definition from the repository by its name.
+func (r *AttributeRepo) Delete(name string) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.definitions[name]; !ok {
+		return ierr.NewNotFound("attribute not found")
+	}
+
+	delete(r.definitions, name)
+	return nil
+}
+
+// GetAll retrieves every attribute Definition from the repository, sorted by name.
+func (r *AttributeRepo) GetAll() ([]*attribute.Definition, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	definitions := make([]*attribute.Definition, 0, len(r.definitions))
+	for _, d := range r.definitions {
+		definitions = append(definitions, d)
+	}
+
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name() < definitions[j].Name()
+	})
+	return definitions, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/webhook"
+	"github.com/google/uuid"
+)
+
+// DeliveryRepo is an in-memory repository holding the deliveries of webhooks.
+type DeliveryRepo struct {
+	mutex      sync.RWMutex
+	deliveries map[uuid.UUID]*webhook.Delivery
+}
+
+// NewDeliveryRepo creates and returns a new instance of DeliveryRepo.
+func NewDeliveryRepo() *DeliveryRepo {
+	return &DeliveryRepo{deliveries: make(map[uuid.UUID]*webhook.Delivery)}
+}
+
+// Save saves a Delivery to the repository.
+func (r *DeliveryRepo) Save(delivery *webhook.Delivery) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if delivery == nil {
+		return ierr.NewValidation("delivery can't be empty")
+	}
+
+	r.deliveries[delivery.Id()] = delivery.Clone()
+	return nil
+}
+
+// GetByWebhook retrieves the deliveries of a webhook, newest first.
+func (r *DeliveryRepo) GetByWebhook(webhookID uuid.UUID) ([]*webhook.Delivery, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var deliveries []*webhook.Delivery
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookID() == webhookID {
+			deliveries = append(deliveries, delivery.Clone())
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt().After(deliveries[j].CreatedAt())
+	})
+	return deliveries, nil
+}
+
+// GetDue retrieves the pending deliveries due for an attempt at the given time, oldest first.
+func (r *DeliveryRepo) GetDue(at time.Time) ([]*webhook.Delivery, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var deliveries []*webhook.Delivery
+	for _, delivery := range r.deliveries {
+		if delivery.Due(at) {
+			deliveries = append(deliveries, delivery.Clone())
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt().Before(deliveries[j].CreatedAt())
+	})
+	return deliveries, nil
+}
+
+// DeleteByWebhook removes every delivery of a webhook.
+func (r *DeliveryRepo) DeleteByWebhook(webhookID uuid.UUID) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for id, delivery := range r.deliveries {
+		if delivery.WebhookID() == webhookID {
+			delete(r.deliveries, id)
+		}
+	}
+	return nil
+}
+
+// DeleteFinishedBefore removes the succeeded and dead-lettered deliveries queued before the given time.
+func (r *DeliveryRepo) DeleteFinishedBefore(at time.Time) (int, ierr.IErr) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	deleted := 0
+	for id, delivery := range r.deliveries {
+		if delivery.Status() != webhook.Pending && delivery.CreatedAt().Before(at) {
+			delete(r.deliveries, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
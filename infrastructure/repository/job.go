@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
+	"github.com/google/uuid"
+)
+
+// compactThreshold is the number of stale records the log of a JobRepo holds before it is rewritten.
+const compactThreshold = 1000
+
+// JobRepo is a repository of background jobs held in memory and, when it has a path, persisted to a log of
+// JSON records a line each, so that the job table survives restarts. Every save appends the record of a single
+// job, and the log is rewritten with the latest record of every job once it holds more stale records than jobs,
+// or when jobs are deleted.
+type JobRepo struct {
+	mutex sync.RWMutex
+	path  string                 // File the jobs are persisted to; not persisted when empty
+	log   *os.File               // The file, open for appending; nil when not persisted
+	stale int                    // Records of the log superseded by a later record of their job, or of deleted jobs
+	jobs  map[uuid.UUID]*job.Job // Jobs mapped to their ID
+	order []uuid.UUID            // IDs of the jobs in the order they were submitted
+}
+
+// jobRecord is a job as it is written to the file of the repository.
+type jobRecord struct {
+	ID              uuid.UUID       `json:"id"`
+	Kind            string          `json:"kind"`
+	Owner           string          `json:"owner"`
+	Tenant          string          `json:"tenant"`
+	Roles           []string        `json:"roles,omitempty"`
+	Permissions     []string        `json:"permissions,omitempty"`
+	Params          json.RawMessage `json:"params,omitempty"`
+	Status          job.Status      `json:"status"`
+	Done            int             `json:"done"`
+	Total           int             `json:"total"`
+	Message         string          `json:"message,omitempty"`
+	Result          json.RawMessage `json:"result,omitempty"`
+	Reason          string          `json:"reason,omitempty"`
+	CancelRequested bool            `json:"cancel_requested,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	StartedAt       *time.Time      `json:"started_at,omitempty"`
+	FinishedAt      *time.Time      `json:"finished_at,omitempty"`
+}
+
+// NewJobRepo creates and returns a new instance of JobRepo persisting the jobs to the file at path,
+// and loads the jobs already in it. The jobs are only kept in memory when the path is empty.
+func NewJobRepo(path string) (*JobRepo, error) {
+	r := &JobRepo{path: path, jobs: make(map[uuid.UUID]*job.Job), order: make([]uuid.UUID, 0)}
+	if path == "" {
+		return r, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("reading jobs: %w", err)
+	}
+	if err := r.load(content); err != nil {
+		return nil, fmt.Errorf("parsing jobs: %w", err)
+	}
+
+	// Start from a log holding a record per job, without the line a crash may have cut short
+	if err := r.rewrite(); err != nil {
+		return nil, fmt.Errorf("writing jobs: %w", err)
+	}
+	return r, nil
+}
+
+// Save saves a Job to the repository, and appends its record to the file.
+// The job isn't saved when the record can't be written.
+func (r *JobRepo) Save(j *job.Job) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if j == nil {
+		return ierr.NewValidation("job can't be empty")
+	}
+
+	if err := r.append(newJobRecord(j.Snapshot())); err != nil {
+		return ierr.NewUnexpected(fmt.Sprintf("failed to persist job: %s", err.Error()))
+	}
+	if _, found := r.jobs[j.Id()]; found {
+		r.stale++
+	} else {
+		r.order = append(r.order, j.Id())
+	}
+	r.jobs[j.Id()] = j.Clone()
+
+	// A failed rewrite leaves the log as it was, and is tried again on the next save
+	if r.stale > compactThreshold && r.stale > len(r.jobs) {
+		r.rewrite()
+	}
+	return nil
+}
+
+// Get retrieves a Job by its ID.
+func (r *JobRepo) Get(id uuid.UUID) (*job.Job, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if j, found := r.jobs[id]; found {
+		return j.Clone(), nil
+	}
+	return nil, ierr.NewNotFound("job not found")
+}
+
+// GetAll retrieves every Job, in the order they were submitted.
+func (r *JobRepo) GetAll() ([]*job.Job, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	jobs := make([]*job.Job, 0, len(r.order))
+	for _, id := range r.order {
+		jobs = append(jobs, r.jobs[id].Clone())
+	}
+	return jobs, nil
+}
+
+// DeleteFinishedBefore removes the jobs that finished before the given time, and rewrites the file with the
+// remaining ones. No job is removed when the file can't be written.
+func (r *JobRepo) DeleteFinishedBefore(at time.Time) (int, ierr.IErr) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	jobs, order := r.jobs, r.order
+	r.jobs, r.order = make(map[uuid.UUID]*job.Job, len(jobs)), make([]uuid.UUID, 0, len(order))
+	for _, id := range order {
+		if finishedAt := jobs[id].FinishedAt(); finishedAt != nil && finishedAt.Before(at) {
+			continue
+		}
+		r.jobs[id] = jobs[id]
+		r.order = append(r.order, id)
+	}
+
+	deleted := len(order) - len(r.order)
+	if deleted == 0 {
+		return 0, nil
+	}
+	if err := r.rewrite(); err != nil {
+		r.jobs, r.order = jobs, order
+		return 0, ierr.NewUnexpected(fmt.Sprintf("failed to persist jobs: %s", err.Error()))
+	}
+	return deleted, nil
+}
+
+// load loads the jobs of the content of the file, keeping the latest record of every job. Files written before
+// the log, which hold a JSON array of records, are read as well. The last line is skipped when it is cut short.
+func (r *JobRepo) load(content []byte) error {
+	var records []jobRecord
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return err
+		}
+	} else {
+		for len(content) > 0 {
+			line, rest, complete := bytes.Cut(content, []byte("\n"))
+			content = rest
+			if !complete {
+				break
+			}
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+
+			var record jobRecord
+			if err := json.Unmarshal(line, &record); err != nil {
+				return err
+			}
+			records = append(records, record)
+		}
+	}
+
+	for _, record := range records {
+		if _, found := r.jobs[record.ID]; !found {
+			r.order = append(r.order, record.ID)
+		}
+		r.jobs[record.ID] = job.Restore(record.snapshot())
+	}
+	return nil
+}
+
+// append appends the record of a job to the file of the repository, and waits for it to reach the disk.
+func (r *JobRepo) append(record jobRecord) error {
+	if r.log == nil {
+		return nil
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := r.log.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return r.log.Sync()
+}
+
+// rewrite writes the record of every job to the file of the repository, through a temporary file renamed
+// over it, so that a crash never leaves a half written file behind, then reopens it for appending.
+func (r *JobRepo) rewrite() error {
+	if r.path == "" {
+		return nil
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	writer := bufio.NewWriter(temp)
+	encoder := json.NewEncoder(writer)
+	for _, id := range r.order {
+		if err := encoder.Encode(newJobRecord(r.jobs[id].Snapshot())); err != nil {
+			temp.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(temp.Name(), r.path); err != nil {
+		return err
+	}
+
+	log, err := os.OpenFile(r.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if r.log != nil {
+		r.log.Close()
+	}
+	r.log = log
+	r.stale = 0
+	return nil
+}
+
+// newJobRecord maps the Snapshot of a job to its record.
+func newJobRecord(s job.Snapshot) jobRecord {
+	return jobRecord{
+		ID:              s.ID,
+		Kind:            s.Kind,
+		Owner:           s.Submitter.Subject,
+		Tenant:          s.Submitter.Tenant,
+		Roles:           s.Submitter.Roles,
+		Permissions:     s.Submitter.Permissions,
+		Params:          s.Params,
+		Status:          s.Status,
+		Done:            s.Progress.Done,
+		Total:           s.Progress.Total,
+		Message:         s.Progress.Message,
+		Result:          s.Result,
+		Reason:          s.Reason,
+		CancelRequested: s.CancelRequested,
+		CreatedAt:       s.CreatedAt,
+		StartedAt:       s.StartedAt,
+		FinishedAt:      s.FinishedAt,
+	}
+}
+
+// snapshot maps the record of a job to its Snapshot.
+func (record jobRecord) snapshot() job.Snapshot {
+	return job.Snapshot{
+		ID:   record.ID,
+		Kind: record.Kind,
+		Submitter: job.Submitter{
+			Subject:     record.Owner,
+			Tenant:      record.Tenant,
+			Roles:       record.Roles,
+			Permissions: record.Permissions,
+		},
+		Params:          record.Params,
+		Status:          record.Status,
+		Progress:        job.Progress{Done: record.Done, Total: record.Total, Message: record.Message},
+		Result:          record.Result,
+		Reason:          record.Reason,
+		CancelRequested: record.CancelRequested,
+		CreatedAt:       record.CreatedAt,
+		StartedAt:       record.StartedAt,
+		FinishedAt:      record.FinishedAt,
+	}
+}
@@ -1,28 +1,87 @@
 package repository
 
 import (
+	"context"
+	"fmt"
+	"sort"
 	"sync"
 
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	apperror "github.com/Efamamo/GoCrudChallange/application/error"
 	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
 	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
 	"github.com/google/uuid"
 )
 
 // PersonRepo is an in-memory repository for managing Person entities.
+// Every tenant gets its own partition, so people of different tenants never meet.
 type PersonRepo struct {
-	mutex  sync.RWMutex
-	people []*model.Person
+	mutex   sync.RWMutex
+	policy  model.UniquenessPolicy // Policy deciding which people can't coexist
+	quotas  tenant.Quotas          // Maximum number of people of each tenant
+	tenants map[string]*partition  // Partitions mapped to the tenant they belong to
 }
 
-// NewPersonRepo creates and returns a new instance of PersonRepo.
-func NewPersonRepo() *PersonRepo {
+// partition holds the people of a single tenant.
+type partition struct {
+	people    map[uuid.UUID]*model.Person
+	order     []uuid.UUID             // IDs of the people in the order they were first saved
+	keys      map[string]uuid.UUID    // Secondary index of uniqueness keys mapped to the ID holding them
+	emails    map[string]uuid.UUID    // Secondary index of emails mapped to the ID holding them
+	merges    []*model.Merge          // History of merges in the order they happened
+	redirects map[uuid.UUID]uuid.UUID // Retired IDs mapped to the ID they were merged into
+}
+
+// NewPersonRepo creates and returns a new instance of PersonRepo enforcing the given uniqueness policy
+// and tenant quotas.
+func NewPersonRepo(policy model.UniquenessPolicy, quotas tenant.Quotas) *PersonRepo {
 	return &PersonRepo{
-		people: make([]*model.Person, 0),
+		policy:  policy,
+		quotas:  quotas,
+		tenants: make(map[string]*partition),
+	}
+}
+
+// newPartition creates an empty partition.
+func newPartition() *partition {
+	return &partition{
+		people:    make(map[uuid.UUID]*model.Person),
+		order:     make([]uuid.UUID, 0),
+		keys:      make(map[string]uuid.UUID),
+		emails:    make(map[string]uuid.UUID),
+		merges:    make([]*model.Merge, 0),
+		redirects: make(map[uuid.UUID]uuid.UUID),
+	}
+}
+
+// partition returns the partition of the tenant carried by the context. Reads of a tenant that
+// never saved anyone get an empty partition, which is only stored once something is written to it.
+func (r *PersonRepo) partition(ctx context.Context, write bool) *partition {
+	id := tenant.From(ctx)
+	p, ok := r.tenants[id]
+	if !ok {
+		p = newPartition()
+		if write {
+			r.tenants[id] = p
+		}
 	}
+	return p
 }
 
 // Save saves a Person to the repository.
-func (r *PersonRepo) Save(person *model.Person) ierr.IErr {
+// The uniqueness checks and the write happen under the same lock, so two concurrent saves
+// of conflicting people can't both succeed.
+func (r *PersonRepo) Save(ctx context.Context, person *model.Person) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.save(ctx, person)
+}
+
+// Create adds a new Person to the repository, failing with a Conflict when a person, current or
+// retired by a merge, already holds its ID. The check and the write happen under the same lock,
+// so two concurrent creations with the same ID can't both succeed.
+func (r *PersonRepo) Create(ctx context.Context, person *model.Person) ierr.IErr {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
@@ -30,71 +89,257 @@ func (r *PersonRepo) Save(person *model.Person) ierr.IErr {
 		return ierr.NewValidation("person can't be empty")
 	}
 
-	var idx = -1
+	p := r.partition(ctx, false)
+	if _, ok := p.people[person.Id()]; ok {
+		return ierr.NewConflictWith(fmt.Sprintf("person with id %s already exists", person.Id()), person.Id().String())
+	}
+	if to, ok := p.redirects[person.Id()]; ok {
+		return ierr.NewConflictWith(fmt.Sprintf("person with id %s was merged into another person", person.Id()), to.String())
+	}
 
-	// Check if the person already exists in the repository.
-	for i, psn := range r.people {
-		if psn.Id() == person.Id() {
-			idx = i
-		}
+	return r.save(ctx, person)
+}
+
+// save writes the Person after checking its uniqueness and the quota of the tenant.
+// The caller should hold the write lock.
+func (r *PersonRepo) save(ctx context.Context, person *model.Person) ierr.IErr {
+	if person == nil {
+		return ierr.NewValidation("person can't be empty")
 	}
 
-	if idx != -1 {
-		r.people[idx] = person
+	p := r.partition(ctx, true)
+	if err := p.checkUnique(r.policy, person, nil); err != nil {
+		return err
+	}
+
+	if previous, ok := p.people[person.Id()]; ok {
+		p.unindex(r.policy, previous)
 	} else {
-		r.people = append(r.people, person)
+		if limit := r.quotas.Limit(tenant.From(ctx)); limit > 0 && len(p.people) >= limit {
+			return apperror.QuotaReached(fmt.Sprintf("tenant can't store more than %d people", limit))
+		}
+		p.order = append(p.order, person.Id())
 	}
 
+	// Store a copy so that changes made by callers only take effect once saved.
+	p.people[person.Id()] = person.Clone()
+	p.index(r.policy, person)
+
 	return nil // Return nil indicating success.
 }
 
 // Get retrieves a Person by its ID from the repository.
-func (r *PersonRepo) Get(id uuid.UUID) (*model.Person, ierr.IErr) {
+func (r *PersonRepo) Get(ctx context.Context, id uuid.UUID) (*model.Person, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	person, ok := r.partition(ctx, false).people[id]
+	if !ok {
+		return nil, ierr.NewNotFound("person not found")
+	}
+	return person.Clone(), nil
+}
+
+// Delete removes a Person from the repository by its ID.
+func (r *PersonRepo) Delete(ctx context.Context, id uuid.UUID) ierr.IErr {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	var person *model.Person
+	p := r.partition(ctx, false)
+	person, ok := p.people[id]
+	if !ok {
+		return ierr.NewNotFound("person not found")
+	}
+
+	p.remove(r.policy, person)
+	return nil
+}
+
+// GetAll retrieves all Person entities from the repository.
+func (r *PersonRepo) GetAll(ctx context.Context) ([]*model.Person, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	p := r.partition(ctx, false)
+	people := make([]*model.Person, 0, len(p.order))
+	for _, id := range p.order {
+		people = append(people, p.people[id].Clone())
+	}
+	return people, nil
+}
+
+// Stream calls yield with a copy of every Person of the tenant, in the order they were first saved.
+// The lock is only held while copying the IDs and then each person, so that writes aren't blocked
+// for the whole stream; people deleted meanwhile are skipped.
+func (r *PersonRepo) Stream(ctx context.Context, yield func(*model.Person) bool) ierr.IErr {
+	r.mutex.RLock()
+	p := r.partition(ctx, false)
+	ids := append([]uuid.UUID(nil), p.order...)
+	r.mutex.RUnlock()
 
-	// Search for the person in the repository.
-	for _, p := range r.people {
-		if p.Id() == id {
-			person = p
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		r.mutex.RLock()
+		person, ok := p.people[id]
+		if ok {
+			person = person.Clone()
+		}
+		r.mutex.RUnlock()
+
+		if ok && !yield(person) {
+			return nil
 		}
 	}
+	return nil
+}
 
-	if person == nil {
-		return nil, ierr.NewNotFound("person not found")
+// Tenants lists the tenants having a partition, in alphabetical order.
+func (r *PersonRepo) Tenants(_ context.Context) ([]string, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	tenants := make([]string, 0, len(r.tenants))
+	for id := range r.tenants {
+		tenants = append(tenants, id)
 	}
-	return person, nil
+	sort.Strings(tenants)
+	return tenants, nil
 }
 
-// Delete removes a Person from the repository by its ID.
-func (r *PersonRepo) Delete(id uuid.UUID) ierr.IErr {
+// Merge saves the survivor of a merge and removes the retired people within a single lock,
+// so that either the whole merge is applied or none of it is.
+func (r *PersonRepo) Merge(ctx context.Context, survivor *model.Person, merge *model.Merge) ierr.IErr {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	idx := -1
+	if survivor == nil || merge == nil {
+		return ierr.NewValidation("merge can't be empty")
+	}
 
-	// Search for the person to delete.
-	for i, p := range r.people {
-		if p.Id() == id {
-			idx = i
+	// Make sure every person taking part in the merge still exists.
+	p := r.partition(ctx, false)
+	previous, ok := p.people[survivor.Id()]
+	if !ok {
+		return ierr.NewNotFound("person not found")
+	}
+	retired := make(map[uuid.UUID]struct{}, len(merge.RetiredIDs()))
+	for _, id := range merge.RetiredIDs() {
+		if _, ok := p.people[id]; !ok {
+			return ierr.NewNotFound("person not found")
 		}
+		retired[id] = struct{}{}
 	}
 
-	if idx == -1 {
-		return ierr.NewNotFound("person not found")
+	// The retired people are about to go away, so they can't conflict with the survivor.
+	if err := p.checkUnique(r.policy, survivor, retired); err != nil {
+		return err
+	}
+
+	for id := range retired {
+		p.remove(r.policy, p.people[id])
+		p.redirects[id] = survivor.Id()
+	}
+
+	// Redirects that pointed to a retired person now point to the survivor.
+	for from, to := range p.redirects {
+		if _, ok := retired[to]; ok {
+			p.redirects[from] = survivor.Id()
+		}
 	}
 
-	// Remove the person from the slice.
-	r.people = append(r.people[0:idx], r.people[idx+1:]...)
+	p.unindex(r.policy, previous)
+	p.people[survivor.Id()] = survivor.Clone()
+	p.index(r.policy, survivor)
+	p.merges = append(p.merges, merge)
+
 	return nil
 }
 
-// GetAll retrieves all Person entities from the repository.
-func (r *PersonRepo) GetAll() ([]*model.Person, ierr.IErr) {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+// Redirect returns the ID of the Person that the given retired ID was merged into.
+func (r *PersonRepo) Redirect(ctx context.Context, id uuid.UUID) (uuid.UUID, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	to, ok := r.partition(ctx, false).redirects[id]
+	if !ok {
+		return uuid.Nil, ierr.NewNotFound("redirect not found")
+	}
+	return to, nil
+}
+
+// Merges lists the merges whose survivor is the given Person, or was later merged into them, oldest first.
+func (r *PersonRepo) Merges(ctx context.Context, id uuid.UUID) ([]*model.Merge, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	p := r.partition(ctx, false)
+	merges := make([]*model.Merge, 0)
+	for _, merge := range p.merges {
+		if merge.SurvivorID() == id || p.redirects[merge.SurvivorID()] == id {
+			merges = append(merges, merge)
+		}
+	}
+	return merges, nil
+}
+
+// checkUnique returns a Conflict when another person already holds the email or the uniqueness key
+// of the given person. People whose IDs are in ignored are not considered.
+func (p *partition) checkUnique(policy model.UniquenessPolicy, person *model.Person, ignored map[uuid.UUID]struct{}) ierr.IErr {
+	conflicts := func(holder uuid.UUID, ok bool) bool {
+		if !ok || holder == person.Id() {
+			return false
+		}
+		_, skip := ignored[holder]
+		return !skip
+	}
+
+	if email := person.Email(); !email.IsZero() {
+		if holder, ok := p.emails[email.String()]; conflicts(holder, ok) {
+			return ierr.NewConflictWith("email is already in use", holder.String())
+		}
+	}
+
+	if key, ok := policy.Key(person); ok {
+		if holder, ok := p.keys[key]; conflicts(holder, ok) {
+			return ierr.NewConflictWith("person already exists", holder.String())
+		}
+	}
+
+	return nil
+}
+
+// index adds the person to the secondary indexes.
+func (p *partition) index(policy model.UniquenessPolicy, person *model.Person) {
+	if email := person.Email(); !email.IsZero() {
+		p.emails[email.String()] = person.Id()
+	}
+	if key, ok := policy.Key(person); ok {
+		p.keys[key] = person.Id()
+	}
+}
+
+// unindex removes the person from the secondary indexes.
+func (p *partition) unindex(policy model.UniquenessPolicy, person *model.Person) {
+	if email := person.Email(); !email.IsZero() && p.emails[email.String()] == person.Id() {
+		delete(p.emails, email.String())
+	}
+	if key, ok := policy.Key(person); ok && p.keys[key] == person.Id() {
+		delete(p.keys, key)
+	}
+}
 
-	return r.people, nil
+// remove deletes the person from the partition and its indexes.
+func (p *partition) remove(policy model.UniquenessPolicy, person *model.Person) {
+	p.unindex(policy, person)
+	delete(p.people, person.Id())
+
+	for i, id := range p.order {
+		if id == person.Id() {
+			p.order = append(p.order[:i], p.order[i+1:]...)
+			break
+		}
+	}
 }
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/relation"
+	"github.com/google/uuid"
+)
+
+// RelationRepo is an in-memory repository for managing relations between people.
+// Every tenant gets its own partition, so relations of different tenants never meet.
+type RelationRepo struct {
+	mutex   sync.RWMutex
+	tenants map[string]*relationPartition // Partitions mapped to the tenant they belong to
+}
+
+// relationPartition holds the relations of a single tenant.
+type relationPartition struct {
+	relations map[uuid.UUID]*relation.Relation
+	byPerson  map[uuid.UUID]map[uuid.UUID]struct{} // Person IDs mapped to the IDs of their relations
+}
+
+// NewRelationRepo creates and returns a new instance of RelationRepo.
+func NewRelationRepo() *RelationRepo {
+	return &RelationRepo{
+		tenants: make(map[string]*relationPartition),
+	}
+}
+
+// partition returns the partition of the tenant carried by the context. Reads of a tenant that
+// never saved a relation get an empty partition, which is only stored once something is written to it.
+func (r *RelationRepo) partition(ctx context.Context, write bool) *relationPartition {
+	id := tenant.From(ctx)
+	p, ok := r.tenants[id]
+	if !ok {
+		p = &relationPartition{
+			relations: make(map[uuid.UUID]*relation.Relation),
+			byPerson:  make(map[uuid.UUID]map[uuid.UUID]struct{}),
+		}
+		if write {
+			r.tenants[id] = p
+		}
+	}
+	return p
+}
+
+// Save saves a Relation to the repository.
+func (r *RelationRepo) Save(ctx context.Context, rel *relation.Relation) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if rel == nil {
+		return ierr.NewValidation("relation can't be empty")
+	}
+
+	p := r.partition(ctx, true)
+
+	// Reject the relation if the same edge is already stored under another ID.
+	for id := range p.byPerson[rel.From()] {
+		if existing := p.relations[id]; existing.Id() != rel.Id() && existing.SameAs(rel) {
+			return ierr.NewConflict("relation already exists")
+		}
+	}
+
+	p.relations[rel.Id()] = rel
+	p.index(rel)
+	return nil
+}
+
+// Get retrieves a Relation by its ID from the repository.
+func (r *RelationRepo) Get(ctx context.Context, id uuid.UUID) (*relation.Relation, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	rel, ok := r.partition(ctx, false).relations[id]
+	if !ok {
+		return nil, ierr.NewNotFound("relation not found")
+	}
+	return rel, nil
+}
+
+// Delete removes a Relation from the repository by its ID.
+func (r *RelationRepo) Delete(ctx context.Context, id uuid.UUID) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	p := r.partition(ctx, false)
+	rel, ok := p.relations[id]
+	if !ok {
+		return ierr.NewNotFound("relation not found")
+	}
+
+	p.remove(rel)
+	return nil
+}
+
+// GetByPerson retrieves every Relation involving the given person, oldest first.
+func (r *RelationRepo) GetByPerson(ctx context.Context, personID uuid.UUID) ([]*relation.Relation, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	p := r.partition(ctx, false)
+	relations := make([]*relation.Relation, 0, len(p.byPerson[personID]))
+	for id := range p.byPerson[personID] {
+		relations = append(relations, p.relations[id])
+	}
+
+	sort.Slice(relations, func(i, j int) bool {
+		return relations[i].CreatedAt().Before(relations[j].CreatedAt())
+	})
+	return relations, nil
+}
+
+// DeleteByPerson removes every Relation involving the given person.
+func (r *RelationRepo) DeleteByPerson(ctx context.Context, personID uuid.UUID) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	p := r.partition(ctx, false)
+	for id := range p.byPerson[personID] {
+		p.remove(p.relations[id])
+	}
+	return nil
+}
+
+// Reassign moves every Relation of one person over to another one.
+func (r *RelationRepo) Reassign(ctx context.Context, from uuid.UUID, to uuid.UUID) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	p := r.partition(ctx, false)
+	for id := range p.byPerson[from] {
+		rel := p.relations[id]
+		p.remove(rel)
+
+		rel.Reassign(from, to)
+		if rel.From() == rel.To() {
+			continue
+		}
+
+		duplicate := false
+		for otherID := range p.byPerson[rel.From()] {
+			if p.relations[otherID].SameAs(rel) {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			continue
+		}
+
+		p.relations[rel.Id()] = rel
+		p.index(rel)
+	}
+	return nil
+}
+
+// index records the relation under both of the people it involves.
+func (p *relationPartition) index(rel *relation.Relation) {
+	for _, personID := range []uuid.UUID{rel.From(), rel.To()} {
+		if p.byPerson[personID] == nil {
+			p.byPerson[personID] = make(map[uuid.UUID]struct{})
+		}
+		p.byPerson[personID][rel.Id()] = struct{}{}
+	}
+}
+
+// remove deletes the relation and its index entries.
+func (p *relationPartition) remove(rel *relation.Relation) {
+	delete(p.relations, rel.Id())
+	for _, personID := range []uuid.UUID{rel.From(), rel.To()} {
+		delete(p.byPerson[personID], rel.Id())
+		if len(p.byPerson[personID]) == 0 {
+			delete(p.byPerson, personID)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/user"
+	"github.com/google/uuid"
+)
+
+// TokenRepo is an in-memory repository for refresh tokens and revoked access tokens.
+type TokenRepo struct {
+	mutex   sync.RWMutex
+	refresh map[uuid.UUID]*user.RefreshToken
+	hashes  map[string]uuid.UUID // Secondary index of secret hashes mapped to refresh token IDs
+	revoked map[string]time.Time // Revoked access token IDs mapped to the time they expire
+}
+
+// NewTokenRepo creates and returns a new instance of TokenRepo.
+func NewTokenRepo() *TokenRepo {
+	return &TokenRepo{
+		refresh: make(map[uuid.UUID]*user.RefreshToken),
+		hashes:  make(map[string]uuid.UUID),
+		revoked: make(map[string]time.Time),
+	}
+}
+
+// SaveRefreshToken saves a RefreshToken to the repository.
+func (r *TokenRepo) SaveRefreshToken(token *user.RefreshToken) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if token == nil {
+		return ierr.NewValidation("refresh token can't be empty")
+	}
+
+	r.refresh[token.Id()] = token
+	r.hashes[token.Hash()] = token.Id()
+	return nil
+}
+
+// GetRefreshToken retrieves a RefreshToken by the hash of its secret.
+func (r *TokenRepo) GetRefreshToken(hash string) (*user.RefreshToken, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	id, ok := r.hashes[hash]
+	if !ok {
+		return nil, ierr.NewNotFound("refresh token not found")
+	}
+	return r.refresh[id], nil
+}
+
+// DeleteRefreshToken removes a RefreshToken by its ID.
+func (r *TokenRepo) DeleteRefreshToken(id uuid.UUID) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	token, ok := r.refresh[id]
+	if !ok {
+		return ierr.NewNotFound("refresh token not found")
+	}
+
+	delete(r.hashes, token.Hash())
+	delete(r.refresh, id)
+	return nil
+}
+
+// DeleteRefreshTokensOf removes every RefreshToken issued to the user.
+func (r *TokenRepo) DeleteRefreshTokensOf(userID uuid.UUID) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for id, token := range r.refresh {
+		if token.UserID() == userID {
+			delete(r.hashes, token.Hash())
+			delete(r.refresh, id)
+		}
+	}
+	return nil
+}
+
+// RevokeAccessToken rejects the access token with the given ID until it expires.
+// Revocations of tokens that have since expired are dropped, as those tokens are rejected anyway.
+func (r *TokenRepo) RevokeAccessToken(id string, expiresAt time.Time) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	for revoked, until := range r.revoked {
+		if !now.Before(until) {
+			delete(r.revoked, revoked)
+		}
+	}
+
+	r.revoked[id] = expiresAt
+	return nil
+}
+
+// IsAccessTokenRevoked reports whether the access token with the given ID was revoked.
+func (r *TokenRepo) IsAccessTokenRevoked(id string) (bool, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	_, ok := r.revoked[id]
+	return ok, nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/google/uuid"
+)
+
+// UploadRepo is a repository of the uploads of imports, written to a file per upload under a directory with a
+// subdirectory per tenant, so that they stay out of the job table and survive restarts along with their job.
+// Uploads are held in memory when it has no directory.
+type UploadRepo struct {
+	dir     string
+	mutex   sync.RWMutex
+	uploads map[string]map[uuid.UUID][]byte // Uploads of each tenant mapped to their ID, when held in memory
+}
+
+// NewUploadRepo creates and returns a new instance of UploadRepo writing the uploads under the directory,
+// or holding them in memory when it is empty.
+func NewUploadRepo(dir string) (*UploadRepo, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("creating the uploads directory: %w", err)
+		}
+	}
+	return &UploadRepo{dir: dir, uploads: make(map[string]map[uuid.UUID][]byte)}, nil
+}
+
+// Save stores the content of an upload in the partition of the tenant carried by the context.
+// The file is written under a temporary name renamed once complete, so that no partial upload is ever read.
+func (r *UploadRepo) Save(ctx context.Context, id uuid.UUID, content []byte) ierr.IErr {
+	if r.dir == "" {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		t := tenant.From(ctx)
+		if r.uploads[t] == nil {
+			r.uploads[t] = make(map[uuid.UUID][]byte)
+		}
+		r.uploads[t][id] = content
+		return nil
+	}
+
+	dir := filepath.Join(r.dir, tenant.From(ctx))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return ierr.NewUnexpected(fmt.Sprintf("failed to save upload: %s", err.Error()))
+	}
+	temp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return ierr.NewUnexpected(fmt.Sprintf("failed to save upload: %s", err.Error()))
+	}
+	defer os.Remove(temp.Name())
+
+	_, err = temp.Write(content)
+	if err == nil {
+		err = temp.Sync()
+	}
+	if cerr := temp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), filepath.Join(dir, id.String()))
+	}
+	if err != nil {
+		return ierr.NewUnexpected(fmt.Sprintf("failed to save upload: %s", err.Error()))
+	}
+	return nil
+}
+
+// Get retrieves the content of an upload from the partition of the tenant carried by the context.
+func (r *UploadRepo) Get(ctx context.Context, id uuid.UUID) ([]byte, ierr.IErr) {
+	if r.dir == "" {
+		r.mutex.RLock()
+		defer r.mutex.RUnlock()
+
+		content, found := r.uploads[tenant.From(ctx)][id]
+		if !found {
+			return nil, ierr.NewNotFound("upload not found")
+		}
+		return content, nil
+	}
+
+	content, err := os.ReadFile(r.path(ctx, id))
+	if err != nil {
+		return nil, r.failed(err)
+	}
+	return content, nil
+}
+
+// Size returns the size in bytes of an upload in the partition of the tenant carried by the context.
+func (r *UploadRepo) Size(ctx context.Context, id uuid.UUID) (int64, ierr.IErr) {
+	if r.dir == "" {
+		content, err := r.Get(ctx, id)
+		return int64(len(content)), err
+	}
+
+	info, err := os.Stat(r.path(ctx, id))
+	if err != nil {
+		return 0, r.failed(err)
+	}
+	return info.Size(), nil
+}
+
+// Delete removes an upload from the partition of the tenant carried by the context.
+func (r *UploadRepo) Delete(ctx context.Context, id uuid.UUID) ierr.IErr {
+	if r.dir == "" {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		if _, found := r.uploads[tenant.From(ctx)][id]; !found {
+			return ierr.NewNotFound("upload not found")
+		}
+		delete(r.uploads[tenant.From(ctx)], id)
+		return nil
+	}
+
+	if err := os.Remove(r.path(ctx, id)); err != nil {
+		return r.failed(err)
+	}
+	return nil
+}
+
+// path returns the file of an upload in the partition of the tenant carried by the context.
+func (r *UploadRepo) path(ctx context.Context, id uuid.UUID) string {
+	return filepath.Join(r.dir, tenant.From(ctx), id.String())
+}
+
+// failed maps the error of a file operation on an upload to a NotFound error when the file doesn't exist.
+func (r *UploadRepo) failed(err error) ierr.IErr {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ierr.NewNotFound("upload not found")
+	}
+	return ierr.NewUnexpected(fmt.Sprintf("failed to read upload: %s", err.Error()))
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/user"
+	"github.com/google/uuid"
+)
+
+// UserRepo is an in-memory repository for managing local user accounts.
+type UserRepo struct {
+	mutex     sync.RWMutex
+	users     map[uuid.UUID]*user.User
+	usernames map[string]uuid.UUID // Secondary index of usernames mapped to user IDs
+}
+
+// NewUserRepo creates and returns a new instance of UserRepo.
+func NewUserRepo() *UserRepo {
+	return &UserRepo{
+		users:     make(map[uuid.UUID]*user.User),
+		usernames: make(map[string]uuid.UUID),
+	}
+}
+
+// Save saves a User to the repository.
+func (r *UserRepo) Save(u *user.User) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if u == nil {
+		return ierr.NewValidation("user can't be empty")
+	}
+
+	if holder, ok := r.usernames[u.Username()]; ok && holder != u.Id() {
+		return ierr.NewConflictWith("username is already in use", holder.String())
+	}
+
+	if previous, ok := r.users[u.Id()]; ok {
+		delete(r.usernames, previous.Username())
+	}
+
+	r.users[u.Id()] = u.Clone()
+	r.usernames[u.Username()] = u.Id()
+	return nil
+}
+
+// Get retrieves a User by its ID from the repository.
+func (r *UserRepo) Get(id uuid.UUID) (*user.User, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return nil, ierr.NewNotFound("user not found")
+	}
+	return u.Clone(), nil
+}
+
+// GetByUsername retrieves a User by its username.
+func (r *UserRepo) GetByUsername(username string) (*user.User, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	id, ok := r.usernames[username]
+	if !ok {
+		return nil, ierr.NewNotFound("user not found")
+	}
+	return r.users[id].Clone(), nil
+}
+
+// Delete removes a User from the repository by its ID.
+func (r *UserRepo) Delete(id uuid.UUID) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return ierr.NewNotFound("user not found")
+	}
+
+	delete(r.usernames, u.Username())
+	delete(r.users, id)
+	return nil
+}
+
+// GetAll retrieves every User from the repository, sorted by username.
+func (r *UserRepo) GetAll() ([]*user.User, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	users := make([]*user.User, 0, len(r.users))
+	for _, u := range r.users {
+		users = append(users, u.Clone())
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username() < users[j].Username()
+	})
+	return users, nil
+}
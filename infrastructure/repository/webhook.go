@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/webhook"
+	"github.com/google/uuid"
+)
+
+// WebhookRepo is an in-memory repository for managing webhook subscriptions.
+type WebhookRepo struct {
+	mutex    sync.RWMutex
+	webhooks map[uuid.UUID]*webhook.Webhook
+}
+
+// NewWebhookRepo creates and returns a new instance of WebhookRepo.
+func NewWebhookRepo() *WebhookRepo {
+	return &WebhookRepo{webhooks: make(map[uuid.UUID]*webhook.Webhook)}
+}
+
+// Save saves a Webhook to the repository.
+func (r *WebhookRepo) Save(hook *webhook.Webhook) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if hook == nil {
+		return ierr.NewValidation("webhook can't be empty")
+	}
+
+	r.webhooks[hook.Id()] = hook.Clone()
+	return nil
+}
+
+// Get retrieves a Webhook by its ID from the repository.
+func (r *WebhookRepo) Get(id uuid.UUID) (*webhook.Webhook, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	hook, ok := r.webhooks[id]
+	if !ok {
+		return nil, ierr.NewNotFound("webhook not found")
+	}
+	return hook.Clone(), nil
+}
+
+// Delete removes a Webhook from the repository by its ID.
+func (r *WebhookRepo) Delete(id uuid.UUID) ierr.IErr {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.webhooks[id]; !ok {
+		return ierr.NewNotFound("webhook not found")
+	}
+
+	delete(r.webhooks, id)
+	return nil
+}
+
+// GetAll retrieves every Webhook from the repository, oldest first.
+func (r *WebhookRepo) GetAll() ([]*webhook.Webhook, ierr.IErr) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	hooks := make([]*webhook.Webhook, 0, len(r.webhooks))
+	for _, hook := range r.webhooks {
+		hooks = append(hooks, hook.Clone())
+	}
+
+	sort.Slice(hooks, func(i, j int) bool {
+		return hooks[i].CreatedAt().Before(hooks[j].CreatedAt())
+	})
+	return hooks, nil
+}
@@ -0,0 +1,167 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	ischedule "github.com/Efamamo/GoCrudChallange/application/common/interface/schedule"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/schedule"
+)
+
+// SchedulerConfig holds configuration settings for creating a new Scheduler.
+type SchedulerConfig struct {
+	Jitter time.Duration // Maximum random delay added to every run, so that instances don't all run at once; none when zero
+}
+
+// Scheduler runs maintenance tasks on the activations of their cron expressions, evaluated in UTC.
+// A task never overlaps itself: an activation happening while the previous run is still running is skipped.
+type Scheduler struct {
+	config  SchedulerConfig
+	mutex   sync.Mutex
+	entries map[string]*entry // Registered tasks mapped to their name
+	running sync.WaitGroup    // Loops and runs of the tasks
+}
+
+// entry is a registered task along with its schedule.
+type entry struct {
+	schedule *model.Schedule
+	task     ischedule.ITask
+}
+
+// Compile-time check to ensure Scheduler implements IScheduler.
+var _ ischedule.IScheduler = &Scheduler{}
+
+// NewScheduler creates and returns a new instance of Scheduler.
+func NewScheduler(config SchedulerConfig) *Scheduler {
+	return &Scheduler{config: config, entries: make(map[string]*entry)}
+}
+
+// Register registers the named task, run on the activations of the cron expression, e.g. "0 3 * * *" or "@hourly".
+// The task is listed but never runs when the expression is empty, and a Validation error is returned when it is invalid.
+// Tasks should be registered before the scheduler starts.
+func (s *Scheduler) Register(name string, spec string, task ischedule.ITask) ierr.IErr {
+	var cron *model.Cron
+	if spec != "" {
+		var err ierr.IErr
+		if cron, err = model.ParseCron(spec); err != nil {
+			return err
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries[name] = &entry{schedule: model.NewSchedule(name, cron), task: task}
+	return nil
+}
+
+// Start runs the enabled tasks on their schedules in the background, until the context is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, e := range s.entries {
+		if !e.schedule.Enabled() {
+			continue
+		}
+		s.running.Add(1)
+		go s.loop(ctx, e)
+	}
+}
+
+// Wait blocks until the scheduler stopped, once the context it was started with is done,
+// including the runs that were in progress, whose context is done too.
+func (s *Scheduler) Wait() {
+	s.running.Wait()
+}
+
+// Schedules returns the schedule of every registered task, with the status of its last run, in alphabetical order.
+func (s *Scheduler) Schedules() []*model.Schedule {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	schedules := make([]*model.Schedule, 0, len(s.entries))
+	for _, e := range s.entries {
+		schedules = append(schedules, e.schedule.Clone())
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].Name() < schedules[j].Name() })
+	return schedules
+}
+
+// loop waits for every activation of the task, and runs it unless its previous run is still running.
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	defer s.running.Done()
+
+	for {
+		next := e.schedule.Cron().Next(time.Now().UTC())
+		if next.IsZero() {
+			log.Printf("schedules: %s never runs again", e.schedule.Name())
+			return
+		}
+		if s.config.Jitter > 0 {
+			next = next.Add(rand.N(s.config.Jitter))
+		}
+
+		s.mutex.Lock()
+		e.schedule.Plan(next)
+		s.mutex.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.mutex.Lock()
+		if e.schedule.Running() {
+			e.schedule.Skip()
+			s.mutex.Unlock()
+			log.Printf("schedules: skipped %s, whose previous run is still running", e.schedule.Name())
+			continue
+		}
+		e.schedule.Start()
+		s.mutex.Unlock()
+
+		s.running.Add(1)
+		go s.run(ctx, e)
+	}
+}
+
+// run runs the task once, and records how it went.
+func (s *Scheduler) run(ctx context.Context, e *entry) {
+	defer s.running.Done()
+
+	run := model.Run{StartedAt: time.Now().UTC(), Outcome: model.Succeeded}
+	message, err := call(ctx, e.task)
+	run.FinishedAt = time.Now().UTC()
+	run.Message = message
+	if err != nil {
+		run.Outcome = model.Failed
+		run.Message = err.Error()
+		log.Printf("schedules: %s failed: %s", e.schedule.Name(), err.Error())
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	e.schedule.Finish(run)
+}
+
+// call runs the task, turning a panic into an error so that a faulty task doesn't take the service down.
+func call(ctx context.Context, task ischedule.ITask) (message string, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("task panicked: %v", recovered)
+		}
+	}()
+
+	return task.Run(ctx)
+}
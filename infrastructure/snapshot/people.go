@@ -0,0 +1,198 @@
+package snapshot
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ischedule "github.com/Efamamo/GoCrudChallange/application/common/interface/schedule"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
+)
+
+// PeopleKind is the name of the scheduled task taking snapshots of people.
+const PeopleKind = "people.snapshot"
+
+// Defaults of the PeopleConfig.
+const defaultKeep = 7
+
+// fileLayout is the layout of the time in the name of the snapshot files, which sorts them chronologically.
+const fileLayout = "20060102T150405Z"
+
+// PeopleConfig holds configuration settings for creating a new PeopleSnapshotter.
+type PeopleConfig struct {
+	People irepo.IPerson
+	Dir    string // Directory the snapshots are written to, with a subdirectory per tenant
+	Keep   int    // Number of snapshots kept per tenant, older ones being removed; 7 when zero
+}
+
+// PeopleSnapshotter is the scheduled task writing every person of every tenant to a snapshot file,
+// as NDJSON that POST /person/import reads back.
+type PeopleSnapshotter struct {
+	config PeopleConfig
+}
+
+// record is a person in a snapshot file.
+type record struct {
+	ID         uuid.UUID      `json:"id"`
+	Name       string         `json:"name"`
+	Age        int16          `json:"age"`
+	Hobbies    []string       `json:"hobbies"`
+	Email      string         `json:"email,omitempty"`
+	Phone      string         `json:"phone,omitempty"`
+	Address    *addressRecord `json:"address,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	Owner      string         `json:"owner,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// addressRecord is the postal address of a person in a snapshot file.
+type addressRecord struct {
+	Street     string `json:"street"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country"`
+}
+
+// Compile-time check to ensure PeopleSnapshotter implements ITask.
+var _ ischedule.ITask = &PeopleSnapshotter{}
+
+// NewPeopleSnapshotter creates and returns a new instance of PeopleSnapshotter, filling in the defaults of the configuration.
+func NewPeopleSnapshotter(config PeopleConfig) *PeopleSnapshotter {
+	if config.Keep <= 0 {
+		config.Keep = defaultKeep
+	}
+	return &PeopleSnapshotter{config: config}
+}
+
+// Run writes a snapshot of the people of every tenant to <dir>/<tenant>/people-<time>.ndjson, removes the
+// snapshots beyond the ones kept, and reports how many people it wrote.
+func (s *PeopleSnapshotter) Run(ctx context.Context) (string, error) {
+	tenants, err := s.config.People.Tenants(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	total := 0
+	for _, t := range tenants {
+		written, err := s.write(tenant.WithTenant(ctx, t), filepath.Join(s.config.Dir, t), now)
+		if err != nil {
+			return "", fmt.Errorf("failed to snapshot tenant %s: %s", t, err.Error())
+		}
+		if err := s.prune(filepath.Join(s.config.Dir, t)); err != nil {
+			return "", fmt.Errorf("failed to prune the snapshots of tenant %s: %s", t, err.Error())
+		}
+		total += written
+	}
+	return fmt.Sprintf("wrote %d people of %d tenants", total, len(tenants)), nil
+}
+
+// write writes the people of the tenant of the context to a new snapshot file in the directory, and returns how
+// many it wrote. The snapshot is written to a temporary file renamed once complete, so that no partial snapshot
+// is ever left behind.
+func (s *PeopleSnapshotter) write(ctx context.Context, dir string, at time.Time) (int, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, err
+	}
+	temp, err := os.CreateTemp(dir, ".people-*.ndjson")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(temp.Name())
+	defer temp.Close()
+
+	buffer := bufio.NewWriter(temp)
+	encoder := json.NewEncoder(buffer)
+	written := 0
+	var encodeErr error
+	if err := s.config.People.Stream(ctx, func(p *model.Person) bool {
+		if encodeErr = encoder.Encode(toRecord(p)); encodeErr != nil {
+			return false
+		}
+		written++
+		return true
+	}); err != nil {
+		return 0, err
+	}
+	if encodeErr != nil {
+		return 0, encodeErr
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if err := buffer.Flush(); err != nil {
+		return 0, err
+	}
+	if err := temp.Sync(); err != nil {
+		return 0, err
+	}
+	if err := temp.Close(); err != nil {
+		return 0, err
+	}
+	name := filepath.Join(dir, fmt.Sprintf("people-%s.ndjson", at.Format(fileLayout)))
+	if err := os.Rename(temp.Name(), name); err != nil {
+		return 0, err
+	}
+	return written, nil
+}
+
+// prune removes the oldest snapshots of the directory, keeping the configured number of them.
+func (s *PeopleSnapshotter) prune(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	snapshots := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, "people-") && strings.HasSuffix(name, ".ndjson") {
+			snapshots = append(snapshots, name)
+		}
+	}
+	sort.Strings(snapshots)
+
+	for len(snapshots) > s.config.Keep {
+		if err := os.Remove(filepath.Join(dir, snapshots[0])); err != nil {
+			return err
+		}
+		snapshots = snapshots[1:]
+	}
+	return nil
+}
+
+// toRecord maps a Person to its record in a snapshot file.
+func toRecord(p *model.Person) *record {
+	r := &record{
+		ID:         p.Id(),
+		Name:       p.Name(),
+		Age:        p.Age(),
+		Hobbies:    p.Hobbies(),
+		Attributes: p.Attributes(),
+		Owner:      p.Owner(),
+		CreatedAt:  p.CreatedAt(),
+		UpdatedAt:  p.UpdatedAt(),
+	}
+	if !p.Email().IsZero() {
+		r.Email = p.Email().String()
+	}
+	if !p.Phone().IsZero() {
+		r.Phone = p.Phone().String()
+	}
+	if a := p.Address(); a != nil {
+		r.Address = &addressRecord{Street: a.Street(), City: a.City(), Region: a.Region(), PostalCode: a.PostalCode(), Country: a.Country()}
+	}
+	return r
+}
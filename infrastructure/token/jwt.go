@@ -0,0 +1,74 @@
+package token
+
+import (
+	"errors"
+	"time"
+
+	itoken "github.com/Efamamo/GoCrudChallange/application/common/interface/token"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// JWTIssuerConfig holds the key and claims used to sign access tokens.
+type JWTIssuerConfig struct {
+	HS256Secret string        // Shared secret signing the tokens
+	Issuer      string        // "iss" claim of the tokens; omitted when empty
+	Audience    string        // "aud" claim of the tokens; omitted when empty
+	TenantClaim string        // Claim naming the tenant of the subject; tenant.Claim when empty
+	TTL         time.Duration // Lifetime of the tokens
+}
+
+// JWTIssuer signs HS256 access tokens, validated by the authentication middleware.
+type JWTIssuer struct {
+	config JWTIssuerConfig
+}
+
+// Compile-time check to ensure JWTIssuer implements IIssuer.
+var _ itoken.IIssuer = &JWTIssuer{}
+
+// NewJWTIssuer creates a new JWTIssuer with the given configuration.
+func NewJWTIssuer(config JWTIssuerConfig) *JWTIssuer {
+	return &JWTIssuer{config: config}
+}
+
+// Issue signs an access token carrying the subject, tenant and roles of the caller.
+func (i *JWTIssuer) Issue(subject string, tenantID string, roles []string) (*itoken.AccessToken, error) {
+	if i.config.HS256Secret == "" {
+		return nil, errors.New("token signing is not configured")
+	}
+
+	now := time.Now()
+	access := &itoken.AccessToken{
+		ID:        uuid.NewString(),
+		ExpiresAt: now.Add(i.config.TTL),
+	}
+
+	claims := jwt.MapClaims{
+		"sub":   subject,
+		"jti":   access.ID,
+		"iat":   now.Unix(),
+		"exp":   access.ExpiresAt.Unix(),
+		"roles": roles,
+	}
+	if tenantID != "" {
+		claim := i.config.TenantClaim
+		if claim == "" {
+			claim = tenant.Claim
+		}
+		claims[claim] = tenantID
+	}
+	if i.config.Issuer != "" {
+		claims["iss"] = i.config.Issuer
+	}
+	if i.config.Audience != "" {
+		claims["aud"] = i.config.Audience
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(i.config.HS256Secret))
+	if err != nil {
+		return nil, err
+	}
+	access.Token = signed
+	return access, nil
+}
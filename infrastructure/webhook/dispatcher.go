@@ -0,0 +1,291 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
+	irepo "github.com/Efamamo/GoCrudChallange/application/common/interface/repository"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/webhook"
+	"github.com/google/uuid"
+)
+
+// Headers of the deliveries, which receivers use to deduplicate and authenticate them.
+const (
+	DeliveryHeader  = "X-Webhook-Delivery"  // ID of the delivery, the same across its attempts
+	EventHeader     = "X-Webhook-Event"     // Type of the delivered event, e.g. person.created
+	TimestampHeader = "X-Webhook-Timestamp" // Unix time of the attempt, covered by the signature
+	SignatureHeader = "X-Webhook-Signature" // "sha256=" followed by the signature of the attempt
+)
+
+// Defaults of the DispatcherConfig.
+const (
+	defaultMaxAttempts = 8
+	defaultBackoff     = 30 * time.Second
+	defaultMaxBackoff  = time.Hour
+	defaultInterval    = time.Second
+	defaultTimeout     = 10 * time.Second
+	defaultConcurrency = 8
+)
+
+// DispatcherConfig holds configuration settings for creating a new Dispatcher.
+type DispatcherConfig struct {
+	Webhooks    irepo.IWebhook
+	Deliveries  irepo.IDelivery
+	Client      *http.Client  // Client posting the deliveries; one with a 10 seconds timeout when nil
+	MaxAttempts int           // Attempts of a delivery before it is dead-lettered; 8 when zero
+	Backoff     time.Duration // Delay before the first retry, doubled after every failed attempt; 30 seconds when zero
+	MaxBackoff  time.Duration // Maximum delay between two attempts; an hour when zero
+	Interval    time.Duration // Time between two looks at the retry queue; a second when zero
+	Concurrency int           // Webhooks posted to at the same time; 8 when zero
+}
+
+// Dispatcher delivers the changes of people to the webhooks subscribed to them. Every change is queued
+// as a delivery per webhook, posted right away and retried with an exponential backoff until the
+// receiver acknowledges it, or until it runs out of attempts and is dead-lettered.
+//
+// Each webhook gets its deliveries posted in order, one at a time, while up to Concurrency webhooks are
+// posted to at the same time, so that a slow receiver doesn't hold up the deliveries of the others.
+type Dispatcher struct {
+	config DispatcherConfig
+	wake   chan struct{} // Signals that deliveries were queued, so they don't wait for the next look at the queue
+	slots  chan struct{} // Holds a token per webhook being posted to
+
+	mutex  sync.Mutex
+	active map[uuid.UUID]struct{} // Webhooks being posted to
+}
+
+// NewDispatcher creates and returns a new instance of Dispatcher, filling in the defaults of the configuration.
+func NewDispatcher(config DispatcherConfig) *Dispatcher {
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: defaultTimeout}
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = defaultBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaultConcurrency
+	}
+	return &Dispatcher{
+		config: config,
+		wake:   make(chan struct{}, 1),
+		slots:  make(chan struct{}, config.Concurrency),
+		active: make(map[uuid.UUID]struct{}),
+	}
+}
+
+// Start subscribes to the broker, then queues the deliveries of the events it publishes, and posts them,
+// in the background until the context is done.
+func (d *Dispatcher) Start(ctx context.Context, broker ievents.IBroker) {
+	events := broker.Subscribe(ctx, 0)
+	go d.queue(ctx, broker, events)
+	go d.deliver(ctx)
+}
+
+// queue queues the deliveries of the events until the context is done. When the broker drops the
+// dispatcher for falling behind, it subscribes again after the last event it queued.
+func (d *Dispatcher) queue(ctx context.Context, broker ievents.IBroker, events <-chan ievents.PersonEvent) {
+	var last uint64
+	for {
+		for event := range events {
+			last = event.ID
+			d.enqueue(event)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		events = broker.Subscribe(ctx, last)
+	}
+}
+
+// enqueue queues a delivery of the event for every webhook subscribed to its type.
+func (d *Dispatcher) enqueue(event ievents.PersonEvent) {
+	hooks, err := d.config.Webhooks.GetAll()
+	if err != nil {
+		log.Printf("webhooks: failed to list webhooks: %s", err.Error())
+		return
+	}
+
+	var body []byte
+	queued := false
+	for _, hook := range hooks {
+		if !hook.Subscribes(string(event.Type)) {
+			continue
+		}
+		if body == nil {
+			var merr error
+			if body, merr = json.Marshal(newPayload(event)); merr != nil {
+				log.Printf("webhooks: failed to encode event %d: %s", event.ID, merr.Error())
+				return
+			}
+		}
+
+		if err := d.config.Deliveries.Save(model.NewDelivery(hook.Id(), string(event.Type), event.ID, body)); err != nil {
+			log.Printf("webhooks: failed to queue event %d for webhook %s: %s", event.ID, hook.Id(), err.Error())
+			continue
+		}
+		queued = true
+	}
+
+	if queued {
+		d.signal()
+	}
+}
+
+// signal wakes the delivery loop up, unless it is already about to look at the queue.
+func (d *Dispatcher) signal() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// deliver hands the deliveries that are due over to the workers posting to their webhook, whenever some are
+// queued and at every interval, until the context is done.
+func (d *Dispatcher) deliver(ctx context.Context) {
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+
+		due, err := d.config.Deliveries.GetDue(time.Now().UTC())
+		if err != nil {
+			log.Printf("webhooks: failed to list due deliveries: %s", err.Error())
+			continue
+		}
+
+		// Group the deliveries by webhook, keeping the order they are due in.
+		order := make([]uuid.UUID, 0)
+		byWebhook := make(map[uuid.UUID][]*model.Delivery)
+		for _, delivery := range due {
+			id := delivery.WebhookID()
+			if _, ok := byWebhook[id]; !ok {
+				order = append(order, id)
+			}
+			byWebhook[id] = append(byWebhook[id], delivery)
+		}
+
+		for _, id := range order {
+			if ctx.Err() != nil {
+				return
+			}
+			if !d.acquire(id) {
+				continue // The webhook is being posted to, or every worker is busy; left for the next look
+			}
+			go d.work(ctx, id, byWebhook[id])
+		}
+	}
+}
+
+// acquire reserves a worker for the webhook, unless one is already posting to it or all of them are busy.
+func (d *Dispatcher) acquire(id uuid.UUID) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, ok := d.active[id]; ok {
+		return false
+	}
+	select {
+	case d.slots <- struct{}{}:
+	default:
+		return false
+	}
+	d.active[id] = struct{}{}
+	return true
+}
+
+// work attempts the deliveries of a webhook in order, then frees its worker and wakes the delivery loop up,
+// so that the deliveries left for lack of workers don't wait for the next interval.
+func (d *Dispatcher) work(ctx context.Context, id uuid.UUID, deliveries []*model.Delivery) {
+	defer func() {
+		d.mutex.Lock()
+		delete(d.active, id)
+		<-d.slots
+		d.mutex.Unlock()
+		d.signal()
+	}()
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return
+		}
+		d.attempt(ctx, delivery)
+	}
+}
+
+// attempt posts the delivery to its webhook, and records the outcome in the delivery log.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *model.Delivery) {
+	hook, err := d.config.Webhooks.Get(delivery.WebhookID())
+	if err != nil {
+		if err.Type() != ierr.NotFound {
+			return
+		}
+		delivery.DeadLetter("webhook was deleted")
+	} else {
+		statusCode, reason := d.post(ctx, hook, delivery)
+		now := time.Now().UTC()
+		if reason == "" {
+			delivery.Succeed(statusCode, now)
+		} else {
+			delivery.Fail(statusCode, reason, now, d.config.MaxAttempts, d.config.Backoff, d.config.MaxBackoff)
+		}
+	}
+
+	if err := d.config.Deliveries.Save(delivery); err != nil {
+		log.Printf("webhooks: failed to save delivery %s: %s", delivery.Id(), err.Error())
+	}
+}
+
+// post sends the payload of the delivery to the URL of the webhook, signed with its secret.
+// It returns the status code of the response, or zero when there was none, and the reason the
+// attempt failed, which is empty when the receiver responded with a 2xx status.
+func (d *Dispatcher) post(ctx context.Context, hook *model.Webhook, delivery *model.Delivery) (int, string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL(), bytes.NewReader(delivery.Payload()))
+	if err != nil {
+		return 0, err.Error()
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GoCrudChallange-Webhooks")
+	req.Header.Set(DeliveryHeader, delivery.Id().String())
+	req.Header.Set(EventHeader, delivery.Event())
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(SignatureHeader, "sha256="+model.Sign(hook.Secret(), now, delivery.Payload()))
+
+	resp, err := d.config.Client.Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Sprintf("receiver responded with %s", resp.Status)
+	}
+	return resp.StatusCode, ""
+}
@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"time"
+
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/google/uuid"
+)
+
+// payload is the JSON body posted to webhooks, describing a change of a person.
+type payload struct {
+	ID         uint64        `json:"id"`          // Sequence number of the event
+	Type       string        `json:"type"`        // Kind of change, e.g. person.created
+	Tenant     string        `json:"tenant"`      // Tenant the person belongs to
+	OccurredAt time.Time     `json:"occurred_at"` // Time the change was saved
+	Person     personPayload `json:"person"`      // The person after the change, or before their deletion
+}
+
+// personPayload describes a person in the payload of a delivery, like the v2 API does.
+type personPayload struct {
+	ID         uuid.UUID       `json:"id"`
+	Name       string          `json:"name"`
+	Age        int16           `json:"age"`
+	Hobbies    []string        `json:"hobbies"`
+	Email      string          `json:"email,omitempty"`
+	Phone      string          `json:"phone,omitempty"`
+	Address    *addressPayload `json:"address,omitempty"`
+	Attributes map[string]any  `json:"attributes,omitempty"`
+	Owner      string          `json:"owner,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// addressPayload describes the postal address of a person in the payload of a delivery.
+type addressPayload struct {
+	Street     string `json:"street"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country"`
+}
+
+// newPayload maps a PersonEvent to the payload of its deliveries.
+func newPayload(event ievents.PersonEvent) payload {
+	p := event.Person
+	body := payload{
+		ID:         event.ID,
+		Type:       string(event.Type),
+		Tenant:     event.Tenant,
+		OccurredAt: event.OccurredAt,
+		Person: personPayload{
+			ID:         p.Id(),
+			Name:       p.Name(),
+			Age:        p.Age(),
+			Hobbies:    p.Hobbies(),
+			Email:      p.Email().String(),
+			Phone:      p.Phone().String(),
+			Attributes: p.Attributes(),
+			Owner:      p.Owner(),
+			CreatedAt:  p.CreatedAt(),
+			UpdatedAt:  p.UpdatedAt(),
+		},
+	}
+	if address := p.Address(); address != nil {
+		body.Person.Address = newAddressPayload(address)
+	}
+	return body
+}
+
+// newAddressPayload maps an Address to its addressPayload.
+func newAddressPayload(a *model.Address) *addressPayload {
+	return &addressPayload{
+		Street:     a.Street(),
+		City:       a.City(),
+		Region:     a.Region(),
+		PostalCode: a.PostalCode(),
+		Country:    a.Country(),
+	}
+}
@@ -0,0 +1,130 @@
+package mocks
+
+import (
+	"sync"
+	"time"
+
+	apperror "github.com/Efamamo/GoCrudChallange/application/error"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/apikey"
+	"github.com/google/uuid"
+)
+
+// MockAPIKeyRepo is a mock implementation of the IAPIKey repository interface.
+type MockAPIKeyRepo struct {
+	mutex         sync.RWMutex
+	keys          map[uuid.UUID]*apikey.APIKey
+	SaveFunc      func(key *apikey.APIKey) ierr.IErr
+	GetFunc       func(id uuid.UUID) (*apikey.APIKey, ierr.IErr)
+	GetByHashFunc func(hash string) (*apikey.APIKey, ierr.IErr)
+	TouchFunc     func(id uuid.UUID, at time.Time) ierr.IErr
+	DeleteFunc    func(id uuid.UUID) ierr.IErr
+	GetAllFunc    func() ([]*apikey.APIKey, ierr.IErr)
+}
+
+// NewMockAPIKeyRepo creates a new instance of MockAPIKeyRepo with default behavior.
+func NewMockAPIKeyRepo() *MockAPIKeyRepo {
+	return &MockAPIKeyRepo{
+		keys: make(map[uuid.UUID]*apikey.APIKey),
+	}
+}
+
+// Save mocks saving an API key to the repository.
+func (m *MockAPIKeyRepo) Save(key *apikey.APIKey) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.SaveFunc != nil {
+		return m.SaveFunc(key)
+	}
+
+	if key == nil {
+		return ierr.NewValidation("api key can't be empty")
+	}
+
+	m.keys[key.Id()] = key
+	return nil
+}
+
+// Get mocks retrieving an API key by ID.
+func (m *MockAPIKeyRepo) Get(id uuid.UUID) (*apikey.APIKey, ierr.IErr) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.GetFunc != nil {
+		return m.GetFunc(id)
+	}
+
+	if key, found := m.keys[id]; found {
+		return key, nil
+	}
+	return nil, ierr.NewNotFound("api key not found")
+}
+
+// GetByHash mocks retrieving an API key by the hash of its secret.
+func (m *MockAPIKeyRepo) GetByHash(hash string) (*apikey.APIKey, ierr.IErr) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.GetByHashFunc != nil {
+		return m.GetByHashFunc(hash)
+	}
+
+	for _, key := range m.keys {
+		if key.Hash() == hash {
+			return key, nil
+		}
+	}
+	return nil, ierr.NewNotFound("api key not found")
+}
+
+// Touch mocks recording the last use of an API key.
+func (m *MockAPIKeyRepo) Touch(id uuid.UUID, at time.Time) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.TouchFunc != nil {
+		return m.TouchFunc(id, at)
+	}
+
+	if key, found := m.keys[id]; found {
+		if !key.Usable(at) {
+			return apperror.InvalidCredential("invalid api key")
+		}
+		key.MarkUsed(at)
+		return nil
+	}
+	return ierr.NewNotFound("api key not found")
+}
+
+// Delete mocks removing an API key by ID.
+func (m *MockAPIKeyRepo) Delete(id uuid.UUID) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+
+	if _, found := m.keys[id]; found {
+		delete(m.keys, id)
+		return nil
+	}
+	return ierr.NewNotFound("api key not found")
+}
+
+// GetAll mocks retrieving every API key.
+func (m *MockAPIKeyRepo) GetAll() ([]*apikey.APIKey, ierr.IErr) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.GetAllFunc != nil {
+		return m.GetAllFunc()
+	}
+
+	var keys []*apikey.APIKey
+	for _, key := range m.keys {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,89 @@
+package mocks
+
+import (
+	"sync"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/attribute"
+)
+
+// MockAttributeRepo is a mock implementation of the IAttribute repository interface.
+type MockAttributeRepo struct {
+	mutex       sync.RWMutex
+	definitions map[string]*attribute.Definition
+	SaveFunc    func(definition *attribute.Definition) ierr.IErr
+	GetFunc     func(name string) (*attribute.Definition, ierr.IErr)
+	DeleteFunc  func(name string) ierr.IErr
+	GetAllFunc  func() ([]*attribute.Definition, ierr.IErr)
+}
+
+// NewMockAttributeRepo creates a new instance of MockAttributeRepo with default behavior.
+func NewMockAttributeRepo() *MockAttributeRepo {
+	return &MockAttributeRepo{
+		definitions: make(map[string]*attribute.Definition),
+	}
+}
+
+// Save mocks saving an attribute definition to the repository.
+func (m *MockAttributeRepo) Save(definition *attribute.Definition) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.SaveFunc != nil {
+		return m.SaveFunc(definition)
+	}
+
+	if definition == nil {
+		return ierr.NewValidation("attribute can't be empty")
+	}
+
+	m.definitions[definition.Name()] = definition
+	return nil
+}
+
+// Get mocks retrieving an attribute definition by name.
+func (m *MockAttributeRepo) Get(name string) (*attribute.Definition, ierr.IErr) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.GetFunc != nil {
+		return m.GetFunc(name)
+	}
+
+	if definition, found := m.definitions[name]; found {
+		return definition, nil
+	}
+	return nil, ierr.NewNotFound("attribute not found")
+}
+
+// Delete mocks removing an attribute definition by name.
+func (m *MockAttributeRepo) Delete(name string) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(name)
+	}
+
+	if _, found := m.definitions[name]; found {
+		delete(m.definitions, name)
+		return nil
+	}
+	return ierr.NewNotFound("attribute not found")
+}
+
+// GetAll mocks retrieving every attribute definition.
+func (m *MockAttributeRepo) GetAll() ([]*attribute.Definition, ierr.IErr) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.GetAllFunc != nil {
+		return m.GetAllFunc()
+	}
+
+	var definitions []*attribute.Definition
+	for _, d := range m.definitions {
+		definitions = append(definitions, d)
+	}
+	return definitions, nil
+}
@@ -1,90 +1,275 @@
 package mocks
 
 import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
 	"sync"
 
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	apperror "github.com/Efamamo/GoCrudChallange/application/error"
 	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
 	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
 	"github.com/google/uuid"
 )
 
 // MockPersonRepo is a mock implementation of the IPerson repository interface.
+// Like the real repository, it keeps the people of each tenant apart.
 type MockPersonRepo struct {
-	mutex      sync.RWMutex
-	people     map[uuid.UUID]*model.Person
-	SaveFunc   func(person *model.Person) ierr.IErr
-	GetFunc    func(id uuid.UUID) (*model.Person, ierr.IErr)
-	DeleteFunc func(id uuid.UUID) ierr.IErr
-	GetAllFunc func() ([]*model.Person, ierr.IErr)
+	mutex        sync.RWMutex
+	people       map[string]map[uuid.UUID]*model.Person
+	redirects    map[string]map[uuid.UUID]uuid.UUID
+	merges       map[string][]*model.Merge
+	Policy       model.UniquenessPolicy
+	Quotas       tenant.Quotas
+	SaveFunc     func(ctx context.Context, person *model.Person) ierr.IErr
+	CreateFunc   func(ctx context.Context, person *model.Person) ierr.IErr
+	GetFunc      func(ctx context.Context, id uuid.UUID) (*model.Person, ierr.IErr)
+	DeleteFunc   func(ctx context.Context, id uuid.UUID) ierr.IErr
+	GetAllFunc   func(ctx context.Context) ([]*model.Person, ierr.IErr)
+	StreamFunc   func(ctx context.Context, yield func(*model.Person) bool) ierr.IErr
+	TenantsFunc  func(ctx context.Context) ([]string, ierr.IErr)
+	MergeFunc    func(ctx context.Context, survivor *model.Person, merge *model.Merge) ierr.IErr
+	RedirectFunc func(ctx context.Context, id uuid.UUID) (uuid.UUID, ierr.IErr)
+	MergesFunc   func(ctx context.Context, id uuid.UUID) ([]*model.Merge, ierr.IErr)
 }
 
 // NewMockPersonRepo creates a new instance of MockPersonRepo with default behavior.
 func NewMockPersonRepo() *MockPersonRepo {
 	return &MockPersonRepo{
-		people: make(map[uuid.UUID]*model.Person),
+		people:    make(map[string]map[uuid.UUID]*model.Person),
+		redirects: make(map[string]map[uuid.UUID]uuid.UUID),
+		merges:    make(map[string][]*model.Merge),
 	}
 }
 
+// partition returns the people and redirects of the tenant carried by the context.
+func (m *MockPersonRepo) partition(ctx context.Context) (map[uuid.UUID]*model.Person, map[uuid.UUID]uuid.UUID) {
+	id := tenant.From(ctx)
+	if _, ok := m.people[id]; !ok {
+		m.people[id] = make(map[uuid.UUID]*model.Person)
+		m.redirects[id] = make(map[uuid.UUID]uuid.UUID)
+	}
+	return m.people[id], m.redirects[id]
+}
+
 // Save mocks saving a person to the repository.
-func (m *MockPersonRepo) Save(p *model.Person) ierr.IErr {
+func (m *MockPersonRepo) Save(ctx context.Context, p *model.Person) ierr.IErr {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
 	if m.SaveFunc != nil {
-		return m.SaveFunc(p)
+		return m.SaveFunc(ctx, p)
+	}
+
+	return m.save(ctx, p)
+}
+
+// Create mocks adding a new person, failing when a person, current or retired, already holds the ID.
+func (m *MockPersonRepo) Create(ctx context.Context, p *model.Person) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, p)
 	}
 
 	if p == nil {
 		return ierr.NewValidation("person can't be empty")
 	}
 
-	m.people[p.Id()] = p
+	people, redirects := m.partition(ctx)
+	if _, found := people[p.Id()]; found {
+		return ierr.NewConflictWith(fmt.Sprintf("person with id %s already exists", p.Id()), p.Id().String())
+	}
+	if to, found := redirects[p.Id()]; found {
+		return ierr.NewConflictWith(fmt.Sprintf("person with id %s was merged into another person", p.Id()), to.String())
+	}
+
+	return m.save(ctx, p)
+}
+
+// save stores the person after the uniqueness and quota checks; the caller holds the lock.
+func (m *MockPersonRepo) save(ctx context.Context, p *model.Person) ierr.IErr {
+	if p == nil {
+		return ierr.NewValidation("person can't be empty")
+	}
+
+	people, _ := m.partition(ctx)
+	key, unique := m.Policy.Key(p)
+	for id, existing := range people {
+		if id == p.Id() {
+			continue
+		}
+		if !p.Email().IsZero() && existing.Email() == p.Email() {
+			return ierr.NewConflictWith("email is already in use", id.String())
+		}
+		if existingKey, _ := m.Policy.Key(existing); unique && existingKey == key {
+			return ierr.NewConflictWith("person already exists", id.String())
+		}
+	}
+
+	if _, found := people[p.Id()]; !found {
+		if limit := m.Quotas.Limit(tenant.From(ctx)); limit > 0 && len(people) >= limit {
+			return apperror.QuotaReached(fmt.Sprintf("tenant can't store more than %d people", limit))
+		}
+	}
+
+	people[p.Id()] = p
 	return nil
 }
 
 // Get mocks retrieving a person by ID.
-func (m *MockPersonRepo) Get(id uuid.UUID) (*model.Person, ierr.IErr) {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
+func (m *MockPersonRepo) Get(ctx context.Context, id uuid.UUID) (*model.Person, ierr.IErr) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 
 	if m.GetFunc != nil {
-		return m.GetFunc(id)
+		return m.GetFunc(ctx, id)
 	}
 
-	if person, found := m.people[id]; found {
+	people, _ := m.partition(ctx)
+	if person, found := people[id]; found {
 		return person, nil
 	}
 	return nil, ierr.NewNotFound("person not found")
 }
 
 // Delete mocks removing a person from the repository by ID.
-func (m *MockPersonRepo) Delete(id uuid.UUID) ierr.IErr {
+func (m *MockPersonRepo) Delete(ctx context.Context, id uuid.UUID) ierr.IErr {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
 	if m.DeleteFunc != nil {
-		return m.DeleteFunc(id)
+		return m.DeleteFunc(ctx, id)
 	}
 
-	if _, found := m.people[id]; found {
-		delete(m.people, id)
+	people, _ := m.partition(ctx)
+	if _, found := people[id]; found {
+		delete(people, id)
 		return nil
 	}
 	return ierr.NewNotFound("person not found")
 }
 
 // GetAll mocks retrieving all persons in the repository.
-func (m *MockPersonRepo) GetAll() ([]*model.Person, ierr.IErr) {
+func (m *MockPersonRepo) GetAll(ctx context.Context) ([]*model.Person, ierr.IErr) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.GetAllFunc != nil {
+		return m.GetAllFunc(ctx)
+	}
+
+	people, _ := m.partition(ctx)
+	var all []*model.Person
+	for _, p := range people {
+		all = append(all, p)
+	}
+	return all, nil
+}
+
+// Stream mocks calling yield with every person in the repository, in no particular order.
+// The people are listed under the lock, which is released before yielding them.
+func (m *MockPersonRepo) Stream(ctx context.Context, yield func(*model.Person) bool) ierr.IErr {
+	if m.StreamFunc != nil {
+		return m.StreamFunc(ctx, yield)
+	}
+
+	all, err := m.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range all {
+		if ctx.Err() != nil || !yield(p) {
+			break
+		}
+	}
+	return nil
+}
+
+// Tenants mocks listing the tenants that stored people, in alphabetical order.
+func (m *MockPersonRepo) Tenants(ctx context.Context) ([]string, ierr.IErr) {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
-	if m.GetAllFunc != nil {
-		return m.GetAllFunc()
+	if m.TenantsFunc != nil {
+		return m.TenantsFunc(ctx)
+	}
+
+	tenants := make([]string, 0, len(m.people))
+	for id := range m.people {
+		tenants = append(tenants, id)
+	}
+	sort.Strings(tenants)
+	return tenants, nil
+}
+
+// Merge mocks atomically merging people into a survivor.
+func (m *MockPersonRepo) Merge(ctx context.Context, survivor *model.Person, merge *model.Merge) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.MergeFunc != nil {
+		return m.MergeFunc(ctx, survivor, merge)
+	}
+
+	people, redirects := m.partition(ctx)
+	if _, found := people[survivor.Id()]; !found {
+		return ierr.NewNotFound("person not found")
+	}
+	for _, id := range merge.RetiredIDs() {
+		if _, found := people[id]; !found {
+			return ierr.NewNotFound("person not found")
+		}
+	}
+
+	people[survivor.Id()] = survivor
+	for _, id := range merge.RetiredIDs() {
+		delete(people, id)
+		redirects[id] = survivor.Id()
+	}
+	for from, to := range redirects {
+		if slices.Contains(merge.RetiredIDs(), to) {
+			redirects[from] = survivor.Id()
+		}
+	}
+	m.merges[tenant.From(ctx)] = append(m.merges[tenant.From(ctx)], merge)
+	return nil
+}
+
+// Redirect mocks looking up the survivor a retired ID was merged into.
+func (m *MockPersonRepo) Redirect(ctx context.Context, id uuid.UUID) (uuid.UUID, ierr.IErr) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.RedirectFunc != nil {
+		return m.RedirectFunc(ctx, id)
+	}
+
+	_, redirects := m.partition(ctx)
+	if to, found := redirects[id]; found {
+		return to, nil
+	}
+	return uuid.Nil, ierr.NewNotFound("redirect not found")
+}
+
+// Merges mocks listing the merges that folded people into a survivor.
+func (m *MockPersonRepo) Merges(ctx context.Context, id uuid.UUID) ([]*model.Merge, ierr.IErr) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.MergesFunc != nil {
+		return m.MergesFunc(ctx, id)
 	}
 
-	var people []*model.Person
-	for _, p := range m.people {
-		people = append(people, p)
+	_, redirects := m.partition(ctx)
+	merges := make([]*model.Merge, 0)
+	for _, merge := range m.merges[tenant.From(ctx)] {
+		if merge.SurvivorID() == id || redirects[merge.SurvivorID()] == id {
+			merges = append(merges, merge)
+		}
 	}
-	return people, nil
+	return merges, nil
 }
@@ -0,0 +1,154 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/relation"
+	"github.com/google/uuid"
+)
+
+// MockRelationRepo is a mock implementation of the IRelation repository interface.
+// Like the real repository, it keeps the relations of each tenant apart.
+type MockRelationRepo struct {
+	mutex              sync.RWMutex
+	relations          map[string]map[uuid.UUID]*relation.Relation
+	SaveFunc           func(ctx context.Context, rel *relation.Relation) ierr.IErr
+	GetFunc            func(ctx context.Context, id uuid.UUID) (*relation.Relation, ierr.IErr)
+	DeleteFunc         func(ctx context.Context, id uuid.UUID) ierr.IErr
+	GetByPersonFunc    func(ctx context.Context, personID uuid.UUID) ([]*relation.Relation, ierr.IErr)
+	DeleteByPersonFunc func(ctx context.Context, personID uuid.UUID) ierr.IErr
+	ReassignFunc       func(ctx context.Context, from uuid.UUID, to uuid.UUID) ierr.IErr
+}
+
+// NewMockRelationRepo creates a new instance of MockRelationRepo with default behavior.
+func NewMockRelationRepo() *MockRelationRepo {
+	return &MockRelationRepo{
+		relations: make(map[string]map[uuid.UUID]*relation.Relation),
+	}
+}
+
+// partition returns the relations of the tenant carried by the context.
+func (m *MockRelationRepo) partition(ctx context.Context) map[uuid.UUID]*relation.Relation {
+	id := tenant.From(ctx)
+	if _, ok := m.relations[id]; !ok {
+		m.relations[id] = make(map[uuid.UUID]*relation.Relation)
+	}
+	return m.relations[id]
+}
+
+// Save mocks saving a relation to the repository.
+func (m *MockRelationRepo) Save(ctx context.Context, rel *relation.Relation) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.SaveFunc != nil {
+		return m.SaveFunc(ctx, rel)
+	}
+
+	if rel == nil {
+		return ierr.NewValidation("relation can't be empty")
+	}
+
+	relations := m.partition(ctx)
+	for _, existing := range relations {
+		if existing.Id() != rel.Id() && existing.SameAs(rel) {
+			return ierr.NewConflict("relation already exists")
+		}
+	}
+
+	relations[rel.Id()] = rel
+	return nil
+}
+
+// Get mocks retrieving a relation by ID.
+func (m *MockRelationRepo) Get(ctx context.Context, id uuid.UUID) (*relation.Relation, ierr.IErr) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, id)
+	}
+
+	if rel, found := m.partition(ctx)[id]; found {
+		return rel, nil
+	}
+	return nil, ierr.NewNotFound("relation not found")
+}
+
+// Delete mocks removing a relation from the repository by ID.
+func (m *MockRelationRepo) Delete(ctx context.Context, id uuid.UUID) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+
+	relations := m.partition(ctx)
+	if _, found := relations[id]; found {
+		delete(relations, id)
+		return nil
+	}
+	return ierr.NewNotFound("relation not found")
+}
+
+// GetByPerson mocks retrieving every relation involving a person.
+func (m *MockRelationRepo) GetByPerson(ctx context.Context, personID uuid.UUID) ([]*relation.Relation, ierr.IErr) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.GetByPersonFunc != nil {
+		return m.GetByPersonFunc(ctx, personID)
+	}
+
+	var relations []*relation.Relation
+	for _, rel := range m.partition(ctx) {
+		if rel.Involves(personID) {
+			relations = append(relations, rel)
+		}
+	}
+	return relations, nil
+}
+
+// DeleteByPerson mocks removing every relation involving a person.
+func (m *MockRelationRepo) DeleteByPerson(ctx context.Context, personID uuid.UUID) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.DeleteByPersonFunc != nil {
+		return m.DeleteByPersonFunc(ctx, personID)
+	}
+
+	relations := m.partition(ctx)
+	for id, rel := range relations {
+		if rel.Involves(personID) {
+			delete(relations, id)
+		}
+	}
+	return nil
+}
+
+// Reassign mocks moving every relation of a person over to another one.
+func (m *MockRelationRepo) Reassign(ctx context.Context, from uuid.UUID, to uuid.UUID) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.ReassignFunc != nil {
+		return m.ReassignFunc(ctx, from, to)
+	}
+
+	relations := m.partition(ctx)
+	for id, rel := range relations {
+		if !rel.Involves(from) {
+			continue
+		}
+		rel.Reassign(from, to)
+		if rel.From() == rel.To() {
+			delete(relations, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package mocks
+
+import (
+	"sync"
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/user"
+	"github.com/google/uuid"
+)
+
+// MockTokenRepo is a mock implementation of the IToken repository interface.
+type MockTokenRepo struct {
+	mutex                    sync.RWMutex
+	refresh                  map[uuid.UUID]*user.RefreshToken
+	revoked                  map[string]time.Time
+	SaveRefreshTokenFunc     func(token *user.RefreshToken) ierr.IErr
+	GetRefreshTokenFunc      func(hash string) (*user.RefreshToken, ierr.IErr)
+	DeleteRefreshTokenFunc   func(id uuid.UUID) ierr.IErr
+	RevokeAccessTokenFunc    func(id string, expiresAt time.Time) ierr.IErr
+	IsAccessTokenRevokedFunc func(id string) (bool, ierr.IErr)
+}
+
+// NewMockTokenRepo creates a new instance of MockTokenRepo with default behavior.
+func NewMockTokenRepo() *MockTokenRepo {
+	return &MockTokenRepo{
+		refresh: make(map[uuid.UUID]*user.RefreshToken),
+		revoked: make(map[string]time.Time),
+	}
+}
+
+// SaveRefreshToken mocks saving a refresh token.
+func (m *MockTokenRepo) SaveRefreshToken(token *user.RefreshToken) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.SaveRefreshTokenFunc != nil {
+		return m.SaveRefreshTokenFunc(token)
+	}
+
+	m.refresh[token.Id()] = token
+	return nil
+}
+
+// GetRefreshToken mocks retrieving a refresh token by the hash of its secret.
+func (m *MockTokenRepo) GetRefreshToken(hash string) (*user.RefreshToken, ierr.IErr) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.GetRefreshTokenFunc != nil {
+		return m.GetRefreshTokenFunc(hash)
+	}
+
+	for _, token := range m.refresh {
+		if token.Hash() == hash {
+			return token, nil
+		}
+	}
+	return nil, ierr.NewNotFound("refresh token not found")
+}
+
+// DeleteRefreshToken mocks removing a refresh token by ID.
+func (m *MockTokenRepo) DeleteRefreshToken(id uuid.UUID) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.DeleteRefreshTokenFunc != nil {
+		return m.DeleteRefreshTokenFunc(id)
+	}
+
+	if _, found := m.refresh[id]; found {
+		delete(m.refresh, id)
+		return nil
+	}
+	return ierr.NewNotFound("refresh token not found")
+}
+
+// DeleteRefreshTokensOf mocks removing every refresh token of a user.
+func (m *MockTokenRepo) DeleteRefreshTokensOf(userID uuid.UUID) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for id, token := range m.refresh {
+		if token.UserID() == userID {
+			delete(m.refresh, id)
+		}
+	}
+	return nil
+}
+
+// RevokeAccessToken mocks revoking an access token.
+func (m *MockTokenRepo) RevokeAccessToken(id string, expiresAt time.Time) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.RevokeAccessTokenFunc != nil {
+		return m.RevokeAccessTokenFunc(id, expiresAt)
+	}
+
+	m.revoked[id] = expiresAt
+	return nil
+}
+
+// IsAccessTokenRevoked mocks checking whether an access token was revoked.
+func (m *MockTokenRepo) IsAccessTokenRevoked(id string) (bool, ierr.IErr) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.IsAccessTokenRevokedFunc != nil {
+		return m.IsAccessTokenRevokedFunc(id)
+	}
+
+	_, ok := m.revoked[id]
+	return ok, nil
+}
@@ -0,0 +1,114 @@
+package mocks
+
+import (
+	"sync"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/user"
+	"github.com/google/uuid"
+)
+
+// MockUserRepo is a mock implementation of the IUser repository interface.
+type MockUserRepo struct {
+	mutex             sync.RWMutex
+	users             map[uuid.UUID]*user.User
+	SaveFunc          func(u *user.User) ierr.IErr
+	GetFunc           func(id uuid.UUID) (*user.User, ierr.IErr)
+	GetByUsernameFunc func(username string) (*user.User, ierr.IErr)
+	DeleteFunc        func(id uuid.UUID) ierr.IErr
+	GetAllFunc        func() ([]*user.User, ierr.IErr)
+}
+
+// NewMockUserRepo creates a new instance of MockUserRepo with default behavior.
+func NewMockUserRepo() *MockUserRepo {
+	return &MockUserRepo{
+		users: make(map[uuid.UUID]*user.User),
+	}
+}
+
+// Save mocks saving a user to the repository, rejecting taken usernames.
+func (m *MockUserRepo) Save(u *user.User) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.SaveFunc != nil {
+		return m.SaveFunc(u)
+	}
+
+	if u == nil {
+		return ierr.NewValidation("user can't be empty")
+	}
+
+	for id, existing := range m.users {
+		if id != u.Id() && existing.Username() == u.Username() {
+			return ierr.NewConflictWith("username is already in use", id.String())
+		}
+	}
+
+	m.users[u.Id()] = u
+	return nil
+}
+
+// Get mocks retrieving a user by ID.
+func (m *MockUserRepo) Get(id uuid.UUID) (*user.User, ierr.IErr) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.GetFunc != nil {
+		return m.GetFunc(id)
+	}
+
+	if u, found := m.users[id]; found {
+		return u, nil
+	}
+	return nil, ierr.NewNotFound("user not found")
+}
+
+// GetByUsername mocks retrieving a user by username.
+func (m *MockUserRepo) GetByUsername(username string) (*user.User, ierr.IErr) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.GetByUsernameFunc != nil {
+		return m.GetByUsernameFunc(username)
+	}
+
+	for _, u := range m.users {
+		if u.Username() == username {
+			return u, nil
+		}
+	}
+	return nil, ierr.NewNotFound("user not found")
+}
+
+// Delete mocks removing a user by ID.
+func (m *MockUserRepo) Delete(id uuid.UUID) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+
+	if _, found := m.users[id]; found {
+		delete(m.users, id)
+		return nil
+	}
+	return ierr.NewNotFound("user not found")
+}
+
+// GetAll mocks retrieving every user.
+func (m *MockUserRepo) GetAll() ([]*user.User, ierr.IErr) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.GetAllFunc != nil {
+		return m.GetAllFunc()
+	}
+
+	var users []*user.User
+	for _, u := range m.users {
+		users = append(users, u)
+	}
+	return users, nil
+}
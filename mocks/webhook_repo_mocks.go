@@ -0,0 +1,202 @@
+package mocks
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/webhook"
+	"github.com/google/uuid"
+)
+
+// MockWebhookRepo is a mock implementation of the IWebhook repository interface.
+type MockWebhookRepo struct {
+	mutex      sync.RWMutex
+	webhooks   map[uuid.UUID]*webhook.Webhook
+	SaveFunc   func(hook *webhook.Webhook) ierr.IErr
+	GetFunc    func(id uuid.UUID) (*webhook.Webhook, ierr.IErr)
+	DeleteFunc func(id uuid.UUID) ierr.IErr
+	GetAllFunc func() ([]*webhook.Webhook, ierr.IErr)
+}
+
+// NewMockWebhookRepo creates a new instance of MockWebhookRepo with default behavior.
+func NewMockWebhookRepo() *MockWebhookRepo {
+	return &MockWebhookRepo{
+		webhooks: make(map[uuid.UUID]*webhook.Webhook),
+	}
+}
+
+// Save mocks saving a webhook to the repository.
+func (m *MockWebhookRepo) Save(hook *webhook.Webhook) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.SaveFunc != nil {
+		return m.SaveFunc(hook)
+	}
+
+	if hook == nil {
+		return ierr.NewValidation("webhook can't be empty")
+	}
+
+	m.webhooks[hook.Id()] = hook.Clone()
+	return nil
+}
+
+// Get mocks retrieving a webhook by ID.
+func (m *MockWebhookRepo) Get(id uuid.UUID) (*webhook.Webhook, ierr.IErr) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.GetFunc != nil {
+		return m.GetFunc(id)
+	}
+
+	if hook, found := m.webhooks[id]; found {
+		return hook.Clone(), nil
+	}
+	return nil, ierr.NewNotFound("webhook not found")
+}
+
+// Delete mocks deleting a webhook by ID.
+func (m *MockWebhookRepo) Delete(id uuid.UUID) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+
+	if _, found := m.webhooks[id]; !found {
+		return ierr.NewNotFound("webhook not found")
+	}
+	delete(m.webhooks, id)
+	return nil
+}
+
+// GetAll mocks retrieving every webhook, oldest first.
+func (m *MockWebhookRepo) GetAll() ([]*webhook.Webhook, ierr.IErr) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.GetAllFunc != nil {
+		return m.GetAllFunc()
+	}
+
+	hooks := make([]*webhook.Webhook, 0, len(m.webhooks))
+	for _, hook := range m.webhooks {
+		hooks = append(hooks, hook.Clone())
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].CreatedAt().Before(hooks[j].CreatedAt()) })
+	return hooks, nil
+}
+
+// MockDeliveryRepo is a mock implementation of the IDelivery repository interface.
+type MockDeliveryRepo struct {
+	mutex               sync.RWMutex
+	deliveries          map[uuid.UUID]*webhook.Delivery
+	SaveFunc            func(delivery *webhook.Delivery) ierr.IErr
+	GetByWebhookFunc    func(webhookID uuid.UUID) ([]*webhook.Delivery, ierr.IErr)
+	GetDueFunc          func(at time.Time) ([]*webhook.Delivery, ierr.IErr)
+	DeleteByWebhookFunc func(webhookID uuid.UUID) ierr.IErr
+	DeleteFinishedFunc  func(at time.Time) (int, ierr.IErr)
+}
+
+// NewMockDeliveryRepo creates a new instance of MockDeliveryRepo with default behavior.
+func NewMockDeliveryRepo() *MockDeliveryRepo {
+	return &MockDeliveryRepo{
+		deliveries: make(map[uuid.UUID]*webhook.Delivery),
+	}
+}
+
+// Save mocks saving a delivery to the repository.
+func (m *MockDeliveryRepo) Save(delivery *webhook.Delivery) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.SaveFunc != nil {
+		return m.SaveFunc(delivery)
+	}
+
+	if delivery == nil {
+		return ierr.NewValidation("delivery can't be empty")
+	}
+
+	m.deliveries[delivery.Id()] = delivery.Clone()
+	return nil
+}
+
+// GetByWebhook mocks retrieving the deliveries of a webhook, newest first.
+func (m *MockDeliveryRepo) GetByWebhook(webhookID uuid.UUID) ([]*webhook.Delivery, ierr.IErr) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.GetByWebhookFunc != nil {
+		return m.GetByWebhookFunc(webhookID)
+	}
+
+	var deliveries []*webhook.Delivery
+	for _, delivery := range m.deliveries {
+		if delivery.WebhookID() == webhookID {
+			deliveries = append(deliveries, delivery.Clone())
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt().After(deliveries[j].CreatedAt()) })
+	return deliveries, nil
+}
+
+// GetDue mocks retrieving the pending deliveries due at the given time, oldest first.
+func (m *MockDeliveryRepo) GetDue(at time.Time) ([]*webhook.Delivery, ierr.IErr) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.GetDueFunc != nil {
+		return m.GetDueFunc(at)
+	}
+
+	var deliveries []*webhook.Delivery
+	for _, delivery := range m.deliveries {
+		if delivery.Due(at) {
+			deliveries = append(deliveries, delivery.Clone())
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt().Before(deliveries[j].CreatedAt()) })
+	return deliveries, nil
+}
+
+// DeleteByWebhook mocks removing every delivery of a webhook.
+func (m *MockDeliveryRepo) DeleteByWebhook(webhookID uuid.UUID) ierr.IErr {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.DeleteByWebhookFunc != nil {
+		return m.DeleteByWebhookFunc(webhookID)
+	}
+
+	for id, delivery := range m.deliveries {
+		if delivery.WebhookID() == webhookID {
+			delete(m.deliveries, id)
+		}
+	}
+	return nil
+}
+
+// DeleteFinishedBefore mocks removing the succeeded and dead-lettered deliveries queued before the given time.
+func (m *MockDeliveryRepo) DeleteFinishedBefore(at time.Time) (int, ierr.IErr) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.DeleteFinishedFunc != nil {
+		return m.DeleteFinishedFunc(at)
+	}
+
+	deleted := 0
+	for id, delivery := range m.deliveries {
+		if delivery.Status() != webhook.Pending && delivery.CreatedAt().Before(at) {
+			delete(m.deliveries, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,231 @@
+package repo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	errapi "github.com/Efamamo/GoCrudChallange/api/error"
+	"github.com/Efamamo/GoCrudChallange/api/middleware"
+	keycmd "github.com/Efamamo/GoCrudChallange/application/apikeys/command"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/apikey"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/repository"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// APIKeyTestSuite is the test suite for API keys and their authentication.
+type APIKeyTestSuite struct {
+	suite.Suite
+	keyRepo    *mocks.MockAPIKeyRepo
+	personRepo *mocks.MockPersonRepo
+	create     *keycmd.CreateAPIKeyHandler
+	engine     *gin.Engine
+}
+
+// SetupTest builds a router authenticating callers by their API keys only.
+func (suite *APIKeyTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.keyRepo = mocks.NewMockAPIKeyRepo()
+	suite.personRepo = mocks.NewMockPersonRepo()
+	suite.create = keycmd.NewCreateAPIKeyHandler(suite.keyRepo)
+
+	authentication, err := middleware.NewAuthentication(middleware.AuthenticationConfig{
+		APIKeys: keycmd.NewAuthenticateAPIKeyHandler(suite.keyRepo),
+	})
+	suite.Require().NoError(err)
+
+	pc := controller.PersonController{
+		BaseController: controller.BaseController{Authorizer: auth.NewAuthorizer(auth.DefaultPolicy())},
+		DeleteHandler:  command.NewDeletePersonHandler(suite.personRepo, mocks.NewMockRelationRepo(), nil, nil),
+	}
+
+	suite.engine = gin.New()
+	suite.engine.DELETE("/person/:id", authentication.Handler(), pc.Delete)
+}
+
+// generate stores a new key with the given scopes and returns it along with its secret.
+func (suite *APIKeyTestSuite) generate(scopes ...string) (*apikey.APIKey, string) {
+	result, err := suite.create.Handle(&keycmd.CreateAPIKeyCommand{Name: "ci", Scopes: scopes})
+	suite.Require().Nil(err)
+	return result.Key, result.Secret
+}
+
+// delete deletes a freshly saved person with the given API key.
+func (suite *APIKeyTestSuite) delete(secret string) int {
+	person, _ := model.CreatePerson(&model.PersonConfig{Name: "John Doe", Age: 30})
+	suite.personRepo.Save(context.Background(), person)
+
+	req := httptest.NewRequest(http.MethodDelete, "/person/"+person.Id().String(), nil)
+	if secret != "" {
+		req.Header.Set(middleware.APIKeyHeader, secret)
+	}
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	return w.Code
+}
+
+// TestCreate_StoresHash tests that only the hash of a generated secret is stored.
+func (suite *APIKeyTestSuite) TestCreate_StoresHash() {
+	key, secret := suite.generate(string(auth.PersonRead))
+
+	assert.NotEmpty(suite.T(), secret)
+	assert.Equal(suite.T(), apikey.Hash(secret), key.Hash())
+	assert.NotContains(suite.T(), key.Hash(), secret)
+	assert.True(suite.T(), len(key.Prefix()) < len(secret))
+}
+
+// TestCreate_UnknownScope tests that keys can't be granted unknown permissions.
+func (suite *APIKeyTestSuite) TestCreate_UnknownScope() {
+	_, err := suite.create.Handle(&keycmd.CreateAPIKeyCommand{Name: "ci", Scopes: []string{"person:everything"}})
+
+	assert.NotNil(suite.T(), err)
+}
+
+// TestAuthenticate_Scopes tests that the scopes of a key decide what it may do.
+func (suite *APIKeyTestSuite) TestAuthenticate_Scopes() {
+	_, reader := suite.generate(string(auth.PersonRead))
+	_, deleter := suite.generate(string(auth.PersonDelete))
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, suite.delete(""))
+	assert.Equal(suite.T(), http.StatusUnauthorized, suite.delete("gck_unknown"))
+	assert.Equal(suite.T(), http.StatusForbidden, suite.delete(reader))
+	assert.Equal(suite.T(), http.StatusNoContent, suite.delete(deleter))
+}
+
+// TestAuthenticate_RecordsUse tests that authenticating with a key updates its last use.
+func (suite *APIKeyTestSuite) TestAuthenticate_RecordsUse() {
+	key, secret := suite.generate(string(auth.PersonDelete))
+	assert.Nil(suite.T(), key.LastUsedAt())
+
+	suite.delete(secret)
+
+	stored, _ := suite.keyRepo.Get(key.Id())
+	assert.NotNil(suite.T(), stored.LastUsedAt())
+}
+
+// TestAuthenticate_RevokedAndExpired tests that revoked and expired keys are rejected.
+func (suite *APIKeyTestSuite) TestAuthenticate_RevokedAndExpired() {
+	key, secret := suite.generate(string(auth.PersonDelete))
+	_, rerr := keycmd.NewRevokeAPIKeyHandler(suite.keyRepo).Handle(key.Id())
+	suite.Require().Nil(rerr)
+	assert.Equal(suite.T(), http.StatusUnauthorized, suite.delete(secret))
+
+	expiresAt := time.Now().Add(20 * time.Millisecond)
+	result, err := suite.create.Handle(&keycmd.CreateAPIKeyCommand{
+		Name:      "short-lived",
+		Scopes:    []string{string(auth.PersonDelete)},
+		ExpiresAt: &expiresAt,
+	})
+	suite.Require().Nil(err)
+	secret = result.Secret
+	assert.Equal(suite.T(), http.StatusNoContent, suite.delete(secret))
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(suite.T(), http.StatusUnauthorized, suite.delete(secret))
+}
+
+// revokingRepo is an API key repository revoking every key right after it is read by its hash,
+// as a revocation landing while the key is being authenticated would.
+type revokingRepo struct {
+	*repository.APIKeyRepo
+}
+
+// GetByHash reads the key, then revokes the stored one.
+func (r *revokingRepo) GetByHash(hash string) (*apikey.APIKey, ierr.IErr) {
+	key, err := r.APIKeyRepo.GetByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := keycmd.NewRevokeAPIKeyHandler(r.APIKeyRepo).Handle(key.Id()); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// TestAuthenticate_RevokedMeanwhile tests that a key revoked while it was being authenticated is rejected,
+// and that recording its use doesn't undo the revocation.
+func (suite *APIKeyTestSuite) TestAuthenticate_RevokedMeanwhile() {
+	repo := repository.NewAPIKeyRepo()
+	result, err := keycmd.NewCreateAPIKeyHandler(repo).Handle(&keycmd.CreateAPIKeyCommand{Name: "ci", Scopes: []string{string(auth.PersonRead)}})
+	suite.Require().Nil(err)
+
+	_, err = keycmd.NewAuthenticateAPIKeyHandler(&revokingRepo{APIKeyRepo: repo}).Handle(result.Secret)
+	suite.Require().NotNil(err)
+	assert.Equal(suite.T(), http.StatusUnauthorized, errapi.Map(err).StatusCode())
+
+	stored, gerr := repo.Get(result.Key.Id())
+	suite.Require().Nil(gerr)
+	assert.NotNil(suite.T(), stored.RevokedAt())
+	assert.Nil(suite.T(), stored.LastUsedAt())
+
+	_, err = keycmd.NewAuthenticateAPIKeyHandler(repo).Handle(result.Secret)
+	assert.NotNil(suite.T(), err)
+}
+
+// TestAuthenticate_Tenant tests that callers authenticated by a key belong to the tenant of the key.
+func (suite *APIKeyTestSuite) TestAuthenticate_Tenant() {
+	result, err := suite.create.Handle(&keycmd.CreateAPIKeyCommand{Name: "ci", Scopes: []string{string(auth.PersonRead)}, Tenant: "acme"})
+	suite.Require().Nil(err)
+
+	principal, err := keycmd.NewAuthenticateAPIKeyHandler(suite.keyRepo).Handle(result.Secret)
+	suite.Require().Nil(err)
+	assert.Equal(suite.T(), "acme", principal.Tenant)
+
+	_, err = suite.create.Handle(&keycmd.CreateAPIKeyCommand{Name: "ci", Scopes: []string{string(auth.PersonRead)}, Tenant: "Not A Tenant"})
+	assert.NotNil(suite.T(), err)
+}
+
+// TestCreate_Tenant tests that keys belong to the tenant of the request, and that only callers belonging
+// to no tenant may create keys of another tenant.
+func (suite *APIKeyTestSuite) TestCreate_Tenant() {
+	kc := controller.APIKeyController{
+		BaseController: controller.BaseController{Authorizer: auth.NewAuthorizer(auth.DefaultPolicy())},
+		CreateHandler:  suite.create,
+	}
+	create := func(principal *auth.Principal, body string) *httptest.ResponseRecorder {
+		engine := gin.New()
+		engine.POST("/admin/apikeys", func(c *gin.Context) {
+			ctx := auth.WithPrincipal(c.Request.Context(), principal)
+			if principal.Tenant != "" {
+				ctx = tenant.WithTenant(ctx, principal.Tenant)
+			}
+			c.Request = c.Request.WithContext(ctx)
+		}, kc.Create)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/apikeys", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+	member := &auth.Principal{Subject: "alice", Permissions: []auth.Permission{auth.Admin}, Tenant: "acme"}
+	operator := &auth.Principal{Subject: "root", Permissions: []auth.Permission{auth.Admin}}
+
+	w := create(member, `{"name": "ci", "scopes": ["person:read"]}`)
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	assert.Contains(suite.T(), w.Body.String(), `"tenant": "acme"`)
+
+	w = create(member, `{"name": "ci", "scopes": ["person:read"], "tenant": "globex"}`)
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+
+	w = create(operator, `{"name": "ci", "scopes": ["person:read"], "tenant": "globex"}`)
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	assert.Contains(suite.T(), w.Body.String(), `"tenant": "globex"`)
+}
+
+// TestAPIKeyTestSuite runs the test suite for API keys.
+func TestAPIKeyTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyTestSuite))
+}
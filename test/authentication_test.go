@@ -0,0 +1,187 @@
+package repo_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	"github.com/Efamamo/GoCrudChallange/api/middleware"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/Efamamo/GoCrudChallange/application/people/query"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// AuthenticationTestSuite is the test suite for the bearer token authentication middleware.
+type AuthenticationTestSuite struct {
+	suite.Suite
+	secret     string
+	privateKey *rsa.PrivateKey
+	engine     *gin.Engine
+}
+
+// SetupTest builds a router protected by HS256 and RS256 tokens, with one public route.
+func (suite *AuthenticationTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	suite.secret = "test-secret"
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+	suite.privateKey = privateKey
+
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	suite.Require().NoError(err)
+	publicKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	authentication, err := middleware.NewAuthentication(middleware.AuthenticationConfig{
+		HS256Secret:  suite.secret,
+		RS256Key:     string(publicKey),
+		Issuer:       "tests",
+		PublicRoutes: []string{"GET /person"},
+	})
+	suite.Require().NoError(err)
+
+	suite.engine = gin.New()
+	group := suite.engine.Group("/person", authentication.Handler())
+	group.GET("", func(c *gin.Context) { c.Status(http.StatusOK) })
+	group.GET("/:id", func(c *gin.Context) {
+		principal, _ := auth.PrincipalFrom(c.Request.Context())
+		c.String(http.StatusOK, principal.Subject)
+	})
+}
+
+// request performs a request on the router with the given bearer token.
+func (suite *AuthenticationTestSuite) request(path string, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	return w
+}
+
+// sign creates a token with the given method, key and claims.
+func (suite *AuthenticationTestSuite) sign(method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	suite.Require().NoError(err)
+	return token
+}
+
+// claims returns valid claims for the given subject.
+func (suite *AuthenticationTestSuite) claims(subject string) jwt.MapClaims {
+	return jwt.MapClaims{"sub": subject, "iss": "tests", "exp": time.Now().Add(time.Hour).Unix()}
+}
+
+// TestValidTokens tests that HS256 and RS256 tokens are accepted and their subject is exposed.
+func (suite *AuthenticationTestSuite) TestValidTokens() {
+	w := suite.request("/person/1", suite.sign(jwt.SigningMethodHS256, []byte(suite.secret), suite.claims("alice")))
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "alice", w.Body.String())
+
+	w = suite.request("/person/1", suite.sign(jwt.SigningMethodRS256, suite.privateKey, suite.claims("bob")))
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "bob", w.Body.String())
+}
+
+// TestInvalidTokens tests that missing, expired, forged and misissued tokens are rejected with a 401.
+func (suite *AuthenticationTestSuite) TestInvalidTokens() {
+	expired := suite.claims("alice")
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+
+	wrongIssuer := suite.claims("alice")
+	wrongIssuer["iss"] = "someone-else"
+
+	tokens := map[string]string{
+		"missing":      "",
+		"malformed":    "not-a-token",
+		"expired":      suite.sign(jwt.SigningMethodHS256, []byte(suite.secret), expired),
+		"wrong secret": suite.sign(jwt.SigningMethodHS256, []byte("other-secret"), suite.claims("alice")),
+		"wrong issuer": suite.sign(jwt.SigningMethodHS256, []byte(suite.secret), wrongIssuer),
+		"unsupported":  suite.sign(jwt.SigningMethodHS512, []byte(suite.secret), suite.claims("alice")),
+	}
+	for name, token := range tokens {
+		w := suite.request("/person/1", token)
+		assert.Equal(suite.T(), http.StatusUnauthorized, w.Code, name)
+		assert.Contains(suite.T(), w.Body.String(), "error", name)
+	}
+}
+
+// TestPublicRoute tests that routes marked public don't require a token.
+func (suite *AuthenticationTestSuite) TestPublicRoute() {
+	w := suite.request("/person", "")
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+}
+
+// TestPublicRoute_Controller tests that a public route reaches a controller enforcing the default policy
+// without credentials, without exposing the people of any owner, while callers presenting a token keep
+// their own identity there.
+func (suite *AuthenticationTestSuite) TestPublicRoute_Controller() {
+	authentication, err := middleware.NewAuthentication(middleware.AuthenticationConfig{
+		HS256Secret:  suite.secret,
+		Issuer:       "tests",
+		PublicRoutes: []string{"GET /person", "GET /person/:id"},
+	})
+	suite.Require().NoError(err)
+
+	repo := mocks.NewMockPersonRepo()
+	authorizer := auth.NewAuthorizer(auth.DefaultPolicy())
+	pc := controller.PersonController{
+		BaseController: controller.BaseController{Authorizer: authorizer},
+		GetHandler:     query.NewGetPersonHandler(repo, authorizer),
+		GetAllHandler:  query.NewGetPeopleHandler(repo, authorizer),
+		DeleteHandler:  command.NewDeletePersonHandler(repo, mocks.NewMockRelationRepo(), authorizer, nil),
+	}
+	suite.engine = gin.New()
+	group := suite.engine.Group("/person", authentication.Handler())
+	group.GET("", pc.GetAll)
+	group.GET("/:id", pc.Get)
+	group.DELETE("/:id", pc.Delete)
+
+	person, perr := model.CreatePerson(&model.PersonConfig{Name: "John Doe", Age: 30, Owner: "alice"})
+	suite.Require().Nil(perr)
+	suite.Require().Nil(repo.Save(context.Background(), person))
+
+	// Anonymous callers own no people, so they neither list nor read the people of others
+	w := suite.request("/person", "")
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	assert.NotContains(suite.T(), w.Body.String(), person.Id().String())
+
+	w = suite.request("/person/"+person.Id().String(), "")
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code, w.Body.String())
+
+	// The owner reads their person
+	alice := suite.claims("alice")
+	alice["roles"] = []string{"viewer"}
+	w = suite.request("/person/"+person.Id().String(), suite.sign(jwt.SigningMethodHS256, []byte(suite.secret), alice))
+	assert.Equal(suite.T(), http.StatusOK, w.Code, w.Body.String())
+
+	// A caller presenting a token is authorized as themselves, and only sees their own people
+	bob := suite.claims("bob")
+	bob["roles"] = []string{"viewer"}
+	w = suite.request("/person/"+person.Id().String(), suite.sign(jwt.SigningMethodHS256, []byte(suite.secret), bob))
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+
+	// Routes that aren't public still need a token
+	req := httptest.NewRequest(http.MethodDelete, "/person/"+person.Id().String(), nil)
+	w = httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	assert.Equal(suite.T(), http.StatusUnauthorized, w.Code)
+}
+
+// TestAuthenticationTestSuite runs the test suite for the authentication middleware.
+func TestAuthenticationTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthenticationTestSuite))
+}
@@ -0,0 +1,93 @@
+package repo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// AuthorizationTestSuite is the test suite for the role-based authorization of controllers.
+type AuthorizationTestSuite struct {
+	suite.Suite
+	mockRepo *mocks.MockPersonRepo
+	engine   *gin.Engine
+}
+
+// SetupTest builds a router whose callers get the roles listed in the X-Roles header.
+func (suite *AuthorizationTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.mockRepo = mocks.NewMockPersonRepo()
+
+	authorizer := auth.NewAuthorizer(auth.DefaultPolicy())
+	pc := controller.PersonController{
+		BaseController: controller.BaseController{Authorizer: authorizer},
+		DeleteHandler:  command.NewDeletePersonHandler(suite.mockRepo, mocks.NewMockRelationRepo(), authorizer, nil),
+	}
+
+	suite.engine = gin.New()
+	suite.engine.Use(func(c *gin.Context) {
+		if roles, ok := c.Request.Header["X-Roles"]; ok {
+			principal := &auth.Principal{Subject: "tester", Roles: roles}
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		}
+	})
+	suite.engine.DELETE("/person/:id", pc.Delete)
+}
+
+// delete deletes a freshly saved person as a caller with the given roles.
+func (suite *AuthorizationTestSuite) delete(roles ...string) int {
+	person, _ := model.CreatePerson(&model.PersonConfig{Name: "John Doe", Age: 30, Owner: "tester"})
+	suite.mockRepo.Save(context.Background(), person)
+
+	req := httptest.NewRequest(http.MethodDelete, "/person/"+person.Id().String(), nil)
+	for _, role := range roles {
+		req.Header.Add("X-Roles", role)
+	}
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	return w.Code
+}
+
+// TestDelete_Permissions tests that deleting requires the person:delete permission.
+func (suite *AuthorizationTestSuite) TestDelete_Permissions() {
+	assert.Equal(suite.T(), http.StatusUnauthorized, suite.delete())
+	assert.Equal(suite.T(), http.StatusForbidden, suite.delete("viewer"))
+	assert.Equal(suite.T(), http.StatusForbidden, suite.delete("viewer", "editor"))
+	assert.Equal(suite.T(), http.StatusNoContent, suite.delete("owner"))
+	assert.Equal(suite.T(), http.StatusNoContent, suite.delete("admin"))
+}
+
+// TestLoadPolicy tests loading a policy file and rejecting unknown permissions.
+func (suite *AuthorizationTestSuite) TestLoadPolicy() {
+	dir := suite.T().TempDir()
+
+	valid := filepath.Join(dir, "valid.json")
+	os.WriteFile(valid, []byte(`{"roles": {"auditor": ["person:read"]}}`), 0o600)
+	policy, err := auth.LoadPolicy(valid)
+	suite.Require().NoError(err)
+	auditor := &auth.Principal{Roles: []string{"auditor"}}
+	assert.True(suite.T(), policy.Allows(auditor, auth.PersonRead))
+	assert.False(suite.T(), policy.Allows(auditor, auth.PersonWrite))
+
+	invalid := filepath.Join(dir, "invalid.json")
+	os.WriteFile(invalid, []byte(`{"roles": {"auditor": ["person:fly"]}}`), 0o600)
+	_, err = auth.LoadPolicy(invalid)
+	assert.Error(suite.T(), err)
+}
+
+// TestAuthorizationTestSuite runs the test suite for role-based authorization.
+func TestAuthorizationTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthorizationTestSuite))
+}
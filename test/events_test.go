@@ -0,0 +1,245 @@
+package repo_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/events"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// serverSentEvent is an event read from a Server-Sent Events stream.
+type serverSentEvent struct {
+	id    string
+	event string
+	data  controller.EventDTO
+}
+
+// EventsTestSuite is the test suite for the change feeds of people.
+type EventsTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+}
+
+// SetupTest serves the routes of people and their change feeds, to owners named by the X-Subject header
+// in the tenant of the X-Tenant-ID header. The broker holds the last 3 changes for replay.
+func (suite *EventsTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	repo := mocks.NewMockPersonRepo()
+	attributes := mocks.NewMockAttributeRepo()
+	authorizer := auth.NewAuthorizer(auth.DefaultPolicy())
+	broker := events.NewBroker(3)
+	base := controller.BaseController{Authorizer: authorizer}
+
+	pc := controller.PersonController{
+		BaseController: base,
+		CreateHandler:  command.NewCreatePersonHandler(repo, attributes, broker),
+		UpdateHandler:  command.NewUpdatePersonHandler(repo, attributes, authorizer, broker),
+		DeleteHandler:  command.NewDeletePersonHandler(repo, mocks.NewMockRelationRepo(), authorizer, broker),
+	}
+	ec := controller.EventController{BaseController: base, Events: broker, Heartbeat: 50 * time.Millisecond}
+
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		ctx := auth.WithPrincipal(c.Request.Context(), &auth.Principal{Subject: c.GetHeader("X-Subject"), Roles: []string{"owner"}})
+		if id := c.GetHeader("X-Tenant-ID"); id != "" {
+			ctx = tenant.WithTenant(ctx, id)
+		}
+		c.Request = c.Request.WithContext(ctx)
+	})
+	engine.POST("/person", pc.Create)
+	engine.PUT("/person/:id", pc.Update)
+	engine.DELETE("/person/:id", pc.Delete)
+	engine.GET("/person/events", ec.Stream)
+	engine.GET("/person/events/ws", ec.WebSocket)
+	suite.server = httptest.NewServer(engine)
+}
+
+// TearDownTest stops the server.
+func (suite *EventsTestSuite) TearDownTest() {
+	suite.server.CloseClientConnections()
+	suite.server.Close()
+}
+
+// do performs a request as the subject in the tenant, and returns its response.
+func (suite *EventsTestSuite) do(method string, path string, body string, subject string, tenantID string) *http.Response {
+	req, err := http.NewRequest(method, suite.server.URL+path, strings.NewReader(body))
+	suite.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Subject", subject)
+	req.Header.Set("X-Tenant-ID", tenantID)
+	resp, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+// create creates a person owned by the subject in the tenant, and returns its id.
+func (suite *EventsTestSuite) create(name string, subject string, tenantID string) string {
+	resp := suite.do(http.MethodPost, "/person", `{"name": "`+name+`", "age": 30}`, subject, tenantID)
+	defer resp.Body.Close()
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var person controller.ResponseDTO
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&person))
+	return person.ID.String()
+}
+
+// subscribe opens the Server-Sent Events stream as alice in acme, resuming after the given event when it isn't empty.
+func (suite *EventsTestSuite) subscribe(query string, lastEventID string) (*http.Response, *bufio.Reader) {
+	req, err := http.NewRequest(http.MethodGet, suite.server.URL+"/person/events"+query, nil)
+	suite.Require().NoError(err)
+	req.Header.Set("X-Subject", "alice")
+	req.Header.Set("X-Tenant-ID", "acme")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { resp.Body.Close() })
+	return resp, bufio.NewReader(resp.Body)
+}
+
+// next reads the next event of the stream, skipping the heartbeats.
+func (suite *EventsTestSuite) next(reader *bufio.Reader) serverSentEvent {
+	var event serverSentEvent
+	for {
+		line, err := reader.ReadString('\n')
+		suite.Require().NoError(err)
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "" && event.id != "":
+			return event
+		case strings.HasPrefix(line, "id: "):
+			event.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			suite.Require().NoError(json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event.data))
+		}
+	}
+}
+
+// TestStream tests that the changes of the people of the caller in their tenant are streamed, filtered by their type.
+func (suite *EventsTestSuite) TestStream() {
+	resp, reader := suite.subscribe("?types=person.created,person.deleted", "")
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "text/event-stream", resp.Header.Get("Content-Type"))
+
+	suite.create("Carol White", "alice", "globex")
+	suite.create("Bobby Jones", "bobby", "acme")
+	id := suite.create("Alice Smith", "alice", "acme")
+	suite.do(http.MethodPut, "/person/"+id, `{"name": "Alice Smith", "age": 31}`, "alice", "acme").Body.Close()
+	suite.do(http.MethodDelete, "/person/"+id, "", "alice", "acme").Body.Close()
+
+	event := suite.next(reader)
+	assert.Equal(suite.T(), "person.created", event.event)
+	assert.Equal(suite.T(), "3", event.id)
+	assert.Equal(suite.T(), map[string]any{"id": id, "name": "Alice Smith"}, map[string]any{
+		"id":   event.data.Person.(map[string]any)["id"],
+		"name": event.data.Person.(map[string]any)["name"],
+	})
+
+	event = suite.next(reader)
+	assert.Equal(suite.T(), "person.deleted", event.event)
+	assert.Equal(suite.T(), "5", event.id)
+	assert.Equal(suite.T(), float64(31), event.data.Person.(map[string]any)["age"])
+}
+
+// TestStream_Resume tests that the changes following the Last-Event-ID are replayed while they are held.
+func (suite *EventsTestSuite) TestStream_Resume() {
+	for _, name := range []string{"Alice Smith", "Bobby Jones", "Carol White", "David Brown"} {
+		suite.create(name, "alice", "acme")
+	}
+
+	_, reader := suite.subscribe("", "2")
+	assert.Equal(suite.T(), "3", suite.next(reader).id)
+	assert.Equal(suite.T(), "4", suite.next(reader).id)
+
+	_, reader = suite.subscribe("?last_event_id=1", "")
+	assert.Equal(suite.T(), "2", suite.next(reader).id)
+
+	// The second change is no longer held, so the replay starts from the oldest one held
+	suite.create("Erin Green", "alice", "acme")
+	_, reader = suite.subscribe("", "1")
+	assert.Equal(suite.T(), "3", suite.next(reader).id)
+}
+
+// TestStream_Heartbeat tests that idle streams send comment lines as heartbeats.
+func (suite *EventsTestSuite) TestStream_Heartbeat() {
+	_, reader := suite.subscribe("", "")
+
+	line, err := reader.ReadString('\n')
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), ": heartbeat\n", line)
+}
+
+// TestStream_InvalidFilters tests that invalid filters are rejected before the stream starts.
+func (suite *EventsTestSuite) TestStream_InvalidFilters() {
+	for query, lastEventID := range map[string]string{"?types=person.renamed": "", "?ids=not-a-uuid": "", "": "latest"} {
+		resp, _ := suite.subscribe(query, lastEventID)
+		assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode, query+lastEventID)
+	}
+}
+
+// TestWebSocket tests that the changes are sent as JSON messages over a WebSocket, filtered by person.
+func (suite *EventsTestSuite) TestWebSocket() {
+	bobby := suite.create("Bobby Jones", "alice", "acme")
+
+	header := http.Header{"X-Subject": {"alice"}, "X-Tenant-ID": {"acme"}}
+	url := "ws" + strings.TrimPrefix(suite.server.URL, "http") + "/person/events/ws?ids=" + bobby
+	conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+	suite.Require().NoError(err)
+	defer conn.Close()
+	assert.Equal(suite.T(), http.StatusSwitchingProtocols, resp.StatusCode)
+
+	pinged := make(chan struct{}, 1)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pinged <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+
+	suite.create("Alice Smith", "alice", "acme")
+	suite.do(http.MethodPut, "/person/"+bobby, `{"name": "Bobby Jones", "age": 41}`, "alice", "acme").Body.Close()
+
+	var event controller.EventDTO
+	suite.Require().NoError(conn.ReadJSON(&event))
+	assert.Equal(suite.T(), "person.updated", event.Type)
+	assert.Equal(suite.T(), uint64(3), event.ID)
+	assert.Equal(suite.T(), bobby, event.Person.(map[string]any)["id"])
+
+	// Keep reading, so that the ping frames sent as heartbeats are handled
+	go func() {
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+	select {
+	case <-pinged:
+	case <-time.After(time.Second):
+		suite.Fail("no heartbeat was sent")
+	}
+}
+
+// TestEventsTestSuite runs the test suite.
+func TestEventsTestSuite(t *testing.T) {
+	suite.Run(t, new(EventsTestSuite))
+}
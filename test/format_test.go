@@ -0,0 +1,223 @@
+package repo_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	"github.com/Efamamo/GoCrudChallange/api/router"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/Efamamo/GoCrudChallange/application/people/query"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/ugorji/go/codec"
+	"gopkg.in/yaml.v3"
+)
+
+// xmlPerson is a person of an XML response.
+type xmlPerson struct {
+	ID      string   `xml:"id"`
+	Name    string   `xml:"name"`
+	Age     int      `xml:"age"`
+	Hobbies []string `xml:"hobbies>hobby"`
+	City    string   `xml:"address>city"`
+}
+
+// FormatTestSuite is the test suite for the formats of the bodies of people.
+type FormatTestSuite struct {
+	suite.Suite
+	engine *gin.Engine
+}
+
+// SetupTest builds the router of the API.
+func (suite *FormatTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	repo := mocks.NewMockPersonRepo()
+	attributes := mocks.NewMockAttributeRepo()
+
+	pc := controller.PersonController{
+		CreateHandler: command.NewCreatePersonHandler(repo, attributes, nil),
+		UpdateHandler: command.NewUpdatePersonHandler(repo, attributes, nil, nil),
+		GetHandler:    query.NewGetPersonHandler(repo, nil),
+		GetAllHandler: query.NewGetPeopleHandler(repo, nil),
+	}
+	suite.engine = router.NewRouter(router.Config{}).Engine(
+		pc, controller.RelationController{}, controller.AttributeController{},
+		controller.APIKeyController{}, controller.AuthController{}, controller.UserController{}, controller.GraphQLController{}, controller.EventController{}, controller.WebhookController{}, controller.JobController{}, controller.ScheduleController{},
+	)
+}
+
+// request performs a request on the router with the given Content-Type and Accept headers.
+func (suite *FormatTestSuite) request(method string, path string, body []byte, contentType string, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	return w
+}
+
+// create creates a person from a JSON body and returns its id.
+func (suite *FormatTestSuite) create(body string) string {
+	w := suite.request(http.MethodPost, "/v1/person", []byte(body), "application/json", "")
+	suite.Require().Equal(http.StatusCreated, w.Code)
+
+	var person controller.ResponseDTO
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &person))
+	return person.ID.String()
+}
+
+// TestXML tests that people are rendered as XML, and created from XML bodies.
+func (suite *FormatTestSuite) TestXML() {
+	body := `<person><name>Alice Smith</name><age>30</age><hobbies><hobby>chess</hobby><hobby>hiking</hobby></hobbies>` +
+		`<address><street>1 Main St</street><city>Springfield</city><country>US</country></address></person>`
+	w := suite.request(http.MethodPost, "/v1/person", []byte(body), "application/xml", "text/xml")
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+
+	var person xmlPerson
+	suite.Require().NoError(xml.Unmarshal(w.Body.Bytes(), &person))
+	assert.Equal(suite.T(), "Alice Smith", person.Name)
+	assert.Equal(suite.T(), 30, person.Age)
+	assert.Equal(suite.T(), []string{"chess", "hiking"}, person.Hobbies)
+	assert.Equal(suite.T(), "Springfield", person.City)
+
+	w = suite.request(http.MethodGet, "/v1/person", nil, "", "application/xml")
+	suite.Require().Equal(http.StatusOK, w.Code)
+	var people struct {
+		People []xmlPerson `xml:"person"`
+	}
+	suite.Require().NoError(xml.Unmarshal(w.Body.Bytes(), &people))
+	suite.Require().Len(people.People, 1)
+	assert.Equal(suite.T(), person.ID, people.People[0].ID)
+}
+
+// TestYAML tests that people are rendered as YAML, and updated from YAML bodies.
+func (suite *FormatTestSuite) TestYAML() {
+	id := suite.create(`{"name": "Alice Smith", "age": 30}`)
+
+	body := "name: Alice Jones\nage: 31\nhobbies: [chess]\naddress:\n  street: 1 Main St\n  city: Springfield\n  postal_code: \"01101\"\n  country: US\n"
+	w := suite.request(http.MethodPut, "/v1/person/"+id, []byte(body), "application/x-yaml", "application/yaml")
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "application/yaml; charset=utf-8", w.Header().Get("Content-Type"))
+
+	var person map[string]any
+	suite.Require().NoError(yaml.Unmarshal(w.Body.Bytes(), &person))
+	assert.Equal(suite.T(), "Alice Jones", person["name"])
+	assert.Equal(suite.T(), 31, person["age"])
+	assert.Equal(suite.T(), "01101", person["address"].(map[string]any)["postal_code"])
+}
+
+// TestMsgPack tests that people are rendered as MessagePack, and created from MessagePack bodies.
+func (suite *FormatTestSuite) TestMsgPack() {
+	handle := &codec.MsgpackHandle{}
+	var body []byte
+	suite.Require().NoError(codec.NewEncoderBytes(&body, handle).Encode(map[string]any{"name": "Alice Smith", "age": 30, "hobbies": []string{"chess"}}))
+
+	w := suite.request(http.MethodPost, "/v1/person", body, "application/msgpack", "application/x-msgpack")
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "application/msgpack", w.Header().Get("Content-Type"))
+
+	var person map[string]any
+	handle.RawToString = true
+	suite.Require().NoError(codec.NewDecoderBytes(w.Body.Bytes(), handle).Decode(&person))
+	assert.Equal(suite.T(), "Alice Smith", person["name"])
+	assert.EqualValues(suite.T(), 30, person["age"])
+}
+
+// TestCSV tests that people are rendered as CSV rows, and created from a CSV row.
+func (suite *FormatTestSuite) TestCSV() {
+	suite.create(`{"name": "Alice Smith", "age": 30, "hobbies": ["chess", "hiking"]}`)
+
+	body := "name,age,hobbies,address.street,address.city,address.country\nBobby Jones,41,golf,1 Main St,Springfield,US\n"
+	w := suite.request(http.MethodPost, "/v1/person", []byte(body), "text/csv", "application/json")
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+
+	w = suite.request(http.MethodGet, "/v1/person", nil, "", "text/csv")
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+
+	records, err := csv.NewReader(bytes.NewReader(w.Body.Bytes())).ReadAll()
+	suite.Require().NoError(err)
+	suite.Require().Len(records, 3)
+	assert.Equal(suite.T(), []string{"id", "name", "age", "hobbies", "address.street", "address.city", "address.country"}, records[0])
+	// The repository lists people in no particular order
+	rows := [][]string{records[1][1:], records[2][1:]}
+	assert.ElementsMatch(suite.T(), [][]string{
+		{"Alice Smith", "30", "chess;hiking", "", "", ""},
+		{"Bobby Jones", "41", "golf", "1 Main St", "Springfield", "US"},
+	}, rows)
+}
+
+// TestV2 tests that the version selected by a vendor media type is rendered in the format of its suffix.
+func (suite *FormatTestSuite) TestV2() {
+	id := suite.create(`{"name": "Alice Smith", "age": 30}`)
+
+	w := suite.request(http.MethodGet, "/person/"+id, nil, "", "application/vnd.gocrud.v2+xml")
+	suite.Require().Equal(http.StatusOK, w.Code)
+
+	var response struct {
+		Data struct {
+			ID        string `xml:"id"`
+			CreatedAt string `xml:"created_at"`
+		} `xml:"data"`
+	}
+	suite.Require().NoError(xml.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(suite.T(), id, response.Data.ID)
+	assert.NotEmpty(suite.T(), response.Data.CreatedAt)
+}
+
+// TestNegotiation tests that the accepted media type with the highest quality is rendered, and that
+// a 406 is responded when none is supported.
+func (suite *FormatTestSuite) TestNegotiation() {
+	id := suite.create(`{"name": "Alice Smith", "age": 30}`)
+
+	for accept, contentType := range map[string]string{
+		"":    "application/json; charset=utf-8",
+		"*/*": "application/json; charset=utf-8",
+		"text/html, text/csv;q=0.5, application/yaml;q=0.8": "application/yaml; charset=utf-8",
+		"application/json; version=2":                       "application/json; charset=utf-8",
+	} {
+		w := suite.request(http.MethodGet, "/person/"+id, nil, "", accept)
+		assert.Equal(suite.T(), http.StatusOK, w.Code, accept)
+		assert.Equal(suite.T(), contentType, w.Header().Get("Content-Type"), accept)
+		assert.Equal(suite.T(), "Accept", w.Header().Get("Vary"), accept)
+	}
+
+	for _, accept := range []string{"text/html", "application/json;q=0, image/png"} {
+		w := suite.request(http.MethodGet, "/person/"+id, nil, "", accept)
+		assert.Equal(suite.T(), http.StatusNotAcceptable, w.Code, accept)
+	}
+}
+
+// TestUnsupportedMediaType tests that bodies in an unsupported format are rejected with a 415,
+// in the format of the response.
+func (suite *FormatTestSuite) TestUnsupportedMediaType() {
+	w := suite.request(http.MethodPost, "/v1/person", []byte("name=Alice"), "application/x-www-form-urlencoded", "application/xml")
+	suite.Require().Equal(http.StatusUnsupportedMediaType, w.Code)
+
+	var response struct {
+		Error string `xml:"error"`
+	}
+	suite.Require().NoError(xml.Unmarshal(w.Body.Bytes(), &response))
+	assert.Contains(suite.T(), response.Error, "Content-Type should be one of")
+
+	w = suite.request(http.MethodPost, "/v1/person", []byte("name\n"), "text/csv", "")
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+// TestFormatTestSuite runs the test suite.
+func TestFormatTestSuite(t *testing.T) {
+	suite.Run(t, new(FormatTestSuite))
+}
@@ -0,0 +1,178 @@
+package repo_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	graphqlapi "github.com/Efamamo/GoCrudChallange/api/graphql"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/Efamamo/GoCrudChallange/application/people/query"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// graphQLResult is the result of a GraphQL request.
+type graphQLResult struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// GraphQLTestSuite is the test suite for the /graphql endpoint.
+type GraphQLTestSuite struct {
+	suite.Suite
+	engine *gin.Engine
+}
+
+// SetupTest builds a router serving the GraphQL schema, whose callers get the roles listed in the X-Roles header.
+func (suite *GraphQLTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	repo := mocks.NewMockPersonRepo()
+	attributes := mocks.NewMockAttributeRepo()
+	authorizer := auth.NewAuthorizer(auth.DefaultPolicy())
+
+	schema, err := graphqlapi.NewSchema(&graphqlapi.Resolver{
+		Authorizer: authorizer,
+
+		CreateHandler: command.NewCreatePersonHandler(repo, attributes, nil),
+		UpdateHandler: command.NewUpdatePersonHandler(repo, attributes, authorizer, nil),
+		DeleteHandler: command.NewDeletePersonHandler(repo, mocks.NewMockRelationRepo(), authorizer, nil),
+		GetHandler:    query.NewGetPersonHandler(repo, authorizer),
+		GetAllHandler: query.NewGetPeopleHandler(repo, authorizer),
+	})
+	suite.Require().NoError(err)
+	gc := controller.GraphQLController{Schema: schema}
+
+	suite.engine = gin.New()
+	suite.engine.Use(func(c *gin.Context) {
+		principal := &auth.Principal{Subject: "tester", Roles: c.Request.Header["X-Roles"]}
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+	})
+	suite.engine.POST("/graphql", gc.Serve)
+}
+
+// execute executes the operation as a caller with the owner role, unless other roles are given.
+func (suite *GraphQLTestSuite) execute(document string, variables map[string]any, roles ...string) graphQLResult {
+	body, err := json.Marshal(map[string]any{"query": document, "variables": variables})
+	suite.Require().NoError(err)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	if len(roles) == 0 {
+		roles = []string{"owner"}
+	}
+	for _, role := range roles {
+		req.Header.Add("X-Roles", role)
+	}
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code)
+
+	var result graphQLResult
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &result))
+	return result
+}
+
+// create creates a person with the hobbies and returns its id.
+func (suite *GraphQLTestSuite) create(name string, hobbies ...string) string {
+	result := suite.execute(`mutation($input: PersonInput!) { createPerson(input: $input) { id } }`, map[string]any{
+		"input": map[string]any{"name": name, "age": 30, "hobbies": hobbies},
+	})
+	suite.Require().Empty(result.Errors)
+	return result.Data["createPerson"].(map[string]any)["id"].(string)
+}
+
+// TestMutations tests that people are created, updated and deleted through the handlers.
+func (suite *GraphQLTestSuite) TestMutations() {
+	result := suite.execute(`mutation($input: PersonInput!) {
+		createPerson(input: $input) { id name hobbies owner address { city region } createdAt }
+	}`, map[string]any{"input": map[string]any{
+		"name":    "Alice Smith",
+		"age":     30,
+		"hobbies": []string{"chess"},
+		"address": map[string]any{"street": "1 Main St", "city": "Springfield", "country": "US"},
+	}})
+	suite.Require().Empty(result.Errors)
+	person := result.Data["createPerson"].(map[string]any)
+	assert.Equal(suite.T(), []any{"chess"}, person["hobbies"])
+	assert.Equal(suite.T(), "tester", person["owner"])
+	assert.Equal(suite.T(), map[string]any{"city": "Springfield", "region": nil}, person["address"])
+	assert.NotEmpty(suite.T(), person["createdAt"])
+	id := person["id"].(string)
+
+	result = suite.execute(`mutation($id: ID!) { updatePerson(id: $id, input: {name: "Alice Smith", age: 31}) { age hobbies } }`, map[string]any{"id": id})
+	suite.Require().Empty(result.Errors)
+	assert.Equal(suite.T(), map[string]any{"age": float64(31), "hobbies": []any{}}, result.Data["updatePerson"])
+
+	result = suite.execute(`mutation($id: ID!) { deletePerson(id: $id) }`, map[string]any{"id": id})
+	suite.Require().Empty(result.Errors)
+	assert.Equal(suite.T(), true, result.Data["deletePerson"])
+
+	result = suite.execute(`query($id: ID!) { person(id: $id) { id } }`, map[string]any{"id": id})
+	suite.Require().Empty(result.Errors)
+	assert.Nil(suite.T(), result.Data["person"])
+}
+
+// TestQueries tests that people are looked up in batches, filtered and paginated.
+func (suite *GraphQLTestSuite) TestQueries() {
+	alice := suite.create("Alice Smith", "Chess")
+	bobby := suite.create("Bobby Jones", "chess", "golf")
+	suite.create("Carol White", "golf")
+
+	result := suite.execute(`query($a: ID!, $b: ID!) { a: person(id: $a) { name } b: person(id: $b) { name } }`, map[string]any{"a": alice, "b": bobby})
+	suite.Require().Empty(result.Errors)
+	assert.Equal(suite.T(), map[string]any{"name": "Alice Smith"}, result.Data["a"])
+	assert.Equal(suite.T(), map[string]any{"name": "Bobby Jones"}, result.Data["b"])
+
+	result = suite.execute(`query($ids: [ID!]) { people(filter: {ids: $ids}) { total } }`, map[string]any{"ids": []string{alice, bobby}})
+	suite.Require().Empty(result.Errors)
+	assert.Equal(suite.T(), float64(2), result.Data["people"].(map[string]any)["total"])
+
+	result = suite.execute(`{ people(filter: {hobby: "chess"}, limit: 1, offset: 1) { total limit offset nodes { id } } }`, nil)
+	suite.Require().Empty(result.Errors)
+	page := result.Data["people"].(map[string]any)
+	assert.Equal(suite.T(), float64(2), page["total"])
+	assert.Len(suite.T(), page["nodes"], 1)
+}
+
+// TestErrors tests that errors of the handlers are reported with their code in the extensions.
+func (suite *GraphQLTestSuite) TestErrors() {
+	id := suite.create("Alice Smith")
+
+	result := suite.execute(`{ person(id: "not-a-uuid") { id } }`, nil)
+	suite.Require().Len(result.Errors, 1)
+	assert.Equal(suite.T(), "BAD_USER_INPUT", result.Errors[0].Extensions["code"])
+	assert.Equal(suite.T(), float64(400), result.Errors[0].Extensions["status"])
+
+	result = suite.execute(`mutation($id: ID) { createPerson(id: $id, input: {name: "Bobby Jones", age: 40}) { id } }`, map[string]any{"id": id})
+	suite.Require().Len(result.Errors, 1)
+	assert.Equal(suite.T(), "CONFLICT", result.Errors[0].Extensions["code"])
+	assert.Equal(suite.T(), id, result.Errors[0].Extensions["existingId"])
+
+	result = suite.execute(`mutation($id: ID!) { updatePerson(id: $id, input: {name: "Bobby Jones", age: 40}) { id } }`, map[string]any{"id": uuid.New().String()})
+	suite.Require().Len(result.Errors, 1)
+	assert.Equal(suite.T(), "NOT_FOUND", result.Errors[0].Extensions["code"])
+
+	result = suite.execute(`mutation($id: ID!) { deletePerson(id: $id) }`, map[string]any{"id": id}, "viewer")
+	suite.Require().Len(result.Errors, 1)
+	assert.Equal(suite.T(), "FORBIDDEN", result.Errors[0].Extensions["code"])
+
+	result = suite.execute(`{ people(limit: 0) { total } }`, nil)
+	suite.Require().Len(result.Errors, 1)
+	assert.Equal(suite.T(), "BAD_USER_INPUT", result.Errors[0].Extensions["code"])
+}
+
+// TestGraphQLTestSuite runs the test suite.
+func TestGraphQLTestSuite(t *testing.T) {
+	suite.Run(t, new(GraphQLTestSuite))
+}
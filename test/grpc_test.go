@@ -0,0 +1,265 @@
+package repo_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	grpcapi "github.com/Efamamo/GoCrudChallange/api/grpc"
+	"github.com/Efamamo/GoCrudChallange/api/grpc/personpb"
+	"github.com/Efamamo/GoCrudChallange/api/middleware"
+	iratelimit "github.com/Efamamo/GoCrudChallange/application/common/interface/ratelimit"
+	apperror "github.com/Efamamo/GoCrudChallange/application/error"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/Efamamo/GoCrudChallange/application/people/query"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/events"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/ratelimit"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// GRPCTestSuite is the test suite for the PersonService served over gRPC.
+type GRPCTestSuite struct {
+	suite.Suite
+	secret string
+	store  *ratelimit.MemoryStore
+	server *grpc.Server
+	conn   *grpc.ClientConn
+	client personpb.PersonServiceClient
+}
+
+// SetupTest serves the PersonService on an in-memory listener, with HS256 tokens and tenants read from X-Tenant-ID,
+// limiting the "limited" subject to a call per minute.
+func (suite *GRPCTestSuite) SetupTest() {
+	suite.secret = "test-secret"
+	suite.store = ratelimit.NewMemoryStore()
+	repo := mocks.NewMockPersonRepo()
+	attributes := mocks.NewMockAttributeRepo()
+	broker := events.NewBroker(0)
+
+	authentication, err := middleware.NewAuthentication(middleware.AuthenticationConfig{HS256Secret: suite.secret})
+	suite.Require().NoError(err)
+
+	suite.server = grpcapi.NewServer(grpcapi.ServerConfig{
+		Person: &grpcapi.PersonServer{
+			CreateHandler: command.NewCreatePersonHandler(repo, attributes, broker),
+			UpdateHandler: command.NewUpdatePersonHandler(repo, attributes, nil, broker),
+			DeleteHandler: command.NewDeletePersonHandler(repo, mocks.NewMockRelationRepo(), nil, broker),
+			GetHandler:    query.NewGetPersonHandler(repo, nil),
+			GetAllHandler: query.NewGetPeopleHandler(repo, nil),
+
+			Events: broker,
+		},
+		Authentication: authentication,
+		Tenancy:        middleware.NewTenancy(middleware.TenancyConfig{Header: "X-Tenant-ID"}),
+		RateLimit: middleware.NewRateLimit(middleware.RateLimitConfig{
+			Name:    "person",
+			Rate:    iratelimit.Rate{Limit: 1000, Period: time.Minute},
+			Clients: map[string]iratelimit.Rate{"limited": {Limit: 1, Period: time.Minute}},
+			Store:   suite.store,
+		}),
+	})
+
+	listener := bufconn.Listen(1 << 20)
+	go suite.server.Serve(listener)
+
+	suite.conn, err = grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	suite.Require().NoError(err)
+	suite.client = personpb.NewPersonServiceClient(suite.conn)
+}
+
+// TearDownTest closes the connection and stops the server.
+func (suite *GRPCTestSuite) TearDownTest() {
+	suite.conn.Close()
+	suite.server.Stop()
+}
+
+// context returns a context carrying a token of the subject belonging to the tenant, and the tenant in its metadata.
+func (suite *GRPCTestSuite) context(subject string, tenantID string) context.Context {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":    subject,
+		"tenant": tenantID,
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(suite.secret))
+	suite.Require().NoError(err)
+
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token, "x-tenant-id", tenantID)
+}
+
+// create creates a person through the PersonService.
+func (suite *GRPCTestSuite) create(ctx context.Context, name string) *personpb.Person {
+	person, err := suite.client.Create(ctx, &personpb.CreatePersonRequest{Person: &personpb.PersonInput{Name: name, Age: 30, Hobbies: []string{"chess"}}})
+	suite.Require().NoError(err)
+	return person
+}
+
+// TestCRUD tests that people are created, retrieved, listed, updated and deleted through the handlers.
+func (suite *GRPCTestSuite) TestCRUD() {
+	ctx := suite.context("alice", "acme")
+
+	created := suite.create(ctx, "Alice Smith")
+	assert.Equal(suite.T(), "alice", created.GetOwner())
+	assert.Equal(suite.T(), []string{"chess"}, created.GetHobbies())
+	assert.NotNil(suite.T(), created.GetCreatedAt())
+
+	person, err := suite.client.Get(ctx, &personpb.GetPersonRequest{Id: created.GetId()})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "Alice Smith", person.GetName())
+
+	updated, err := suite.client.Update(ctx, &personpb.UpdatePersonRequest{Id: created.GetId(), Person: &personpb.PersonInput{Name: "Alice Smith", Age: 31}})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), int32(31), updated.GetAge())
+
+	list, err := suite.client.List(ctx, &personpb.ListPeopleRequest{})
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), list.GetPeople(), 1)
+
+	_, err = suite.client.Delete(ctx, &personpb.DeletePersonRequest{Id: created.GetId()})
+	suite.Require().NoError(err)
+
+	_, err = suite.client.Get(ctx, &personpb.GetPersonRequest{Id: created.GetId()})
+	assert.Equal(suite.T(), codes.NotFound, status.Code(err))
+}
+
+// TestErrors tests that errors of the handlers and invalid requests are mapped to gRPC status codes.
+func (suite *GRPCTestSuite) TestErrors() {
+	ctx := suite.context("alice", "acme")
+	created := suite.create(ctx, "Alice Smith")
+
+	_, err := suite.client.Get(ctx, &personpb.GetPersonRequest{Id: "not-a-uuid"})
+	assert.Equal(suite.T(), codes.InvalidArgument, status.Code(err))
+
+	_, err = suite.client.Create(ctx, &personpb.CreatePersonRequest{Person: &personpb.PersonInput{Name: "", Age: 30}})
+	assert.Equal(suite.T(), codes.InvalidArgument, status.Code(err))
+
+	_, err = suite.client.Create(ctx, &personpb.CreatePersonRequest{Id: created.GetId(), Person: &personpb.PersonInput{Name: "Bobby Jones", Age: 40}})
+	suite.Require().Equal(codes.AlreadyExists, status.Code(err))
+	details := status.Convert(err).Details()
+	suite.Require().Len(details, 1)
+	assert.Equal(suite.T(), created.GetId(), details[0].(*errdetails.ResourceInfo).GetResourceName())
+
+	_, err = suite.client.Get(suite.context("alice", "globex"), &personpb.GetPersonRequest{Id: created.GetId()})
+	assert.Equal(suite.T(), codes.NotFound, status.Code(err))
+
+	_, err = suite.client.List(context.Background(), &personpb.ListPeopleRequest{})
+	assert.Equal(suite.T(), codes.Unauthenticated, status.Code(err))
+
+	_, err = suite.client.List(suite.context("alice", "Not A Tenant"), &personpb.ListPeopleRequest{})
+	assert.Equal(suite.T(), codes.InvalidArgument, status.Code(err))
+}
+
+// TestMap tests that every type of error has a status code.
+func (suite *GRPCTestSuite) TestMap() {
+	cases := map[codes.Code]ierr.IErr{
+		codes.NotFound:          ierr.NewNotFound("not found"),
+		codes.InvalidArgument:   ierr.NewValidation("invalid"),
+		codes.AlreadyExists:     ierr.NewConflict("conflict"),
+		codes.Internal:          ierr.NewUnexpected("unexpected"),
+		codes.Unauthenticated:   apperror.InvalidCredential("invalid credential"),
+		codes.PermissionDenied:  apperror.AccessDenied("access denied"),
+		codes.ResourceExhausted: apperror.QuotaReached("quota reached"),
+	}
+	for code, err := range cases {
+		assert.Equal(suite.T(), code, status.Code(grpcapi.Map(err)), err.Error())
+	}
+}
+
+// TestWatch tests that changes of the tenant are streamed, filtered by their type.
+func (suite *GRPCTestSuite) TestWatch() {
+	ctx, cancel := context.WithCancel(suite.context("alice", "acme"))
+	defer cancel()
+
+	stream, err := suite.client.Watch(ctx, &personpb.WatchPeopleRequest{Types: []personpb.PersonEvent_Type{personpb.PersonEvent_CREATED, personpb.PersonEvent_DELETED}})
+	suite.Require().NoError(err)
+	_, err = stream.Header()
+	suite.Require().NoError(err)
+
+	suite.create(suite.context("alice", "globex"), "Carol White")
+	created := suite.create(ctx, "Alice Smith")
+	_, err = suite.client.Update(ctx, &personpb.UpdatePersonRequest{Id: created.GetId(), Person: &personpb.PersonInput{Name: "Alice Smith", Age: 31}})
+	suite.Require().NoError(err)
+	_, err = suite.client.Delete(ctx, &personpb.DeletePersonRequest{Id: created.GetId()})
+	suite.Require().NoError(err)
+
+	event, err := stream.Recv()
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), personpb.PersonEvent_CREATED, event.GetType())
+	assert.Equal(suite.T(), "Alice Smith", event.GetPerson().GetName())
+
+	event, err = stream.Recv()
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), personpb.PersonEvent_DELETED, event.GetType())
+	assert.Equal(suite.T(), created.GetId(), event.GetPerson().GetId())
+	assert.Equal(suite.T(), int32(31), event.GetPerson().GetAge())
+
+	cancel()
+	_, err = stream.Recv()
+	assert.Equal(suite.T(), codes.Canceled, status.Code(err))
+}
+
+// TestBroker_SlowSubscriber tests that subscribers falling too far behind are dropped instead of blocking publishers.
+func (suite *GRPCTestSuite) TestBroker_SlowSubscriber() {
+	broker := events.NewBroker(0)
+	subscription := broker.Subscribe(context.Background(), 0)
+
+	create := command.NewCreatePersonHandler(mocks.NewMockPersonRepo(), mocks.NewMockAttributeRepo(), broker)
+	for i := 0; i < 100; i++ {
+		_, err := create.Handle(&command.CreatePersonCommand{ID: uuid.New(), Name: "Alice Smith", Age: 30})
+		suite.Require().Nil(err)
+	}
+
+	received := 0
+	for range subscription {
+		received++
+	}
+	assert.Less(suite.T(), received, 100)
+}
+
+// TestRateLimit tests that calls are limited per client, in the buckets of the /person routes.
+func (suite *GRPCTestSuite) TestRateLimit() {
+	ctx := suite.context("limited", "acme")
+	suite.create(ctx, "Alice Smith")
+
+	var header metadata.MD
+	_, err := suite.client.List(ctx, &personpb.ListPeopleRequest{}, grpc.Header(&header))
+	assert.Equal(suite.T(), codes.ResourceExhausted, status.Code(err))
+	assert.Equal(suite.T(), []string{"0"}, header.Get("x-ratelimit-remaining"))
+	assert.Equal(suite.T(), []string{"60"}, header.Get("retry-after"))
+
+	// The HTTP routes see the same bucket, while other clients have their own.
+	result, terr := suite.store.Take(context.Background(), "person|limited", iratelimit.Rate{Limit: 1, Period: time.Minute})
+	suite.Require().NoError(terr)
+	assert.False(suite.T(), result.Allowed)
+	suite.create(suite.context("alice", "acme"), "Bobby Jones")
+}
+
+// TestReflection tests that the reflection service is only served when the configuration enables it.
+func (suite *GRPCTestSuite) TestReflection() {
+	_, served := suite.server.GetServiceInfo()["grpc.reflection.v1.ServerReflection"]
+	assert.False(suite.T(), served)
+
+	server := grpcapi.NewServer(grpcapi.ServerConfig{Person: &grpcapi.PersonServer{}, Reflection: true})
+	defer server.Stop()
+	_, served = server.GetServiceInfo()["grpc.reflection.v1.ServerReflection"]
+	assert.True(suite.T(), served)
+}
+
+// TestGRPCTestSuite runs the test suite.
+func TestGRPCTestSuite(t *testing.T) {
+	suite.Run(t, new(GRPCTestSuite))
+}
@@ -0,0 +1,179 @@
+package repo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/api/middleware"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/idempotency"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// IdempotencyTestSuite is the test suite for the replay of requests retried with an Idempotency-Key.
+type IdempotencyTestSuite struct {
+	suite.Suite
+	store   *idempotency.MemoryStore
+	engine  *gin.Engine
+	created int
+	status  int
+	padding string                     // Appended to the responses, to make them larger
+	retried *httptest.ResponseRecorder // Response of a retry sent while the first request is in progress
+	retry   func() *httptest.ResponseRecorder
+}
+
+// SetupTest builds a router whose POST /person mints a new id on each request it processes. Bodies larger
+// than 100 bytes are rejected, and responses larger than 100 bytes aren't stored.
+func (suite *IdempotencyTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.store = idempotency.NewMemoryStore()
+	suite.created = 0
+	suite.status = http.StatusCreated
+	suite.padding = ""
+	suite.retried = nil
+	suite.retry = nil
+
+	suite.engine = gin.New()
+	suite.engine.POST("/person", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(tenant.WithTenant(c.Request.Context(), c.GetHeader("X-Tenant-ID")))
+	}, middleware.NewIdempotency(middleware.IdempotencyConfig{
+		Store:           suite.store,
+		TTL:             time.Hour,
+		MaxBodySize:     100,
+		MaxResponseSize: 100,
+	}).Handler(), func(c *gin.Context) {
+		suite.created++
+		if suite.retry != nil {
+			retry := suite.retry
+			suite.retry = nil
+			suite.retried = retry()
+		}
+		c.IndentedJSON(suite.status, gin.H{"id": uuid.New(), "padding": suite.padding})
+	})
+}
+
+// request performs a POST /person with the given key, body and tenant.
+func (suite *IdempotencyTestSuite) request(key string, body string, tenantID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/person", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant-ID", tenantID)
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	return w
+}
+
+// TestReplay tests that retries with the same key and body get the first response back.
+func (suite *IdempotencyTestSuite) TestReplay() {
+	body := `{"name": "Alice Smith", "age": 30}`
+
+	first := suite.request("key-1", body, "acme")
+	suite.Require().Equal(http.StatusCreated, first.Code)
+	assert.Empty(suite.T(), first.Header().Get(middleware.IdempotentReplayedHeader))
+
+	retry := suite.request("key-1", body, "acme")
+	suite.Require().Equal(http.StatusCreated, retry.Code)
+	assert.Equal(suite.T(), "true", retry.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(suite.T(), first.Body.String(), retry.Body.String())
+	assert.Equal(suite.T(), first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+	assert.Equal(suite.T(), 1, suite.created)
+}
+
+// TestReplay_OtherRequests tests that other keys, tenants and requests without a key are processed.
+func (suite *IdempotencyTestSuite) TestReplay_OtherRequests() {
+	body := `{"name": "Alice Smith", "age": 30}`
+
+	suite.request("key-1", body, "acme")
+	suite.request("key-2", body, "acme")
+	suite.request("key-1", body, "globex")
+	suite.request("", body, "acme")
+	suite.request("", body, "acme")
+
+	assert.Equal(suite.T(), 5, suite.created)
+}
+
+// TestReuse_OtherBody tests that reusing a key for another body is rejected with a 422.
+func (suite *IdempotencyTestSuite) TestReuse_OtherBody() {
+	suite.request("key-1", `{"name": "Alice Smith", "age": 30}`, "acme")
+
+	w := suite.request("key-1", `{"name": "Bobby Jones", "age": 40}`, "acme")
+	assert.Equal(suite.T(), http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(suite.T(), 1, suite.created)
+}
+
+// TestInProgress tests that retries of a request still in progress are rejected with a 409.
+func (suite *IdempotencyTestSuite) TestInProgress() {
+	body := `{"name": "Alice Smith", "age": 30}`
+	suite.retry = func() *httptest.ResponseRecorder { return suite.request("key-1", body, "acme") }
+
+	w := suite.request("key-1", body, "acme")
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+	suite.Require().NotNil(suite.retried)
+	assert.Equal(suite.T(), http.StatusConflict, suite.retried.Code)
+	assert.Equal(suite.T(), 1, suite.created)
+}
+
+// TestStore_Expiry tests that keys can be reused once their TTL elapsed.
+func (suite *IdempotencyTestSuite) TestStore_Expiry() {
+	record, err := suite.store.Begin(context.Background(), "key-1", "fingerprint", 10*time.Millisecond)
+	suite.Require().NoError(err)
+	suite.Require().Nil(record)
+
+	record, err = suite.store.Begin(context.Background(), "key-1", "other", time.Hour)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(record)
+	assert.Equal(suite.T(), "fingerprint", record.Fingerprint)
+
+	time.Sleep(20 * time.Millisecond)
+	record, err = suite.store.Begin(context.Background(), "key-1", "other", time.Hour)
+	suite.Require().NoError(err)
+	assert.Nil(suite.T(), record)
+}
+
+// TestServerError tests that server errors aren't stored, so the request can be retried.
+func (suite *IdempotencyTestSuite) TestServerError() {
+	body := `{"name": "Alice Smith", "age": 30}`
+	suite.status = http.StatusInternalServerError
+	suite.request("key-1", body, "acme")
+
+	suite.status = http.StatusCreated
+	w := suite.request("key-1", body, "acme")
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+	assert.Empty(suite.T(), w.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(suite.T(), 2, suite.created)
+}
+
+// TestTooLarge tests that requests with a key whose body is too large are rejected with a 413, unprocessed.
+func (suite *IdempotencyTestSuite) TestTooLarge() {
+	body := `{"name": "` + strings.Repeat("A", 100) + `", "age": 30}`
+
+	w := suite.request("key-1", body, "acme")
+	assert.Equal(suite.T(), http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(suite.T(), 0, suite.created)
+}
+
+// TestLargeResponse tests that responses too large to be stored aren't replayed, so the request can be retried.
+func (suite *IdempotencyTestSuite) TestLargeResponse() {
+	body := `{"name": "Alice Smith", "age": 30}`
+	suite.padding = strings.Repeat("x", 100)
+
+	suite.request("key-1", body, "acme")
+	w := suite.request("key-1", body, "acme")
+	assert.Equal(suite.T(), http.StatusCreated, w.Code)
+	assert.Empty(suite.T(), w.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(suite.T(), 2, suite.created)
+}
+
+// TestIdempotencyTestSuite runs the test suite.
+func TestIdempotencyTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyTestSuite))
+}
@@ -0,0 +1,394 @@
+package repo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	ijobs "github.com/Efamamo/GoCrudChallange/application/common/interface/jobs"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	jobcmd "github.com/Efamamo/GoCrudChallange/application/jobs/command"
+	jobquery "github.com/Efamamo/GoCrudChallange/application/jobs/query"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/attribute"
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/jobs"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/repository"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// blockingTask is a task reporting progress, then running until it is released or its context is done.
+type blockingTask struct {
+	started chan string   // Receives the subject of the principal of every job it starts
+	release chan struct{} // Closed to let the running jobs succeed
+}
+
+// Validate rejects the parameters asking for a failure.
+func (t *blockingTask) Validate(_ context.Context, params []byte) error {
+	if strings.Contains(string(params), "invalid") {
+		return errors.New("invalid params")
+	}
+	return nil
+}
+
+// Run reports progress, and waits to be released.
+func (t *blockingTask) Run(ctx context.Context, params []byte, report ijobs.Report) (any, error) {
+	subject := ""
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		subject = principal.Subject
+	}
+	t.started <- subject
+	report(job.Progress{Done: 1, Total: 2, Message: "halfway"})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-t.release:
+	}
+	if strings.Contains(string(params), "fail") {
+		return nil, errors.New("task failed")
+	}
+	return map[string]int{"done": 2}, nil
+}
+
+// JobsTestSuite is the test suite for background jobs, their runner and their routes.
+type JobsTestSuite struct {
+	suite.Suite
+	path   string
+	repo   *repository.JobRepo
+	runner *jobs.Runner
+	task   *blockingTask
+	cancel context.CancelFunc
+	engine *gin.Engine
+}
+
+// SetupTest starts a runner with a single worker over a job table persisted to a temporary file,
+// and builds a router submitting jobs as the principal named by the X-Subject header.
+func (suite *JobsTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.path = filepath.Join(suite.T().TempDir(), "jobs.json")
+	suite.task = &blockingTask{started: make(chan string, 10), release: make(chan struct{})}
+	suite.start()
+
+	authorizer := auth.NewAuthorizer(auth.DefaultPolicy())
+	jc := controller.JobController{
+		BaseController: controller.BaseController{Authorizer: authorizer},
+
+		SubmitHandler: jobcmd.NewSubmitJobHandler(suite.runner),
+		CancelHandler: jobcmd.NewCancelJobHandler(suite.runner, suite.repo, authorizer),
+		GetHandler:    jobquery.NewGetJobHandler(suite.repo, authorizer),
+		GetAllHandler: jobquery.NewGetJobsHandler(suite.repo, authorizer),
+	}
+	suite.engine = gin.New()
+	jobRoutes := suite.engine.Group("/jobs", func(c *gin.Context) {
+		ctx := auth.WithPrincipal(c.Request.Context(), &auth.Principal{Subject: c.GetHeader("X-Subject"), Roles: []string{c.GetHeader("X-Role")}})
+		c.Request = c.Request.WithContext(tenant.WithTenant(ctx, c.GetHeader("X-Tenant-ID")))
+	})
+	jobRoutes.POST("", jc.Submit)
+	jobRoutes.GET("", jc.GetAll)
+	jobRoutes.GET("/:id", jc.Get)
+	jobRoutes.POST("/:id/cancel", jc.Cancel)
+}
+
+// start starts a runner over the job table, as a restarted process would.
+func (suite *JobsTestSuite) start() {
+	repo, err := repository.NewJobRepo(suite.path)
+	suite.Require().NoError(err)
+	suite.repo = repo
+	suite.runner = jobs.NewRunner(jobs.RunnerConfig{Jobs: repo, Workers: 1, ReportInterval: time.Millisecond})
+	suite.runner.Register("test.block", suite.task)
+
+	var ctx context.Context
+	ctx, suite.cancel = context.WithCancel(context.Background())
+	suite.Require().NoError(suite.runner.Start(ctx))
+}
+
+// TearDownTest stops the runner, and waits for its workers.
+func (suite *JobsTestSuite) TearDownTest() {
+	suite.cancel()
+	suite.runner.Wait()
+}
+
+// request performs a request on the router as the subject, with the given role, and decodes the job in the response body.
+func (suite *JobsTestSuite) request(method string, path string, subject string, role string, body string) (*httptest.ResponseRecorder, controller.JobResponseDTO) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Subject", subject)
+	req.Header.Set("X-Role", role)
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+
+	var response controller.JobResponseDTO
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+// list lists the running jobs as the subject, with the given role.
+func (suite *JobsTestSuite) list(subject string, role string) []controller.JobResponseDTO {
+	req := httptest.NewRequest(http.MethodGet, "/jobs?status=running", nil)
+	req.Header.Set("X-Subject", subject)
+	req.Header.Set("X-Role", role)
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	var listed []controller.JobResponseDTO
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &listed))
+	return listed
+}
+
+// await waits until the job reaches the status, and returns it.
+func (suite *JobsTestSuite) await(id uuid.UUID, status job.Status) *job.Job {
+	var j *job.Job
+	suite.Require().Eventually(func() bool {
+		var err ierr.IErr
+		j, err = suite.repo.Get(id)
+		return err == nil && j.Status() == status
+	}, 2*time.Second, 5*time.Millisecond, "job %s never became %s", id, status)
+	return j
+}
+
+// TestSubmit tests that a submitted job is queued, runs on behalf of its submitter, reports progress and succeeds with its result.
+func (suite *JobsTestSuite) TestSubmit() {
+	w, submitted := suite.request(http.MethodPost, "/jobs", "alice", "editor", `{"kind": "test.block", "params": {"size": 2}}`)
+	suite.Require().Equal(http.StatusAccepted, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "/jobs/"+submitted.ID.String(), w.Header().Get("Location"))
+	assert.Equal(suite.T(), "queued", submitted.Status)
+	assert.Equal(suite.T(), "alice", submitted.Owner)
+	assert.JSONEq(suite.T(), `{"size": 2}`, string(submitted.Params))
+
+	assert.Equal(suite.T(), "alice", <-suite.task.started)
+	suite.Require().Eventually(func() bool {
+		_, running := suite.request(http.MethodGet, "/jobs/"+submitted.ID.String(), "alice", "viewer", "")
+		return running.Status == "running" && running.Progress.Done == 1 && running.Progress.Message == "halfway"
+	}, 2*time.Second, 5*time.Millisecond)
+
+	close(suite.task.release)
+	suite.await(submitted.ID, job.Succeeded)
+	w, succeeded := suite.request(http.MethodGet, "/jobs/"+submitted.ID.String(), "alice", "viewer", "")
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.JSONEq(suite.T(), `{"done": 2}`, string(succeeded.Result))
+	assert.NotNil(suite.T(), succeeded.StartedAt)
+	assert.NotNil(suite.T(), succeeded.FinishedAt)
+}
+
+// TestSubmit_Invalid tests that unknown kinds and parameters rejected by the task are reported as bad requests.
+func (suite *JobsTestSuite) TestSubmit_Invalid() {
+	w, _ := suite.request(http.MethodPost, "/jobs", "alice", "editor", `{"kind": "test.unknown"}`)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	w, _ = suite.request(http.MethodPost, "/jobs", "alice", "editor", `{"kind": "test.block", "params": "invalid"}`)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	w, _ = suite.request(http.MethodPost, "/jobs", "alice", "editor", `{"kind": "test.block", "params": "`+strings.Repeat("x", 64<<10)+`"}`)
+	assert.Equal(suite.T(), http.StatusRequestEntityTooLarge, w.Code)
+
+	w, _ = suite.request(http.MethodPost, "/jobs", "alice", "viewer", `{"kind": "test.block"}`)
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+}
+
+// TestFail tests that the error of a task fails its job, with the error as reason.
+func (suite *JobsTestSuite) TestFail() {
+	_, submitted := suite.request(http.MethodPost, "/jobs", "alice", "editor", `{"kind": "test.block", "params": "fail"}`)
+	<-suite.task.started
+	close(suite.task.release)
+
+	failed := suite.await(submitted.ID, job.Failed)
+	assert.Equal(suite.T(), "task failed", failed.Reason())
+}
+
+// TestCancel tests that a queued job is cancelled right away, a running one once its task stops, and a finished one not at all.
+func (suite *JobsTestSuite) TestCancel() {
+	_, running := suite.request(http.MethodPost, "/jobs", "alice", "editor", `{"kind": "test.block"}`)
+	<-suite.task.started
+	_, queued := suite.request(http.MethodPost, "/jobs", "alice", "editor", `{"kind": "test.block"}`)
+
+	w, cancelled := suite.request(http.MethodPost, "/jobs/"+queued.ID.String()+"/cancel", "alice", "editor", "")
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "cancelled", cancelled.Status)
+
+	w, cancelling := suite.request(http.MethodPost, "/jobs/"+running.ID.String()+"/cancel", "alice", "editor", "")
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	assert.True(suite.T(), cancelling.CancelRequested)
+	suite.await(running.ID, job.Cancelled)
+
+	w, _ = suite.request(http.MethodPost, "/jobs/"+running.ID.String()+"/cancel", "alice", "editor", "")
+	assert.Equal(suite.T(), http.StatusConflict, w.Code)
+	assert.Empty(suite.T(), suite.task.started, "the cancelled queued job should never run")
+}
+
+// TestAccess tests that callers only see and cancel their own jobs of their tenant, unless they are admins.
+func (suite *JobsTestSuite) TestAccess() {
+	_, submitted := suite.request(http.MethodPost, "/jobs", "alice", "editor", `{"kind": "test.block"}`)
+	<-suite.task.started
+
+	w, _ := suite.request(http.MethodGet, "/jobs/"+submitted.ID.String(), "bob", "viewer", "")
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+	w, _ = suite.request(http.MethodPost, "/jobs/"+submitted.ID.String()+"/cancel", "bob", "editor", "")
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/"+submitted.ID.String(), nil)
+	req.Header.Set("X-Subject", "alice")
+	req.Header.Set("X-Role", "viewer")
+	req.Header.Set("X-Tenant-ID", "acme")
+	w = httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+
+	assert.Empty(suite.T(), suite.list("bob", "viewer"))
+	assert.Len(suite.T(), suite.list("root", "admin"), 1)
+
+	w, _ = suite.request(http.MethodGet, "/jobs?status=unknown", "alice", "viewer", "")
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	close(suite.task.release)
+}
+
+// TestRestart tests that the job table survives restarts: queued jobs run, and the running ones are failed as interrupted.
+func (suite *JobsTestSuite) TestRestart() {
+	_, running := suite.request(http.MethodPost, "/jobs", "alice", "editor", `{"kind": "test.block"}`)
+	<-suite.task.started
+	_, queued := suite.request(http.MethodPost, "/jobs", "alice", "editor", `{"kind": "test.block"}`)
+
+	// Simulate a crash, leaving the job running in the table
+	interrupted, err := suite.repo.Get(running.ID)
+	suite.Require().Nil(err)
+	suite.cancel()
+	suite.runner.Wait()
+	suite.Require().Nil(suite.repo.Save(interrupted))
+
+	suite.start()
+	assert.Equal(suite.T(), "alice", <-suite.task.started)
+	close(suite.task.release)
+	suite.await(queued.ID, job.Succeeded)
+
+	failed := suite.await(running.ID, job.Failed)
+	assert.Equal(suite.T(), "interrupted by a restart", failed.Reason())
+}
+
+// TestRestart_TornRecord tests that the job table is loaded when a crash cut the last record of its log short.
+func (suite *JobsTestSuite) TestRestart_TornRecord() {
+	_, submitted := suite.request(http.MethodPost, "/jobs", "alice", "editor", `{"kind": "test.block"}`)
+	<-suite.task.started
+	close(suite.task.release)
+	suite.await(submitted.ID, job.Succeeded)
+	suite.cancel()
+	suite.runner.Wait()
+
+	log, err := os.OpenFile(suite.path, os.O_WRONLY|os.O_APPEND, 0)
+	suite.Require().NoError(err)
+	_, err = log.WriteString(`{"id": "` + submitted.ID.String() + `", "status": "run`)
+	suite.Require().NoError(err)
+	suite.Require().NoError(log.Close())
+
+	repo, err := repository.NewJobRepo(suite.path)
+	suite.Require().NoError(err)
+	j, gerr := repo.Get(submitted.ID)
+	suite.Require().Nil(gerr)
+	assert.Equal(suite.T(), job.Succeeded, j.Status())
+	assert.Nil(suite.T(), j.Params())
+}
+
+// TestPurge tests that purge jobs delete the people matching their attributes, along with their relations.
+func (suite *JobsTestSuite) TestPurge() {
+	people := mocks.NewMockPersonRepo()
+	attributes := mocks.NewMockAttributeRepo()
+	department, derr := attribute.CreateDefinition(&attribute.DefinitionConfig{Name: "department", Type: attribute.String})
+	suite.Require().Nil(derr)
+	suite.Require().Nil(attributes.Save(department))
+	create := command.NewCreatePersonHandler(people, attributes, nil)
+	for _, department := range []string{"sales", "sales", "marketing"} {
+		_, err := create.Handle(&command.CreatePersonCommand{
+			Context:    as("alice", "owner"),
+			Name:       "Alice Smith",
+			Age:        30,
+			Attributes: map[string]any{"department": department},
+		})
+		suite.Require().Nil(err)
+	}
+	suite.runner.Register(command.PurgeKind, command.NewPurgePeopleHandler(people, mocks.NewMockRelationRepo(), auth.NewAuthorizer(auth.DefaultPolicy()), nil))
+
+	w, _ := suite.request(http.MethodPost, "/jobs", "alice", "owner", `{"kind": "people.purge", "params": {}}`)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	w, denied := suite.request(http.MethodPost, "/jobs", "alice", "editor", `{"kind": "people.purge", "params": {"all": true}}`)
+	suite.Require().Equal(http.StatusAccepted, w.Code, w.Body.String())
+	assert.Contains(suite.T(), suite.await(denied.ID, job.Failed).Reason(), "person:delete")
+
+	w, submitted := suite.request(http.MethodPost, "/jobs", "alice", "owner", `{"kind": "people.purge", "params": {"attributes": {"department": "sales"}}}`)
+	suite.Require().Equal(http.StatusAccepted, w.Code, w.Body.String())
+	purged := suite.await(submitted.ID, job.Succeeded)
+	assert.JSONEq(suite.T(), `{"deleted": 2}`, string(purged.Result()))
+	assert.Equal(suite.T(), job.Progress{Done: 2, Total: 2, Message: "deleted people"}, purged.Progress())
+
+	remaining, err := people.GetAll(context.Background())
+	suite.Require().Nil(err)
+	suite.Require().Len(remaining, 1)
+	assert.Equal(suite.T(), "marketing", remaining[0].Attributes()["department"])
+}
+
+// TestSchedulePurge tests that the scheduled purge submits a purge job per tenant, which deletes the people
+// of every owner marked by the attributes.
+func (suite *JobsTestSuite) TestSchedulePurge() {
+	people := mocks.NewMockPersonRepo()
+	attributes := mocks.NewMockAttributeRepo()
+	deleted, derr := attribute.CreateDefinition(&attribute.DefinitionConfig{Name: "deleted", Type: attribute.Boolean})
+	suite.Require().Nil(derr)
+	suite.Require().Nil(attributes.Save(deleted))
+	create := command.NewCreatePersonHandler(people, attributes, nil)
+	for _, p := range []struct {
+		tenant  string
+		owner   string
+		deleted bool
+	}{{"acme", "alice", true}, {"acme", "bob", true}, {"acme", "bob", false}, {"globex", "alice", true}} {
+		_, err := create.Handle(&command.CreatePersonCommand{
+			Context:    tenant.WithTenant(as(p.owner, "owner"), p.tenant),
+			Name:       "Alice Smith",
+			Age:        30,
+			Attributes: map[string]any{"deleted": p.deleted},
+		})
+		suite.Require().Nil(err)
+	}
+	suite.runner.Register(command.PurgeKind, command.NewPurgePeopleHandler(people, mocks.NewMockRelationRepo(), auth.NewAuthorizer(auth.DefaultPolicy()), nil))
+
+	purge, perr := command.ParsePurgeAttributes([]string{"deleted=true"})
+	suite.Require().Nil(perr)
+	message, err := command.NewSchedulePurgeHandler(people, suite.runner, purge).Run(context.Background())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "submitted 2 purge jobs", message)
+
+	submitted, gerr := suite.repo.GetAll()
+	suite.Require().Nil(gerr)
+	suite.Require().Len(submitted, 2)
+	for _, j := range submitted {
+		assert.Equal(suite.T(), command.SchedulerSubject, j.Owner())
+		suite.await(j.Id(), job.Succeeded)
+	}
+
+	acme, _ := people.GetAll(tenant.WithTenant(context.Background(), "acme"))
+	suite.Require().Len(acme, 1)
+	assert.Equal(suite.T(), false, acme[0].Attributes()["deleted"])
+	globex, _ := people.GetAll(tenant.WithTenant(context.Background(), "globex"))
+	assert.Empty(suite.T(), globex)
+
+	_, perr = command.ParsePurgeAttributes([]string{"deleted"})
+	assert.NotNil(suite.T(), perr)
+}
+
+// TestJobsTestSuite runs the test suite.
+func TestJobsTestSuite(t *testing.T) {
+	suite.Run(t, new(JobsTestSuite))
+}
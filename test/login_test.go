@@ -0,0 +1,171 @@
+package repo_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	"github.com/Efamamo/GoCrudChallange/api/middleware"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	usercmd "github.com/Efamamo/GoCrudChallange/application/users/command"
+	userquery "github.com/Efamamo/GoCrudChallange/application/users/query"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/token"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// LoginTestSuite is the test suite for local users logging in, refreshing and logging out.
+type LoginTestSuite struct {
+	suite.Suite
+	engine *gin.Engine
+}
+
+// SetupTest builds a router issuing tokens for a single user and accepting them on a protected route.
+func (suite *LoginTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	users := mocks.NewMockUserRepo()
+	tokens := mocks.NewMockTokenRepo()
+
+	_, err := usercmd.NewCreateUserHandler(users).Handle(&usercmd.CreateUserCommand{
+		Username: "Alice",
+		Password: "correct horse",
+		Roles:    []string{"viewer"},
+		Tenant:   "acme",
+	})
+	suite.Require().Nil(err)
+
+	secret := "test-secret"
+	issuer := token.NewJWTIssuer(token.JWTIssuerConfig{HS256Secret: secret, TTL: time.Minute})
+	authentication, aerr := middleware.NewAuthentication(middleware.AuthenticationConfig{
+		HS256Secret: secret,
+		Revocations: userquery.NewIsTokenRevokedHandler(tokens),
+	})
+	suite.Require().NoError(aerr)
+
+	ac := controller.AuthController{
+		LoginHandler:   usercmd.NewLoginHandler(users, tokens, issuer, time.Hour),
+		RefreshHandler: usercmd.NewRefreshHandler(users, tokens, issuer, time.Hour),
+		LogoutHandler:  usercmd.NewLogoutHandler(tokens),
+	}
+
+	suite.engine = gin.New()
+	suite.engine.POST("/auth/login", ac.Login)
+	suite.engine.POST("/auth/refresh", ac.Refresh)
+	suite.engine.POST("/auth/logout", authentication.Handler(), ac.Logout)
+	suite.engine.GET("/me", authentication.Handler(), func(c *gin.Context) {
+		principal, _ := auth.PrincipalFrom(c.Request.Context())
+		c.String(http.StatusOK, principal.Tenant)
+	})
+}
+
+// post sends a JSON body to the router, with a bearer token when given.
+func (suite *LoginTestSuite) post(path string, body string, bearer string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	return w
+}
+
+// me requests the protected route with the bearer token.
+func (suite *LoginTestSuite) me(bearer string) int {
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	return w.Code
+}
+
+// login logs in with the password and decodes the issued tokens.
+func (suite *LoginTestSuite) login(password string) (*httptest.ResponseRecorder, controller.TokenResponseDTO) {
+	w := suite.post("/auth/login", `{"username": "alice", "password": "`+password+`"}`, "")
+	var tokens controller.TokenResponseDTO
+	json.Unmarshal(w.Body.Bytes(), &tokens)
+	return w, tokens
+}
+
+// TestLogin tests that valid credentials get tokens accepted by the middleware.
+func (suite *LoginTestSuite) TestLogin() {
+	w, tokens := suite.login("correct horse")
+
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "Bearer", tokens.TokenType)
+	assert.NotEmpty(suite.T(), tokens.RefreshToken)
+	assert.Equal(suite.T(), http.StatusOK, suite.me(tokens.AccessToken))
+}
+
+// TestLogin_Tenant tests that the tokens of a user confine it to its tenant.
+func (suite *LoginTestSuite) TestLogin_Tenant() {
+	_, tokens := suite.login("correct horse")
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "acme", w.Body.String())
+}
+
+// TestLogin_WrongPassword tests that wrong credentials are rejected with the message of the error.
+func (suite *LoginTestSuite) TestLogin_WrongPassword() {
+	w, _ := suite.login("wrong horse")
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "invalid username or password")
+}
+
+// TestLogin_UnknownUser tests that unknown usernames are rejected as wrong passwords are, after checking
+// a password as well, so that neither the response nor its timing reveals which usernames exist.
+func (suite *LoginTestSuite) TestLogin_UnknownUser() {
+	started := time.Now()
+	wrong, _ := suite.login("wrong horse")
+	checked := time.Since(started)
+
+	started = time.Now()
+	unknown := suite.post("/auth/login", `{"username": "mallory", "password": "wrong horse"}`, "")
+	elapsed := time.Since(started)
+
+	assert.Equal(suite.T(), wrong.Code, unknown.Code)
+	assert.Equal(suite.T(), wrong.Body.String(), unknown.Body.String())
+	assert.Greater(suite.T(), elapsed, checked/4)
+}
+
+// TestRefresh tests that a refresh token issues new tokens and can only be used once.
+func (suite *LoginTestSuite) TestRefresh() {
+	_, tokens := suite.login("correct horse")
+	body := `{"refresh_token": "` + tokens.RefreshToken + `"}`
+
+	w := suite.post("/auth/refresh", body, "")
+	suite.Require().Equal(http.StatusOK, w.Code)
+	var refreshed controller.TokenResponseDTO
+	json.Unmarshal(w.Body.Bytes(), &refreshed)
+	assert.NotEqual(suite.T(), tokens.RefreshToken, refreshed.RefreshToken)
+	assert.Equal(suite.T(), http.StatusOK, suite.me(refreshed.AccessToken))
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, suite.post("/auth/refresh", body, "").Code)
+}
+
+// TestLogout tests that logging out revokes both the access token and the refresh token.
+func (suite *LoginTestSuite) TestLogout() {
+	_, tokens := suite.login("correct horse")
+	body := `{"refresh_token": "` + tokens.RefreshToken + `"}`
+
+	assert.Equal(suite.T(), http.StatusNoContent, suite.post("/auth/logout", body, tokens.AccessToken).Code)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, suite.me(tokens.AccessToken))
+	assert.Equal(suite.T(), http.StatusUnauthorized, suite.post("/auth/refresh", body, "").Code)
+}
+
+// TestLoginTestSuite runs the test suite for local users.
+func TestLoginTestSuite(t *testing.T) {
+	suite.Run(t, new(LoginTestSuite))
+}
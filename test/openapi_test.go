@@ -0,0 +1,113 @@
+package repo_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	"github.com/Efamamo/GoCrudChallange/api/docs"
+	"github.com/Efamamo/GoCrudChallange/api/router"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// openAPIDocument is the part of an OpenAPI document checked against the router.
+type openAPIDocument struct {
+	OpenAPI    string                               `json:"openapi"`
+	Paths      map[string]map[string]map[string]any `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]any `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+// OpenAPITestSuite is the test suite for the OpenAPI document describing the API.
+type OpenAPITestSuite struct {
+	suite.Suite
+	engine   *gin.Engine
+	document openAPIDocument
+}
+
+// SetupTest builds the router of the API and parses the OpenAPI document.
+func (suite *OpenAPITestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.engine = router.NewRouter(router.Config{}).Engine(
+		controller.PersonController{}, controller.RelationController{}, controller.AttributeController{},
+		controller.APIKeyController{}, controller.AuthController{}, controller.UserController{}, controller.GraphQLController{}, controller.EventController{}, controller.WebhookController{}, controller.JobController{}, controller.ScheduleController{},
+	)
+	suite.Require().NoError(json.Unmarshal(docs.OpenAPI, &suite.document))
+}
+
+// pathParam matches the parameters of gin routes, e.g. ":id".
+var pathParam = regexp.MustCompile(`:([A-Za-z]+)`)
+
+// TestRoutes_Documented tests that every route registered in the router is described, and the other way around.
+func (suite *OpenAPITestSuite) TestRoutes_Documented() {
+	assert.True(suite.T(), strings.HasPrefix(suite.document.OpenAPI, "3.1"))
+
+	registered := make(map[string]bool)
+	for _, route := range suite.engine.Routes() {
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		method := strings.ToLower(route.Method)
+		registered[method+" "+path] = true
+
+		_, ok := suite.document.Paths[path][method]
+		assert.True(suite.T(), ok, "%s %s is missing from the OpenAPI document", route.Method, route.Path)
+	}
+
+	for path, operations := range suite.document.Paths {
+		for method := range operations {
+			assert.True(suite.T(), registered[method+" "+path], "%s %s is documented but not registered", method, path)
+		}
+	}
+}
+
+// TestSchemas_MatchDTOs tests that the schemas of people describe every field of their DTOs.
+func (suite *OpenAPITestSuite) TestSchemas_MatchDTOs() {
+	dtos := map[string]any{
+		"PersonInput":   controller.CreateDTO{},
+		"Person":        controller.ResponseDTO{},
+		"Address":       controller.AddressDTO{},
+		"Merge":         controller.MergeDTO{},
+		"MergeResponse": controller.MergeResponseDTO{},
+		"RelationInput": controller.CreateRelationDTO{},
+		"Relation":      controller.RelationResponseDTO{},
+	}
+
+	for name, dto := range dtos {
+		schema, ok := suite.document.Components.Schemas[name]
+		suite.Require().True(ok, "schema %s is missing", name)
+
+		fields := reflect.TypeOf(dto)
+		for i := 0; i < fields.NumField(); i++ {
+			field, _, _ := strings.Cut(fields.Field(i).Tag.Get("json"), ",")
+			_, ok := schema.Properties[field]
+			assert.True(suite.T(), ok, "field %s of %s is missing from the schema", field, name)
+		}
+	}
+}
+
+// TestServe tests that the document and the Swagger UI are served without authentication.
+func (suite *OpenAPITestSuite) TestServe() {
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.JSONEq(suite.T(), string(docs.OpenAPI), w.Body.String())
+
+	w = httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), "/openapi.json")
+}
+
+// TestOpenAPITestSuite runs the test suite.
+func TestOpenAPITestSuite(t *testing.T) {
+	suite.Run(t, new(OpenAPITestSuite))
+}
@@ -0,0 +1,85 @@
+package repo_test
+
+import (
+	"testing"
+
+	attrcmd "github.com/Efamamo/GoCrudChallange/application/attributes/command"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/Efamamo/GoCrudChallange/application/people/query"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/attribute"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// PersonAttributesTestSuite is the test suite for custom attributes on people.
+type PersonAttributesTestSuite struct {
+	suite.Suite
+	mockRepo          *mocks.MockPersonRepo
+	mockAttributeRepo *mocks.MockAttributeRepo
+}
+
+// SetupTest initializes the mock repositories and defines a department and a shirt size attribute.
+func (suite *PersonAttributesTestSuite) SetupTest() {
+	suite.mockRepo = mocks.NewMockPersonRepo()
+	suite.mockAttributeRepo = mocks.NewMockAttributeRepo()
+
+	handler := attrcmd.NewSaveAttributeHandler(suite.mockAttributeRepo)
+	_, err := handler.Handle(&attrcmd.SaveAttributeCommand{Name: "department", Type: attribute.String, Required: true})
+	suite.Require().Nil(err)
+
+	min, max := 36.0, 56.0
+	_, err = handler.Handle(&attrcmd.SaveAttributeCommand{Name: "shirt_size", Type: attribute.Integer, Min: &min, Max: &max})
+	suite.Require().Nil(err)
+}
+
+// TestCreatePersonHandler_ValidatesAttributes tests that attributes are checked against the schema.
+func (suite *PersonAttributesTestSuite) TestCreatePersonHandler_ValidatesAttributes() {
+	handler := command.NewCreatePersonHandler(suite.mockRepo, suite.mockAttributeRepo, nil)
+
+	cases := map[string]map[string]any{
+		"missing required": {"shirt_size": 40.0},
+		"unknown":          {"department": "sales", "nickname": "JD"},
+		"wrong type":       {"department": 7.0},
+		"out of range":     {"department": "sales", "shirt_size": 60.0},
+		"not an integer":   {"department": "sales", "shirt_size": 40.5},
+	}
+	for name, attributes := range cases {
+		_, err := handler.Handle(&command.CreatePersonCommand{Name: "John Doe", Age: 30, Attributes: attributes})
+		if assert.NotNil(suite.T(), err, name) {
+			assert.Equal(suite.T(), ierr.Validation, err.Type(), name)
+		}
+	}
+
+	person, err := handler.Handle(&command.CreatePersonCommand{
+		Name:       "John Doe",
+		Age:        30,
+		Attributes: map[string]any{"department": "sales", "shirt_size": 40.0},
+	})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), map[string]any{"department": "sales", "shirt_size": int64(40)}, person.Attributes())
+}
+
+// TestGetPeopleHandler_FiltersOnAttributes tests listing people with attribute filters.
+func (suite *PersonAttributesTestSuite) TestGetPeopleHandler_FiltersOnAttributes() {
+	create := command.NewCreatePersonHandler(suite.mockRepo, suite.mockAttributeRepo, nil)
+	sales, _ := create.Handle(&command.CreatePersonCommand{
+		Name: "John Doe", Age: 30, Attributes: map[string]any{"department": "sales", "shirt_size": 40.0},
+	})
+	create.Handle(&command.CreatePersonCommand{
+		Name: "Jane Doe", Age: 30, Attributes: map[string]any{"department": "support", "shirt_size": 40.0},
+	})
+
+	people, err := query.NewGetPeopleHandler(suite.mockRepo, nil).Handle(&query.GetPeopleQuery{
+		Attributes: map[string]string{"department": "sales", "shirt_size": "40"},
+	})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), people, 1)
+	assert.Equal(suite.T(), sales.Id(), people[0].Id())
+}
+
+// TestPersonAttributesTestSuite runs the test suite for custom attributes on people.
+func TestPersonAttributesTestSuite(t *testing.T) {
+	suite.Run(t, new(PersonAttributesTestSuite))
+}
@@ -0,0 +1,86 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// PersonContactTestSuite is the test suite for the contact details of people.
+type PersonContactTestSuite struct {
+	suite.Suite
+	mockRepo          *mocks.MockPersonRepo
+	mockAttributeRepo *mocks.MockAttributeRepo
+}
+
+// SetupTest initializes the mock repository before each test.
+func (suite *PersonContactTestSuite) SetupTest() {
+	suite.mockRepo = mocks.NewMockPersonRepo()
+	suite.mockAttributeRepo = mocks.NewMockAttributeRepo()
+}
+
+// TestValueObjects_Validation tests the validation of the email, phone number and address value objects.
+func (suite *PersonContactTestSuite) TestValueObjects_Validation() {
+	email, err := model.NewEmail("John.Doe@Example.com")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "john.doe@example.com", email.String())
+
+	_, err = model.NewEmail("John Doe <john@example.com>")
+	assert.Equal(suite.T(), ierr.Validation, err.Type())
+
+	phone, err := model.NewPhoneNumber("+251 (91) 123-4567")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "+251911234567", phone.String())
+
+	_, err = model.NewPhoneNumber("0911234567")
+	assert.Equal(suite.T(), ierr.Validation, err.Type())
+
+	address, err := model.NewAddress(&model.AddressConfig{Street: "Bole Road 12", City: "Addis Ababa", Country: "et"})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "ET", address.Country())
+
+	_, err = model.NewAddress(&model.AddressConfig{Street: "Bole Road 12", City: "Addis Ababa", Country: "ETH"})
+	assert.Equal(suite.T(), ierr.Validation, err.Type())
+}
+
+// TestCreatePersonHandler_Failure_DuplicateEmail tests that two people can't share the same email.
+func (suite *PersonContactTestSuite) TestCreatePersonHandler_Failure_DuplicateEmail() {
+	handler := command.NewCreatePersonHandler(suite.mockRepo, suite.mockAttributeRepo, nil)
+
+	_, err := handler.Handle(&command.CreatePersonCommand{Name: "John Doe", Age: 30, Email: "john@example.com"})
+	assert.Nil(suite.T(), err)
+
+	result, err := handler.Handle(&command.CreatePersonCommand{Name: "Johnny Doe", Age: 31, Email: "JOHN@example.com"})
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), ierr.Conflict, err.Type())
+}
+
+// TestUpdatePersonHandler_Failure_InvalidContact tests that invalid contact details leave the person unchanged.
+func (suite *PersonContactTestSuite) TestUpdatePersonHandler_Failure_InvalidContact() {
+	existing, _ := model.CreatePerson(&model.PersonConfig{Name: "John Doe", Age: 30, Email: "john@example.com"})
+	suite.mockRepo.Save(context.Background(), existing)
+
+	handler := command.NewUpdatePersonHandler(suite.mockRepo, suite.mockAttributeRepo, nil, nil)
+
+	result, err := handler.Handle(&command.UpdatePersonCommand{
+		ID:    existing.Id(),
+		Name:  "John Doe",
+		Age:   30,
+		Email: "jane@example.com",
+		Phone: "not a phone",
+	})
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), ierr.Validation, err.Type())
+	assert.Equal(suite.T(), "john@example.com", existing.Email().String())
+}
+
+// TestPersonContactTestSuite runs the test suite for the contact details of people.
+func TestPersonContactTestSuite(t *testing.T) {
+	suite.Run(t, new(PersonContactTestSuite))
+}
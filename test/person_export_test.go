@@ -0,0 +1,201 @@
+package repo_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	"github.com/Efamamo/GoCrudChallange/api/router"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/Efamamo/GoCrudChallange/application/people/query"
+	"github.com/Efamamo/GoCrudChallange/domain/model/attribute"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/repository"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// PersonExportTestSuite is the test suite for the streaming exports of people.
+type PersonExportTestSuite struct {
+	suite.Suite
+	repo   *repository.PersonRepo
+	engine *gin.Engine
+}
+
+// SetupTest builds the router of the API, over a repository keeping people in the order they were created,
+// and a schema with a department attribute.
+func (suite *PersonExportTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.repo = repository.NewPersonRepo(model.UniqueName, tenant.Quotas{})
+	attributes := mocks.NewMockAttributeRepo()
+	department, err := attribute.CreateDefinition(&attribute.DefinitionConfig{Name: "department", Type: attribute.String})
+	suite.Require().Nil(err)
+	suite.Require().Nil(attributes.Save(department))
+
+	pc := controller.PersonController{
+		CreateHandler: command.NewCreatePersonHandler(suite.repo, attributes, nil),
+		ExportHandler: query.NewExportPeopleHandler(suite.repo, attributes, nil),
+	}
+	suite.engine = router.NewRouter(router.Config{}).Engine(
+		pc, controller.RelationController{}, controller.AttributeController{},
+		controller.APIKeyController{}, controller.AuthController{}, controller.UserController{}, controller.GraphQLController{}, controller.EventController{}, controller.WebhookController{}, controller.JobController{}, controller.ScheduleController{},
+	)
+
+	suite.create(`{"name": "Alice Smith", "age": 30, "hobbies": ["chess", "hiking"], "attributes": {"department": "sales"}}`)
+	suite.create(`{"name": "Bobby Jones", "age": 41, "address": {"street": "1 Main St", "city": "Springfield", "country": "US"}}`)
+	suite.create(`{"name": "Carol White", "age": 25, "attributes": {"department": "sales"}}`)
+}
+
+// create creates a person from a JSON body.
+func (suite *PersonExportTestSuite) create(body string) {
+	req := httptest.NewRequest(http.MethodPost, "/v1/person", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	suite.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+}
+
+// request exports people with the given headers.
+func (suite *PersonExportTestSuite) request(path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	return w
+}
+
+// TestExport_NDJSON tests that people are exported as a person per line by default, in the order they were created.
+func (suite *PersonExportTestSuite) TestExport_NDJSON() {
+	w := suite.request("/v1/person/export", nil)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Equal(suite.T(), `attachment; filename="people.ndjson"`, w.Header().Get("Content-Disposition"))
+
+	var names []string
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var person controller.ResponseDTO
+		suite.Require().NoError(json.Unmarshal(scanner.Bytes(), &person))
+		names = append(names, person.Name)
+	}
+	assert.Equal(suite.T(), []string{"Alice Smith", "Bobby Jones", "Carol White"}, names)
+}
+
+// TestExport_CSV tests that CSV exports have fixed columns, including one per attribute of the schema.
+func (suite *PersonExportTestSuite) TestExport_CSV() {
+	w := suite.request("/v1/person/export?format=csv", nil)
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	suite.Require().NoError(err)
+	suite.Require().Len(records, 4)
+	assert.Equal(suite.T(), []string{
+		"id", "name", "age", "hobbies", "email", "phone",
+		"address.street", "address.city", "address.region", "address.postal_code", "address.country",
+		"attributes.department", "owner",
+	}, records[0])
+	assert.Equal(suite.T(), []string{"Alice Smith", "30", "chess;hiking", "", "", "", "", "", "", "", "sales", ""}, records[1][1:])
+	assert.Equal(suite.T(), []string{"Bobby Jones", "41", "", "", "", "1 Main St", "Springfield", "", "", "US", "", ""}, records[2][1:])
+}
+
+// TestExport_JSON tests that JSON exports are an array of the people of the version, filtered on attributes.
+func (suite *PersonExportTestSuite) TestExport_JSON() {
+	w := suite.request("/v2/person/export?attributes[department]=sales", map[string]string{"Accept": "application/json"})
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(suite.T(), "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	var people []controller.PersonV2DTO
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &people))
+	suite.Require().Len(people, 2)
+	assert.Equal(suite.T(), "Alice Smith", people[0].Name)
+	assert.Equal(suite.T(), "Carol White", people[1].Name)
+	assert.False(suite.T(), people[0].CreatedAt.IsZero())
+
+	w = suite.request("/v2/person/export?format=json&attributes[department]=marketing", nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.JSONEq(suite.T(), `[]`, w.Body.String())
+}
+
+// TestExport_Gzip tests that exports are compressed when the Accept-Encoding header or the gzip query parameter asks for it.
+func (suite *PersonExportTestSuite) TestExport_Gzip() {
+	for _, tc := range []struct {
+		path     string
+		encoding string
+		gzipped  bool
+	}{
+		{"/v1/person/export", "gzip, deflate", true},
+		{"/v1/person/export", "gzip;q=0", false},
+		{"/v1/person/export?gzip=true", "", true},
+		{"/v1/person/export?gzip=false", "gzip", false},
+	} {
+		w := suite.request(tc.path, map[string]string{"Accept-Encoding": tc.encoding})
+		suite.Require().Equal(http.StatusOK, w.Code)
+
+		body := io.Reader(w.Body)
+		if tc.gzipped {
+			assert.Equal(suite.T(), "gzip", w.Header().Get("Content-Encoding"), tc.path)
+			reader, err := gzip.NewReader(w.Body)
+			suite.Require().NoError(err)
+			body = reader
+		} else {
+			assert.Empty(suite.T(), w.Header().Get("Content-Encoding"), tc.path)
+		}
+
+		raw, err := io.ReadAll(body)
+		suite.Require().NoError(err)
+		assert.Equal(suite.T(), 3, bytes.Count(raw, []byte("\n")), fmt.Sprintf("%s with %q", tc.path, tc.encoding))
+	}
+}
+
+// TestExport_Invalid tests that unsupported formats and invalid query parameters are rejected.
+func (suite *PersonExportTestSuite) TestExport_Invalid() {
+	w := suite.request("/v1/person/export?format=xml", nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	w = suite.request("/v1/person/export", map[string]string{"Accept": "application/xml"})
+	assert.Equal(suite.T(), http.StatusNotAcceptable, w.Code)
+
+	w = suite.request("/v1/person/export?gzip=maybe", nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+// TestStream tests that the repository streams people in order, and stops when asked to.
+func (suite *PersonExportTestSuite) TestStream() {
+	var ids []uuid.UUID
+	err := suite.repo.Stream(context.Background(), func(p *model.Person) bool {
+		ids = append(ids, p.Id())
+		return true
+	})
+	suite.Require().Nil(err)
+	suite.Require().Len(ids, 3)
+
+	suite.Require().Nil(suite.repo.Delete(context.Background(), ids[1]))
+	var names []string
+	err = suite.repo.Stream(context.Background(), func(p *model.Person) bool {
+		names = append(names, p.Name())
+		return false
+	})
+	suite.Require().Nil(err)
+	assert.Equal(suite.T(), []string{"Alice Smith"}, names)
+}
+
+// TestPersonExportTestSuite runs the test suite.
+func TestPersonExportTestSuite(t *testing.T) {
+	suite.Run(t, new(PersonExportTestSuite))
+}
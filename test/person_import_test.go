@@ -0,0 +1,247 @@
+package repo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	jobquery "github.com/Efamamo/GoCrudChallange/application/jobs/query"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/jobs"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/repository"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// PersonImportTestSuite is the test suite for the bulk imports of people.
+type PersonImportTestSuite struct {
+	suite.Suite
+	repo    *mocks.MockPersonRepo
+	uploads string // Directory of the uploads imported in the background
+	runner  *jobs.Runner
+	cancel  context.CancelFunc
+	router  *gin.Engine
+}
+
+// SetupTest serves the imports to owners named by the X-Subject header. Uploads larger than
+// 200 bytes are imported by a job, and uploads larger than 1000 bytes are rejected.
+func (suite *PersonImportTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.repo = mocks.NewMockPersonRepo()
+	attributes := mocks.NewMockAttributeRepo()
+	authorizer := auth.NewAuthorizer(auth.DefaultPolicy())
+	suite.uploads = suite.T().TempDir()
+	uploads, err := repository.NewUploadRepo(suite.uploads)
+	suite.Require().NoError(err)
+	importer := command.NewImportPeopleHandler(suite.repo, attributes, uploads, nil, controller.ImportRows, 1000)
+
+	jobRepo, err := repository.NewJobRepo("")
+	suite.Require().NoError(err)
+	suite.runner = jobs.NewRunner(jobs.RunnerConfig{Jobs: jobRepo, Workers: 1, ReportInterval: time.Millisecond})
+	suite.runner.Register(command.ImportKind, importer)
+	var ctx context.Context
+	ctx, suite.cancel = context.WithCancel(context.Background())
+	suite.Require().NoError(suite.runner.Start(ctx))
+
+	pc := controller.PersonController{
+		BaseController:     controller.BaseController{Authorizer: authorizer},
+		CreateHandler:      command.NewCreatePersonHandler(suite.repo, attributes, nil),
+		ImportHandler:      importer,
+		StartImportHandler: command.NewStartImportHandler(uploads, suite.runner),
+		ImportSyncLimit:    200,
+		ImportMaxSize:      1000,
+	}
+	jc := controller.JobController{
+		BaseController: controller.BaseController{Authorizer: authorizer},
+		GetHandler:     jobquery.NewGetJobHandler(jobRepo, authorizer),
+	}
+
+	suite.router = gin.New()
+	suite.router.Use(func(c *gin.Context) {
+		principal := &auth.Principal{Subject: c.GetHeader("X-Subject"), Roles: []string{"owner"}}
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+	})
+	suite.router.POST("/person/import", pc.Import)
+	suite.router.GET("/jobs/:id", jc.Get)
+}
+
+// TearDownTest stops the runner, and waits for its workers.
+func (suite *PersonImportTestSuite) TearDownTest() {
+	suite.cancel()
+	suite.runner.Wait()
+}
+
+// upload imports the body as alice, and decodes the response.
+func (suite *PersonImportTestSuite) upload(query string, contentType string, body []byte) (*httptest.ResponseRecorder, controller.ImportResponseDTO) {
+	req := httptest.NewRequest(http.MethodPost, "/person/import"+query, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Subject", "alice")
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var response controller.ImportResponseDTO
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+// poll retrieves the job of an import as the subject.
+func (suite *PersonImportTestSuite) poll(location string, subject string) (*httptest.ResponseRecorder, controller.JobResponseDTO) {
+	req := httptest.NewRequest(http.MethodGet, location, nil)
+	req.Header.Set("X-Subject", subject)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	var response controller.JobResponseDTO
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+// TestImport_CSV tests that the valid rows of a CSV upload are created, and the others reported with their line.
+func (suite *PersonImportTestSuite) TestImport_CSV() {
+	body := "\ufeffname,age,hobbies,email\nAlice Smith,30,chess;hiking,alice@example.com\nBobby Jones,abc,,\nCar\"ol,40,,\nDavid Brown,41,,\n"
+	w, report := suite.upload("", "text/csv", []byte(body))
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	assert.Equal(suite.T(), "completed", report.Status)
+	assert.Equal(suite.T(), 4, report.Processed)
+	assert.Equal(suite.T(), 2, report.Succeeded)
+	assert.Equal(suite.T(), 2, report.Failed)
+	assert.NotNil(suite.T(), report.FinishedAt)
+
+	lines := make([]int, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		lines = append(lines, row.Line)
+	}
+	assert.Equal(suite.T(), []int{2, 3, 4, 5}, lines)
+	assert.NotNil(suite.T(), report.Rows[0].ID)
+	assert.NotEmpty(suite.T(), report.Rows[1].Error)
+	assert.NotEmpty(suite.T(), report.Rows[2].Error)
+
+	alice, err := suite.repo.Get(context.Background(), *report.Rows[0].ID)
+	suite.Require().Nil(err)
+	assert.Equal(suite.T(), []string{"chess", "hiking"}, alice.Hobbies())
+	assert.Equal(suite.T(), "alice", alice.Owner())
+}
+
+// TestImport_NDJSON tests that NDJSON uploads sent as the file of a multipart form are imported a line at a time.
+func (suite *PersonImportTestSuite) TestImport_NDJSON() {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("file", "people.ndjson")
+	suite.Require().NoError(err)
+	file.Write([]byte(`{"name": "Alice Smith", "age": 30}` + "\n\n" + `{"name": "", "age": 30}` + "\n" + `{"name": "Bobby Jones",` + "\n"))
+	suite.Require().NoError(form.Close())
+
+	w, report := suite.upload("", form.FormDataContentType(), body.Bytes())
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(suite.T(), 1, report.Succeeded)
+	assert.Equal(suite.T(), 2, report.Failed)
+	assert.Equal(suite.T(), 3, report.Rows[1].Line)
+	assert.Equal(suite.T(), 4, report.Rows[2].Line)
+}
+
+// TestImport_DryRun tests that a dry run validates every row without creating anyone, and reports duplicate IDs.
+func (suite *PersonImportTestSuite) TestImport_DryRun() {
+	id := "5c7b7e6e-9d4a-4b7e-8f1e-2f8e1a6b9c01"
+	body := "id,name,age\n" + id + ",Alice Smith,30\n" + id + ",Bobby Jones,41\n,Carol White,-1\n"
+	w, report := suite.upload("?dry_run=true", "text/csv", []byte(body))
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	assert.True(suite.T(), report.DryRun)
+	assert.Equal(suite.T(), 1, report.Succeeded)
+	assert.Contains(suite.T(), report.Rows[1].Error, "line 2")
+	assert.NotEmpty(suite.T(), report.Rows[2].Error)
+
+	people, _ := suite.repo.GetAll(context.Background())
+	assert.Empty(suite.T(), people)
+}
+
+// TestImport_Background tests that large uploads are imported by a job, whose result is polled by its owner,
+// and that the upload is kept apart from the job until it finished.
+func (suite *PersonImportTestSuite) TestImport_Background() {
+	body := "name,age\n" + strings.Repeat("Alice Smith,30\n", 20)
+	w, _ := suite.upload("", "text/csv", []byte(body))
+	suite.Require().Equal(http.StatusAccepted, w.Code, w.Body.String())
+
+	var submitted controller.JobResponseDTO
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &submitted))
+	assert.Equal(suite.T(), command.ImportKind, submitted.Kind)
+	location := w.Header().Get("Location")
+	assert.Equal(suite.T(), "/jobs/"+submitted.ID.String(), location)
+	var params command.ImportParams
+	suite.Require().NoError(json.Unmarshal(submitted.Params, &params))
+	assert.NotEqual(suite.T(), uuid.Nil, params.UploadID)
+	assert.NotContains(suite.T(), string(submitted.Params), "Alice Smith")
+
+	var result command.ImportResult
+	var finished controller.JobResponseDTO
+	assert.Eventually(suite.T(), func() bool {
+		_, finished = suite.poll(location, "alice")
+		return finished.Status == "succeeded" && json.Unmarshal(finished.Result, &result) == nil
+	}, time.Second, 10*time.Millisecond)
+	assert.Empty(suite.T(), finished.Params)
+	assert.NoFileExists(suite.T(), filepath.Join(suite.uploads, tenant.Default, params.UploadID.String()))
+	assert.Equal(suite.T(), 20, result.Succeeded)
+	assert.Len(suite.T(), result.Rows, 20)
+
+	people, _ := suite.repo.GetAll(context.Background())
+	suite.Require().Len(people, 20)
+	assert.Equal(suite.T(), "alice", people[0].Owner())
+
+	w, _ = suite.poll(location, "bobby")
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+// TestImport_InvalidUploads tests that uploads in other formats, or too large, are rejected.
+func (suite *PersonImportTestSuite) TestImport_InvalidUploads() {
+	w, _ := suite.upload("", "application/json", []byte(`[{"name": "Alice Smith", "age": 30}]`))
+	assert.Equal(suite.T(), http.StatusUnsupportedMediaType, w.Code)
+
+	w, _ = suite.upload("", "text/csv", []byte("name,age\n"+strings.Repeat("Alice Smith,30\n", 100)))
+	assert.Equal(suite.T(), http.StatusRequestEntityTooLarge, w.Code)
+
+	w, _ = suite.upload("?dry_run=maybe", "text/csv", []byte("name,age\n"))
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+// TestImport_JobLimits tests that import jobs are only submitted for uploads of the tenant no larger than the limit.
+func (suite *PersonImportTestSuite) TestImport_JobLimits() {
+	uploads, err := repository.NewUploadRepo(suite.uploads)
+	suite.Require().NoError(err)
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "alice", Roles: []string{"owner"}})
+	submit := func(id uuid.UUID) error {
+		params, _ := json.Marshal(command.ImportParams{Format: "text/csv", UploadID: id})
+		_, err := suite.runner.Submit(ctx, command.ImportKind, params)
+		return err
+	}
+
+	large := uuid.New()
+	suite.Require().Nil(uploads.Save(ctx, large, []byte("name,age\n"+strings.Repeat("Alice Smith,30\n", 100))))
+	assert.Error(suite.T(), submit(large))
+
+	foreign := uuid.New()
+	suite.Require().Nil(uploads.Save(tenant.WithTenant(ctx, "globex"), foreign, []byte("name,age\n")))
+	assert.Error(suite.T(), submit(foreign))
+
+	small := uuid.New()
+	suite.Require().Nil(uploads.Save(ctx, small, []byte("name,age\nAlice Smith,30\n")))
+	assert.NoError(suite.T(), submit(small))
+}
+
+// TestPersonImportTestSuite runs the test suite.
+func TestPersonImportTestSuite(t *testing.T) {
+	suite.Run(t, new(PersonImportTestSuite))
+}
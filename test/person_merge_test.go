@@ -0,0 +1,131 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/Efamamo/GoCrudChallange/application/people/query"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/repository"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// PersonMergeTestSuite is the test suite for duplicate detection and merging of people.
+type PersonMergeTestSuite struct {
+	suite.Suite
+	mockRepo         *mocks.MockPersonRepo
+	mockRelationRepo *mocks.MockRelationRepo
+}
+
+// SetupTest initializes the mock repository before each test.
+func (suite *PersonMergeTestSuite) SetupTest() {
+	suite.mockRepo = mocks.NewMockPersonRepo()
+	suite.mockRelationRepo = mocks.NewMockRelationRepo()
+}
+
+// createPerson creates a person and saves it into the mock repository.
+func (suite *PersonMergeTestSuite) createPerson(name string, age int16, hobbies ...string) *model.Person {
+	person, err := model.CreatePerson(&model.PersonConfig{Name: name, Age: age, Hobbies: hobbies})
+	suite.Require().Nil(err)
+	suite.Require().Nil(suite.mockRepo.Save(context.Background(), person))
+	return person
+}
+
+// TestGetDuplicatesHandler_GroupsSimilarNamesWithSameAge tests that only similar names of the same age are grouped.
+func (suite *PersonMergeTestSuite) TestGetDuplicatesHandler_GroupsSimilarNamesWithSameAge() {
+	john := suite.createPerson("John Smith", 30)
+	johnny := suite.createPerson("john  smith", 30)
+	suite.createPerson("John Smith", 45)
+	suite.createPerson("Alice Walker", 30)
+
+	handler := query.NewGetDuplicatesHandler(suite.mockRepo, nil)
+
+	groups, err := handler.Handle(&query.GetDuplicatesQuery{})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), groups, 1)
+	assert.ElementsMatch(suite.T(), []*model.Person{john, johnny}, groups[0])
+}
+
+// TestMergePeopleHandler_Success tests merging people into a survivor.
+func (suite *PersonMergeTestSuite) TestMergePeopleHandler_Success() {
+	survivor := suite.createPerson("John Smith", 30, "Reading")
+	retired := suite.createPerson("Jon Smith", 30, "Reading", "Running")
+
+	handler := command.NewMergePeopleHandler(suite.mockRepo, suite.mockRelationRepo, nil, nil)
+
+	age := int16(31)
+	result, err := handler.Handle(&command.MergePeopleCommand{
+		IDs:  []uuid.UUID{survivor.Id(), retired.Id()},
+		Name: "Johnathan Smith",
+		Age:  &age,
+	})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), survivor.Id(), result.Survivor.Id())
+	assert.Equal(suite.T(), "Johnathan Smith", result.Survivor.Name())
+	assert.Equal(suite.T(), age, result.Survivor.Age())
+	assert.Equal(suite.T(), []string{"Reading", "Running"}, result.Survivor.Hobbies())
+	assert.Equal(suite.T(), []uuid.UUID{retired.Id()}, result.Merge.RetiredIDs())
+
+	_, getErr := suite.mockRepo.Get(context.Background(), retired.Id())
+	assert.NotNil(suite.T(), getErr)
+
+	to, redirectErr := query.NewGetRedirectHandler(suite.mockRepo, nil).Handle(&query.GetRedirectQuery{ID: retired.Id()})
+	assert.NoError(suite.T(), redirectErr)
+	assert.Equal(suite.T(), survivor.Id(), to)
+}
+
+// TestMergePeopleHandler_Failure_NotFound tests that nothing is merged when one of the people does not exist.
+func (suite *PersonMergeTestSuite) TestMergePeopleHandler_Failure_NotFound() {
+	survivor := suite.createPerson("John Smith", 30, "Reading")
+
+	handler := command.NewMergePeopleHandler(suite.mockRepo, suite.mockRelationRepo, nil, nil)
+
+	result, err := handler.Handle(&command.MergePeopleCommand{
+		IDs: []uuid.UUID{survivor.Id(), uuid.New()},
+	})
+	assert.NotNil(suite.T(), err)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), []string{"Reading"}, survivor.Hobbies())
+}
+
+// TestGetMergesHandler_History tests that a survivor lists the merges that folded people into them,
+// including the ones of people later merged into them, and that retired people list none.
+func (suite *PersonMergeTestSuite) TestGetMergesHandler_History() {
+	repo := repository.NewPersonRepo(model.UniqueNameAge, tenant.Quotas{})
+	save := func(name string) *model.Person {
+		person, err := model.CreatePerson(&model.PersonConfig{Name: name, Age: 30})
+		suite.Require().Nil(err)
+		suite.Require().Nil(repo.Save(context.Background(), person))
+		return person
+	}
+	john, jon, johnny := save("John Smith"), save("Jon Smith"), save("Johnny Smith")
+	unrelated := save("Alice Walker")
+
+	merge := command.NewMergePeopleHandler(repo, suite.mockRelationRepo, nil, nil)
+	first, err := merge.Handle(&command.MergePeopleCommand{IDs: []uuid.UUID{jon.Id(), johnny.Id()}})
+	suite.Require().Nil(err)
+	second, err := merge.Handle(&command.MergePeopleCommand{IDs: []uuid.UUID{john.Id(), jon.Id()}})
+	suite.Require().Nil(err)
+
+	handler := query.NewGetMergesHandler(repo, nil)
+	merges, qerr := handler.Handle(&query.GetMergesQuery{Context: context.Background(), ID: john.Id()})
+	suite.Require().NoError(qerr)
+	assert.Equal(suite.T(), []*model.Merge{first.Merge, second.Merge}, merges)
+
+	merges, qerr = handler.Handle(&query.GetMergesQuery{Context: context.Background(), ID: unrelated.Id()})
+	suite.Require().NoError(qerr)
+	assert.Empty(suite.T(), merges)
+
+	_, qerr = handler.Handle(&query.GetMergesQuery{Context: context.Background(), ID: jon.Id()})
+	assert.Error(suite.T(), qerr)
+}
+
+// TestPersonMergeTestSuite runs the test suite for duplicate detection and merging.
+func TestPersonMergeTestSuite(t *testing.T) {
+	suite.Run(t, new(PersonMergeTestSuite))
+}
@@ -0,0 +1,215 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/Efamamo/GoCrudChallange/application/people/query"
+	relcmd "github.com/Efamamo/GoCrudChallange/application/relations/command"
+	relquery "github.com/Efamamo/GoCrudChallange/application/relations/query"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/Efamamo/GoCrudChallange/domain/model/relation"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// PersonOwnershipTestSuite is the test suite for people belonging to the principal who created them.
+type PersonOwnershipTestSuite struct {
+	suite.Suite
+	mockRepo   *mocks.MockPersonRepo
+	authorizer *auth.Authorizer
+}
+
+// SetupTest initializes the repository and an authorizer enforcing the default policy.
+func (suite *PersonOwnershipTestSuite) SetupTest() {
+	suite.mockRepo = mocks.NewMockPersonRepo()
+	suite.authorizer = auth.NewAuthorizer(auth.DefaultPolicy())
+}
+
+// as returns a context carrying a principal with the given subject and roles.
+func as(subject string, roles ...string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{Subject: subject, Roles: roles})
+}
+
+// create creates a person as the given caller.
+func (suite *PersonOwnershipTestSuite) create(ctx context.Context, name string) *model.Person {
+	person, err := command.NewCreatePersonHandler(suite.mockRepo, mocks.NewMockAttributeRepo(), nil).Handle(&command.CreatePersonCommand{
+		Context: ctx,
+		Name:    name,
+		Age:     30,
+	})
+	suite.Require().Nil(err)
+	return person
+}
+
+// TestCreate_SetsOwner tests that the creator of a person becomes its owner.
+func (suite *PersonOwnershipTestSuite) TestCreate_SetsOwner() {
+	person := suite.create(as("alice", "editor"), "Alice Smith")
+
+	assert.Equal(suite.T(), "alice", person.Owner())
+}
+
+// TestGetAll_ScopedToOwner tests that callers only list their own people, unless they are admins.
+func (suite *PersonOwnershipTestSuite) TestGetAll_ScopedToOwner() {
+	suite.create(as("alice", "editor"), "Alice Smith")
+	suite.create(as("bob", "editor"), "Bobby Jones")
+	handler := query.NewGetPeopleHandler(suite.mockRepo, suite.authorizer)
+
+	people, err := handler.Handle(&query.GetPeopleQuery{Context: as("alice", "viewer")})
+	suite.Require().NoError(err)
+	suite.Require().Len(people, 1)
+	assert.Equal(suite.T(), "alice", people[0].Owner())
+
+	people, err = handler.Handle(&query.GetPeopleQuery{Context: as("root", "admin")})
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), people, 2)
+}
+
+// TestGet_OtherOwner tests that people of someone else are reported as not found.
+func (suite *PersonOwnershipTestSuite) TestGet_OtherOwner() {
+	person := suite.create(as("alice", "editor"), "Alice Smith")
+	handler := query.NewGetPersonHandler(suite.mockRepo, suite.authorizer)
+
+	_, err := handler.Handle(&query.GetPersonQuery{Context: as("bob", "viewer"), ID: person.Id()})
+	suite.Require().Error(err)
+	assert.Equal(suite.T(), ierr.NotFound, err.(ierr.IErr).Type())
+
+	found, err := handler.Handle(&query.GetPersonQuery{Context: as("alice", "viewer"), ID: person.Id()})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), person.Id(), found.Id())
+}
+
+// TestUpdate_OtherOwner tests that people of someone else can't be updated.
+func (suite *PersonOwnershipTestSuite) TestUpdate_OtherOwner() {
+	person := suite.create(as("alice", "editor"), "Alice Smith")
+
+	_, err := command.NewUpdatePersonHandler(suite.mockRepo, mocks.NewMockAttributeRepo(), suite.authorizer, nil).Handle(&command.UpdatePersonCommand{
+		Context: as("bob", "editor"),
+		ID:      person.Id(),
+		Name:    "Mallory Smith",
+		Age:     30,
+	})
+
+	suite.Require().NotNil(err)
+	assert.Equal(suite.T(), ierr.NotFound, err.Type())
+}
+
+// TestTransfer tests that the owner can hand a person over, after which only the new owner can access it.
+func (suite *PersonOwnershipTestSuite) TestTransfer() {
+	person := suite.create(as("alice", "editor"), "Alice Smith")
+	transfer := command.NewTransferOwnershipHandler(suite.mockRepo, suite.authorizer, nil)
+
+	_, err := transfer.Handle(&command.TransferOwnershipCommand{Context: as("bob", "editor"), ID: person.Id(), Owner: "bob"})
+	suite.Require().NotNil(err)
+
+	transferred, err := transfer.Handle(&command.TransferOwnershipCommand{Context: as("alice", "editor"), ID: person.Id(), Owner: "bob"})
+	suite.Require().Nil(err)
+	assert.Equal(suite.T(), "bob", transferred.Owner())
+
+	_, derr := command.NewDeletePersonHandler(suite.mockRepo, mocks.NewMockRelationRepo(), suite.authorizer, nil).Handle(&command.DeletePersonCommand{
+		Context: as("alice", "owner"),
+		ID:      person.Id(),
+	})
+	assert.NotNil(suite.T(), derr)
+}
+
+// TestMerge_OtherOwner tests that people of someone else can neither survive nor be retired by a merge.
+func (suite *PersonOwnershipTestSuite) TestMerge_OtherOwner() {
+	alice := suite.create(as("alice", "editor"), "John Smith")
+	bob := suite.create(as("bob", "editor"), "Jon Smith")
+	merge := command.NewMergePeopleHandler(suite.mockRepo, mocks.NewMockRelationRepo(), suite.authorizer, nil)
+
+	for _, ids := range [][]uuid.UUID{{alice.Id(), bob.Id()}, {bob.Id(), alice.Id()}} {
+		_, err := merge.Handle(&command.MergePeopleCommand{Context: as("alice", "editor"), IDs: ids})
+		suite.Require().NotNil(err)
+		assert.Equal(suite.T(), ierr.NotFound, err.Type())
+	}
+
+	_, err := suite.mockRepo.Get(context.Background(), bob.Id())
+	assert.Nil(suite.T(), err)
+}
+
+// TestDuplicatesAndRedirect_ScopedToOwner tests that duplicates and redirects only reveal the caller's people.
+func (suite *PersonOwnershipTestSuite) TestDuplicatesAndRedirect_ScopedToOwner() {
+	suite.create(as("alice", "editor"), "John Smith")
+	suite.create(as("bob", "editor"), "John Smith")
+	bob := suite.create(as("bob", "editor"), "Jon Smith")
+
+	duplicates := query.NewGetDuplicatesHandler(suite.mockRepo, suite.authorizer)
+	groups, err := duplicates.Handle(&query.GetDuplicatesQuery{Context: as("alice", "viewer")})
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), groups)
+
+	groups, err = duplicates.Handle(&query.GetDuplicatesQuery{Context: as("bob", "viewer")})
+	suite.Require().NoError(err)
+	suite.Require().Len(groups, 1)
+	assert.Len(suite.T(), groups[0], 2)
+
+	retired := suite.create(as("bob", "editor"), "Johnny Smith")
+	_, merr := command.NewMergePeopleHandler(suite.mockRepo, mocks.NewMockRelationRepo(), suite.authorizer, nil).Handle(&command.MergePeopleCommand{
+		Context: as("bob", "editor"),
+		IDs:     []uuid.UUID{bob.Id(), retired.Id()},
+	})
+	suite.Require().Nil(merr)
+
+	redirect := query.NewGetRedirectHandler(suite.mockRepo, suite.authorizer)
+	_, err = redirect.Handle(&query.GetRedirectQuery{Context: as("alice", "viewer"), ID: retired.Id()})
+	suite.Require().Error(err)
+	assert.Equal(suite.T(), ierr.NotFound, err.(ierr.IErr).Type())
+
+	to, err := redirect.Handle(&query.GetRedirectQuery{Context: as("bob", "viewer"), ID: retired.Id()})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), bob.Id(), to)
+}
+
+// TestRelations_ScopedToOwner tests that relations can't reach the people of someone else.
+func (suite *PersonOwnershipTestSuite) TestRelations_ScopedToOwner() {
+	relations := mocks.NewMockRelationRepo()
+	alice := suite.create(as("alice", "editor"), "Alice Smith")
+	carol := suite.create(as("alice", "editor"), "Carol Jones")
+	bob := suite.create(as("bob", "editor"), "Bobby Brown")
+
+	add := relcmd.NewAddRelationHandler(suite.mockRepo, relations, suite.authorizer)
+	_, err := add.Handle(&relcmd.AddRelationCommand{Context: as("alice", "editor"), From: alice.Id(), To: bob.Id(), Type: relation.Friend})
+	suite.Require().NotNil(err)
+	assert.Equal(suite.T(), ierr.NotFound, err.Type())
+
+	// Relations added by an admin link the people of both owners.
+	for _, to := range []*model.Person{bob, carol} {
+		_, err = add.Handle(&relcmd.AddRelationCommand{Context: as("root", "admin"), From: alice.Id(), To: to.Id(), Type: relation.Friend})
+		suite.Require().Nil(err)
+	}
+	_, err = add.Handle(&relcmd.AddRelationCommand{Context: as("root", "admin"), From: bob.Id(), To: carol.Id(), Type: relation.Friend})
+	suite.Require().Nil(err)
+
+	rels, qerr := relquery.NewGetRelationsHandler(suite.mockRepo, relations, suite.authorizer).Handle(&relquery.GetRelationsQuery{Context: as("alice", "viewer"), PersonID: alice.Id()})
+	suite.Require().NoError(qerr)
+	suite.Require().Len(rels, 1)
+	assert.True(suite.T(), rels[0].Involves(carol.Id()))
+
+	_, qerr = relquery.NewGetMutualHandler(suite.mockRepo, relations, suite.authorizer).Handle(&relquery.GetMutualQuery{Context: as("alice", "viewer"), PersonID: alice.Id(), OtherID: bob.Id()})
+	suite.Require().Error(qerr)
+
+	mutual, qerr := relquery.NewGetMutualHandler(suite.mockRepo, relations, suite.authorizer).Handle(&relquery.GetMutualQuery{Context: as("bob", "viewer"), PersonID: bob.Id(), OtherID: bob.Id()})
+	suite.Require().NoError(qerr)
+	assert.Empty(suite.T(), mutual)
+
+	path := relquery.NewGetPathHandler(suite.mockRepo, relations, suite.authorizer)
+	_, qerr = path.Handle(&relquery.GetPathQuery{Context: as("alice", "viewer"), From: alice.Id(), To: bob.Id()})
+	suite.Require().Error(qerr)
+	assert.Equal(suite.T(), ierr.NotFound, qerr.(ierr.IErr).Type())
+
+	found, qerr := path.Handle(&relquery.GetPathQuery{Context: as("root", "admin"), From: alice.Id(), To: bob.Id()})
+	suite.Require().NoError(qerr)
+	assert.Len(suite.T(), found, 2)
+}
+
+// TestPersonOwnershipTestSuite runs the test suite for the ownership of people.
+func TestPersonOwnershipTestSuite(t *testing.T) {
+	suite.Run(t, new(PersonOwnershipTestSuite))
+}
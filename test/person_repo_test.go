@@ -1,6 +1,7 @@
 package repo_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/Efamamo/GoCrudChallange/application/people/command"
@@ -15,22 +16,28 @@ import (
 // PersonCommandTestSuite is the base test suite for person command handlers.
 type PersonCommandTestSuite struct {
 	suite.Suite
-	mockRepo *mocks.MockPersonRepo
+	mockRepo          *mocks.MockPersonRepo
+	mockRelationRepo  *mocks.MockRelationRepo
+	mockAttributeRepo *mocks.MockAttributeRepo
 }
 
 // SetupTest initializes the mock repository before each test.
 func (suite *PersonCommandTestSuite) SetupTest() {
 	suite.mockRepo = mocks.NewMockPersonRepo()
+	suite.mockRelationRepo = mocks.NewMockRelationRepo()
+	suite.mockAttributeRepo = mocks.NewMockAttributeRepo()
 }
 
 // TearDownTest cleans up after each test.
 func (suite *PersonCommandTestSuite) TearDownTest() {
 	suite.mockRepo = nil
+	suite.mockRelationRepo = nil
+	suite.mockAttributeRepo = nil
 }
 
 // TestCreatePersonHandler_Success tests the successful creation of a person.
 func (suite *PersonCommandTestSuite) TestCreatePersonHandler_Success() {
-	handler := command.NewCreatePersonHandler(suite.mockRepo)
+	handler := command.NewCreatePersonHandler(suite.mockRepo, suite.mockAttributeRepo, nil)
 
 	cmd := &command.CreatePersonCommand{
 		Name:    "John Doe",
@@ -47,10 +54,10 @@ func (suite *PersonCommandTestSuite) TestCreatePersonHandler_Success() {
 
 // TestCreatePersonHandler_Failure_InvalidPerson tests the failure case for creating a person with invalid data.
 func (suite *PersonCommandTestSuite) TestCreatePersonHandler_Failure_InvalidPerson() {
-	handler := command.NewCreatePersonHandler(suite.mockRepo)
+	handler := command.NewCreatePersonHandler(suite.mockRepo, suite.mockAttributeRepo, nil)
 
 	// Custom behavior to return an error for invalid data
-	suite.mockRepo.SaveFunc = func(p *model.Person) ierr.IErr {
+	suite.mockRepo.CreateFunc = func(_ context.Context, p *model.Person) ierr.IErr {
 		return ierr.NewValidation("person can't be empty")
 	}
 
@@ -65,9 +72,41 @@ func (suite *PersonCommandTestSuite) TestCreatePersonHandler_Failure_InvalidPers
 	assert.Nil(suite.T(), result)
 }
 
+// TestCreatePersonHandler_ClientID tests that people can be created with an ID chosen by the client.
+func (suite *PersonCommandTestSuite) TestCreatePersonHandler_ClientID() {
+	handler := command.NewCreatePersonHandler(suite.mockRepo, suite.mockAttributeRepo, nil)
+	id := uuid.New()
+
+	result, err := handler.Handle(&command.CreatePersonCommand{ID: id, Name: "John Doe", Age: 30})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), id, result.Id())
+
+	stored, err := suite.mockRepo.Get(context.Background(), id)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "John Doe", stored.Name())
+}
+
+// TestCreatePersonHandler_Failure_IDTaken tests that creating a person with an ID already taken is a conflict.
+func (suite *PersonCommandTestSuite) TestCreatePersonHandler_Failure_IDTaken() {
+	handler := command.NewCreatePersonHandler(suite.mockRepo, suite.mockAttributeRepo, nil)
+	existing, err := handler.Handle(&command.CreatePersonCommand{Name: "John Doe", Age: 30})
+	suite.Require().NoError(err)
+
+	result, err := handler.Handle(&command.CreatePersonCommand{ID: existing.Id(), Name: "Jane Doe", Age: 40})
+	suite.Require().Error(err)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), ierr.Conflict, err.(ierr.IErr).Type())
+	assert.Equal(suite.T(), existing.Id().String(), err.(*ierr.Error).ExistingID())
+
+	// The existing person is left untouched.
+	stored, err := suite.mockRepo.Get(context.Background(), existing.Id())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "John Doe", stored.Name())
+}
+
 // TestUpdatePersonHandler_Success tests the successful update of a person's details.
 func (suite *PersonCommandTestSuite) TestUpdatePersonHandler_Success() {
-	handler := command.NewUpdatePersonHandler(suite.mockRepo)
+	handler := command.NewUpdatePersonHandler(suite.mockRepo, suite.mockAttributeRepo, nil, nil)
 
 	existingPerson, err := model.CreatePerson(
 		&model.PersonConfig{
@@ -76,7 +115,7 @@ func (suite *PersonCommandTestSuite) TestUpdatePersonHandler_Success() {
 			Hobbies: []string{"Swimming"},
 		},
 	)
-	suite.mockRepo.Save(existingPerson)
+	suite.mockRepo.Save(context.Background(), existingPerson)
 
 	cmd := &command.UpdatePersonCommand{
 		ID:      existingPerson.Id(),
@@ -94,7 +133,7 @@ func (suite *PersonCommandTestSuite) TestUpdatePersonHandler_Success() {
 
 // TestUpdatePersonHandler_Failure_NotFound tests the failure case for updating a non-existent person.
 func (suite *PersonCommandTestSuite) TestUpdatePersonHandler_Failure_NotFound() {
-	handler := command.NewUpdatePersonHandler(suite.mockRepo)
+	handler := command.NewUpdatePersonHandler(suite.mockRepo, suite.mockAttributeRepo, nil, nil)
 
 	cmd := &command.UpdatePersonCommand{
 		ID:      uuid.New(),
@@ -110,7 +149,7 @@ func (suite *PersonCommandTestSuite) TestUpdatePersonHandler_Failure_NotFound()
 
 // TestDeletePersonHandler_Success tests the successful deletion of a person by ID.
 func (suite *PersonCommandTestSuite) TestDeletePersonHandler_Success() {
-	handler := command.NewDeletePersonHandler(suite.mockRepo)
+	handler := command.NewDeletePersonHandler(suite.mockRepo, suite.mockRelationRepo, nil, nil)
 
 	existingPerson, err := model.CreatePerson(
 		&model.PersonConfig{
@@ -120,20 +159,20 @@ func (suite *PersonCommandTestSuite) TestDeletePersonHandler_Success() {
 		},
 	)
 
-	suite.mockRepo.Save(existingPerson)
+	suite.mockRepo.Save(context.Background(), existingPerson)
 
-	success, err := handler.Handle(existingPerson.Id())
+	success, err := handler.Handle(&command.DeletePersonCommand{ID: existingPerson.Id()})
 	assert.NoError(suite.T(), err)
 	assert.True(suite.T(), success)
 }
 
 // TestDeletePersonHandler_Failure_NotFound tests the failure case for deleting a non-existent person.
 func (suite *PersonCommandTestSuite) TestDeletePersonHandler_Failure_NotFound() {
-	handler := command.NewDeletePersonHandler(suite.mockRepo)
+	handler := command.NewDeletePersonHandler(suite.mockRepo, suite.mockRelationRepo, nil, nil)
 
 	nonExistentID := uuid.New()
 
-	success, err := handler.Handle(nonExistentID)
+	success, err := handler.Handle(&command.DeletePersonCommand{ID: nonExistentID})
 	assert.Error(suite.T(), err)
 	assert.False(suite.T(), success)
 }
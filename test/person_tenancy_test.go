@@ -0,0 +1,225 @@
+package repo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Efamamo/GoCrudChallange/api/middleware"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	apperror "github.com/Efamamo/GoCrudChallange/application/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/Efamamo/GoCrudChallange/domain/model/relation"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// PersonTenancyTestSuite is the test suite for people partitioned by tenant.
+type PersonTenancyTestSuite struct {
+	suite.Suite
+	acme   context.Context
+	globex context.Context
+}
+
+// SetupTest creates the contexts of two tenants.
+func (suite *PersonTenancyTestSuite) SetupTest() {
+	suite.acme = tenant.WithTenant(context.Background(), "acme")
+	suite.globex = tenant.WithTenant(context.Background(), "globex")
+}
+
+// newPerson creates a person with the given name.
+func (suite *PersonTenancyTestSuite) newPerson(name string) *model.Person {
+	person, err := model.CreatePerson(&model.PersonConfig{Name: name, Age: 30})
+	suite.Require().Nil(err)
+	return person
+}
+
+// TestRepo_Isolation tests that tenants neither see nor reach the people of each other.
+func (suite *PersonTenancyTestSuite) TestRepo_Isolation() {
+	repo := repository.NewPersonRepo(model.UniqueName, tenant.Quotas{})
+	person := suite.newPerson("Alice Smith")
+	suite.Require().Nil(repo.Save(suite.acme, person))
+
+	_, err := repo.Get(suite.globex, person.Id())
+	assert.NotNil(suite.T(), err)
+	people, err := repo.GetAll(suite.globex)
+	suite.Require().Nil(err)
+	assert.Empty(suite.T(), people)
+	assert.NotNil(suite.T(), repo.Delete(suite.globex, person.Id()))
+
+	found, err := repo.Get(suite.acme, person.Id())
+	suite.Require().Nil(err)
+	assert.Equal(suite.T(), "Alice Smith", found.Name())
+
+	// Uniqueness only applies within a tenant.
+	assert.Nil(suite.T(), repo.Save(suite.globex, suite.newPerson("Alice Smith")))
+}
+
+// TestRelationRepo_Isolation tests that tenants neither see nor reach the relations of each other,
+// even between people whose IDs are the same in both tenants.
+func (suite *PersonTenancyTestSuite) TestRelationRepo_Isolation() {
+	repo := repository.NewRelationRepo()
+	alice, bob := suite.newPerson("Alice Smith"), suite.newPerson("Bobby Jones")
+	config := &relation.RelationConfig{From: alice.Id(), To: bob.Id(), Type: relation.Friend}
+
+	rel, err := relation.CreateRelation(config)
+	suite.Require().Nil(err)
+	suite.Require().Nil(repo.Save(suite.acme, rel))
+
+	_, err = repo.Get(suite.globex, rel.Id())
+	assert.NotNil(suite.T(), err)
+	relations, err := repo.GetByPerson(suite.globex, alice.Id())
+	suite.Require().Nil(err)
+	assert.Empty(suite.T(), relations)
+	assert.NotNil(suite.T(), repo.Delete(suite.globex, rel.Id()))
+	suite.Require().Nil(repo.DeleteByPerson(suite.globex, alice.Id()))
+	suite.Require().Nil(repo.Reassign(suite.globex, bob.Id(), alice.Id()))
+
+	relations, err = repo.GetByPerson(suite.acme, bob.Id())
+	suite.Require().Nil(err)
+	suite.Require().Len(relations, 1)
+	assert.Equal(suite.T(), rel.Id(), relations[0].Id())
+	assert.Equal(suite.T(), alice.Id(), relations[0].From())
+
+	// The same edge doesn't conflict with the one of another tenant.
+	same, err := relation.CreateRelation(config)
+	suite.Require().Nil(err)
+	assert.Nil(suite.T(), repo.Save(suite.globex, same))
+}
+
+// TestRepo_Quota tests that tenants can't store more people than their quota allows.
+func (suite *PersonTenancyTestSuite) TestRepo_Quota() {
+	repo := repository.NewPersonRepo(model.UniqueName, tenant.Quotas{Default: 1, Tenants: map[string]int{"globex": 0}})
+	person := suite.newPerson("Alice Smith")
+	suite.Require().Nil(repo.Save(suite.acme, person))
+
+	err := repo.Save(suite.acme, suite.newPerson("Bobby Jones"))
+	suite.Require().NotNil(err)
+	assert.Equal(suite.T(), apperror.QuotaExceeded, err.Type())
+
+	// Updating a stored person doesn't count against the quota.
+	suite.Require().Nil(person.SetAge(31))
+	assert.Nil(suite.T(), repo.Save(suite.acme, person))
+
+	assert.Nil(suite.T(), repo.Save(suite.globex, suite.newPerson("Bobby Jones")))
+	assert.Nil(suite.T(), repo.Save(suite.globex, suite.newPerson("Carol White")))
+}
+
+// TestParseQuotas tests parsing of the quota configuration.
+func (suite *PersonTenancyTestSuite) TestParseQuotas() {
+	quotas, err := tenant.ParseQuotas("10", []string{"acme=2", " globex = 0 "})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 2, quotas.Limit("acme"))
+	assert.Equal(suite.T(), 0, quotas.Limit("globex"))
+	assert.Equal(suite.T(), 10, quotas.Limit("initech"))
+
+	_, err = tenant.ParseQuotas("10", []string{"acme"})
+	assert.Error(suite.T(), err)
+	_, err = tenant.ParseQuotas("-1", nil)
+	assert.Error(suite.T(), err)
+}
+
+// serve performs a request through the tenancy middleware, optionally as the given principal,
+// and returns the response recorder.
+func (suite *PersonTenancyTestSuite) serve(config middleware.TenancyConfig, host string, header string, principal *auth.Principal) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/person", func(c *gin.Context) {
+		if principal != nil {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		}
+	}, middleware.NewTenancy(config).Handler(), func(c *gin.Context) {
+		c.String(http.StatusOK, tenant.From(c.Request.Context()))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/person", nil)
+	req.Host = host
+	if header != "" {
+		req.Header.Set("X-Tenant-ID", header)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+// TestMiddleware_Resolution tests that the tenant is read from the subdomain, then the header.
+func (suite *PersonTenancyTestSuite) TestMiddleware_Resolution() {
+	config := middleware.TenancyConfig{Header: "X-Tenant-ID", BaseDomain: "example.com"}
+
+	w := suite.serve(config, "acme.example.com:8080", "globex", nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "acme", w.Body.String())
+
+	w = suite.serve(config, "example.com", "Globex", nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "globex", w.Body.String())
+
+	w = suite.serve(config, "example.com", "", nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.Equal(suite.T(), tenant.Default, w.Body.String())
+
+	w = suite.serve(config, "example.com", "not a tenant", nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	config.Required = true
+	w = suite.serve(config, "example.com", "", nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+// TestMiddleware_Claim tests that authenticated callers are confined to the tenant of their token.
+func (suite *PersonTenancyTestSuite) TestMiddleware_Claim() {
+	config := middleware.TenancyConfig{Header: "X-Tenant-ID", Claim: "tenant"}
+	member := &auth.Principal{Subject: "alice", Claims: map[string]any{"tenant": "acme"}}
+
+	w := suite.serve(config, "example.com", "", member)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "acme", w.Body.String())
+
+	w = suite.serve(config, "example.com", "acme", member)
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+
+	w = suite.serve(config, "example.com", "globex", member)
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+
+	w = suite.serve(config, "example.com", "acme", &auth.Principal{Subject: "bob"})
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+}
+
+// TestMiddleware_PrincipalTenant tests that authenticated callers are confined to the tenant of their user or key,
+// and that those belonging to no tenant can't name one, while anonymous callers can.
+func (suite *PersonTenancyTestSuite) TestMiddleware_PrincipalTenant() {
+	config := middleware.TenancyConfig{Header: "X-Tenant-ID", BaseDomain: "example.com"}
+	member := &auth.Principal{Subject: "alice", Tenant: "acme"}
+
+	w := suite.serve(config, "example.com", "", member)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "acme", w.Body.String())
+
+	w = suite.serve(config, "example.com", "globex", member)
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+	w = suite.serve(config, "globex.example.com", "", member)
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+
+	outsider := &auth.Principal{Subject: "bob"}
+	w = suite.serve(config, "example.com", "", outsider)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.Equal(suite.T(), tenant.Default, w.Body.String())
+
+	w = suite.serve(config, "example.com", "acme", outsider)
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+	w = suite.serve(config, "acme.example.com", "", outsider)
+	assert.Equal(suite.T(), http.StatusForbidden, w.Code)
+
+	w = suite.serve(config, "example.com", "acme", auth.Anonymous())
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "acme", w.Body.String())
+}
+
+// TestPersonTenancyTestSuite runs the test suite.
+func TestPersonTenancyTestSuite(t *testing.T) {
+	suite.Run(t, new(PersonTenancyTestSuite))
+}
@@ -0,0 +1,125 @@
+package repo_test
+
+import (
+	"context"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	"sync"
+	"testing"
+
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// PersonUniquenessTestSuite is the test suite for the uniqueness policies of the person repository.
+type PersonUniquenessTestSuite struct {
+	suite.Suite
+}
+
+// newPerson creates a person without saving it.
+func (suite *PersonUniquenessTestSuite) newPerson(name string, age int16) *model.Person {
+	person, err := model.CreatePerson(&model.PersonConfig{Name: name, Age: age})
+	suite.Require().Nil(err)
+	return person
+}
+
+// TestSave_NamePolicy tests that names are unique regardless of case and spacing.
+func (suite *PersonUniquenessTestSuite) TestSave_NamePolicy() {
+	repo := repository.NewPersonRepo(model.UniqueName, tenant.Quotas{})
+
+	existing := suite.newPerson("John Doe", 30)
+	assert.Nil(suite.T(), repo.Save(context.Background(), existing))
+
+	err := repo.Save(context.Background(), suite.newPerson("  john   DOE ", 45))
+	if assert.NotNil(suite.T(), err) {
+		assert.Equal(suite.T(), ierr.Conflict, err.Type())
+		assert.Equal(suite.T(), existing.Id().String(), err.(*ierr.Error).ExistingID())
+	}
+
+	// Saving the existing person again is not a conflict, and renaming it frees the name.
+	assert.Nil(suite.T(), repo.Save(context.Background(), existing))
+	existing.SetName("Johnny Doe")
+	assert.Nil(suite.T(), repo.Save(context.Background(), existing))
+	assert.Nil(suite.T(), repo.Save(context.Background(), suite.newPerson("John Doe", 45)))
+}
+
+// TestSave_NameAgePolicy tests that only people with the same name and age conflict.
+func (suite *PersonUniquenessTestSuite) TestSave_NameAgePolicy() {
+	repo := repository.NewPersonRepo(model.UniqueNameAge, tenant.Quotas{})
+
+	assert.Nil(suite.T(), repo.Save(context.Background(), suite.newPerson("John Doe", 30)))
+	assert.Nil(suite.T(), repo.Save(context.Background(), suite.newPerson("John Doe", 31)))
+	assert.NotNil(suite.T(), repo.Save(context.Background(), suite.newPerson("JOHN DOE", 30)))
+}
+
+// TestSave_ConcurrentConflicts tests that only one of many concurrent conflicting saves succeeds.
+func (suite *PersonUniquenessTestSuite) TestSave_ConcurrentConflicts() {
+	repo := repository.NewPersonRepo(model.UniqueName, tenant.Quotas{})
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	saved := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if repo.Save(context.Background(), suite.newPerson("John Doe", 30)) == nil {
+				mutex.Lock()
+				saved++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	people, _ := repo.GetAll(context.Background())
+	assert.Equal(suite.T(), 1, saved)
+	assert.Len(suite.T(), people, 1)
+}
+
+// racingRepo is a person repository where another person takes every ID right after it is found
+// available, as a concurrent creation landing between the check and the write would.
+type racingRepo struct {
+	*repository.PersonRepo
+}
+
+// Get looks the person up, then saves another one under the ID when it was not found.
+func (r *racingRepo) Get(ctx context.Context, id uuid.UUID) (*model.Person, ierr.IErr) {
+	person, err := r.PersonRepo.Get(ctx, id)
+	if err != nil && err.Type() == ierr.NotFound {
+		other, cerr := model.CreatePerson(&model.PersonConfig{ID: id, Name: "Jane Doe", Age: 40})
+		if cerr != nil {
+			return nil, cerr
+		}
+		if serr := r.PersonRepo.Save(ctx, other); serr != nil {
+			return nil, serr
+		}
+	}
+	return person, err
+}
+
+// TestCreate_ConcurrentSameID tests that creating a person with an ID taken concurrently is a conflict,
+// instead of overwriting the person who took it.
+func (suite *PersonUniquenessTestSuite) TestCreate_ConcurrentSameID() {
+	repo := repository.NewPersonRepo(model.UniqueNone, tenant.Quotas{})
+	handler := command.NewCreatePersonHandler(&racingRepo{PersonRepo: repo}, repository.NewAttributeRepo(), nil)
+	id := uuid.New()
+
+	_, err := handler.Handle(&command.CreatePersonCommand{ID: id, Name: "John Doe", Age: 30})
+	suite.Require().NotNil(err)
+	assert.Equal(suite.T(), ierr.Conflict, err.Type())
+	assert.Equal(suite.T(), id.String(), err.(*ierr.Error).ExistingID())
+
+	stored, gerr := repo.Get(context.Background(), id)
+	suite.Require().Nil(gerr)
+	assert.Equal(suite.T(), "Jane Doe", stored.Name())
+}
+
+// TestPersonUniquenessTestSuite runs the test suite for the uniqueness policies.
+func TestPersonUniquenessTestSuite(t *testing.T) {
+	suite.Run(t, new(PersonUniquenessTestSuite))
+}
@@ -0,0 +1,106 @@
+package repo_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// PersonUpsertTestSuite is the test suite for client-supplied IDs and PUT /person/:id creating missing people.
+type PersonUpsertTestSuite struct {
+	suite.Suite
+	mockRepo *mocks.MockPersonRepo
+	pc       controller.PersonController
+}
+
+// SetupTest initializes a person controller with upserts enabled.
+func (suite *PersonUpsertTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.mockRepo = mocks.NewMockPersonRepo()
+	attributes := mocks.NewMockAttributeRepo()
+
+	suite.pc = controller.PersonController{
+		CreateHandler: command.NewCreatePersonHandler(suite.mockRepo, attributes, nil),
+		UpdateHandler: command.NewUpdatePersonHandler(suite.mockRepo, attributes, nil, nil),
+		Upsert:        true,
+	}
+}
+
+// request performs a request on a router serving the person controller.
+func (suite *PersonUpsertTestSuite) request(method string, path string, body string) (*httptest.ResponseRecorder, map[string]any) {
+	engine := gin.New()
+	engine.POST("/person", suite.pc.Create)
+	engine.PUT("/person/:id", suite.pc.Update)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	response := map[string]any{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+// TestCreate_ClientID tests that POST /person keeps the id of the body, and rejects taken ones with a 409.
+func (suite *PersonUpsertTestSuite) TestCreate_ClientID() {
+	id := uuid.New().String()
+
+	w, response := suite.request(http.MethodPost, "/person", `{"id": "`+id+`", "name": "Alice Smith", "age": 30}`)
+	suite.Require().Equal(http.StatusCreated, w.Code)
+	assert.Equal(suite.T(), id, response["id"])
+
+	w, response = suite.request(http.MethodPost, "/person", `{"id": "`+id+`", "name": "Bobby Jones", "age": 40}`)
+	suite.Require().Equal(http.StatusConflict, w.Code)
+	assert.Equal(suite.T(), id, response["existing_id"])
+
+	w, _ = suite.request(http.MethodPost, "/person", `{"id": "not-a-uuid", "name": "Bobby Jones", "age": 40}`)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+// TestUpdate_Upsert tests that PUT /person/:id creates a missing person with a 201, then updates it with a 200.
+func (suite *PersonUpsertTestSuite) TestUpdate_Upsert() {
+	id := uuid.New()
+
+	w, response := suite.request(http.MethodPut, "/person/"+id.String(), `{"name": "Alice Smith", "age": 30}`)
+	suite.Require().Equal(http.StatusCreated, w.Code)
+	assert.Equal(suite.T(), id.String(), response["id"])
+
+	w, response = suite.request(http.MethodPut, "/person/"+id.String(), `{"name": "Alice Smith", "age": 31}`)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.Equal(suite.T(), float64(31), response["age"])
+
+	people, err := suite.mockRepo.GetAll(context.Background())
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), people, 1)
+}
+
+// TestUpdate_UpsertDisabled tests that PUT /person/:id doesn't create missing people unless enabled.
+func (suite *PersonUpsertTestSuite) TestUpdate_UpsertDisabled() {
+	suite.pc.Upsert = false
+
+	w, _ := suite.request(http.MethodPut, "/person/"+uuid.New().String(), `{"name": "Alice Smith", "age": 30}`)
+	assert.Equal(suite.T(), http.StatusNotFound, w.Code)
+}
+
+// TestUpdate_MismatchedID tests that an id in the body must match the one of the URL.
+func (suite *PersonUpsertTestSuite) TestUpdate_MismatchedID() {
+	w, _ := suite.request(http.MethodPut, "/person/"+uuid.New().String(), `{"id": "`+uuid.New().String()+`", "name": "Alice Smith", "age": 30}`)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+// TestPersonUpsertTestSuite runs the test suite.
+func TestPersonUpsertTestSuite(t *testing.T) {
+	suite.Run(t, new(PersonUpsertTestSuite))
+}
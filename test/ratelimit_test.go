@@ -0,0 +1,234 @@
+package repo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	"github.com/Efamamo/GoCrudChallange/api/middleware"
+	"github.com/Efamamo/GoCrudChallange/api/router"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	iratelimit "github.com/Efamamo/GoCrudChallange/application/common/interface/ratelimit"
+	"github.com/Efamamo/GoCrudChallange/application/people/query"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/ratelimit"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// RateLimitTestSuite is the test suite for the token bucket rate limiting middleware.
+type RateLimitTestSuite struct {
+	suite.Suite
+	store *ratelimit.MemoryStore
+}
+
+// SetupTest initializes an empty in-memory store.
+func (suite *RateLimitTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.store = ratelimit.NewMemoryStore()
+}
+
+// engine builds a router limited by the given configuration, authenticating requests carrying a subject header.
+func (suite *RateLimitTestSuite) engine(config middleware.RateLimitConfig) *gin.Engine {
+	config.Store = suite.store
+	engine := gin.New()
+	engine.POST("/person", func(c *gin.Context) {
+		if subject := c.GetHeader("X-Subject"); subject != "" {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), &auth.Principal{Subject: subject}))
+		}
+	}, middleware.NewRateLimit(config).Handler(), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	return engine
+}
+
+// request performs a request on the router from the given address, as the given subject when not empty.
+func (suite *RateLimitTestSuite) request(engine *gin.Engine, address string, subject string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/person", nil)
+	req.RemoteAddr = address + ":1234"
+	if subject != "" {
+		req.Header.Set("X-Subject", subject)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+// TestStore_Refill tests that buckets allow bursts, then refill at the configured rate.
+func (suite *RateLimitTestSuite) TestStore_Refill() {
+	rate := iratelimit.Rate{Limit: 10, Period: 500 * time.Millisecond, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		result, err := suite.store.Take(context.Background(), "client", rate)
+		suite.Require().NoError(err)
+		assert.True(suite.T(), result.Allowed)
+	}
+
+	result, err := suite.store.Take(context.Background(), "client", rate)
+	suite.Require().NoError(err)
+	assert.False(suite.T(), result.Allowed)
+	assert.Equal(suite.T(), 0, result.Remaining)
+	assert.Greater(suite.T(), result.RetryAfter, time.Duration(0))
+
+	time.Sleep(60 * time.Millisecond)
+	result, err = suite.store.Take(context.Background(), "client", rate)
+	suite.Require().NoError(err)
+	assert.True(suite.T(), result.Allowed)
+
+	_, err = suite.store.Take(context.Background(), "client", iratelimit.Rate{})
+	assert.Error(suite.T(), err)
+}
+
+// TestMiddleware_Headers tests that requests beyond the quota get a 429 with the rate limit headers.
+func (suite *RateLimitTestSuite) TestMiddleware_Headers() {
+	engine := suite.engine(middleware.RateLimitConfig{Name: "person", Rate: iratelimit.Rate{Limit: 2, Period: time.Minute}})
+
+	w := suite.request(engine, "10.0.0.1", "")
+	suite.Require().Equal(http.StatusCreated, w.Code)
+	assert.Equal(suite.T(), "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(suite.T(), "1", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(suite.T(), "30", w.Header().Get("X-RateLimit-Reset"))
+
+	suite.Require().Equal(http.StatusCreated, suite.request(engine, "10.0.0.1", "").Code)
+
+	w = suite.request(engine, "10.0.0.1", "")
+	suite.Require().Equal(http.StatusTooManyRequests, w.Code)
+	assert.Equal(suite.T(), "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(suite.T(), "30", w.Header().Get("Retry-After"))
+
+	// Other addresses have their own bucket.
+	assert.Equal(suite.T(), http.StatusCreated, suite.request(engine, "10.0.0.2", "").Code)
+}
+
+// TestMiddleware_Clients tests that authenticated callers are limited by subject, with their own quota when configured.
+func (suite *RateLimitTestSuite) TestMiddleware_Clients() {
+	engine := suite.engine(middleware.RateLimitConfig{
+		Name:    "person",
+		Rate:    iratelimit.Rate{Limit: 1, Period: time.Minute},
+		Clients: map[string]iratelimit.Rate{"apikey:partner": {Limit: 3, Period: time.Minute}},
+	})
+
+	// Callers sharing an address are limited separately.
+	assert.Equal(suite.T(), http.StatusCreated, suite.request(engine, "10.0.0.1", "alice").Code)
+	assert.Equal(suite.T(), http.StatusTooManyRequests, suite.request(engine, "10.0.0.1", "alice").Code)
+	assert.Equal(suite.T(), http.StatusCreated, suite.request(engine, "10.0.0.1", "bob").Code)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(suite.T(), http.StatusCreated, suite.request(engine, "10.0.0.1", "apikey:partner").Code)
+	}
+	assert.Equal(suite.T(), http.StatusTooManyRequests, suite.request(engine, "10.0.0.1", "apikey:partner").Code)
+}
+
+// routed builds the router of the API, limiting the /person routes to a request per minute.
+func (suite *RateLimitTestSuite) routed(trustedProxies ...string) *gin.Engine {
+	rateLimit := middleware.NewRateLimit(middleware.RateLimitConfig{
+		Name:  "person",
+		Store: suite.store,
+		Rate:  iratelimit.Rate{Limit: 1, Period: time.Minute},
+	})
+	pc := controller.PersonController{GetAllHandler: query.NewGetPeopleHandler(mocks.NewMockPersonRepo(), nil)}
+
+	return router.NewRouter(router.Config{
+		RateLimits:     router.RateLimits{Person: rateLimit},
+		TrustedProxies: trustedProxies,
+	}).Engine(
+		pc, controller.RelationController{}, controller.AttributeController{},
+		controller.APIKeyController{}, controller.AuthController{}, controller.UserController{}, controller.GraphQLController{}, controller.EventController{}, controller.WebhookController{}, controller.JobController{}, controller.ScheduleController{},
+	)
+}
+
+// forwarded lists people from the given address, with the given X-Forwarded-For header.
+func (suite *RateLimitTestSuite) forwarded(engine *gin.Engine, address string, forwardedFor string) int {
+	req := httptest.NewRequest(http.MethodGet, "/v1/person", nil)
+	req.RemoteAddr = address + ":1234"
+	req.Header.Set("X-Forwarded-For", forwardedFor)
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w.Code
+}
+
+// TestRouter_SpoofedForwardedFor tests that callers can't get a fresh bucket by sending another X-Forwarded-For,
+// which only names the client when it is sent by a trusted proxy.
+func (suite *RateLimitTestSuite) TestRouter_SpoofedForwardedFor() {
+	engine := suite.routed()
+	assert.Equal(suite.T(), http.StatusOK, suite.forwarded(engine, "10.0.0.1", "192.0.2.1"))
+	assert.Equal(suite.T(), http.StatusTooManyRequests, suite.forwarded(engine, "10.0.0.1", "192.0.2.2"))
+
+	suite.store = ratelimit.NewMemoryStore()
+	engine = suite.routed("10.0.0.0/8")
+	assert.Equal(suite.T(), http.StatusOK, suite.forwarded(engine, "10.0.0.1", "192.0.2.1"))
+	assert.Equal(suite.T(), http.StatusOK, suite.forwarded(engine, "10.0.0.1", "192.0.2.2"))
+	assert.Equal(suite.T(), http.StatusTooManyRequests, suite.forwarded(engine, "10.0.0.2", "192.0.2.2"))
+
+	// Untrusted callers still can't spoof their address.
+	assert.Equal(suite.T(), http.StatusOK, suite.forwarded(engine, "198.51.100.1", "192.0.2.3"))
+	assert.Equal(suite.T(), http.StatusTooManyRequests, suite.forwarded(engine, "198.51.100.1", "192.0.2.4"))
+}
+
+// TestMiddleware_ByAddress tests that limits keyed by address share the bucket of an address between its callers.
+func (suite *RateLimitTestSuite) TestMiddleware_ByAddress() {
+	engine := suite.engine(middleware.RateLimitConfig{Name: "ip", Rate: iratelimit.Rate{Limit: 1, Period: time.Minute}, ByAddress: true})
+
+	assert.Equal(suite.T(), http.StatusCreated, suite.request(engine, "10.0.0.1", "alice").Code)
+	assert.Equal(suite.T(), http.StatusTooManyRequests, suite.request(engine, "10.0.0.1", "bob").Code)
+	assert.Equal(suite.T(), http.StatusCreated, suite.request(engine, "10.0.0.2", "bob").Code)
+}
+
+// TestRouter_AddressBeforeAuthentication tests that the limit of each address turns away callers sending invalid
+// credentials before they are checked, while other addresses are still authenticated.
+func (suite *RateLimitTestSuite) TestRouter_AddressBeforeAuthentication() {
+	authentication, err := middleware.NewAuthentication(middleware.AuthenticationConfig{HS256Secret: "test-secret"})
+	suite.Require().NoError(err)
+	pc := controller.PersonController{GetAllHandler: query.NewGetPeopleHandler(mocks.NewMockPersonRepo(), nil)}
+	engine := router.NewRouter(router.Config{
+		Authentication: authentication,
+		RateLimits: router.RateLimits{Address: middleware.NewRateLimit(middleware.RateLimitConfig{
+			Name:      "ip",
+			Store:     suite.store,
+			Rate:      iratelimit.Rate{Limit: 1, Period: time.Minute},
+			ByAddress: true,
+		})},
+	}).Engine(
+		pc, controller.RelationController{}, controller.AttributeController{},
+		controller.APIKeyController{}, controller.AuthController{}, controller.UserController{}, controller.GraphQLController{}, controller.EventController{}, controller.WebhookController{}, controller.JobController{}, controller.ScheduleController{},
+	)
+	invalid := func(address string) int {
+		req := httptest.NewRequest(http.MethodGet, "/v1/person", nil)
+		req.RemoteAddr = address + ":1234"
+		req.Header.Set("Authorization", "Bearer not-a-token")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, invalid("10.0.0.1"))
+	assert.Equal(suite.T(), http.StatusTooManyRequests, invalid("10.0.0.1"))
+	assert.Equal(suite.T(), http.StatusUnauthorized, invalid("10.0.0.2"))
+}
+
+// TestParseRate tests parsing of the rate limit configuration.
+func (suite *RateLimitTestSuite) TestParseRate() {
+	rate, err := middleware.ParseRate("100/1m/20")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), iratelimit.Rate{Limit: 100, Period: time.Minute, Burst: 20}, rate)
+
+	rates, err := middleware.ParseClientRates([]string{"apikey:partner=5/1s"})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), iratelimit.Rate{Limit: 5, Period: time.Second}, rates["apikey:partner"])
+
+	for _, value := range []string{"", "100", "0/1m", "100/forever", "100/1m/0", "1/1s/2/3"} {
+		_, err := middleware.ParseRate(value)
+		assert.Error(suite.T(), err, value)
+	}
+	_, err = middleware.ParseClientRates([]string{"5/1s"})
+	assert.Error(suite.T(), err)
+}
+
+// TestRateLimitTestSuite runs the test suite.
+func TestRateLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitTestSuite))
+}
@@ -0,0 +1,102 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	relcmd "github.com/Efamamo/GoCrudChallange/application/relations/command"
+	relquery "github.com/Efamamo/GoCrudChallange/application/relations/query"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/person"
+	"github.com/Efamamo/GoCrudChallange/domain/model/relation"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// RelationTestSuite is the test suite for the relations between people.
+type RelationTestSuite struct {
+	suite.Suite
+	mockRepo         *mocks.MockPersonRepo
+	mockRelationRepo *mocks.MockRelationRepo
+}
+
+// SetupTest initializes the mock repositories before each test.
+func (suite *RelationTestSuite) SetupTest() {
+	suite.mockRepo = mocks.NewMockPersonRepo()
+	suite.mockRelationRepo = mocks.NewMockRelationRepo()
+}
+
+// createPerson creates a person and saves it into the mock repository.
+func (suite *RelationTestSuite) createPerson(name string) *model.Person {
+	person, err := model.CreatePerson(&model.PersonConfig{Name: name, Age: 30})
+	suite.Require().Nil(err)
+	suite.Require().Nil(suite.mockRepo.Save(context.Background(), person))
+	return person
+}
+
+// relate relates two people through the AddRelationHandler.
+func (suite *RelationTestSuite) relate(from, to *model.Person, kind string, directed bool) (*relation.Relation, ierr.IErr) {
+	handler := relcmd.NewAddRelationHandler(suite.mockRepo, suite.mockRelationRepo, nil)
+	return handler.Handle(&relcmd.AddRelationCommand{From: from.Id(), To: to.Id(), Type: kind, Directed: directed})
+}
+
+// TestAddRelationHandler_Failure_Duplicate tests that the same undirected relation can't be added twice.
+func (suite *RelationTestSuite) TestAddRelationHandler_Failure_Duplicate() {
+	alice, bob := suite.createPerson("Alice Walker"), suite.createPerson("Bobby Brown")
+
+	_, err := suite.relate(alice, bob, relation.Friend, false)
+	assert.Nil(suite.T(), err)
+
+	_, err = suite.relate(bob, alice, relation.Friend, false)
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), ierr.Conflict, err.Type())
+}
+
+// TestGetMutualHandler tests retrieving the connections two people have in common.
+func (suite *RelationTestSuite) TestGetMutualHandler() {
+	alice, bob, carol := suite.createPerson("Alice Walker"), suite.createPerson("Bobby Brown"), suite.createPerson("Carol Jones")
+	suite.relate(alice, carol, relation.Friend, false)
+	suite.relate(carol, bob, relation.Colleague, false)
+
+	handler := relquery.NewGetMutualHandler(suite.mockRepo, suite.mockRelationRepo, nil)
+
+	mutual, err := handler.Handle(&relquery.GetMutualQuery{PersonID: alice.Id(), OtherID: bob.Id()})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []*model.Person{carol}, mutual)
+}
+
+// TestGetPathHandler_FollowsDirections tests that directed relations are only followed forward.
+func (suite *RelationTestSuite) TestGetPathHandler_FollowsDirections() {
+	alice, bob, carol := suite.createPerson("Alice Walker"), suite.createPerson("Bobby Brown"), suite.createPerson("Carol Jones")
+	suite.relate(alice, bob, relation.Manager, true)
+	suite.relate(bob, carol, relation.Friend, false)
+
+	handler := relquery.NewGetPathHandler(suite.mockRepo, suite.mockRelationRepo, nil)
+
+	path, err := handler.Handle(&relquery.GetPathQuery{From: alice.Id(), To: carol.Id()})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []*model.Person{alice, bob, carol}, path)
+
+	path, err = handler.Handle(&relquery.GetPathQuery{From: carol.Id(), To: alice.Id()})
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), path)
+}
+
+// TestDeletePersonHandler_CascadesRelations tests that deleting a person removes their relations.
+func (suite *RelationTestSuite) TestDeletePersonHandler_CascadesRelations() {
+	alice, bob := suite.createPerson("Alice Walker"), suite.createPerson("Bobby Brown")
+	suite.relate(alice, bob, relation.Family, false)
+
+	_, err := command.NewDeletePersonHandler(suite.mockRepo, suite.mockRelationRepo, nil, nil).Handle(&command.DeletePersonCommand{ID: alice.Id()})
+	assert.Nil(suite.T(), err)
+
+	relations, _ := suite.mockRelationRepo.GetByPerson(context.Background(), bob.Id())
+	assert.Empty(suite.T(), relations)
+}
+
+// TestRelationTestSuite runs the test suite for relations between people.
+func TestRelationTestSuite(t *testing.T) {
+	suite.Run(t, new(RelationTestSuite))
+}
@@ -0,0 +1,373 @@
+package repo_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	"github.com/Efamamo/GoCrudChallange/application/common/auth"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	jobcmd "github.com/Efamamo/GoCrudChallange/application/jobs/command"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	schedquery "github.com/Efamamo/GoCrudChallange/application/schedules/query"
+	hookcmd "github.com/Efamamo/GoCrudChallange/application/webhooks/command"
+	ierr "github.com/Efamamo/GoCrudChallange/domain/error"
+	"github.com/Efamamo/GoCrudChallange/domain/model/job"
+	model "github.com/Efamamo/GoCrudChallange/domain/model/schedule"
+	"github.com/Efamamo/GoCrudChallange/domain/model/webhook"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/repository"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/schedule"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/snapshot"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// countingTask is a scheduled task counting its runs, which block until released when release is set.
+type countingTask struct {
+	runs    atomic.Int32
+	release chan struct{} // Closed to let the blocked runs finish; runs don't block when nil
+	err     error         // Error returned by every run
+	panics  bool          // Whether every run panics
+}
+
+// Run counts the run, then blocks, panics or fails as configured.
+func (t *countingTask) Run(ctx context.Context) (string, error) {
+	t.runs.Add(1)
+	if t.release != nil {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-t.release:
+		}
+	}
+	if t.panics {
+		panic("boom")
+	}
+	if t.err != nil {
+		return "", t.err
+	}
+	return "done", nil
+}
+
+// ScheduleTestSuite is the test suite for cron expressions, the scheduler and the maintenance tasks.
+type ScheduleTestSuite struct {
+	suite.Suite
+	scheduler *schedule.Scheduler
+	cancel    context.CancelFunc
+}
+
+// SetupTest creates a scheduler without jitter.
+func (suite *ScheduleTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.scheduler = schedule.NewScheduler(schedule.SchedulerConfig{})
+	suite.cancel = func() {}
+}
+
+// TearDownTest stops the scheduler, and waits for it.
+func (suite *ScheduleTestSuite) TearDownTest() {
+	suite.cancel()
+	suite.scheduler.Wait()
+}
+
+// start starts the scheduler until the test ends.
+func (suite *ScheduleTestSuite) start() {
+	var ctx context.Context
+	ctx, suite.cancel = context.WithCancel(context.Background())
+	suite.scheduler.Start(ctx)
+}
+
+// schedule returns the schedule of the named task.
+func (suite *ScheduleTestSuite) schedule(name string) *model.Schedule {
+	for _, s := range suite.scheduler.Schedules() {
+		if s.Name() == name {
+			return s
+		}
+	}
+	suite.FailNow("no schedule named " + name)
+	return nil
+}
+
+// TestParseCron tests the next activations of valid cron expressions.
+func (suite *ScheduleTestSuite) TestParseCron() {
+	// A Wednesday
+	from := time.Date(2026, time.January, 14, 10, 7, 30, 0, time.UTC)
+	cases := map[string]time.Time{
+		"* * * * *":        time.Date(2026, time.January, 14, 10, 8, 0, 0, time.UTC),
+		"*/15 * * * *":     time.Date(2026, time.January, 14, 10, 15, 0, 0, time.UTC),
+		"0 3 * * *":        time.Date(2026, time.January, 15, 3, 0, 0, 0, time.UTC),
+		"30 2 * * MON-FRI": time.Date(2026, time.January, 15, 2, 30, 0, 0, time.UTC),
+		"0 0 * * 0":        time.Date(2026, time.January, 18, 0, 0, 0, 0, time.UTC),
+		"0 0 * * 7":        time.Date(2026, time.January, 18, 0, 0, 0, 0, time.UTC),
+		"0 0 1 mar *":      time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC),
+		"0 0 13 * 5":       time.Date(2026, time.January, 16, 0, 0, 0, 0, time.UTC),
+		"5,10 9-11 * * *":  time.Date(2026, time.January, 14, 10, 10, 0, 0, time.UTC),
+		"5 12-14 * * *":    time.Date(2026, time.January, 14, 12, 5, 0, 0, time.UTC),
+		"0 0 29 2 *":       time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		"@hourly":          time.Date(2026, time.January, 14, 11, 0, 0, 0, time.UTC),
+		"@daily":           time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC),
+		"@weekly":          time.Date(2026, time.January, 18, 0, 0, 0, 0, time.UTC),
+		"@monthly":         time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),
+		"@yearly":          time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC),
+		"@every 90s":       time.Date(2026, time.January, 14, 10, 9, 0, 0, time.UTC),
+	}
+	for spec, expected := range cases {
+		cron, err := model.ParseCron(spec)
+		suite.Require().Nil(err, spec)
+		assert.Equal(suite.T(), expected, cron.Next(from), spec)
+		assert.Equal(suite.T(), spec, cron.String())
+	}
+
+	never, err := model.ParseCron("0 0 30 2 *")
+	suite.Require().Nil(err)
+	assert.True(suite.T(), never.Next(from).IsZero())
+}
+
+// TestParseCron_Invalid tests that invalid cron expressions are rejected with a Validation error.
+func (suite *ScheduleTestSuite) TestParseCron_Invalid() {
+	for _, spec := range []string{"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "* * * FOO *", "@often", "@every", "@every -1m", "@every soon"} {
+		_, err := model.ParseCron(spec)
+		if assert.NotNil(suite.T(), err, spec) {
+			assert.Equal(suite.T(), ierr.Validation, err.Type(), spec)
+		}
+	}
+}
+
+// TestRegister tests that disabled tasks are listed, and that invalid expressions are rejected.
+func (suite *ScheduleTestSuite) TestRegister() {
+	suite.Require().Nil(suite.scheduler.Register("b.enabled", "@daily", &countingTask{}))
+	suite.Require().Nil(suite.scheduler.Register("a.disabled", "", &countingTask{}))
+	assert.NotNil(suite.T(), suite.scheduler.Register("c.invalid", "every day", &countingTask{}))
+	suite.start()
+
+	schedules := suite.scheduler.Schedules()
+	suite.Require().Len(schedules, 2)
+	assert.Equal(suite.T(), "a.disabled", schedules[0].Name())
+	assert.False(suite.T(), schedules[0].Enabled())
+	assert.Equal(suite.T(), "b.enabled", schedules[1].Name())
+	assert.True(suite.T(), schedules[1].Enabled())
+	assert.Eventually(suite.T(), func() bool { return !suite.schedule("b.enabled").NextRun().IsZero() }, time.Second, time.Millisecond)
+	assert.True(suite.T(), suite.schedule("a.disabled").NextRun().IsZero())
+}
+
+// TestRun tests that a task runs on every activation, and records how its last run went.
+func (suite *ScheduleTestSuite) TestRun() {
+	task := &countingTask{}
+	suite.Require().Nil(suite.scheduler.Register("test.run", "@every 10ms", task))
+	suite.start()
+
+	assert.Eventually(suite.T(), func() bool { return suite.schedule("test.run").Runs() >= 3 }, 2*time.Second, time.Millisecond)
+	s := suite.schedule("test.run")
+	suite.Require().NotNil(s.LastRun())
+	assert.Equal(suite.T(), model.Succeeded, s.LastRun().Outcome)
+	assert.Equal(suite.T(), "done", s.LastRun().Message)
+	assert.Zero(suite.T(), s.Failures())
+}
+
+// TestRun_Overlap tests that activations due while the previous run is still running are skipped.
+func (suite *ScheduleTestSuite) TestRun_Overlap() {
+	task := &countingTask{release: make(chan struct{})}
+	suite.Require().Nil(suite.scheduler.Register("test.slow", "@every 10ms", task))
+	suite.start()
+
+	assert.Eventually(suite.T(), func() bool { return suite.schedule("test.slow").Skipped() >= 3 }, 2*time.Second, time.Millisecond)
+	assert.Equal(suite.T(), int32(1), task.runs.Load())
+	assert.True(suite.T(), suite.schedule("test.slow").Running())
+
+	close(task.release)
+	assert.Eventually(suite.T(), func() bool { return suite.schedule("test.slow").Runs() >= 2 }, 2*time.Second, time.Millisecond)
+}
+
+// TestRun_Failure tests that failed and panicking runs are recorded without stopping the scheduler.
+func (suite *ScheduleTestSuite) TestRun_Failure() {
+	suite.Require().Nil(suite.scheduler.Register("test.fail", "@every 10ms", &countingTask{err: errors.New("disk full")}))
+	suite.Require().Nil(suite.scheduler.Register("test.panic", "@every 10ms", &countingTask{panics: true}))
+	suite.start()
+
+	assert.Eventually(suite.T(), func() bool {
+		return suite.schedule("test.fail").Failures() >= 2 && suite.schedule("test.panic").Failures() >= 2
+	}, 2*time.Second, time.Millisecond)
+	assert.Equal(suite.T(), model.Failed, suite.schedule("test.fail").LastRun().Outcome)
+	assert.Equal(suite.T(), "disk full", suite.schedule("test.fail").LastRun().Message)
+	assert.Contains(suite.T(), suite.schedule("test.panic").LastRun().Message, "boom")
+}
+
+// TestWait tests that the runs in progress are cancelled and waited for once the scheduler stops.
+func (suite *ScheduleTestSuite) TestWait() {
+	task := &countingTask{release: make(chan struct{})}
+	suite.Require().Nil(suite.scheduler.Register("test.slow", "@every 10ms", task))
+	suite.start()
+	assert.Eventually(suite.T(), func() bool { return suite.schedule("test.slow").Running() }, 2*time.Second, time.Millisecond)
+
+	suite.cancel()
+	suite.scheduler.Wait()
+	s := suite.schedule("test.slow")
+	assert.False(suite.T(), s.Running())
+	assert.Equal(suite.T(), model.Failed, s.LastRun().Outcome)
+	assert.Equal(suite.T(), context.Canceled.Error(), s.LastRun().Message)
+}
+
+// TestCompactJobs tests that only the jobs finished before the retention are removed.
+func (suite *ScheduleTestSuite) TestCompactJobs() {
+	repo, rerr := repository.NewJobRepo(filepath.Join(suite.T().TempDir(), "jobs.json"))
+	suite.Require().NoError(rerr)
+	old := job.NewJob("test.block", job.Submitter{}, nil)
+	old.Fail("failed", time.Now().UTC().Add(-48*time.Hour))
+	recent := job.NewJob("test.block", job.Submitter{}, nil)
+	recent.Succeed([]byte(`{}`), time.Now().UTC())
+	queued := job.NewJob("test.block", job.Submitter{}, nil)
+	for _, j := range []*job.Job{old, recent, queued} {
+		suite.Require().Nil(repo.Save(j))
+	}
+
+	message, err := jobcmd.NewCompactJobsHandler(repo, 24*time.Hour).Run(context.Background())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "deleted 1 jobs", message)
+
+	remaining, gerr := repo.GetAll()
+	suite.Require().Nil(gerr)
+	assert.Len(suite.T(), remaining, 2)
+	_, gerr = repo.Get(old.Id())
+	suite.Require().NotNil(gerr)
+	assert.Equal(suite.T(), ierr.NotFound, gerr.Type())
+}
+
+// TestCompactDeliveries tests that finished deliveries are removed once the retention is over, and pending ones kept.
+func (suite *ScheduleTestSuite) TestCompactDeliveries() {
+	repo := repository.NewDeliveryRepo()
+	hook := uuid.New()
+	succeeded := webhook.NewDelivery(hook, "person.created", 1, []byte(`{}`))
+	succeeded.Succeed(200, time.Now().UTC())
+	dead := webhook.NewDelivery(hook, "person.deleted", 2, []byte(`{}`))
+	dead.DeadLetter("gone")
+	pending := webhook.NewDelivery(hook, "person.updated", 3, []byte(`{}`))
+	for _, d := range []*webhook.Delivery{succeeded, dead, pending} {
+		suite.Require().Nil(repo.Save(d))
+	}
+
+	message, err := hookcmd.NewCompactDeliveriesHandler(repo, time.Hour).Run(context.Background())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "deleted 0 deliveries", message)
+
+	message, err = hookcmd.NewCompactDeliveriesHandler(repo, -time.Minute).Run(context.Background())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "deleted 2 deliveries", message)
+
+	remaining, gerr := repo.GetByWebhook(hook)
+	suite.Require().Nil(gerr)
+	suite.Require().Len(remaining, 1)
+	assert.Equal(suite.T(), pending.Id(), remaining[0].Id())
+}
+
+// TestSnapshotPeople tests that the people of every tenant are written to their own snapshot,
+// and that the oldest snapshots are pruned.
+func (suite *ScheduleTestSuite) TestSnapshotPeople() {
+	people := mocks.NewMockPersonRepo()
+	create := command.NewCreatePersonHandler(people, mocks.NewMockAttributeRepo(), nil)
+	for i, t := range []string{"acme", "acme", "globex"} {
+		_, err := create.Handle(&command.CreatePersonCommand{
+			Context: tenant.WithTenant(as("alice", "owner"), t),
+			Name:    "Alice Smith",
+			Age:     30,
+			Email:   fmt.Sprintf("alice%d@example.com", i),
+		})
+		suite.Require().Nil(err)
+	}
+
+	dir := suite.T().TempDir()
+	stale := filepath.Join(dir, "acme", "people-20000101T000000Z.ndjson")
+	suite.Require().NoError(os.MkdirAll(filepath.Dir(stale), 0o755))
+	suite.Require().NoError(os.WriteFile(stale, nil, 0o644))
+
+	snapshotter := snapshot.NewPeopleSnapshotter(snapshot.PeopleConfig{People: people, Dir: dir, Keep: 1})
+	message, err := snapshotter.Run(context.Background())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "wrote 3 people of 2 tenants", message)
+
+	counts := map[string]int{}
+	for _, t := range []string{"acme", "globex"} {
+		files, gerr := filepath.Glob(filepath.Join(dir, t, "*"))
+		suite.Require().NoError(gerr)
+		suite.Require().Len(files, 1, t)
+		assert.NotEqual(suite.T(), stale, files[0])
+
+		file, oerr := os.Open(files[0])
+		suite.Require().NoError(oerr)
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var record map[string]any
+			suite.Require().NoError(json.Unmarshal(scanner.Bytes(), &record))
+			assert.Equal(suite.T(), "Alice Smith", record["name"])
+			assert.Contains(suite.T(), record["email"], "@example.com")
+			assert.Equal(suite.T(), "alice", record["owner"])
+			counts[t]++
+		}
+		file.Close()
+	}
+	assert.Equal(suite.T(), map[string]int{"acme": 2, "globex": 1}, counts)
+}
+
+// TestGetAll tests that the schedules are listed at /admin/schedules, to admins only.
+func (suite *ScheduleTestSuite) TestGetAll() {
+	task := &countingTask{}
+	suite.Require().Nil(suite.scheduler.Register("test.run", "@every 10ms", task))
+	suite.Require().Nil(suite.scheduler.Register("test.disabled", "", task))
+	suite.start()
+	assert.Eventually(suite.T(), func() bool { return suite.schedule("test.run").Runs() >= 1 }, 2*time.Second, time.Millisecond)
+
+	sc := controller.ScheduleController{
+		BaseController: controller.BaseController{Authorizer: auth.NewAuthorizer(auth.DefaultPolicy())},
+
+		GetAllHandler: schedquery.NewGetSchedulesHandler(suite.scheduler),
+	}
+	engine := gin.New()
+	engine.GET("/admin/schedules", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(as("alice", c.GetHeader("X-Role")))
+	}, sc.GetAll)
+
+	request := func(role string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin/schedules", nil)
+		req.Header.Set("X-Role", role)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(suite.T(), http.StatusForbidden, request("owner").Code)
+
+	w := request("admin")
+	suite.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+	var schedules []controller.ScheduleResponseDTO
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &schedules))
+	suite.Require().Len(schedules, 2)
+
+	assert.Equal(suite.T(), "test.disabled", schedules[0].Name)
+	assert.False(suite.T(), schedules[0].Enabled)
+	assert.Empty(suite.T(), schedules[0].Cron)
+	assert.Nil(suite.T(), schedules[0].LastRun)
+
+	assert.Equal(suite.T(), "test.run", schedules[1].Name)
+	assert.True(suite.T(), schedules[1].Enabled)
+	assert.Equal(suite.T(), "@every 10ms", schedules[1].Cron)
+	assert.NotNil(suite.T(), schedules[1].NextRunAt)
+	suite.Require().NotNil(schedules[1].LastRun)
+	assert.Equal(suite.T(), "succeeded", schedules[1].LastRun.Outcome)
+	assert.GreaterOrEqual(suite.T(), schedules[1].Runs, 1)
+}
+
+// TestScheduleTestSuite runs the test suite.
+func TestScheduleTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduleTestSuite))
+}
@@ -0,0 +1,131 @@
+package repo_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	"github.com/Efamamo/GoCrudChallange/api/router"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	"github.com/Efamamo/GoCrudChallange/application/people/query"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// VersioningTestSuite is the test suite for the /v1 and /v2 route groups.
+type VersioningTestSuite struct {
+	suite.Suite
+	engine *gin.Engine
+}
+
+// SetupTest builds the router of the API, with v1 sunsetting at the end of 2027.
+func (suite *VersioningTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	repo := mocks.NewMockPersonRepo()
+	attributes := mocks.NewMockAttributeRepo()
+
+	pc := controller.PersonController{
+		CreateHandler: command.NewCreatePersonHandler(repo, attributes, nil),
+		GetHandler:    query.NewGetPersonHandler(repo, nil),
+		GetAllHandler: query.NewGetPeopleHandler(repo, nil),
+	}
+	suite.engine = router.NewRouter(router.Config{V1Sunset: time.Date(2027, 12, 31, 0, 0, 0, 0, time.UTC)}).Engine(
+		pc, controller.RelationController{}, controller.AttributeController{},
+		controller.APIKeyController{}, controller.AuthController{}, controller.UserController{}, controller.GraphQLController{}, controller.EventController{}, controller.WebhookController{}, controller.JobController{}, controller.ScheduleController{},
+	)
+}
+
+// request performs a request on the router with the given Accept header, and decodes the response body.
+func (suite *VersioningTestSuite) request(method string, path string, body string, accept string) (*httptest.ResponseRecorder, any) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+
+	var response any
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+// create creates a person through v1 and returns its id.
+func (suite *VersioningTestSuite) create(name string) string {
+	w, response := suite.request(http.MethodPost, "/v1/person", `{"name": "`+name+`", "age": 30}`, "")
+	suite.Require().Equal(http.StatusCreated, w.Code)
+	return response.(map[string]any)["id"].(string)
+}
+
+// TestV1_Deprecated tests that v1, bare or not, keeps its bodies and announces its deprecation.
+func (suite *VersioningTestSuite) TestV1_Deprecated() {
+	id := suite.create("Alice Smith")
+
+	for _, path := range []string{"/v1/person/" + id, "/person/" + id} {
+		w, response := suite.request(http.MethodGet, path, "", "")
+		suite.Require().Equal(http.StatusOK, w.Code, path)
+		assert.Equal(suite.T(), id, response.(map[string]any)["id"])
+		assert.NotContains(suite.T(), response, "created_at")
+		assert.Equal(suite.T(), "true", w.Header().Get("Deprecation"))
+		assert.Equal(suite.T(), "Fri, 31 Dec 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+		assert.Equal(suite.T(), `</v2/person>; rel="successor-version"`, w.Header().Get("Link"))
+	}
+}
+
+// TestV2_Envelope tests that v2 wraps its bodies in an envelope, with the timestamps of people.
+func (suite *VersioningTestSuite) TestV2_Envelope() {
+	w, response := suite.request(http.MethodPost, "/v2/person", `{"name": "Alice Smith", "age": 30}`, "")
+	suite.Require().Equal(http.StatusCreated, w.Code)
+	assert.Empty(suite.T(), w.Header().Get("Deprecation"))
+
+	person := response.(map[string]any)["data"].(map[string]any)
+	assert.Equal(suite.T(), "Alice Smith", person["name"])
+	assert.NotEmpty(suite.T(), person["created_at"])
+	assert.NotEmpty(suite.T(), person["updated_at"])
+}
+
+// TestV2_Accept tests that the Accept header selects v2 on the bare routes.
+func (suite *VersioningTestSuite) TestV2_Accept() {
+	id := suite.create("Alice Smith")
+
+	for _, accept := range []string{"application/vnd.gocrud.v2+json", "application/json; version=2"} {
+		w, response := suite.request(http.MethodGet, "/person/"+id, "", accept)
+		suite.Require().Equal(http.StatusOK, w.Code, accept)
+		assert.Equal(suite.T(), id, response.(map[string]any)["data"].(map[string]any)["id"])
+		assert.Empty(suite.T(), w.Header().Get("Deprecation"))
+		assert.Equal(suite.T(), "Accept", w.Header().Get("Vary"))
+	}
+}
+
+// TestV2_Pagination tests that v2 lists people a page at a time.
+func (suite *VersioningTestSuite) TestV2_Pagination() {
+	suite.create("Alice Smith")
+	suite.create("Bobby Jones")
+	suite.create("Carol White")
+
+	w, response := suite.request(http.MethodGet, "/v2/person?limit=2&offset=1", "", "")
+	suite.Require().Equal(http.StatusOK, w.Code)
+	body := response.(map[string]any)
+	assert.Len(suite.T(), body["data"], 2)
+	assert.Equal(suite.T(), map[string]any{"total": float64(3), "limit": float64(2), "offset": float64(1)}, body["meta"])
+
+	_, response = suite.request(http.MethodGet, "/v2/person?offset=5", "", "")
+	assert.Len(suite.T(), response.(map[string]any)["data"], 0)
+
+	w, _ = suite.request(http.MethodGet, "/v2/person?limit=0", "", "")
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+
+	_, response = suite.request(http.MethodGet, "/v1/person", "", "")
+	assert.Len(suite.T(), response, 3)
+}
+
+// TestVersioningTestSuite runs the test suite.
+func TestVersioningTestSuite(t *testing.T) {
+	suite.Run(t, new(VersioningTestSuite))
+}
@@ -0,0 +1,286 @@
+package repo_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Efamamo/GoCrudChallange/api/controller"
+	ievents "github.com/Efamamo/GoCrudChallange/application/common/interface/events"
+	"github.com/Efamamo/GoCrudChallange/application/common/tenant"
+	"github.com/Efamamo/GoCrudChallange/application/people/command"
+	hookcmd "github.com/Efamamo/GoCrudChallange/application/webhooks/command"
+	hookquery "github.com/Efamamo/GoCrudChallange/application/webhooks/query"
+	"github.com/Efamamo/GoCrudChallange/domain/model/webhook"
+	"github.com/Efamamo/GoCrudChallange/infrastructure/events"
+	dispatch "github.com/Efamamo/GoCrudChallange/infrastructure/webhook"
+	"github.com/Efamamo/GoCrudChallange/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// receivedDelivery is a delivery received by the test receiver.
+type receivedDelivery struct {
+	header http.Header
+	body   []byte
+}
+
+// WebhookTestSuite is the test suite for webhooks, their administration and their deliveries.
+type WebhookTestSuite struct {
+	suite.Suite
+	engine   *gin.Engine
+	create   *command.CreatePersonHandler
+	delete   *command.DeletePersonHandler
+	cancel   context.CancelFunc
+	receiver *httptest.Server
+
+	mutex    sync.Mutex
+	received []receivedDelivery
+	statuses []int // Status codes the receiver responds with, in order; the last one is repeated
+}
+
+// SetupTest builds a router administering webhooks, and a dispatcher retrying failed deliveries up to
+// 3 times within milliseconds, delivering to a local receiver.
+func (suite *WebhookTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	webhooks := mocks.NewMockWebhookRepo()
+	deliveries := mocks.NewMockDeliveryRepo()
+	broker := events.NewBroker(10)
+	repo := mocks.NewMockPersonRepo()
+
+	suite.received = nil
+	suite.statuses = []int{http.StatusNoContent}
+	suite.receiver = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		suite.mutex.Lock()
+		suite.received = append(suite.received, receivedDelivery{header: r.Header.Clone(), body: body})
+		status := suite.statuses[min(len(suite.received), len(suite.statuses))-1]
+		suite.mutex.Unlock()
+		w.WriteHeader(status)
+	}))
+
+	var ctx context.Context
+	ctx, suite.cancel = context.WithCancel(context.Background())
+	dispatch.NewDispatcher(dispatch.DispatcherConfig{
+		Webhooks:    webhooks,
+		Deliveries:  deliveries,
+		MaxAttempts: 3,
+		Backoff:     10 * time.Millisecond,
+		MaxBackoff:  20 * time.Millisecond,
+		Interval:    5 * time.Millisecond,
+	}).Start(ctx, broker)
+
+	suite.create = command.NewCreatePersonHandler(repo, mocks.NewMockAttributeRepo(), broker)
+	suite.delete = command.NewDeletePersonHandler(repo, mocks.NewMockRelationRepo(), nil, broker)
+
+	wc := controller.WebhookController{
+		CreateHandler:     hookcmd.NewCreateWebhookHandler(webhooks),
+		UpdateHandler:     hookcmd.NewUpdateWebhookHandler(webhooks),
+		DeleteHandler:     hookcmd.NewDeleteWebhookHandler(webhooks, deliveries),
+		GetHandler:        hookquery.NewGetWebhookHandler(webhooks),
+		GetAllHandler:     hookquery.NewGetWebhooksHandler(webhooks),
+		DeliveriesHandler: hookquery.NewGetDeliveriesHandler(webhooks, deliveries),
+	}
+	suite.engine = gin.New()
+	suite.engine.POST("/admin/webhooks", wc.Create)
+	suite.engine.GET("/admin/webhooks", wc.GetAll)
+	suite.engine.GET("/admin/webhooks/:id", wc.Get)
+	suite.engine.PUT("/admin/webhooks/:id", wc.Update)
+	suite.engine.DELETE("/admin/webhooks/:id", wc.Delete)
+	suite.engine.GET("/admin/webhooks/:id/deliveries", wc.Deliveries)
+}
+
+// TearDownTest stops the dispatcher and the receiver.
+func (suite *WebhookTestSuite) TearDownTest() {
+	suite.cancel()
+	suite.receiver.Close()
+}
+
+// request performs a request on the router, and decodes the response body.
+func (suite *WebhookTestSuite) request(method string, path string, body string) (*httptest.ResponseRecorder, any) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+
+	var response any
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+// subscribe creates a webhook delivering the event types to the receiver, and returns its id and secret.
+func (suite *WebhookTestSuite) subscribe(events ...string) (string, string) {
+	body, err := json.Marshal(map[string]any{"url": suite.receiver.URL + "/hooks", "events": events})
+	suite.Require().NoError(err)
+
+	w, response := suite.request(http.MethodPost, "/admin/webhooks", string(body))
+	suite.Require().Equal(http.StatusCreated, w.Code)
+	hook := response.(map[string]any)
+	return hook["id"].(string), hook["secret"].(string)
+}
+
+// createPerson creates a person in the acme tenant through the command handler, which publishes the change.
+func (suite *WebhookTestSuite) createPerson(name string) uuid.UUID {
+	p, err := suite.create.Handle(&command.CreatePersonCommand{Context: tenant.WithTenant(context.Background(), "acme"), ID: uuid.New(), Name: name, Age: 30})
+	suite.Require().Nil(err)
+	return p.Id()
+}
+
+// deliveries waits until the webhook has a delivery in the state, and returns its delivery log.
+func (suite *WebhookTestSuite) deliveries(id string, status webhook.DeliveryStatus) []any {
+	var log []any
+	suite.Require().Eventually(func() bool {
+		w, response := suite.request(http.MethodGet, "/admin/webhooks/"+id+"/deliveries?status="+string(status), "")
+		suite.Require().Equal(http.StatusOK, w.Code)
+		log = response.([]any)
+		return len(log) > 0
+	}, 2*time.Second, 5*time.Millisecond)
+	return log
+}
+
+// TestAdmin tests that webhooks are created, listed, updated and deleted, and that their secret is only shown once.
+func (suite *WebhookTestSuite) TestAdmin() {
+	for _, body := range []string{
+		`{"url": "ftp://example.com", "events": ["person.created"]}`,
+		`{"url": "https://example.com", "events": ["person.renamed"]}`,
+		`{"url": "https://example.com", "events": []}`,
+	} {
+		w, _ := suite.request(http.MethodPost, "/admin/webhooks", body)
+		assert.Equal(suite.T(), http.StatusBadRequest, w.Code, body)
+	}
+
+	id, secret := suite.subscribe("person.created")
+	assert.True(suite.T(), strings.HasPrefix(secret, "whsec_"))
+
+	w, response := suite.request(http.MethodGet, "/admin/webhooks", "")
+	suite.Require().Equal(http.StatusOK, w.Code)
+	suite.Require().Len(response, 1)
+	assert.NotContains(suite.T(), response.([]any)[0], "secret")
+
+	w, response = suite.request(http.MethodPut, "/admin/webhooks/"+id, `{"url": "https://example.com/hooks", "events": ["person.deleted"]}`)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	assert.Equal(suite.T(), []any{"person.deleted"}, response.(map[string]any)["events"])
+
+	w, _ = suite.request(http.MethodDelete, "/admin/webhooks/"+id, "")
+	assert.Equal(suite.T(), http.StatusNoContent, w.Code)
+
+	for _, path := range []string{"/admin/webhooks/" + id, "/admin/webhooks/" + id + "/deliveries"} {
+		w, _ = suite.request(http.MethodGet, path, "")
+		assert.Equal(suite.T(), http.StatusNotFound, w.Code, path)
+	}
+
+	w, _ = suite.request(http.MethodGet, "/admin/webhooks/"+uuid.NewString()+"/deliveries?status=lost", "")
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
+
+// TestDelivery_Signed tests that the subscribed changes are posted to the receiver, signed with the secret.
+func (suite *WebhookTestSuite) TestDelivery_Signed() {
+	id, secret := suite.subscribe("person.deleted")
+
+	personID := suite.createPerson("Alice Smith")
+	_, err := suite.delete.Handle(&command.DeletePersonCommand{Context: tenant.WithTenant(context.Background(), "acme"), ID: personID})
+	suite.Require().Nil(err)
+
+	log := suite.deliveries(id, webhook.Succeeded)
+	suite.Require().Len(log, 1)
+	delivery := log[0].(map[string]any)
+	assert.Equal(suite.T(), "person.deleted", delivery["event"])
+	assert.Equal(suite.T(), float64(1), delivery["attempts"])
+	assert.Equal(suite.T(), float64(http.StatusNoContent), delivery["status_code"])
+
+	suite.mutex.Lock()
+	defer suite.mutex.Unlock()
+	suite.Require().Len(suite.received, 1)
+	received := suite.received[0]
+	assert.Equal(suite.T(), delivery["id"], received.header.Get(dispatch.DeliveryHeader))
+	assert.Equal(suite.T(), "person.deleted", received.header.Get(dispatch.EventHeader))
+
+	timestamp, perr := strconv.ParseInt(received.header.Get(dispatch.TimestampHeader), 10, 64)
+	suite.Require().NoError(perr)
+	assert.Equal(suite.T(), "sha256="+webhook.Sign(secret, time.Unix(timestamp, 0), received.body), received.header.Get(dispatch.SignatureHeader))
+
+	var payload map[string]any
+	suite.Require().NoError(json.Unmarshal(received.body, &payload))
+	assert.Equal(suite.T(), string(ievents.PersonDeleted), payload["type"])
+	assert.Equal(suite.T(), "acme", payload["tenant"])
+	assert.Equal(suite.T(), personID.String(), payload["person"].(map[string]any)["id"])
+}
+
+// TestDelivery_Retried tests that failed deliveries are retried until the receiver acknowledges them.
+func (suite *WebhookTestSuite) TestDelivery_Retried() {
+	suite.statuses = []int{http.StatusServiceUnavailable, http.StatusOK}
+	id, _ := suite.subscribe("person.created")
+	suite.createPerson("Alice Smith")
+
+	delivery := suite.deliveries(id, webhook.Succeeded)[0].(map[string]any)
+	assert.Equal(suite.T(), float64(2), delivery["attempts"])
+	assert.Equal(suite.T(), float64(http.StatusOK), delivery["status_code"])
+	assert.NotContains(suite.T(), delivery, "last_error")
+
+	suite.mutex.Lock()
+	defer suite.mutex.Unlock()
+	suite.Require().Len(suite.received, 2)
+	assert.Equal(suite.T(), suite.received[0].header.Get(dispatch.DeliveryHeader), suite.received[1].header.Get(dispatch.DeliveryHeader))
+}
+
+// TestDelivery_DeadLettered tests that deliveries are dead-lettered once they run out of attempts.
+func (suite *WebhookTestSuite) TestDelivery_DeadLettered() {
+	suite.statuses = []int{http.StatusInternalServerError}
+	id, _ := suite.subscribe("person.created")
+	suite.createPerson("Alice Smith")
+
+	delivery := suite.deliveries(id, webhook.DeadLettered)[0].(map[string]any)
+	assert.Equal(suite.T(), float64(3), delivery["attempts"])
+	assert.Equal(suite.T(), float64(http.StatusInternalServerError), delivery["status_code"])
+	assert.Equal(suite.T(), "receiver responded with 500 Internal Server Error", delivery["last_error"])
+	assert.NotContains(suite.T(), delivery, "next_attempt_at")
+}
+
+// TestDelivery_SlowReceiver tests that a receiver slow to respond doesn't hold up the deliveries of the other webhooks.
+func (suite *WebhookTestSuite) TestDelivery_SlowReceiver() {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	w, _ := suite.request(http.MethodPost, "/admin/webhooks", `{"url": "`+slow.URL+`/hooks", "events": ["person.created"]}`)
+	suite.Require().Equal(http.StatusCreated, w.Code)
+	id, _ := suite.subscribe("person.created")
+
+	suite.createPerson("Alice Smith")
+	suite.createPerson("Bobby Jones")
+
+	suite.Require().Eventually(func() bool {
+		return len(suite.deliveries(id, webhook.Succeeded)) == 2
+	}, 2*time.Second, 5*time.Millisecond)
+}
+
+// TestDelivery_Backoff tests that the delay between two attempts doubles, up to its maximum.
+func (suite *WebhookTestSuite) TestDelivery_Backoff() {
+	delivery := webhook.NewDelivery(uuid.New(), string(ievents.PersonCreated), 1, nil)
+	at := time.Now()
+
+	for _, delay := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		delivery.Fail(0, "connection refused", at, 10, time.Second, 5*time.Second)
+		assert.Equal(suite.T(), at.Add(delay), delivery.NextAttemptAt())
+		assert.False(suite.T(), delivery.Due(at.Add(delay-time.Millisecond)))
+		assert.True(suite.T(), delivery.Due(at.Add(delay)))
+	}
+}
+
+// TestWebhookTestSuite runs the test suite.
+func TestWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}